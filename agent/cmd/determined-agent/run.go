@@ -85,6 +85,9 @@ func newRunCmd() *cobra.Command {
 	// Labels flags.
 	cmd.Flags().StringVar(&opts.Label, "label", "",
 		"Label attached to the agent for scheduling constraints")
+	cmd.Flags().StringToStringVar(&opts.Labels, "labels", nil,
+		"Key/value labels attached to the agent for agent selectors and affinities, "+
+			"e.g., gpu=a100,zone=us-east-1a")
 
 	// ResourcePool flags.
 	cmd.Flags().StringVar(&opts.ResourcePool, "resource-pool", "",
@@ -99,6 +102,8 @@ func newRunCmd() *cobra.Command {
 	// Device flags.
 	cmd.Flags().StringVar(&opts.SlotType, "slot-type", "auto", "slot type to expose")
 	cmd.Flags().StringVar(&opts.VisibleGPUs, "visible-gpus", "", "GPUs to expose as slots")
+	cmd.Flags().IntVar(&opts.SlotsPerDevice, "slots-per-device", 1,
+		"number of slots to split each device into for tasks to share it")
 
 	// Security flags.
 	cmd.Flags().BoolVar(
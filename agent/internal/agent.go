@@ -44,6 +44,7 @@ type agent struct {
 	Options               `json:"options"`
 	MasterSetAgentOptions *aproto.MasterSetAgentOptions
 	Devices               []device.Device `json:"devices"`
+	HostInfo              aproto.HostInfo `json:"host_info"`
 
 	socket *actor.Ref
 	cm     *actor.Ref
@@ -70,6 +71,10 @@ func (a *agent) Receive(ctx *actor.Context) error {
 		switch {
 		case msg.MasterSetAgentOptions != nil:
 			if a.MasterSetAgentOptions != nil {
+				if msg.MasterSetAgentOptions.MasterInfo.MasterID !=
+					a.MasterSetAgentOptions.MasterInfo.MasterID {
+					return a.reregister(ctx, msg.MasterSetAgentOptions)
+				}
 				ctx.Log().Debugf("received MasterStepAgentOptions more than once: %v",
 					*msg.MasterSetAgentOptions)
 				return nil
@@ -418,14 +423,34 @@ func (a *agent) setup(ctx *actor.Context) error {
 	}
 	a.cm, _ = ctx.ActorOf("containers", cm)
 
+	a.sendAgentStarted(ctx)
+	return nil
+}
+
+// reregister registers the agent with a master other than the one it was set up by, which happens
+// when it reconnects after the master restarted or another master took over as the leader. The new
+// master restores its tasks from the database, so the containers of the old master are killed.
+func (a *agent) reregister(ctx *actor.Context, opts *aproto.MasterSetAgentOptions) error {
+	ctx.Log().Infof("reconnected to a different master %s; registering again",
+		opts.MasterInfo.MasterID)
+	if err := ctx.Ask(a.cm, masterChanged{MasterInfo: opts.MasterInfo}).Error(); err != nil {
+		return errors.Wrap(err, "error switching container manager to the new master")
+	}
+	a.MasterSetAgentOptions = opts
+	a.sendAgentStarted(ctx)
+	return nil
+}
+
+func (a *agent) sendAgentStarted(ctx *actor.Context) {
 	ctx.Ask(a.socket, api.WriteMessage{Message: aproto.MasterMessage{
 		AgentStarted: &aproto.AgentStarted{
-			Version: a.Version,
-			Devices: a.Devices,
-			Label:   a.Label,
+			Version:  a.Version,
+			Devices:  a.Devices,
+			Label:    a.Label,
+			Labels:   a.Labels,
+			HostInfo: a.HostInfo,
 		},
 	}})
-	return nil
 }
 
 func (a *agent) connectToMaster(ctx *actor.Context) error {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"syscall"
 
 	dcontainer "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
@@ -29,6 +30,11 @@ const (
 	dockerMasterLabel           = "ai.determined.container.master"
 )
 
+// masterChanged notifies the container manager that the agent registered with a different master.
+type masterChanged struct {
+	MasterInfo aproto.MasterInfo
+}
+
 type containerManager struct {
 	Options       Options           `json:"-"`
 	MasterInfo    aproto.MasterInfo `json:"-"`
@@ -58,41 +64,19 @@ func (c *containerManager) Receive(ctx *actor.Context) error {
 		}
 		c.docker = d
 
-		masterScheme := httpInsecureScheme
-		if c.Options.Security.TLS.Enabled {
-			masterScheme = httpSecureScheme
-		}
-
-		masterHost := c.Options.ContainerMasterHost
-		if masterHost == "" {
-			masterHost = c.Options.MasterHost
-		}
-
-		masterPort := c.Options.ContainerMasterPort
-		if masterPort == 0 {
-			masterPort = c.Options.MasterPort
-		}
-
-		c.GlobalEnvVars = []string{
-			fmt.Sprintf("DET_CLUSTER_ID=%s", c.MasterInfo.ClusterID),
-			fmt.Sprintf("DET_MASTER_ID=%s", c.MasterInfo.MasterID),
-			fmt.Sprintf("DET_MASTER=%s://%s:%d", masterScheme, masterHost, masterPort),
-			fmt.Sprintf("DET_MASTER_HOST=%s", masterHost),
-			fmt.Sprintf("DET_MASTER_ADDR=%s", masterHost),
-			fmt.Sprintf("DET_MASTER_PORT=%d", masterPort),
-			fmt.Sprintf("DET_AGENT_ID=%s", c.Options.AgentID),
-		}
-
-		if a := c.Options.Security.TLS.MasterCertName; a != "" {
-			c.GlobalEnvVars = append(c.GlobalEnvVars, fmt.Sprintf("DET_MASTER_CERT_NAME=%s", a))
-		}
+		c.setMasterInfo(c.MasterInfo)
 
-		c.Labels = map[string]string{
-			dockerContainerTypeLabel: dockerContainerTypeValue,
-			dockerAgentLabel:         c.Options.AgentID,
-			dockerClusterLabel:       c.MasterInfo.ClusterID,
-			dockerMasterLabel:        c.MasterInfo.MasterID,
+	case masterChanged:
+		// The containers were started by the previous master, which the new master knows
+		// nothing about, so nothing will ever stop them.
+		for _, ref := range ctx.Children() {
+			ctx.Log().Infof("killing container %s started by master %s",
+				ref.Address().Local(), c.MasterInfo.MasterID)
+			ctx.Tell(ref, aproto.SignalContainer{
+				ContainerID: cproto.ID(ref.Address().Local()), Signal: syscall.SIGKILL,
+			})
 		}
+		c.setMasterInfo(msg.MasterInfo)
 
 	case aproto.ContainerLog, aproto.ContainerStateChanged, model.TrialLog:
 		ctx.Tell(ctx.Self().Parent(), msg)
@@ -128,6 +112,47 @@ func (c *containerManager) Receive(ctx *actor.Context) error {
 	return nil
 }
 
+// setMasterInfo sets the master the containers started from now on belong to.
+func (c *containerManager) setMasterInfo(info aproto.MasterInfo) {
+	c.MasterInfo = info
+
+	masterScheme := httpInsecureScheme
+	if c.Options.Security.TLS.Enabled {
+		masterScheme = httpSecureScheme
+	}
+
+	masterHost := c.Options.ContainerMasterHost
+	if masterHost == "" {
+		masterHost = c.Options.MasterHost
+	}
+
+	masterPort := c.Options.ContainerMasterPort
+	if masterPort == 0 {
+		masterPort = c.Options.MasterPort
+	}
+
+	c.GlobalEnvVars = []string{
+		fmt.Sprintf("DET_CLUSTER_ID=%s", c.MasterInfo.ClusterID),
+		fmt.Sprintf("DET_MASTER_ID=%s", c.MasterInfo.MasterID),
+		fmt.Sprintf("DET_MASTER=%s://%s:%d", masterScheme, masterHost, masterPort),
+		fmt.Sprintf("DET_MASTER_HOST=%s", masterHost),
+		fmt.Sprintf("DET_MASTER_ADDR=%s", masterHost),
+		fmt.Sprintf("DET_MASTER_PORT=%d", masterPort),
+		fmt.Sprintf("DET_AGENT_ID=%s", c.Options.AgentID),
+	}
+
+	if a := c.Options.Security.TLS.MasterCertName; a != "" {
+		c.GlobalEnvVars = append(c.GlobalEnvVars, fmt.Sprintf("DET_MASTER_CERT_NAME=%s", a))
+	}
+
+	c.Labels = map[string]string{
+		dockerContainerTypeLabel: dockerContainerTypeValue,
+		dockerAgentLabel:         c.Options.AgentID,
+		dockerClusterLabel:       c.MasterInfo.ClusterID,
+		dockerMasterLabel:        c.MasterInfo.MasterID,
+	}
+}
+
 func (c *containerManager) handleAPIRequest(ctx *actor.Context, apiCtx echo.Context) {
 	switch apiCtx.Request().Method {
 	case echo.GET:
@@ -203,12 +228,50 @@ func gpuDeviceRequests(cont cproto.Container) []dcontainer.DeviceRequest {
 }
 
 func containerEnvVars(cont cproto.Container) []string {
+	// The shares of a physical device are exposed as a single slot.
 	var slotIds []string
+	seen := make(map[int]bool)
 	for _, d := range cont.Devices {
-		slotIds = append(slotIds, strconv.Itoa(d.ID))
+		if !seen[d.PhysicalID()] {
+			seen[d.PhysicalID()] = true
+			slotIds = append(slotIds, strconv.Itoa(d.PhysicalID()))
+		}
 	}
-	return []string{
+	envVars := []string{
 		fmt.Sprintf("DET_CONTAINER_ID=%s", cont.ID),
 		fmt.Sprintf("DET_SLOT_IDS=[%s]", strings.Join(slotIds, ",")),
 	}
+
+	gpuUUIDs := cont.GPUDeviceUUIDs()
+	if len(gpuUUIDs) == 0 {
+		return envVars
+	}
+	envVars = append(envVars,
+		fmt.Sprintf("NVIDIA_VISIBLE_DEVICES=%s", strings.Join(gpuUUIDs, ",")))
+	if fraction := gpuMemoryFraction(cont); fraction < 1 {
+		// Frameworks that honor these only grow their GPU memory up to the share of the container
+		// instead of grabbing the whole GPU.
+		envVars = append(envVars,
+			fmt.Sprintf("DET_GPU_MEMORY_FRACTION=%g", fraction),
+			"TF_FORCE_GPU_ALLOW_GROWTH=true",
+		)
+	}
+	return envVars
+}
+
+// gpuMemoryFraction returns the smallest fraction of any GPU of the container that it holds.
+func gpuMemoryFraction(cont cproto.Container) float64 {
+	fractions := make(map[string]float64)
+	for _, d := range cont.Devices {
+		if d.Type == device.GPU {
+			fractions[d.UUID] += d.SlotFraction()
+		}
+	}
+	smallest := 1.0
+	for _, fraction := range fractions {
+		if fraction < smallest {
+			smallest = fraction
+		}
+	}
+	return smallest
 }
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestDetectSharedArtificialSlots(t *testing.T) {
+	a := agent{Options: Options{ArtificialSlots: 2, SlotsPerDevice: 4}}
+	assert.NilError(t, a.detect())
+	assert.Equal(t, len(a.Devices), 8)
+	for i, d := range a.Devices {
+		assert.Equal(t, d.ID, i)
+		assert.Equal(t, d.Sharing, 4)
+		assert.Equal(t, d.UUID, a.Devices[i/4*4].UUID)
+	}
+}
+
+func TestContainerEnvVarsSharedGPUs(t *testing.T) {
+	gpus := device.Share([]device.Device{
+		{ID: 0, UUID: "GPU-0", Type: device.GPU},
+		{ID: 1, UUID: "GPU-1", Type: device.GPU},
+	}, 4)
+
+	cont := cproto.Container{ID: "container", Devices: gpus[4:6]}
+	assert.DeepEqual(t, containerEnvVars(cont), []string{
+		"DET_CONTAINER_ID=container",
+		"DET_SLOT_IDS=[1]",
+		"NVIDIA_VISIBLE_DEVICES=GPU-1",
+		"DET_GPU_MEMORY_FRACTION=0.5",
+		"TF_FORCE_GPU_ALLOW_GROWTH=true",
+	})
+
+	cont = cproto.Container{ID: "container", Devices: gpus}
+	assert.DeepEqual(t, containerEnvVars(cont), []string{
+		"DET_CONTAINER_ID=container",
+		"DET_SLOT_IDS=[0,1]",
+		"NVIDIA_VISIBLE_DEVICES=GPU-0,GPU-1",
+	})
+}
@@ -12,8 +12,10 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/mem"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/device"
 )
 
@@ -54,9 +56,30 @@ func (a *agent) detect() error {
 	default:
 		panic("unrecognized slot type")
 	}
+	a.Devices = device.Share(a.Devices, a.SlotsPerDevice)
+
+	hostInfo, err := detectHostInfo()
+	if err != nil {
+		return err
+	}
+	a.HostInfo = hostInfo
 	return nil
 }
 
+// detectHostInfo returns the number of logical CPUs and the total memory of the host, which tasks
+// may request besides slots.
+func detectHostInfo() (aproto.HostInfo, error) {
+	cpus, err := cpu.Counts(true)
+	if err != nil {
+		return aproto.HostInfo{}, errors.Wrap(err, "error while counting CPUs")
+	}
+	memory, err := mem.VirtualMemory()
+	if err != nil {
+		return aproto.HostInfo{}, errors.Wrap(err, "error while gathering memory info")
+	}
+	return aproto.HostInfo{CPUs: cpus, Memory: int64(memory.Total)}, nil
+}
+
 // detectCPUs returns the list of available CPUs; all the cores are returned as a single device.
 func detectCPUs() ([]device.Device, error) {
 	switch cpuInfo, err := cpu.Info(); {
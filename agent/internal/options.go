@@ -21,14 +21,17 @@ type Options struct {
 	ContainerMasterHost string `json:"container_master_host"`
 	ContainerMasterPort int    `json:"container_master_port"`
 
-	Label        string `json:"label"`
-	ResourcePool string `json:"resource_pool"`
+	Label        string            `json:"label"`
+	Labels       map[string]string `json:"labels"`
+	ResourcePool string            `json:"resource_pool"`
 
 	APIEnabled bool   `json:"api_enabled"`
 	BindIP     string `json:"bind_ip"`
 	BindPort   int    `json:"bind_port"`
 
 	VisibleGPUs string `json:"visible_gpus"`
+	// SlotsPerDevice is the number of slots each device is split into for tasks to share it.
+	SlotsPerDevice int `json:"slots_per_device"`
 
 	TLS      bool   `json:"tls"`
 	CertFile string `json:"cert_file"`
@@ -49,6 +52,7 @@ func (o Options) Validate() []error {
 	return []error{
 		o.validateTLS(),
 		check.In(o.SlotType, []string{"gpu", "cpu", "auto", "none"}),
+		check.GreaterThanOrEqualTo(o.SlotsPerDevice, 1, "slots_per_device must be >= 1"),
 	}
 }
 
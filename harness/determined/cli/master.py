@@ -1,10 +1,11 @@
 import json
 import time
 from argparse import Namespace
-from typing import Any, List
+from typing import Any, Dict, List
 
 from requests import Response
 
+from determined.cli import render
 from determined.common import api, yaml
 from determined.common.api import authentication
 from determined.common.check import check_gt
@@ -57,6 +58,35 @@ def logs(args: Namespace) -> None:
                 break
 
 
+@authentication.required
+def audit_log(args: Namespace) -> None:
+    params = {"offset": args.offset, "limit": args.limit}  # type: Dict[str, Any]
+    for key in ["method", "target_id", "result_code", "since", "until"]:
+        if getattr(args, key):
+            params[key] = getattr(args, key)
+    if args.user:
+        params["usernames"] = args.user
+
+    entries = api.get(args.master, "api/v1/audit-log", params=params).json()["entries"]
+    if args.json:
+        print(json.dumps(entries, indent=4))
+        return
+
+    headers = ["Time", "User", "Method", "Targets", "Result", "Source IP"]
+    values = [
+        [
+            render.format_time(e["time"]),
+            e["username"] or e["allocationId"],
+            e["method"],
+            json.dumps(e["targets"]),
+            e["resultCode"],
+            e["sourceIp"],
+        ]
+        for e in entries
+    ]
+    render.tabulate_or_csv(headers, values, args.csv)
+
+
 # fmt: off
 
 args_description = [
@@ -72,6 +102,20 @@ args_description = [
                 help="number of lines to show, counting from the end "
                 "of the log (default is all)")
         ]),
+        Cmd("audit-log", audit_log, "fetch the audit log of mutating API calls (admin only)", [
+            Arg("--user", action="append",
+                help="only show calls made by this user (may be repeated)"),
+            Arg("--method", type=str, help="only show calls of methods containing this string"),
+            Arg("--target-id", type=str, help="only show calls that targeted this id"),
+            Arg("--result-code", type=str, help="only show calls with this result code, e.g. OK"),
+            Arg("--since", type=str, help="only show calls made at or after this RFC 3339 time"),
+            Arg("--until", type=str, help="only show calls made before this RFC 3339 time"),
+            Arg("--offset", type=int, default=0, help="number of entries to skip"),
+            Arg("--limit", type=int, default=100,
+                help="maximum number of entries to show, most recent first"),
+            Arg("--json", action="store_true", help="print the entries as JSON"),
+            Arg("--csv", action="store_true", help="print the entries as CSV"),
+        ]),
     ])
 ]  # type: List[Any]
 
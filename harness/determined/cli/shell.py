@@ -141,6 +141,8 @@ def _open_shell(
         if cert.name:
             proxy_cmd += ' --cert-name "{}"'.format(cert.name)
 
+        proxy_cmd += ' --user "{}"'.format(authentication.must_cli_auth().get_session_user())
+
         username = shell["agentUserGroup"]["user"] or "root"
 
         cmd = [
@@ -18,6 +18,12 @@ def render_tasks(args: Namespace, tasks: Dict[str, Dict[str, Any]]) -> None:
             return agent
         return [c["agent"] for c in containers]
 
+    def pending_reason(t: Dict[str, Any]) -> str:
+        reason = t.get("pending_reason")
+        if not reason:
+            return ""
+        return str(reason["message"])
+
     if args.json:
         print(json.dumps(tasks, indent=4))
         return
@@ -31,6 +37,7 @@ def render_tasks(args: Namespace, tasks: Dict[str, Dict[str, Any]]) -> None:
         "Agent",
         "Priority",
         "Resource Pool",
+        "Pending Reason",
     ]
     values = [
         [
@@ -42,6 +49,7 @@ def render_tasks(args: Namespace, tasks: Dict[str, Dict[str, Any]]) -> None:
             agent_info(task),
             task["priority"] if task["scheduler_type"] == "priority" else "N/A",
             task["resource_pool"],
+            pending_reason(task),
         ]
         for task_id, task in sorted(
             tasks.items(),
@@ -59,6 +67,49 @@ def list_tasks(args: Namespace) -> None:
     render_tasks(args, tasks)
 
 
+@authentication.required
+def forward_port(args: Namespace) -> None:
+    body: Dict[str, Any] = {"containerId": args.container_id or "", "tcp": args.tcp}
+    if args.expiry is not None:
+        body["expirySeconds"] = args.expiry
+    r = api.post(
+        args.master, "api/v1/allocations/{}/ports/{}".format(args.allocation_id, args.port), body
+    )
+    forwarded = r.json()
+    if args.tcp:
+        print(
+            "Forwarding port {} until {}. Tunnel TCP connections to it with:\n"
+            "  python -m determined.cli.tunnel {} {}".format(
+                args.port, forwarded["expireTime"], args.master, forwarded["serviceId"]
+            )
+        )
+    else:
+        print(
+            "Forwarding port {} at {} until {}".format(
+                args.port,
+                api.make_url(args.master, forwarded["proxyAddress"]),
+                forwarded["expireTime"],
+            )
+        )
+
+
+@authentication.required
+def stop_forwarding_port(args: Namespace) -> None:
+    params = {"containerId": args.container_id} if args.container_id else None
+    api.delete(
+        args.master,
+        "api/v1/allocations/{}/ports/{}".format(args.allocation_id, args.port),
+        params=params,
+    )
+    print("Stopped forwarding port {}".format(args.port))
+
+
+container_id_arg = Arg(
+    "--container-id",
+    type=str,
+    help="the container to forward the port of, if the allocation has more than one",
+)
+
 args_description: List[Any] = [
     Cmd(
         "task",
@@ -77,6 +128,36 @@ args_description: List[Any] = [
                 ],
                 is_default=True,
             ),
+            Cmd(
+                "forward-port",
+                forward_port,
+                "forward a port of a running task through the master",
+                [
+                    Arg("allocation_id", type=str, help="allocation ID"),
+                    Arg("port", type=int, help="port exposed by the task container"),
+                    container_id_arg,
+                    Arg(
+                        "--tcp",
+                        action="store_true",
+                        help="tunnel TCP connections instead of proxying HTTP requests",
+                    ),
+                    Arg(
+                        "--expiry",
+                        type=int,
+                        help="number of seconds to forward the port for (default: 3600)",
+                    ),
+                ],
+            ),
+            Cmd(
+                "stop-forwarding-port",
+                stop_forwarding_port,
+                "stop forwarding a port of a task",
+                [
+                    Arg("allocation_id", type=str, help="allocation ID"),
+                    Arg("port", type=int, help="forwarded port"),
+                    container_id_arg,
+                ],
+            ),
         ],
     ),
 ]
@@ -14,7 +14,7 @@ from typing import Optional
 
 import lomond
 
-from determined.common.api import request
+from determined.common.api import authentication, request
 
 
 class CustomSSLWebsocketSession(lomond.session.WebsocketSession):  # type: ignore
@@ -84,13 +84,25 @@ def copy_from_websocket(
 
 
 def http_connect_tunnel(
-    master: str, service: str, cert_file: Optional[str], cert_name: Optional[str]
+    master: str,
+    service: str,
+    cert_file: Optional[str],
+    cert_name: Optional[str],
+    user: Optional[str] = None,
 ) -> None:
     parsed_master = request.parse_master_address(master)
     assert parsed_master.hostname is not None, "Failed to parse master address: {}".format(master)
     url = request.make_url(master, "proxy/{}/".format(service))
     ws = lomond.WebSocket(request.maybe_upgrade_ws_scheme(url))
 
+    # The master only proxies services to their owners and the users they are shared with, so
+    # authenticate as the given user, or as the active user of the CLI.
+    token_store = authentication.TokenStore(master)
+    user = user or token_store.get_active_user()
+    token = token_store.get_token(user) if user else None
+    if token is not None:
+        ws.add_header("Authorization".encode(), "Bearer {}".format(token).encode())
+
     # We can't send data to the WebSocket before the connection becomes ready, which takes a bit of
     # time; this semaphore lets the sending thread wait for that to happen.
     ready_sem = threading.Semaphore(0)
@@ -116,6 +128,9 @@ if __name__ == "__main__":
     parser.add_argument("service_uuid")
     parser.add_argument("--cert-file")
     parser.add_argument("--cert-name")
+    parser.add_argument("--user")
     args = parser.parse_args()
 
-    http_connect_tunnel(args.master_addr, args.service_uuid, args.cert_file, args.cert_name)
+    http_connect_tunnel(
+        args.master_addr, args.service_uuid, args.cert_file, args.cert_name, args.user
+    )
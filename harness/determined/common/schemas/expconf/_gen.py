@@ -3,6 +3,213 @@
 import json
 
 schemas = {
+    "http://determined.ai/schemas/expconf/v0/agent-affinity.json": json.loads(
+        r"""
+{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/agent-affinity.json",
+    "title": "AgentAffinity",
+    "additionalProperties": false,
+    "required": [
+        "key"
+    ],
+    "type": "object",
+    "properties": {
+        "weight": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "maximum": 100,
+            "default": 1
+        },
+        "key": {
+            "type": "string",
+            "checks": {
+                "key must be non-empty": {
+                    "minLength": 1
+                }
+            }
+        },
+        "operator": {
+            "enum": [
+                null,
+                "In",
+                "NotIn",
+                "Exists",
+                "DoesNotExist"
+            ],
+            "default": "In"
+        },
+        "values": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "type": "string"
+            },
+            "default": null
+        }
+    },
+    "checks": {
+        "values must be non-empty for the In and NotIn operators": {
+            "conditional": {
+                "$comment": "when the operator is In or NotIn, expect values",
+                "when": {
+                    "properties": {
+                        "operator": {
+                            "enum": [
+                                null,
+                                "In",
+                                "NotIn"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "required": [
+                        "values"
+                    ],
+                    "properties": {
+                        "values": {
+                            "type": "array",
+                            "minItems": 1
+                        }
+                    }
+                }
+            }
+        },
+        "values must be empty for the Exists and DoesNotExist operators": {
+            "conditional": {
+                "$comment": "when the operator is Exists or DoesNotExist, forbid values",
+                "when": {
+                    "required": [
+                        "operator"
+                    ],
+                    "properties": {
+                        "operator": {
+                            "enum": [
+                                "Exists",
+                                "DoesNotExist"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "properties": {
+                        "values": {
+                            "maxItems": 0
+                        }
+                    }
+                }
+            }
+        }
+    }
+}
+
+"""
+    ),
+    "http://determined.ai/schemas/expconf/v0/agent-selector.json": json.loads(
+        r"""
+{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/agent-selector.json",
+    "title": "AgentSelector",
+    "additionalProperties": false,
+    "required": [
+        "key"
+    ],
+    "type": "object",
+    "properties": {
+        "key": {
+            "type": "string",
+            "checks": {
+                "key must be non-empty": {
+                    "minLength": 1
+                }
+            }
+        },
+        "operator": {
+            "enum": [
+                null,
+                "In",
+                "NotIn",
+                "Exists",
+                "DoesNotExist"
+            ],
+            "default": "In"
+        },
+        "values": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "type": "string"
+            },
+            "default": null
+        }
+    },
+    "checks": {
+        "values must be non-empty for the In and NotIn operators": {
+            "conditional": {
+                "$comment": "when the operator is In or NotIn, expect values",
+                "when": {
+                    "properties": {
+                        "operator": {
+                            "enum": [
+                                null,
+                                "In",
+                                "NotIn"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "required": [
+                        "values"
+                    ],
+                    "properties": {
+                        "values": {
+                            "type": "array",
+                            "minItems": 1
+                        }
+                    }
+                }
+            }
+        },
+        "values must be empty for the Exists and DoesNotExist operators": {
+            "conditional": {
+                "$comment": "when the operator is Exists or DoesNotExist, forbid values",
+                "when": {
+                    "required": [
+                        "operator"
+                    ],
+                    "properties": {
+                        "operator": {
+                            "enum": [
+                                "Exists",
+                                "DoesNotExist"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "properties": {
+                        "values": {
+                            "maxItems": 0
+                        }
+                    }
+                }
+            }
+        }
+    }
+}
+
+"""
+    ),
     "http://determined.ai/schemas/expconf/v0/azure.json": json.loads(
         r"""
 {
@@ -1157,6 +1364,16 @@ schemas = {
             "minimum": 0,
             "default": 5
         },
+        "metric_rules": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/metric-rule.json"
+            }
+        },
         "min_checkpoint_period": {
             "type": [
                 "object",
@@ -1828,6 +2045,137 @@ schemas = {
     }
 }
 
+"""
+    ),
+    "http://determined.ai/schemas/expconf/v0/metric-rule.json": json.loads(
+        r"""
+{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/metric-rule.json",
+    "title": "MetricRule",
+    "additionalProperties": false,
+    "required": [
+        "name",
+        "metric",
+        "condition"
+    ],
+    "type": "object",
+    "properties": {
+        "name": {
+            "type": "string",
+            "checks": {
+                "name must be non-empty": {
+                    "minLength": 1
+                }
+            }
+        },
+        "metric": {
+            "type": "string",
+            "checks": {
+                "metric must be non-empty": {
+                    "minLength": 1
+                }
+            }
+        },
+        "metric_type": {
+            "enum": [
+                null,
+                "training",
+                "validation"
+            ],
+            "default": "validation"
+        },
+        "condition": {
+            "enum": [
+                "nan",
+                "greater_than",
+                "less_than",
+                "no_improvement"
+            ]
+        },
+        "threshold": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "default": null
+        },
+        "patience": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": null
+        },
+        "smaller_is_better": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": true
+        },
+        "action": {
+            "enum": [
+                null,
+                "kill_trial",
+                "pause_experiment",
+                "notify"
+            ],
+            "default": "notify"
+        }
+    },
+    "checks": {
+        "threshold must be set for the greater_than and less_than conditions": {
+            "conditional": {
+                "$comment": "when the condition compares to a threshold, expect a threshold",
+                "when": {
+                    "properties": {
+                        "condition": {
+                            "enum": [
+                                "greater_than",
+                                "less_than"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "required": [
+                        "threshold"
+                    ],
+                    "properties": {
+                        "threshold": {
+                            "type": "number"
+                        }
+                    }
+                }
+            }
+        },
+        "patience must be set for the no_improvement condition": {
+            "conditional": {
+                "$comment": "when the condition is no_improvement, expect a patience",
+                "when": {
+                    "properties": {
+                        "condition": {
+                            "const": "no_improvement"
+                        }
+                    }
+                },
+                "enforce": {
+                    "required": [
+                        "patience"
+                    ],
+                    "properties": {
+                        "patience": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        }
+    }
+}
+
 """
     ),
     "http://determined.ai/schemas/expconf/v0/native.json": json.loads(
@@ -2098,6 +2446,44 @@ schemas = {
             ],
             "default": ""
         },
+        "agent_selectors": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/agent-selector.json"
+            },
+            "default": []
+        },
+        "agent_affinity": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/agent-affinity.json"
+            },
+            "default": []
+        },
+        "agent_anti_affinity": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/agent-affinity.json"
+            },
+            "default": []
+        },
+        "cpus": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "minimum": 0,
+            "default": null
+        },
         "devices": {
             "type": [
                 "array",
@@ -2113,6 +2499,14 @@ schemas = {
             ],
             "default": null
         },
+        "memory": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 0,
+            "default": null
+        },
         "native_parallel": {
             "type": [
                 "boolean",
@@ -2143,6 +2537,15 @@ schemas = {
             ],
             "default": null
         },
+        "slot_fraction": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "maximum": 1,
+            "default": null
+        },
         "slots": {
             "type": [
                 "integer",
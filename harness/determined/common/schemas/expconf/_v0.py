@@ -21,28 +21,74 @@ class DeviceV0(schemas.SchemaBase):
         pass
 
 
+class AgentSelectorV0(schemas.SchemaBase):
+    _id = "http://determined.ai/schemas/expconf/v0/agent-selector.json"
+    key: str
+    operator: Optional[str] = None
+    values: Optional[List[str]] = None
+
+    @schemas.auto_init
+    def __init__(
+        self,
+        key: str,
+        operator: Optional[str] = None,
+        values: Optional[List[str]] = None,
+    ) -> None:
+        pass
+
+
+class AgentAffinityV0(schemas.SchemaBase):
+    _id = "http://determined.ai/schemas/expconf/v0/agent-affinity.json"
+    key: str
+    operator: Optional[str] = None
+    values: Optional[List[str]] = None
+    weight: Optional[int] = None
+
+    @schemas.auto_init
+    def __init__(
+        self,
+        key: str,
+        operator: Optional[str] = None,
+        values: Optional[List[str]] = None,
+        weight: Optional[int] = None,
+    ) -> None:
+        pass
+
+
 class ResourcesConfigV0(schemas.SchemaBase):
     _id = "http://determined.ai/schemas/expconf/v0/resources.json"
+    agent_affinity: Optional[List[AgentAffinityV0]] = None
+    agent_anti_affinity: Optional[List[AgentAffinityV0]] = None
     agent_label: Optional[str] = None
+    agent_selectors: Optional[List[AgentSelectorV0]] = None
+    cpus: Optional[float] = None
     devices: Optional[List[DeviceV0]] = None
     max_slots: Optional[int] = None
+    memory: Optional[int] = None
     native_parallel: Optional[bool] = None
     priority: Optional[int] = None
     resource_pool: Optional[str] = None
     shm_size: Optional[int] = None
+    slot_fraction: Optional[float] = None
     slots_per_trial: Optional[int] = None
     weight: Optional[float] = None
 
     @schemas.auto_init
     def __init__(
         self,
+        agent_affinity: Optional[List[AgentAffinityV0]] = None,
+        agent_anti_affinity: Optional[List[AgentAffinityV0]] = None,
         agent_label: Optional[str] = None,
+        agent_selectors: Optional[List[AgentSelectorV0]] = None,
+        cpus: Optional[float] = None,
         devices: Optional[List[DeviceV0]] = None,
         max_slots: Optional[int] = None,
+        memory: Optional[int] = None,
         native_parallel: Optional[bool] = None,
         priority: Optional[int] = None,
         resource_pool: Optional[str] = None,
         shm_size: Optional[int] = None,
+        slot_fraction: Optional[float] = None,
         slots_per_trial: Optional[int] = None,
         weight: Optional[float] = None,
     ) -> None:
@@ -877,6 +923,32 @@ CheckpointStorageConfigV0_Type = Union[
 CheckpointStorageConfigV0.finalize(CheckpointStorageConfigV0_Type)
 
 
+class MetricRuleV0(schemas.SchemaBase):
+    _id = "http://determined.ai/schemas/expconf/v0/metric-rule.json"
+    name: str
+    metric: str
+    condition: str
+    metric_type: Optional[str] = None
+    threshold: Optional[float] = None
+    patience: Optional[int] = None
+    smaller_is_better: Optional[bool] = None
+    action: Optional[str] = None
+
+    @schemas.auto_init
+    def __init__(
+        self,
+        name: str,
+        metric: str,
+        condition: str,
+        metric_type: Optional[str] = None,
+        threshold: Optional[float] = None,
+        patience: Optional[int] = None,
+        smaller_is_better: Optional[bool] = None,
+        action: Optional[str] = None,
+    ) -> None:
+        pass
+
+
 class ExperimentConfigV0(schemas.SchemaBase):
     _id = "http://determined.ai/schemas/expconf/v0/experiment.json"
 
@@ -902,6 +974,7 @@ class ExperimentConfigV0(schemas.SchemaBase):
     # internal: Optional[InternalConfigV0] = None
     labels: Optional[str] = None
     max_restarts: Optional[int] = None
+    metric_rules: Optional[List[MetricRuleV0]] = None
     min_checkpoint_period: Optional[LengthV0] = None
     min_validation_period: Optional[LengthV0] = None
     name: Optional[str] = None
@@ -932,6 +1005,7 @@ class ExperimentConfigV0(schemas.SchemaBase):
         # internal: Optional[InternalConfigV0] = None,
         labels: Optional[str] = None,
         max_restarts: Optional[int] = None,
+        metric_rules: Optional[List[MetricRuleV0]] = None,
         min_checkpoint_period: Optional[LengthV0] = None,
         min_validation_period: Optional[LengthV0] = None,
         name: Optional[str] = None,
@@ -637,6 +637,11 @@ class EstimatorTrialController(det.TrialController):
         if session_config is None:
             session_config = tf.compat.v1.ConfigProto()
         session_config.gpu_options.allow_growth = True
+        # Trials that share GPUs with other trials must stay within their share of the memory of
+        # the GPUs.
+        memory_fraction = det.gpu.get_memory_fraction()
+        if memory_fraction is not None:
+            session_config.gpu_options.per_process_gpu_memory_fraction = memory_fraction
 
         if not hvd_config.use:
             return session_config
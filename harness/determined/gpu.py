@@ -1,7 +1,8 @@
 import csv
 import logging
+import os
 import subprocess
-from typing import List, NamedTuple
+from typing import List, NamedTuple, Optional
 
 gpu_fields = [
     "index",
@@ -17,6 +18,7 @@ class GPU(NamedTuple):
     uuid: str
     load: float
     memoryUtil: float
+    memoryTotal: float
 
 
 warned_fields = set()
@@ -62,6 +64,7 @@ def get_gpus() -> List[GPU]:
                         load=float_or_default(fields, "utilization.gpu", 0.0) / 100,
                         memoryUtil=float_or_default(fields, "memory.used", 0.0)
                         / float_or_default(fields, "memory.total", 1.0),
+                        memoryTotal=float_or_default(fields, "memory.total", 0.0),
                     )
                 )
             except ValueError:
@@ -75,3 +78,36 @@ def get_gpus() -> List[GPU]:
 
 def get_gpu_uuids() -> List[str]:
     return [gpu.uuid for gpu in sorted(get_gpus(), key=lambda gpu: gpu.id)]
+
+
+def get_memory_fraction() -> Optional[float]:
+    """
+    Return the fraction of the memory of each GPU that the container may use, which the agent sets
+    in DET_GPU_MEMORY_FRACTION when the container shares its GPUs with other containers, or None if
+    the container holds whole GPUs.
+    """
+    value = os.environ.get("DET_GPU_MEMORY_FRACTION")
+    if value is None:
+        return None
+    try:
+        fraction = float(value)
+    except ValueError:
+        logging.warning(f"Ignoring invalid DET_GPU_MEMORY_FRACTION: {value}")
+        return None
+    if not 0 < fraction < 1:
+        return None
+    return fraction
+
+
+def get_memory_limit_mb() -> Optional[int]:
+    """
+    Return the memory in MiB that the container may use on each of its GPUs when it shares them,
+    based on the smallest of them, or None if it holds whole GPUs or the memory is unknown.
+    """
+    fraction = get_memory_fraction()
+    if fraction is None:
+        return None
+    totals = [gpu.memoryTotal for gpu in get_gpus() if gpu.memoryTotal > 0]
+    if not totals:
+        return None
+    return int(min(totals) * fraction)
@@ -194,8 +194,13 @@ class TFKerasTrialController(det.TrialController):
         hvd_config: horovod.HorovodContext,
         session_config: tf.compat.v1.ConfigProto,
     ) -> Optional[tf.compat.v1.Session]:
+        # Trials that share GPUs with other trials must stay within their share of the memory of
+        # the GPUs.
+        memory_fraction = det.gpu.get_memory_fraction()
         if not tf.executing_eagerly():
             session_config.gpu_options.allow_growth = True
+            if memory_fraction is not None:
+                session_config.gpu_options.per_process_gpu_memory_fraction = memory_fraction
             if hvd_config.use:
                 # We launch a horovod process per GPU. Each process
                 # needs to bind to a unique GPU.
@@ -215,7 +220,15 @@ class TFKerasTrialController(det.TrialController):
                 local_rank = hvd.local_rank() if hvd_config.use else 0
                 gpu = gpus[local_rank]
                 tf.config.experimental.set_visible_devices(gpu, "GPU")
-                tf.config.experimental.set_memory_growth(gpu, True)
+                memory_limit = det.gpu.get_memory_limit_mb() if memory_fraction else None
+                if memory_limit is not None:
+                    # Memory growth cannot be combined with a memory limit.
+                    tf.config.experimental.set_virtual_device_configuration(
+                        gpu,
+                        [tf.config.experimental.VirtualDeviceConfiguration(memory_limit=memory_limit)],
+                    )
+                else:
+                    tf.config.experimental.set_memory_growth(gpu, True)
 
             return None
 
@@ -6,7 +6,7 @@ import torch
 import torch.nn as nn
 
 import determined as det
-from determined import profiler, pytorch
+from determined import gpu, profiler, pytorch
 from determined.common import check
 from determined.horovod import hvd
 from determined.tensorboard import get_base_path
@@ -305,6 +305,18 @@ class PyTorchTrialContext(det.TrialContext, pytorch._PyTorchReducerContext):
             self.device = torch.device("cpu")
         check.is_not_none(self.device)
 
+        # Containers that share GPUs with other containers must stay within their share of the
+        # memory of the GPUs.
+        fraction = gpu.get_memory_fraction()
+        if self.n_gpus > 0 and fraction is not None:
+            if hasattr(torch.cuda, "set_per_process_memory_fraction"):
+                torch.cuda.set_per_process_memory_fraction(fraction, self.device)
+            else:
+                logging.warning(
+                    "This version of PyTorch cannot limit the GPU memory of the trial to its "
+                    f"fraction of the GPU ({fraction})"
+                )
+
     def to_device(self, data: pytorch._Data) -> pytorch.TorchData:
         """Map generated data to the device allocated by the Determined cluster.
 
@@ -0,0 +1,42 @@
+from typing import Optional
+
+import pytest
+
+from determined import gpu
+
+
+def make_gpu(id: int, memory_total: float) -> gpu.GPU:
+    return gpu.GPU(id=id, uuid=f"GPU-{id}", load=0.0, memoryUtil=0.0, memoryTotal=memory_total)
+
+
+@pytest.mark.parametrize(
+    "value,expected",
+    [
+        (None, None),
+        ("0.25", 0.25),
+        ("1.0", None),
+        ("0", None),
+        ("half", None),
+    ],
+)
+def test_get_memory_fraction(
+    monkeypatch: pytest.MonkeyPatch, value: Optional[str], expected: Optional[float]
+) -> None:
+    if value is None:
+        monkeypatch.delenv("DET_GPU_MEMORY_FRACTION", raising=False)
+    else:
+        monkeypatch.setenv("DET_GPU_MEMORY_FRACTION", value)
+    assert gpu.get_memory_fraction() == expected
+
+
+def test_get_memory_limit_mb(monkeypatch: pytest.MonkeyPatch) -> None:
+    monkeypatch.setattr(gpu, "get_gpus", lambda: [make_gpu(0, 16000.0), make_gpu(1, 8000.0)])
+
+    monkeypatch.delenv("DET_GPU_MEMORY_FRACTION", raising=False)
+    assert gpu.get_memory_limit_mb() is None
+
+    monkeypatch.setenv("DET_GPU_MEMORY_FRACTION", "0.5")
+    assert gpu.get_memory_limit_mb() == 4000
+
+    monkeypatch.setattr(gpu, "get_gpus", lambda: [])
+    assert gpu.get_memory_limit_mb() is None
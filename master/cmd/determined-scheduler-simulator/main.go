@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/determined-ai/determined/master/internal/resourcemanagers"
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+type options struct {
+	trace                    string
+	schedulers               []string
+	fittingPolicies          []string
+	preemption               bool
+	defaultPriority          int
+	resourcePool             string
+	maxAuxContainersPerAgent int
+	preemptionDelay          time.Duration
+	json                     bool
+}
+
+func newRootCmd() *cobra.Command {
+	opts := options{}
+	cmd := &cobra.Command{
+		Use:   "determined-scheduler-simulator",
+		Short: "replay a cluster trace through the scheduling policies of Determined",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := run(opts); err != nil {
+				log.Error(fmt.Sprintf("%+v", err))
+				os.Exit(1)
+			}
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.trace, "trace", "",
+		"path to the JSON trace, e.g. as exported by the master at /resources/trace")
+	flags.StringSliceVar(&opts.schedulers, "scheduler",
+		[]string{"fair_share", "priority", "round_robin", "drf"}, "scheduling policies to simulate")
+	flags.StringSliceVar(&opts.fittingPolicies, "fitting-policy",
+		[]string{"best", "worst"}, "fitting policies to simulate")
+	flags.BoolVar(&opts.preemption, "preemption", true,
+		"whether the priority scheduler preempts tasks of lower priority")
+	flags.IntVar(&opts.defaultPriority, "default-priority", 42,
+		"priority of the tasks without one for the priority scheduler")
+	flags.StringVar(&opts.resourcePool, "resource-pool", "",
+		"only simulate the agents and tasks of this resource pool")
+	flags.IntVar(&opts.maxAuxContainersPerAgent, "max-aux-containers-per-agent", 100,
+		"number of zero-slot tasks each agent can run")
+	flags.DurationVar(&opts.preemptionDelay, "preemption-delay", 30*time.Second,
+		"time preempted tasks take to release their resources")
+	flags.BoolVar(&opts.json, "json", false, "print the reports as JSON")
+	if err := cmd.MarkFlagRequired("trace"); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func run(opts options) error {
+	bs, err := ioutil.ReadFile(opts.trace)
+	if err != nil {
+		return errors.Wrapf(err, "cannot read trace %s", opts.trace)
+	}
+	var trace resourcemanagers.Trace
+	if err = json.Unmarshal(bs, &trace); err != nil {
+		return errors.Wrapf(err, "cannot parse trace %s", opts.trace)
+	}
+
+	var reports []*resourcemanagers.SimulationReport
+	for _, scheduler := range opts.schedulers {
+		for _, fittingPolicy := range opts.fittingPolicies {
+			config, err := schedulerConfig(opts, scheduler, fittingPolicy)
+			if err != nil {
+				return err
+			}
+			report, err := resourcemanagers.Simulate(trace, resourcemanagers.SimulationConfig{
+				Scheduler:                config,
+				ResourcePool:             opts.resourcePool,
+				MaxAuxContainersPerAgent: opts.maxAuxContainersPerAgent,
+				PreemptionDelay:          opts.preemptionDelay,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "cannot simulate %s scheduling with %s fit",
+					scheduler, fittingPolicy)
+			}
+			reports = append(reports, report)
+		}
+	}
+
+	if opts.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	}
+	return printReports(reports)
+}
+
+// schedulerConfig parses the scheduler configuration the same way the master configuration does.
+func schedulerConfig(
+	opts options, scheduler, fittingPolicy string,
+) (*resourcemanagers.SchedulerConfig, error) {
+	raw := map[string]interface{}{"type": scheduler, "fitting_policy": fittingPolicy}
+	if scheduler == "priority" {
+		raw["preemption"] = opts.preemption
+		raw["default_priority"] = opts.defaultPriority
+	}
+	bs, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var config resourcemanagers.SchedulerConfig
+	if err := json.Unmarshal(bs, &config); err != nil {
+		return nil, errors.Wrapf(err, "invalid scheduler %s", scheduler)
+	}
+	if err := check.Validate(config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func printReports(reports []*resourcemanagers.SimulationReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Scheduler\tFit\tTasks\tCompleted\tUnscheduled\tPreemptions\t"+
+		"Interruptions\tMean Delay\tP50 Delay\tP95 Delay\tMax Delay\tUtilization\tMakespan")
+	for _, r := range reports {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t%.1f%%\t%s\n",
+			r.Scheduler, r.FittingPolicy, r.Tasks, r.Completed, r.Unscheduled, r.Preemptions,
+			r.Interruptions, seconds(r.MeanQueueingDelaySeconds), seconds(r.P50QueueingDelaySeconds),
+			seconds(r.P95QueueingDelaySeconds), seconds(r.MaxQueueingDelaySeconds),
+			100*r.Utilization, seconds(r.MakespanSeconds))
+	}
+	return w.Flush()
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Second)
+}
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		log.WithError(err).Fatal("fatal error running the scheduler simulator")
+	}
+}
@@ -34,6 +34,7 @@ type (
 		containers       map[cproto.ID]*actor.Ref
 		resourcePoolName string
 		label            string
+		labels           map[string]string
 		// started tracks if we have received the AgentStarted message.
 		started bool
 		// enabled and draining are duplicated in resourcepool agentState.
@@ -281,16 +282,25 @@ func (a *agent) handleIncomingWSMessage(ctx *actor.Context, msg aproto.MasterMes
 		ctx.Log().Infof("agent connected ip: %v resource pool: %s slots: %d",
 			a.address, a.resourcePoolName, len(msg.AgentStarted.Devices))
 
-		ctx.Tell(a.resourcePool, sproto.AddAgent{Agent: ctx.Self(), Label: msg.AgentStarted.Label})
+		ctx.Tell(a.resourcePool, sproto.AddAgent{
+			Agent:    ctx.Self(),
+			Label:    msg.AgentStarted.Label,
+			Labels:   msg.AgentStarted.Labels,
+			HostInfo: msg.AgentStarted.HostInfo,
+		})
 		ctx.Tell(a.slots, *msg.AgentStarted)
 		a.started = true
 		a.label = msg.AgentStarted.Label
+		a.labels = msg.AgentStarted.Labels
 	case msg.ContainerStateChanged != nil:
 		a.containerStateChanged(ctx, *msg.ContainerStateChanged)
 	case msg.ContainerLog != nil:
 		ref, ok := a.containers[msg.ContainerLog.Container.ID]
-		check.Panic(check.True(ok,
-			"container not allocated to agent: container %s", msg.ContainerLog.Container.ID))
+		if !ok {
+			// Containers started by another master may log while the agent kills them.
+			ctx.Log().Debugf("ignoring log of unknown container %s", msg.ContainerLog.Container.ID)
+			return
+		}
 		ctx.Tell(ref, sproto.ContainerLog{
 			Container:   msg.ContainerLog.Container,
 			Timestamp:   msg.ContainerLog.Timestamp,
@@ -305,7 +315,13 @@ func (a *agent) handleIncomingWSMessage(ctx *actor.Context, msg aproto.MasterMes
 
 func (a *agent) containerStateChanged(ctx *actor.Context, sc aproto.ContainerStateChanged) {
 	taskActor, ok := a.containers[sc.Container.ID]
-	check.Panic(check.True(ok, "container not allocated to agent: container %s", sc.Container.ID))
+	if !ok {
+		// An agent that reconnected from another master reports the containers that master
+		// started as they are killed; this master never allocated them.
+		ctx.Log().Warnf("ignoring state change of unknown container %s to %s",
+			sc.Container.ID, sc.Container.State)
+		return
+	}
 
 	rsc := sproto.TaskContainerStateChanged{Container: sc.Container}
 	switch sc.Container.State {
@@ -338,6 +354,7 @@ func (a *agent) summarize(ctx *actor.Context) model.AgentSummary {
 		NumContainers:  len(a.containers),
 		ResourcePool:   a.resourcePoolName,
 		Label:          a.label,
+		Labels:         a.labels,
 		Addresses:      []string{a.address},
 		Enabled:        a.enabled,
 		Draining:       a.draining,
@@ -22,7 +22,10 @@ import (
 func Initialize(
 	system *actor.System, e *echo.Echo, opts *aproto.MasterSetAgentOptions,
 ) {
-	_, ok := system.ActorOf(sproto.AgentsAddr, &agents{opts: opts})
+	_, ok := system.ActorOf(sproto.AgentsAddr, &agents{
+		opts:       opts,
+		registered: map[string]bool{},
+	})
 	check.Panic(check.True(ok, "agents address already taken"))
 	// Route /agents and /agents/<agent id>/slots to the agents actor and slots actors.
 	e.Any("/agents*", api.Route(system, nil))
@@ -30,6 +33,8 @@ func Initialize(
 
 type agents struct {
 	opts *aproto.MasterSetAgentOptions
+	// registered tracks the IDs of all agents that have connected to this master.
+	registered map[string]bool
 }
 
 func (a *agents) Receive(ctx *actor.Context) error {
@@ -44,17 +49,24 @@ func (a *agents) Receive(ctx *actor.Context) error {
 		}
 
 		if reconnect {
-			if ctx.Child(id) != nil {
+			switch {
+			case ctx.Child(id) != nil:
 				// If the agent actor is still alive on our side when an
 				// agent tries to reconnect, accept it.
 				ctx.Respond(ctx.Ask(ctx.Child(id), msg).Get())
-			} else {
+				return nil
+			case a.registered[id]:
 				// In the event it has closed and the agent is trying to reconnect,
 				// continue to deny it. This case is nearly impossible (master waits
 				// longer than agent tries, to avoid it).
 				ctx.Respond(aproto.ErrAgentMustReconnect)
+				return nil
+			default:
+				// The agent was connected to another master, e.g., the previous leader of a
+				// highly available cluster or this master before it restarted. Accept it as a
+				// new agent; it registers again once it sees our master ID.
+				ctx.Log().Infof("agent %s reconnected from another master", id)
 			}
-			return nil
 		}
 		// There is a case not explicitly handled: !reconnect && ctx.Child(id) != nil.
 		// If the agent is unable to reconnect then crashes and _is_ able to reconnect,
@@ -106,6 +118,7 @@ func (a *agents) createAgentActor(
 	if !ok {
 		return nil, errors.Errorf("agent already connected: %s", id)
 	}
+	a.registered[id] = true
 	return ref, nil
 }
 
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) GetAuditLog(
+	ctx context.Context, req *apiv1.GetAuditLogRequest,
+) (*apiv1.GetAuditLogResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx, a.m.db, &a.m.config.InternalConfig.ExternalSessions)
+	if err != nil {
+		return nil, err
+	}
+	if !curUser.Admin {
+		return nil, grpcutil.ErrPermissionDenied
+	}
+	if err = grpcutil.ValidateRequest(grpcutil.ValidateLimit(req.Limit)); err != nil {
+		return nil, err
+	}
+
+	var since, until *time.Time
+	if req.Since != nil {
+		t := req.Since.AsTime()
+		since = &t
+	}
+	if req.Until != nil {
+		t := req.Until.AsTime()
+		until = &t
+	}
+
+	resp := &apiv1.GetAuditLogResponse{}
+	return resp, a.m.db.QueryProto(
+		"get_audit_log",
+		resp,
+		req.Usernames,
+		req.Method,
+		req.TargetId,
+		req.ResultCode,
+		since,
+		until,
+		req.Offset,
+		req.Limit,
+	)
+}
@@ -0,0 +1,275 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/lttb"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// maxComparedTrials is the most trials whose metrics can be compared in one request.
+const maxComparedTrials = 100
+
+// comparedTrial is a trial whose metrics are compared, with a raw series for each metric.
+type comparedTrial struct {
+	trial  *model.Trial
+	series map[string][]lttb.Point
+}
+
+func (a *apiServer) CompareMetrics(
+	_ context.Context, req *apiv1.CompareMetricsRequest,
+) (*apiv1.CompareMetricsResponse, error) {
+	switch {
+	case len(req.ExperimentIds) == 0 && len(req.TrialIds) == 0:
+		return nil, status.Error(codes.InvalidArgument, "must specify experiments or trials")
+	case len(req.MetricNames) == 0:
+		return nil, status.Error(codes.InvalidArgument, "must specify metric names")
+	case req.MetricType == apiv1.MetricType_METRIC_TYPE_UNSPECIFIED:
+		return nil, status.Error(codes.InvalidArgument, "must specify a metric type")
+	}
+	maxDatapoints := int(req.MaxDatapoints)
+	if maxDatapoints == 0 {
+		maxDatapoints = 1000
+	}
+	startBatches := int(req.StartBatches)
+	endBatches := int(req.EndBatches)
+	if endBatches <= 0 {
+		endBatches = math.MaxInt32
+	}
+
+	// Group the trials by experiment, keeping the experiments in the order they are requested.
+	var experimentIDs []int
+	trialIDs := make(map[int][]int)
+	addExperiment := func(id int) {
+		if _, ok := trialIDs[id]; !ok {
+			experimentIDs = append(experimentIDs, id)
+			trialIDs[id] = nil
+		}
+	}
+	seen := make(map[int]bool)
+	for _, id := range req.ExperimentIds {
+		if err := a.checkExperimentExists(int(id)); err != nil {
+			return nil, err
+		}
+		ids, err := a.m.db.ExperimentTrialIDs(int(id))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error fetching trials of experiment %d", id)
+		}
+		addExperiment(int(id))
+		for _, trialID := range ids {
+			if !seen[trialID] {
+				seen[trialID] = true
+				trialIDs[int(id)] = append(trialIDs[int(id)], trialID)
+			}
+		}
+	}
+	for _, id := range req.TrialIds {
+		if err := a.checkTrialExists(int(id)); err != nil {
+			return nil, err
+		}
+		if seen[int(id)] {
+			continue
+		}
+		seen[int(id)] = true
+		experimentID, err := a.m.db.ExperimentIDByTrialID(int(id))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error fetching experiment of trial %d", id)
+		}
+		addExperiment(experimentID)
+		trialIDs[experimentID] = append(trialIDs[experimentID], int(id))
+	}
+	if len(seen) > maxComparedTrials {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"cannot compare the metrics of %d trials, more than the maximum of %d",
+			len(seen), maxComparedTrials)
+	}
+
+	// The series of each metric are fetched for all the trials at once.
+	var allTrialIDs []int
+	for _, experimentID := range experimentIDs {
+		allTrialIDs = append(allTrialIDs, trialIDs[experimentID]...)
+	}
+	seriesByMetric := make(map[string]map[int][]lttb.Point)
+	for _, name := range req.MetricNames {
+		series, err := a.metricSeries(allTrialIDs, name, req.MetricType, startBatches, endBatches)
+		if err != nil {
+			return nil, err
+		}
+		seriesByMetric[name] = series
+	}
+
+	trials := make(map[int][]comparedTrial)
+	allSeries := make(map[string][][]lttb.Point)
+	for _, experimentID := range experimentIDs {
+		for _, trialID := range trialIDs[experimentID] {
+			trial, err := a.m.db.TrialByID(trialID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error fetching trial metadata")
+			}
+			compared := comparedTrial{trial: trial, series: make(map[string][]lttb.Point)}
+			for _, name := range req.MetricNames {
+				series := lttb.Downsample(seriesByMetric[name][trialID], maxDatapoints)
+				compared.series[name] = series
+				allSeries[name] = append(allSeries[name], series)
+			}
+			trials[experimentID] = append(trials[experimentID], compared)
+		}
+	}
+
+	grids := make(map[string][]float64)
+	for name, series := range allSeries {
+		grids[name] = metricGrid(series, maxDatapoints)
+	}
+
+	resp := &apiv1.CompareMetricsResponse{}
+	for _, experimentID := range experimentIDs {
+		experiment := &apiv1.CompareMetricsResponse_Experiment{ExperimentId: int32(experimentID)}
+		aligned := make(map[string][][]lttb.Point)
+		for _, compared := range trials[experimentID] {
+			trial := &apiv1.CompareMetricsResponse_Trial{
+				TrialId: int32(compared.trial.ID),
+				Hparams: protoutils.ToStruct(compared.trial.HParams),
+			}
+			for _, name := range req.MetricNames {
+				series := resample(compared.series[name], grids[name])
+				aligned[name] = append(aligned[name], series)
+				trial.Metrics = append(trial.Metrics, toSeriesProto(name, series))
+			}
+			experiment.Trials = append(experiment.Trials, trial)
+		}
+		if req.Aggregation != apiv1.MetricAggregation_METRIC_AGGREGATION_UNSPECIFIED {
+			for _, name := range req.MetricNames {
+				experiment.Aggregates = append(experiment.Aggregates, toSeriesProto(
+					name, aggregateSeries(aligned[name], grids[name], req.Aggregation)))
+			}
+		}
+		resp.Experiments = append(resp.Experiments, experiment)
+	}
+	return resp, nil
+}
+
+// metricSeries returns the series of a metric in each of the trials, keyed by trial ID.
+func (a *apiServer) metricSeries(trialIDs []int, metricName string, metricType apiv1.MetricType,
+	startBatches int, endBatches int) (metricSeries map[int][]lttb.Point, err error) {
+	switch metricType {
+	case apiv1.MetricType_METRIC_TYPE_TRAINING:
+		metricSeries, err = a.m.db.TrainingMetricsSeriesByTrial(trialIDs,
+			metricName, startBatches, endBatches)
+	case apiv1.MetricType_METRIC_TYPE_VALIDATION:
+		metricSeries, err = a.m.db.ValidationMetricsSeriesByTrial(trialIDs,
+			metricName, startBatches, endBatches)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid metric type %s", metricType)
+	}
+	return metricSeries, errors.Wrapf(err, "error fetching time series of metrics")
+}
+
+func toSeriesProto(name string, series []lttb.Point) *apiv1.CompareMetricsResponse_Series {
+	out := &apiv1.CompareMetricsResponse_Series{MetricName: name}
+	for _, in := range series {
+		out.Data = append(out.Data, &apiv1.CompareMetricsResponse_DataPoint{
+			Batches: int32(in.X),
+			Value:   in.Y,
+		})
+	}
+	return out
+}
+
+// metricGrid returns the batches at which the series of a metric are reported: the batches of any
+// of the series, evenly thinned to at most maxDatapoints of them.
+func metricGrid(series [][]lttb.Point, maxDatapoints int) []float64 {
+	seen := make(map[float64]bool)
+	var grid []float64
+	for _, s := range series {
+		for _, p := range s {
+			if !seen[p.X] {
+				seen[p.X] = true
+				grid = append(grid, p.X)
+			}
+		}
+	}
+	sort.Float64s(grid)
+	if maxDatapoints < 2 || len(grid) <= maxDatapoints {
+		return grid
+	}
+	thinned := make([]float64, maxDatapoints)
+	for i := range thinned {
+		thinned[i] = grid[i*(len(grid)-1)/(maxDatapoints-1)]
+	}
+	return thinned
+}
+
+// resample returns the values of the series at the batches of the grid that are within its range,
+// linearly interpolating between its points. The series must be sorted by batches.
+func resample(series []lttb.Point, grid []float64) []lttb.Point {
+	if len(series) == 0 {
+		return nil
+	}
+	var out []lttb.Point
+	i := 0
+	for _, x := range grid {
+		if x < series[0].X || x > series[len(series)-1].X {
+			continue
+		}
+		for series[i].X < x {
+			i++
+		}
+		if series[i].X == x || i == 0 {
+			out = append(out, lttb.Point{X: x, Y: series[i].Y})
+			continue
+		}
+		prev, next := series[i-1], series[i]
+		y := prev.Y + (next.Y-prev.Y)*(x-prev.X)/(next.X-prev.X)
+		out = append(out, lttb.Point{X: x, Y: y})
+	}
+	return out
+}
+
+// aggregateSeries aggregates series resampled on the grid at each of its batches at which any of
+// them has a value.
+func aggregateSeries(
+	series [][]lttb.Point, grid []float64, aggregation apiv1.MetricAggregation,
+) []lttb.Point {
+	values := make(map[float64][]float64)
+	for _, s := range series {
+		for _, p := range s {
+			values[p.X] = append(values[p.X], p.Y)
+		}
+	}
+
+	var out []lttb.Point
+	for _, x := range grid {
+		vs, ok := values[x]
+		if !ok {
+			continue
+		}
+		var y float64
+		switch aggregation {
+		case apiv1.MetricAggregation_METRIC_AGGREGATION_MIN:
+			y = math.Inf(1)
+			for _, v := range vs {
+				y = math.Min(y, v)
+			}
+		case apiv1.MetricAggregation_METRIC_AGGREGATION_MAX:
+			y = math.Inf(-1)
+			for _, v := range vs {
+				y = math.Max(y, v)
+			}
+		default:
+			for _, v := range vs {
+				y += v
+			}
+			y /= float64(len(vs))
+		}
+		out = append(out, lttb.Point{X: x, Y: y})
+	}
+	return out
+}
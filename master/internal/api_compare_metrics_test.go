@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/lttb"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestCompareMetricsAlignment(t *testing.T) {
+	series := [][]lttb.Point{
+		{{X: 100, Y: 1}, {X: 300, Y: 3}},
+		{{X: 200, Y: 4}, {X: 400, Y: 8}},
+	}
+	grid := metricGrid(series, 10)
+	assert.DeepEqual(t, grid, []float64{100, 200, 300, 400})
+	assert.DeepEqual(t, metricGrid(series, 3), []float64{100, 200, 400})
+
+	// Series are interpolated within their range but not extrapolated beyond it.
+	first, second := resample(series[0], grid), resample(series[1], grid)
+	assert.DeepEqual(t, first, []lttb.Point{{X: 100, Y: 1}, {X: 200, Y: 2}, {X: 300, Y: 3}})
+	assert.DeepEqual(t, second, []lttb.Point{{X: 200, Y: 4}, {X: 300, Y: 6}, {X: 400, Y: 8}})
+
+	aligned := [][]lttb.Point{first, second}
+	assert.DeepEqual(t,
+		aggregateSeries(aligned, grid, apiv1.MetricAggregation_METRIC_AGGREGATION_MEAN),
+		[]lttb.Point{{X: 100, Y: 1}, {X: 200, Y: 3}, {X: 300, Y: 4.5}, {X: 400, Y: 8}})
+	assert.DeepEqual(t,
+		aggregateSeries(aligned, grid, apiv1.MetricAggregation_METRIC_AGGREGATION_MIN),
+		[]lttb.Point{{X: 100, Y: 1}, {X: 200, Y: 2}, {X: 300, Y: 3}, {X: 400, Y: 8}})
+	assert.DeepEqual(t,
+		aggregateSeries(aligned, grid, apiv1.MetricAggregation_METRIC_AGGREGATION_MAX),
+		[]lttb.Point{{X: 100, Y: 1}, {X: 200, Y: 4}, {X: 300, Y: 6}, {X: 400, Y: 8}})
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/schemas"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/master/pkg/searcher"
@@ -297,6 +298,31 @@ func (a *apiServer) GetExperimentValidationHistory(
 	return &resp, nil
 }
 
+func (a *apiServer) GetExperimentMetricRuleFirings(
+	_ context.Context, req *apiv1.GetExperimentMetricRuleFiringsRequest,
+) (*apiv1.GetExperimentMetricRuleFiringsResponse, error) {
+	if err := a.checkExperimentExists(int(req.ExperimentId)); err != nil {
+		return nil, err
+	}
+
+	var trialID *int
+	if req.TrialId != 0 {
+		trialID = ptrs.IntPtr(int(req.TrialId))
+	}
+	firings, err := a.m.db.MetricRuleFirings(int(req.ExperimentId), trialID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apiv1.GetExperimentMetricRuleFiringsResponse{
+		Firings: []*experimentv1.MetricRuleFiring{},
+	}
+	for _, f := range firings {
+		resp.Firings = append(resp.Firings, f.Proto())
+	}
+	return resp, nil
+}
+
 func (a *apiServer) PreviewHPSearch(
 	_ context.Context, req *apiv1.PreviewHPSearchRequest) (*apiv1.PreviewHPSearchResponse, error) {
 	bytes, err := protojson.Marshal(req.Config)
@@ -126,6 +126,19 @@ func (a *apiServer) ResourceAllocationRaw(
 	return resp, nil
 }
 
+func (a *apiServer) ResourceCost(
+	_ context.Context,
+	req *apiv1.ResourceCostRequest,
+) (*apiv1.ResourceCostResponse, error) {
+	if req.TimestampAfter == nil {
+		return nil, errors.New("no start time provided")
+	}
+	if req.TimestampBefore == nil {
+		return nil, errors.New("no end time provided")
+	}
+	return a.m.fetchResourceCost(req.TimestampAfter.AsTime(), req.TimestampBefore.AsTime())
+}
+
 func (a *apiServer) ResourceAllocationAggregated(
 	_ context.Context,
 	req *apiv1.ResourceAllocationAggregatedRequest,
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/resourcemanagers"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+func (a *apiServer) GetPendingTasks(
+	_ context.Context, req *apiv1.GetPendingTasksRequest,
+) (*apiv1.GetPendingTasksResponse, error) {
+	summaries := a.m.system.Ask(a.m.rm, sproto.GetTaskSummaries{}).Get()
+	return &apiv1.GetPendingTasksResponse{
+		PendingTasks: pendingTasks(
+			summaries.(map[model.AllocationID]resourcemanagers.TaskSummary), req.ResourcePool),
+	}, nil
+}
+
+// pendingTasks returns the tasks that the schedulers recorded a pending reason for in the order
+// they registered.
+func pendingTasks(
+	summaries map[model.AllocationID]resourcemanagers.TaskSummary, resourcePool string,
+) []*taskv1.PendingTask {
+	pending := make([]resourcemanagers.TaskSummary, 0)
+	for _, summary := range summaries {
+		if summary.PendingReason == nil {
+			continue
+		}
+		if resourcePool != "" && summary.ResourcePool != resourcePool {
+			continue
+		}
+		pending = append(pending, summary)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RegisteredTime.Before(pending[j].RegisteredTime)
+	})
+
+	tasks := make([]*taskv1.PendingTask, 0, len(pending))
+	for _, summary := range pending {
+		tasks = append(tasks, &taskv1.PendingTask{
+			TaskId:       string(summary.TaskID),
+			AllocationId: string(summary.AllocationID),
+			Name:         summary.Name,
+			ResourcePool: summary.ResourcePool,
+			SlotsNeeded:  int32(summary.SlotsNeeded),
+			Reason:       summary.PendingReason.Code.Proto(),
+			Message:      summary.PendingReason.Message,
+		})
+	}
+	return tasks
+}
+
+func (a *apiServer) ForwardAllocationPort(
+	ctx context.Context, req *apiv1.ForwardAllocationPortRequest,
+) (*apiv1.ForwardAllocationPortResponse, error) {
+	user, _, err := grpcutil.GetUser(ctx, a.m.db, &a.m.config.InternalConfig.ExternalSessions)
+	if err != nil {
+		return nil, err
+	}
+	handler, err := a.allocationHandlerByID(model.AllocationID(req.AllocationId))
+	if err != nil {
+		return nil, err
+	}
+
+	var forwarded task.ForwardedPort
+	if err := a.ask(handler.Address(), task.ForwardPort{
+		User:        *user,
+		ContainerID: cproto.ID(req.ContainerId),
+		Port:        int(req.Port),
+		ProxyTCP:    req.Tcp,
+		Expiry:      time.Duration(req.ExpirySeconds) * time.Second,
+	}, &forwarded); err != nil {
+		return nil, err
+	}
+
+	return &apiv1.ForwardAllocationPortResponse{
+		ServiceId:    forwarded.ServiceID,
+		ProxyAddress: fmt.Sprintf("/proxy/%s/", forwarded.ServiceID),
+		ExpireTime:   timestamppb.New(forwarded.ExpireTime),
+	}, nil
+}
+
+func (a *apiServer) StopForwardingAllocationPort(
+	ctx context.Context, req *apiv1.StopForwardingAllocationPortRequest,
+) (*apiv1.StopForwardingAllocationPortResponse, error) {
+	user, _, err := grpcutil.GetUser(ctx, a.m.db, &a.m.config.InternalConfig.ExternalSessions)
+	if err != nil {
+		return nil, err
+	}
+	handler, err := a.allocationHandlerByID(model.AllocationID(req.AllocationId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.ask(handler.Address(), task.StopForwardingPort{
+		User:        *user,
+		ContainerID: cproto.ID(req.ContainerId),
+		Port:        int(req.Port),
+	}, nil); err != nil {
+		return nil, err
+	}
+	return &apiv1.StopForwardingAllocationPortResponse{}, nil
+}
@@ -31,10 +31,12 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/updates"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
@@ -551,6 +553,7 @@ func (a *apiServer) PostTrialProfilerMetricsBatch(
 			errs = multierror.Append(errs, fmt.Errorf("failed to insert batch: %w", err))
 			continue
 		}
+		a.m.metricExporter.exportProfilerMetrics(batch)
 	}
 	return &apiv1.PostTrialProfilerMetricsBatchResponse{}, errs.ErrorOrNil()
 }
@@ -719,6 +722,8 @@ func (a *apiServer) ReportTrialTrainingMetrics(
 	if err := a.m.db.AddTrainingMetrics(ctx, req.TrainingMetrics); err != nil {
 		return nil, err
 	}
+	a.m.metricExporter.exportTrialMetrics("training", req.TrainingMetrics)
+	a.evaluateMetricRules(trainingMetricType, req.TrainingMetrics)
 	return &apiv1.ReportTrialTrainingMetricsResponse{}, nil
 }
 
@@ -731,6 +736,8 @@ func (a *apiServer) ReportTrialValidationMetrics(
 	if err := a.m.db.AddValidationMetrics(ctx, req.ValidationMetrics); err != nil {
 		return nil, err
 	}
+	a.m.metricExporter.exportTrialMetrics("validation", req.ValidationMetrics)
+	a.evaluateMetricRules(validationMetricType, req.ValidationMetrics)
 	return &apiv1.ReportTrialValidationMetricsResponse{}, nil
 }
 
@@ -743,6 +750,30 @@ func (a *apiServer) ReportTrialCheckpointMetadata(
 	if err := a.m.db.AddCheckpointMetadata(ctx, req.CheckpointMetadata); err != nil {
 		return nil, err
 	}
+	// The checkpoint is already saved, so failing to publish its update does not fail the report,
+	// which the harness would retry.
+	trialID := int(req.CheckpointMetadata.TrialId)
+	eID, _, err := a.m.db.TrialExperimentAndRequestID(trialID)
+	if err != nil {
+		log.WithError(err).Errorf("failed to publish the update of checkpoint %s",
+			req.CheckpointMetadata.Uuid)
+		return &apiv1.ReportTrialCheckpointMetadataResponse{}, nil
+	}
+	owner, labels, err := a.m.db.ExperimentOwnerAndLabels(eID)
+	if err != nil {
+		log.WithError(err).Errorf("failed to publish the update of checkpoint %s",
+			req.CheckpointMetadata.Uuid)
+		return &apiv1.ReportTrialCheckpointMetadataResponse{}, nil
+	}
+	updates.Publish(a.m.system, model.Update{
+		Kind:           model.UpdateKindCheckpoint,
+		ExperimentID:   eID,
+		TrialID:        &trialID,
+		CheckpointUUID: &req.CheckpointMetadata.Uuid,
+		State:          string(model.CompletedState),
+		Owner:          owner,
+		Labels:         labels,
+	})
 	return &apiv1.ReportTrialCheckpointMetadataResponse{}, nil
 }
 
@@ -801,6 +832,31 @@ func (a *apiServer) PostTrialRunnerMetadata(
 	return &apiv1.PostTrialRunnerMetadataResponse{}, nil
 }
 
+// evaluateMetricRules has the experiment of a trial evaluate its metric rules against the metrics
+// reported by the trial. The metrics are already saved, so failures are logged rather than failing
+// the report, which the harness would retry, saving the metrics again.
+func (a *apiServer) evaluateMetricRules(metricType string, m *trialv1.TrialMetrics) {
+	if a.m.metricRules.empty() {
+		return
+	}
+	trial, hasRules, err := a.m.metricRules.trial(
+		int(m.TrialId), a.m.db.TrialExperimentAndRequestID)
+	switch {
+	case err != nil:
+		log.WithError(err).Errorf("failed to evaluate the metric rules of trial %d", m.TrialId)
+		return
+	case !hasRules:
+		return
+	}
+	a.m.system.TellAt(actor.Addr("experiments", trial.experimentID), trialReportMetrics{
+		requestID:    trial.requestID,
+		trialID:      int(m.TrialId),
+		metricType:   metricType,
+		totalBatches: int(m.LatestBatch),
+		metrics:      m.Metrics,
+	})
+}
+
 func (a *apiServer) checkTrialExists(id int) error {
 	ok, err := a.m.db.CheckTrialExists(id)
 	switch {
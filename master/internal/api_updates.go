@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/updates"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// updatesBatchSize is the number of updates read from the update log at once when resuming a
+// stream.
+const updatesBatchSize = 1000
+
+func (a *apiServer) StreamUpdates(
+	req *apiv1.StreamUpdatesRequest, resp apiv1.Determined_StreamUpdatesServer,
+) error {
+	filter := model.UpdateFilter{Users: req.Users, Labels: req.Labels}
+	for _, id := range req.ExperimentIds {
+		filter.ExperimentIDs = append(filter.ExperimentIDs, int(id))
+	}
+
+	// Subscribe before reading the update log so that no update falls between the two.
+	sub, err := updates.Subscribe(a.m.system, filter)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	if req.SinceSeq > 0 {
+		for after := req.SinceSeq; after < sub.Head; {
+			batch, err := a.m.db.Updates(filter, after, sub.Head, updatesBatchSize)
+			if err != nil {
+				return err
+			}
+			for _, u := range batch {
+				if err := resp.Send(u.Proto()); err != nil {
+					return err
+				}
+			}
+			if len(batch) < updatesBatchSize {
+				break
+			}
+			after = batch[len(batch)-1].Seq
+		}
+	}
+
+	for {
+		select {
+		case u, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable,
+					"the stream fell behind the updates; resume it with since_seq")
+			}
+			if err := resp.Send(u.Proto()); err != nil {
+				return err
+			}
+		case <-resp.Context().Done():
+			return nil
+		}
+	}
+}
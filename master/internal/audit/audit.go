@@ -0,0 +1,224 @@
+// Package audit records mutating API calls in the append-only audit log, along with who made them
+// and their outcome, and prunes entries older than the retention period.
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/actor/actors"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// pruneInterval is the time between deletions of expired entries.
+	pruneInterval = time.Hour
+	// redacted replaces the values of sensitive fields in request summaries.
+	redacted = "********"
+	// maxSummaryString is the longest string kept in request summaries; longer strings, such as
+	// model definitions, are replaced with their length.
+	maxSummaryString = 256
+	// maxSummaryList is the most elements of a list kept in request summaries.
+	maxSummaryList = 20
+	// maxSummaryDepth is the deepest nesting of messages kept in request summaries.
+	maxSummaryDepth = 4
+)
+
+// Config configures the audit log.
+type Config struct {
+	// RetentionDays is how many days entries are kept for; 0 keeps them forever.
+	RetentionDays int `json:"retention_days"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c Config) Validate() []error {
+	return []error{
+		check.GreaterThanOrEqualTo(c.RetentionDays, 0,
+			"audit log retention_days must be non-negative"),
+	}
+}
+
+// Record appends the entry to the audit log. Failing to record an entry does not fail the call it
+// records, which has already completed.
+func Record(pgDB *db.PgDB, e model.AuditLogEntry) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if err := pgDB.AddAuditLogEntry(&e); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"method": e.Method,
+			"user":   e.Username,
+		}).Error("failed to record API call in the audit log")
+	}
+}
+
+// sensitive returns whether a field may hold a secret. Experiment and template configurations may
+// embed checkpoint storage credentials, so they are redacted as a whole.
+func sensitive(name string) bool {
+	return strings.Contains(name, "password") || strings.Contains(name, "secret") ||
+		strings.Contains(name, "token") || name == "config"
+}
+
+// target returns whether a field identifies an object targeted by a call.
+func target(name string) bool {
+	return name == "id" || name == "name" || name == "username" || name == "uuid" ||
+		strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "_ids") ||
+		strings.HasSuffix(name, "_name") || strings.HasSuffix(name, "_uuid")
+}
+
+// Summarize summarizes a request for the audit log: sensitive fields are redacted, and long
+// strings, bytes and long lists are cut short.
+func Summarize(msg proto.Message) model.JSONObj {
+	if msg == nil {
+		return model.JSONObj{}
+	}
+	return summarizeMessage(msg.ProtoReflect(), 0)
+}
+
+func summarizeMessage(m protoreflect.Message, depth int) model.JSONObj {
+	summary := model.JSONObj{}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		switch {
+		case sensitive(name):
+			summary[name] = redacted
+		case fd.IsList():
+			list := v.List()
+			values := make([]interface{}, 0, list.Len())
+			for i := 0; i < list.Len() && i < maxSummaryList; i++ {
+				values = append(values, summarizeValue(fd, list.Get(i), depth))
+			}
+			if list.Len() > maxSummaryList {
+				values = append(values, fmt.Sprintf("<%d more>", list.Len()-maxSummaryList))
+			}
+			summary[name] = values
+		case fd.IsMap():
+			values := model.JSONObj{}
+			v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				values[k.String()] = summarizeValue(fd.MapValue(), v, depth)
+				return true
+			})
+			summary[name] = values
+		default:
+			summary[name] = summarizeValue(fd, v, depth)
+		}
+		return true
+	})
+	return summary
+}
+
+func summarizeValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, depth int) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		m := v.Message()
+		if strings.HasPrefix(string(m.Descriptor().FullName()), "google.protobuf.") {
+			// Well-known types, such as timestamps and structs, summarize best as their JSON.
+			b, err := protojson.Marshal(m.Interface())
+			if err != nil || len(b) > maxSummaryString {
+				return fmt.Sprintf("<%s>", m.Descriptor().Name())
+			}
+			return string(b)
+		}
+		if depth >= maxSummaryDepth {
+			return fmt.Sprintf("<%s>", m.Descriptor().Name())
+		}
+		return summarizeMessage(m, depth+1)
+	case protoreflect.BytesKind:
+		return fmt.Sprintf("<%d bytes>", len(v.Bytes()))
+	case protoreflect.StringKind:
+		if s := v.String(); len(s) > maxSummaryString {
+			return fmt.Sprintf("<%d characters>", len(s))
+		}
+		return v.String()
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	default:
+		return v.Interface()
+	}
+}
+
+// Targets returns the ids of the objects targeted by a call: the identifying fields of its request
+// and response and of the messages they contain directly, e.g., the id of the experiment created
+// by CreateExperiment. Fields of nested messages are keyed by the field of the message.
+func Targets(msgs ...proto.Message) model.JSONObj {
+	targets := model.JSONObj{}
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		addTargets(targets, "", msg.ProtoReflect(), true)
+	}
+	return targets
+}
+
+func addTargets(targets model.JSONObj, prefix string, m protoreflect.Message, nested bool) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := prefix + string(fd.Name())
+		switch {
+		case fd.IsMap():
+		case fd.Kind() == protoreflect.MessageKind:
+			if nested && !fd.IsList() &&
+				!strings.HasPrefix(string(fd.Message().FullName()), "google.protobuf.") {
+				addTargets(targets, name+".", v.Message(), false)
+			}
+		case fd.Kind() == protoreflect.BytesKind || !target(string(fd.Name())):
+		case fd.IsList():
+			list := v.List()
+			ids := make([]interface{}, 0, list.Len())
+			for i := 0; i < list.Len(); i++ {
+				ids = append(ids, list.Get(i).Interface())
+			}
+			targets[name] = ids
+		default:
+			targets[name] = v.Interface()
+		}
+		return true
+	})
+}
+
+type prune struct{}
+
+type pruner struct {
+	db        *db.PgDB
+	retention time.Duration
+}
+
+// NewPruner creates the actor that deletes the entries of the audit log older than the retention
+// period, if there is one.
+func NewPruner(system *actor.System, db *db.PgDB, config Config) {
+	if config.RetentionDays == 0 {
+		return
+	}
+	system.ActorOf(actor.Addr("audit-log-pruner"), &pruner{
+		db:        db,
+		retention: time.Duration(config.RetentionDays) * 24 * time.Hour,
+	})
+}
+
+func (p *pruner) Receive(ctx *actor.Context) error {
+	switch ctx.Message().(type) {
+	case actor.PreStart:
+		ctx.Tell(ctx.Self(), prune{})
+	case prune:
+		if err := p.db.DeleteAuditLogBefore(time.Now().Add(-p.retention)); err != nil {
+			ctx.Log().WithError(err).Error("failed to prune the audit log")
+		}
+		actors.NotifyAfter(ctx, pruneInterval, prune{})
+	case actor.PostStop:
+	default:
+		return actor.ErrUnexpectedMessage(ctx)
+	}
+	return nil
+}
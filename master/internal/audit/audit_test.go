@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+	"github.com/determined-ai/determined/proto/pkg/userv1"
+	"github.com/determined-ai/determined/proto/pkg/utilv1"
+)
+
+func TestSummarizeRedactsSecrets(t *testing.T) {
+	assert.DeepEqual(t, Summarize(&apiv1.SetUserPasswordRequest{
+		Username: "alice", Password: "hunter2",
+	}), model.JSONObj{"username": "alice", "password": redacted})
+
+	assert.DeepEqual(t, Summarize(&apiv1.LoginRequest{
+		Username: "alice", Password: "hunter2", IsHashed: true,
+	}), model.JSONObj{"username": "alice", "password": redacted, "is_hashed": true})
+
+	assert.DeepEqual(t, Summarize(&apiv1.PostUserRequest{
+		User: &userv1.User{Username: "bob", Admin: true}, Password: "hunter2",
+	}), model.JSONObj{
+		"user":     model.JSONObj{"username": "bob", "admin": true},
+		"password": redacted,
+	})
+
+	// Configurations may embed checkpoint storage credentials.
+	summary := Summarize(&apiv1.CreateExperimentRequest{
+		Config: "checkpoint_storage: {type: s3, secret_key: hunter2}",
+	})
+	assert.DeepEqual(t, summary, model.JSONObj{"config": redacted})
+}
+
+func TestSummarizeCutsLongValues(t *testing.T) {
+	summary := Summarize(&apiv1.PatchExperimentRequest{Experiment: &experimentv1.Experiment{
+		Id: 1, Notes: strings.Repeat("x", maxSummaryString+1),
+	}})
+	assert.DeepEqual(t, summary, model.JSONObj{"experiment": model.JSONObj{
+		"id": int32(1), "notes": "<257 characters>",
+	}})
+
+	files := make([]*utilv1.File, maxSummaryList+2)
+	for i := range files {
+		files[i] = &utilv1.File{Content: []byte("print()")}
+	}
+	summary = Summarize(&apiv1.CreateExperimentRequest{ModelDefinition: files})
+	definition := summary["model_definition"].([]interface{})
+	assert.Equal(t, len(definition), maxSummaryList+1)
+	assert.DeepEqual(t, definition[0], model.JSONObj{"content": "<7 bytes>"})
+	assert.Equal(t, definition[maxSummaryList], "<2 more>")
+}
+
+func TestTargets(t *testing.T) {
+	assert.DeepEqual(t, Targets(&apiv1.KillExperimentRequest{Id: 3}, &apiv1.KillExperimentResponse{}),
+		model.JSONObj{"id": int32(3)})
+	assert.DeepEqual(t, Targets(
+		&apiv1.PatchExperimentRequest{Experiment: &experimentv1.Experiment{Id: 3, Notes: "notes"}},
+		nil,
+	), model.JSONObj{"experiment.id": int32(3)})
+	assert.DeepEqual(t, Targets(&apiv1.SetUserPasswordRequest{
+		Username: "alice", Password: "hunter2",
+	}), model.JSONObj{"username": "alice"})
+}
@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// unauditedPaths are the prefixes of the paths of HTTP calls that are not recorded: /api/v1 is
+// served by gRPC, whose calls are recorded by the gRPC interceptors, and the rest are not calls to
+// the master API but traffic to proxied services, logs shipped by agents and debugging endpoints.
+var unauditedPaths = []string{"/api/v1/", "/proxy/", "/trial_logs", "/debug/"}
+
+// Middleware records the mutating HTTP calls outside of /api/v1 in the audit log. Request bodies
+// are not recorded, since they may hold passwords; the targets are the parameters of the path.
+func Middleware(pgDB *db.PgDB) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if !mutatingHTTPMethod(req.Method) || unauditedPath(req.URL.Path) {
+				return next(c)
+			}
+
+			err := next(c)
+
+			code := c.Response().Status
+			if he, ok := err.(*echo.HTTPError); ok {
+				code = he.Code
+			} else if err != nil {
+				code = http.StatusInternalServerError
+			}
+			e := model.AuditLogEntry{
+				Method:     req.Method + " " + req.URL.Path,
+				Targets:    model.JSONObj{},
+				Request:    model.JSONObj{},
+				ResultCode: strconv.Itoa(code),
+				SourceIP:   remoteHost(req.RemoteAddr),
+			}
+			for i, name := range c.ParamNames() {
+				if i < len(c.ParamValues()) {
+					e.Targets[name] = c.ParamValues()[i]
+				}
+			}
+			for k, v := range req.URL.Query() {
+				if sensitive(k) {
+					e.Request[k] = redacted
+				} else {
+					e.Request[k] = strings.Join(v, ",")
+				}
+			}
+			if user, ok := c.Get("user").(model.User); ok {
+				e.UserID = &user.ID
+				e.Username = user.Username
+			}
+			Record(pgDB, e)
+			return err
+		}
+	}
+}
+
+// remoteHost returns the host of the client address of a request. X-Forwarded-For is not trusted,
+// since clients may set it to anything.
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func mutatingHTTPMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+func unauditedPath(path string) bool {
+	for _, prefix := range unauditedPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -107,9 +107,10 @@ func (c *command) Receive(ctx *actor.Context) error {
 		var portProxyConf *sproto.PortProxyConfig
 		if c.GenericCommandSpec.Port != nil {
 			portProxyConf = &sproto.PortProxyConfig{
-				ServiceID: string(c.taskID),
-				Port:      *c.GenericCommandSpec.Port,
-				ProxyTCP:  c.ProxyTCP,
+				ServiceID:  string(c.taskID),
+				Port:       *c.GenericCommandSpec.Port,
+				ProxyTCP:   c.ProxyTCP,
+				SharedWith: c.Config.ShareWith,
 			}
 		}
 
@@ -137,16 +138,27 @@ func (c *command) Receive(ctx *actor.Context) error {
 			}
 		}
 
+		slots, slotFraction := model.SlotRequests(
+			c.Config.Resources.Slots, c.Config.Resources.SlotFraction)
+		cpus, memory := model.HostRequests(c.Config.Resources.CPUs, c.Config.Resources.Memory)
 		allocation := task.NewAllocation(sproto.AllocateRequest{
 			AllocationID: c.allocationID,
 			TaskID:       c.taskID,
 			Name:         c.Config.Description,
 			TaskActor:    ctx.Self(),
 			Group:        ctx.Self(),
-
-			SlotsNeeded:  c.Config.Resources.Slots,
-			Label:        c.Config.Resources.AgentLabel,
-			ResourcePool: c.Config.Resources.ResourcePool,
+			JobID:        c.jobID(),
+			Username:     c.Base.Owner.Username,
+
+			SlotsNeeded:       slots,
+			SlotFraction:      slotFraction,
+			CPUsNeeded:        cpus,
+			MemoryNeeded:      memory,
+			Label:             c.Config.Resources.AgentLabel,
+			AgentSelectors:    c.Config.Resources.AgentSelectors,
+			AgentAffinity:     c.Config.Resources.AgentAffinity,
+			AgentAntiAffinity: c.Config.Resources.AgentAntiAffinity,
+			ResourcePool:      c.Config.Resources.ResourcePool,
 			FittingRequirements: sproto.FittingRequirements{
 				SingleAgent: true,
 			},
@@ -8,8 +8,11 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/determined-ai/determined/master/internal/audit"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/hpimportance"
+	"github.com/determined-ai/determined/master/internal/leader"
+	"github.com/determined-ai/determined/master/internal/metricexport"
 	"github.com/determined-ai/determined/master/internal/resourcemanagers"
 	"github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -63,6 +66,7 @@ func DefaultConfig() *Config {
 			QueueLimit:     16,
 			CoresPerWorker: 1,
 			MaxTrees:       100,
+			Importance:     "permutation",
 		},
 		ResourceConfig: resourcemanagers.DefaultResourceConfig(),
 	}
@@ -88,6 +92,9 @@ type Config struct {
 	ClusterName           string                            `json:"cluster_name"`
 	Logging               model.LoggingConfig               `json:"logging"`
 	HPImportance          hpimportance.HPImportanceConfig   `json:"hyperparameter_importance"`
+	MetricsExport         *metricexport.Config              `json:"metrics_export"`
+	HighAvailability      *leader.Config                    `json:"high_availability"`
+	AuditLog              audit.Config                      `json:"audit_log"`
 
 	*resourcemanagers.ResourceConfig
 
@@ -104,6 +111,23 @@ func (c Config) Printable() ([]byte, error) {
 
 	c.CheckpointStorage = c.CheckpointStorage.Printable()
 
+	if c.MetricsExport != nil {
+		metricsExport := c.MetricsExport.Printable()
+		c.MetricsExport = &metricsExport
+	}
+
+	if c.ResourceConfig != nil && c.ResourcePools != nil {
+		resources := *c.ResourceConfig
+		resources.ResourcePools = make([]resourcemanagers.ResourcePoolConfig, 0, len(c.ResourcePools))
+		for _, pool := range c.ResourcePools {
+			if pool.Provider != nil {
+				pool.Provider = pool.Provider.Printable()
+			}
+			resources.ResourcePools = append(resources.ResourcePools, pool)
+		}
+		c.ResourceConfig = &resources
+	}
+
 	optJSON, err := json.Marshal(c)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to convert config to JSON")
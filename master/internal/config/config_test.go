@@ -10,6 +10,7 @@ import (
 	"gotest.tools/assert"
 
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/metricexport"
 	"github.com/determined-ai/determined/master/internal/provisioner"
 	"github.com/determined-ai/determined/master/internal/resourcemanagers"
 	"github.com/determined-ai/determined/master/pkg/logger"
@@ -244,6 +245,7 @@ func TestPrintableConfig(t *testing.T) {
 	s3Secret := "my_secret_key_secret"
 	masterSecret := "my_master_secret"
 	webuiSecret := "my_webui_secret"
+	exportToken := "my_export_token"
 
 	raw := fmt.Sprintf(`
 db:
@@ -262,7 +264,21 @@ telemetry:
   enabled: true
   segment_master_key: %v
   segment_webui_key: %v
-`, s3Key, s3Secret, masterSecret, webuiSecret)
+
+metrics_export:
+  type: prometheus_remote_write
+  endpoint: http://prometheus:9090/api/v1/write
+  headers:
+    Authorization: %v
+`, s3Key, s3Secret, masterSecret, webuiSecret, exportToken)
+
+	metricsExport := metricexport.Config{}
+	err := yaml.Unmarshal([]byte(`{
+"type": "prometheus_remote_write",
+"endpoint": "http://prometheus:9090/api/v1/write",
+"headers": {"Authorization": "`+exportToken+`"}}`), &metricsExport)
+	assert.NilError(t, err)
+	assert.Equal(t, metricsExport.BatchSize, 500)
 
 	expected := Config{
 		Logging: model.LoggingConfig{
@@ -286,6 +302,7 @@ telemetry:
 			SegmentMasterKey: masterSecret,
 			SegmentWebUIKey:  webuiSecret,
 		},
+		MetricsExport: &metricsExport,
 	}
 
 	unmarshaled := Config{
@@ -293,7 +310,7 @@ telemetry:
 			DefaultLoggingConfig: &model.DefaultLoggingConfig{},
 		},
 	}
-	err := yaml.Unmarshal([]byte(raw), &unmarshaled, yaml.DisallowUnknownFields)
+	err = yaml.Unmarshal([]byte(raw), &unmarshaled, yaml.DisallowUnknownFields)
 	assert.NilError(t, err)
 	assert.DeepEqual(t, unmarshaled, expected)
 
@@ -305,6 +322,7 @@ telemetry:
 	assert.Assert(t, !bytes.Contains(printable, []byte(s3Secret)))
 	assert.Assert(t, !bytes.Contains(printable, []byte(masterSecret)))
 	assert.Assert(t, !bytes.Contains(printable, []byte(webuiSecret)))
+	assert.Assert(t, !bytes.Contains(printable, []byte(exportToken)))
 
 	// Ensure that the original was unmodified.
 	assert.DeepEqual(t, unmarshaled, expected)
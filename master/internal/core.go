@@ -20,6 +20,7 @@ import (
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	promclient "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/determined-ai/determined/master/internal/config"
@@ -37,14 +38,19 @@ import (
 	"github.com/soheilhy/cmux"
 
 	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/audit"
 	"github.com/determined-ai/determined/master/internal/command"
 	detContext "github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/hpimportance"
+	"github.com/determined-ai/determined/master/internal/leader"
+	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/proxy"
 	"github.com/determined-ai/determined/master/internal/resourcemanagers"
+	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/telemetry"
+	"github.com/determined-ai/determined/master/internal/updates"
 	"github.com/determined-ai/determined/master/internal/template"
 	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/pkg/actor"
@@ -81,16 +87,22 @@ type Master struct {
 	trialLogger     *actor.Ref
 	trialLogBackend TrialLogBackend
 	hpImportance    *actor.Ref
+	metricExporter  *metricExporter
+	metricRules     *metricRuleRegistry
+	// lostLeadership receives an error if the master stops being the leader; it is nil if high
+	// availability is disabled.
+	lostLeadership <-chan error
 }
 
 // New creates an instance of the Determined master.
 func New(version string, logStore *logger.LogBuffer, config *config.Config) *Master {
 	logger.SetLogrus(config.Log)
 	return &Master{
-		MasterID: uuid.New().String(),
-		Version:  version,
-		logs:     logStore,
-		config:   config,
+		MasterID:    uuid.New().String(),
+		Version:     version,
+		logs:        logStore,
+		config:      config,
+		metricRules: newMetricRuleRegistry(),
 	}
 }
 
@@ -252,6 +264,117 @@ func (m *Master) getRawResourceAllocation(c echo.Context) error {
 	return nil
 }
 
+// @Summary Get the cost of the provisioned instances during the given time period (CSV).
+// @Tags Cluster
+// @ID get-resource-cost-csv
+// @Accept  json
+// @Produce  text/csv
+//nolint:lll
+// @Param   timestamp_after query string true "Start time to get the cost for (YYYY-MM-DDTHH:MM:SSZ format)"
+//nolint:lll
+// @Param   timestamp_before query string true "End time to get the cost for (YYYY-MM-DDTHH:MM:SSZ format)"
+//nolint:lll
+// @Success 200 {} string "A CSV file containing the fields resource_pool,kind,experiment_id,username,labels,slot_seconds,cost"
+//nolint:godot
+// @Router /cost/raw [get]
+func (m *Master) getRawResourceCost(c echo.Context) error {
+	args := struct {
+		Start string `query:"timestamp_after"`
+		End   string `query:"timestamp_before"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+
+	start, err := time.Parse("2006-01-02T15:04:05Z", args.Start)
+	if err != nil {
+		return errors.Wrap(err, "invalid start time")
+	}
+	end, err := time.Parse("2006-01-02T15:04:05Z", args.End)
+	if err != nil {
+		return errors.Wrap(err, "invalid end time")
+	}
+	resp, err := m.fetchResourceCost(start, end)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Content-Type", "text/csv")
+
+	labelEscaper := strings.NewReplacer("\\", "\\\\", ",", "\\,")
+	csvWriter := csv.NewWriter(c.Response())
+
+	header := []string{
+		"resource_pool", "kind", "experiment_id", "username", "labels", "slot_seconds", "cost",
+	}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range resp.CostEntries {
+		var labels []string
+		for _, label := range entry.Labels {
+			labels = append(labels, labelEscaper.Replace(label))
+		}
+		experimentID := ""
+		if entry.ExperimentId != 0 {
+			experimentID = strconv.Itoa(int(entry.ExperimentId))
+		}
+		fields := []string{
+			entry.ResourcePool, entry.Kind, experimentID, entry.Username, strings.Join(labels, ","),
+			fmt.Sprintf("%f", entry.SlotSeconds), fmt.Sprintf("%f", entry.Cost),
+		}
+		if err := csvWriter.Write(fields); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return nil
+}
+
+// @Summary Get a trace of the agents and tasks during the given time period for the scheduler
+// simulator.
+// @Tags Cluster
+// @ID get-scheduler-trace
+// @Accept  json
+// @Produce  json
+//nolint:lll
+// @Param   timestamp_after query string true "Start time to get the trace for (YYYY-MM-DDTHH:MM:SSZ format)"
+//nolint:lll
+// @Param   timestamp_before query string true "End time to get the trace for (YYYY-MM-DDTHH:MM:SSZ format)"
+//nolint:godot
+// @Router /resources/trace [get]
+func (m *Master) getSchedulerTrace(c echo.Context) (interface{}, error) {
+	args := struct {
+		Start string `query:"timestamp_after"`
+		End   string `query:"timestamp_before"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+
+	start, err := time.Parse("2006-01-02T15:04:05Z", args.Start)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid start time")
+	}
+	end, err := time.Parse("2006-01-02T15:04:05Z", args.End)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid end time")
+	}
+	if start.After(end) {
+		return nil, errors.New("start time cannot be after end time")
+	}
+
+	var trace resourcemanagers.Trace
+	if err := m.db.Query("get_trace_agents", &trace.Agents, start.UTC(), end.UTC()); err != nil {
+		return nil, errors.Wrap(err, "error fetching agent trace")
+	}
+	if err := m.db.Query("get_trace_tasks", &trace.Tasks, start.UTC(), end.UTC()); err != nil {
+		return nil, errors.Wrap(err, "error fetching task trace")
+	}
+	return trace, nil
+}
+
 func (m *Master) fetchAggregatedResourceAllocation(
 	req *apiv1.ResourceAllocationAggregatedRequest,
 ) (*apiv1.ResourceAllocationAggregatedResponse, error) {
@@ -446,6 +569,8 @@ func (m *Master) startServers(ctx context.Context, cert *tls.Certificate) error
 	select {
 	case err := <-errs:
 		return err
+	case err := <-m.lostLeadership:
+		return err
 	case <-ctx.Done():
 		return ctx.Err()
 	}
@@ -469,6 +594,18 @@ func (m *Master) tryRestoreExperiment(sema chan struct{}, e *model.Experiment) {
 			log.WithError(err).Error("failed to mark experiment as errored")
 		}
 		telemetry.ReportExperimentStateChanged(m.system, m.db, *e)
+		owner, labels, err := m.db.ExperimentOwnerAndLabels(e.ID)
+		if err != nil {
+			log.WithError(err).Errorf("failed to publish the update of experiment %d", e.ID)
+			return
+		}
+		updates.Publish(m.system, model.Update{
+			Kind:         model.UpdateKindExperiment,
+			ExperimentID: e.ID,
+			State:        string(e.State),
+			Owner:        owner,
+			Labels:       labels,
+		})
 	}
 }
 
@@ -553,6 +690,17 @@ func (m *Master) Run(ctx context.Context) error {
 		return errors.Wrap(err, "could not set static root")
 	}
 
+	// With high availability, only the leader touches the database or serves requests; standbys
+	// wait here and take over by restoring from the database like a restarted master.
+	if m.config.HighAvailability != nil {
+		leadership, lErr := leader.Campaign(ctx, &m.config.DB, *m.config.HighAvailability)
+		if lErr != nil {
+			return errors.Wrap(lErr, "could not become the leader")
+		}
+		defer leadership.Resign()
+		m.lostLeadership = leadership.Lost()
+	}
+
 	m.db, err = db.Setup(&m.config.DB)
 	if err != nil {
 		return err
@@ -637,12 +785,20 @@ func (m *Master) Run(ctx context.Context) error {
 	}
 
 	m.system.MustActorOf(actor.Addr("allocation-aggregator"), &allocationAggregator{db: m.db})
+	m.system.MustActorOf(sproto.InstanceRecorderAddr, &instanceRecorder{db: m.db})
 
-	hpi, err := hpimportance.NewManager(m.db, m.system, m.config.HPImportance, m.config.Root)
-	if err != nil {
-		return err
+	m.hpImportance, _ = m.system.ActorOf(actor.Addr(hpimportance.RootAddr),
+		hpimportance.NewManager(m.db, m.system, m.config.HPImportance))
+
+	if _, err = updates.NewPublisher(m.system, m.db); err != nil {
+		return errors.Wrap(err, "cannot initialize updates")
 	}
-	m.hpImportance, _ = m.system.ActorOf(actor.Addr(hpimportance.RootAddr), hpi)
+	audit.NewPruner(m.system, m.db, m.config.AuditLog)
+
+	if m.metricExporter, err = newMetricExporter(m.db, m.config.MetricsExport); err != nil {
+		return errors.Wrap(err, "cannot initialize metrics export")
+	}
+	defer m.metricExporter.close()
 
 	// Initialize the HTTP server and listen for incoming requests.
 	m.echo = echo.New()
@@ -686,6 +842,7 @@ func (m *Master) Run(ctx context.Context) error {
 		}
 	})
 
+	m.echo.Use(audit.Middleware(m.db))
 	m.echo.Use(convertDBErrorsToNotFound)
 
 	m.echo.Logger = logger.New()
@@ -701,6 +858,11 @@ func (m *Master) Run(ctx context.Context) error {
 	tasksGroup := m.echo.Group("/tasks", authFuncs...)
 	tasksGroup.GET("", api.Route(m.getTasks))
 	tasksGroup.GET("/:task_id", api.Route(m.getTask))
+	tasksGroup.GET("/:task_id/preemptions", api.Route(m.getTaskPreemptions))
+
+	if err = m.syncReservations(); err != nil {
+		return errors.Wrap(err, "could not load reservations")
+	}
 
 	// Distributed lock server.
 	rwCoordinator := newRWCoordinator()
@@ -788,6 +950,7 @@ func (m *Master) Run(ctx context.Context) error {
 	experimentsGroup.GET("/:experiment_id", api.Route(m.getExperiment))
 	experimentsGroup.GET("/:experiment_id/checkpoints", api.Route(m.getExperimentCheckpoints))
 	experimentsGroup.GET("/:experiment_id/config", api.Route(m.getExperimentConfig))
+	experimentsGroup.GET("/:experiment_id/hp_importance", api.Route(m.getExperimentHPImportance))
 	experimentsGroup.GET("/:experiment_id/model_def", m.getExperimentModelDefinition)
 	experimentsGroup.GET("/:experiment_id/preview_gc", api.Route(m.getExperimentCheckpointsToGC))
 	experimentsGroup.GET("/:experiment_id/summary", api.Route(m.getExperimentSummary))
@@ -813,6 +976,15 @@ func (m *Master) Run(ctx context.Context) error {
 	resourcesGroup := m.echo.Group("/resources", authFuncs...)
 	resourcesGroup.GET("/allocation/raw", m.getRawResourceAllocation)
 	resourcesGroup.GET("/allocation/aggregated", m.getAggregatedResourceAllocation)
+	resourcesGroup.GET("/cost/raw", m.getRawResourceCost)
+	resourcesGroup.GET("/trace", api.Route(m.getSchedulerTrace))
+
+	reservationsGroup := m.echo.Group("/reservations", authFuncs...)
+	reservationsGroup.GET("", api.Route(m.getReservations))
+	reservationsGroup.GET("/:reservation_id", api.Route(m.getReservation))
+	reservationsGroup.POST("", api.Route(m.postReservation))
+	reservationsGroup.PUT("/:reservation_id", api.Route(m.putReservation))
+	reservationsGroup.DELETE("/:reservation_id", api.Route(m.deleteReservation))
 
 	m.echo.POST("/trial_logs", api.Route(m.postTrialLogs))
 
@@ -828,11 +1000,12 @@ func (m *Master) Run(ctx context.Context) error {
 	if m.config.InternalConfig.PrometheusEnabled {
 		p := prometheus.NewPrometheus("echo", nil)
 		p.Use(m.echo)
+		promclient.MustRegister(prom.NewActorCollector(m.system))
 		m.echo.Any("/debug/prom/metrics", echo.WrapHandler(promhttp.Handler()))
 	}
 
 	handler := m.system.AskAt(actor.Addr("proxy"), proxy.NewProxyHandler{ServiceID: "service"})
-	m.echo.Any("/proxy/:service/*", handler.Get().(echo.HandlerFunc))
+	m.echo.Any("/proxy/:service/*", handler.Get().(echo.HandlerFunc), authFuncs...)
 
 	user.RegisterAPIHandler(m.echo, userService, authFuncs...)
 	command.RegisterAPIHandler(
@@ -163,6 +163,16 @@ func (m *Master) getExperimentConfig(c echo.Context) (interface{}, error) {
 	return m.db.ExperimentConfigRaw(args.ExperimentID)
 }
 
+func (m *Master) getExperimentHPImportance(c echo.Context) (interface{}, error) {
+	args := struct {
+		ExperimentID int `path:"experiment_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	return m.db.GetHPImportance(args.ExperimentID)
+}
+
 func (m *Master) getExperimentSummaryMetrics(c echo.Context) (interface{}, error) {
 	args := struct {
 		ExperimentID int `path:"experiment_id"`
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (m *Master) getReservations(c echo.Context) (interface{}, error) {
+	args := struct {
+		ResourcePool *string `query:"resource_pool"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+
+	// Reservations that have ended no longer affect the scheduler, so they are left out.
+	reservations, err := m.db.Reservations(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]model.Reservation, 0, len(reservations))
+	for _, r := range reservations {
+		if args.ResourcePool == nil || r.ResourcePool == *args.ResourcePool {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered, nil
+}
+
+func (m *Master) getReservation(c echo.Context) (interface{}, error) {
+	args := struct {
+		ReservationID int `path:"reservation_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	return m.db.ReservationByID(args.ReservationID)
+}
+
+func (m *Master) postReservation(c echo.Context) (interface{}, error) {
+	var reservation model.Reservation
+	if err := c.Bind(&reservation); err != nil {
+		return nil, err
+	}
+	reservation.ID = 0
+	if err := m.saveReservation(c, &reservation, m.db.AddReservation); err != nil {
+		return nil, err
+	}
+	c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/reservations/%v", reservation.ID))
+	return reservation, nil
+}
+
+func (m *Master) putReservation(c echo.Context) (interface{}, error) {
+	args := struct {
+		ReservationID int `path:"reservation_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	var reservation model.Reservation
+	if err := c.Bind(&reservation); err != nil {
+		return nil, err
+	}
+	reservation.ID = args.ReservationID
+	if err := m.saveReservation(c, &reservation, m.db.UpdateReservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (m *Master) deleteReservation(c echo.Context) (interface{}, error) {
+	args := struct {
+		ReservationID int `path:"reservation_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	if !c.(*context.DetContext).MustGetUser().Admin {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "only admins may delete reservations")
+	}
+	if err := m.db.DeleteReservation(args.ReservationID); err != nil {
+		return nil, err
+	}
+	return nil, m.syncReservations()
+}
+
+// saveReservation validates the reservation, saves it unless the reservations of its resource
+// pool would then reserve more slots than the pool can have at some point of its window, and
+// sends the reservations to the resource manager.
+func (m *Master) saveReservation(
+	c echo.Context,
+	reservation *model.Reservation,
+	save func(*model.Reservation, func([]model.Reservation) error) error,
+) error {
+	if !c.(*context.DetContext).MustGetUser().Admin {
+		return echo.NewHTTPError(http.StatusForbidden, "only admins may reserve slots")
+	}
+	if !sproto.UseAgentRM(m.system) {
+		return echo.NewHTTPError(http.StatusBadRequest,
+			"reservations are only supported by the agent resource manager")
+	}
+	if err := check.Validate(reservation); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	capacity, err := m.reservationCapacity(reservation.ResourcePool)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var conflict error
+	err = save(reservation, func(overlapping []model.Reservation) error {
+		if peak := model.PeakReservedSlots(append(overlapping, *reservation)); peak > capacity {
+			conflict = errors.Errorf(
+				"resource pool %s would have %d slots reserved at the same time but can only have %d",
+				reservation.ResourcePool, peak, capacity)
+		}
+		return conflict
+	})
+	switch {
+	case conflict != nil:
+		return echo.NewHTTPError(http.StatusConflict, conflict.Error())
+	case err != nil:
+		return err
+	}
+	return m.syncReservations()
+}
+
+// reservationCapacity returns the number of slots that the resource pool can have: the slots its
+// provisioner can launch, or the slots of its agents if that is larger or it has no provisioner.
+func (m *Master) reservationCapacity(resourcePool string) (int, error) {
+	resp := m.system.AskAt(sproto.AgentRMAddr, &apiv1.GetResourcePoolsRequest{})
+	if err := resp.Error(); err != nil {
+		return 0, err
+	}
+	pools, ok := resp.Get().(*apiv1.GetResourcePoolsResponse)
+	if !ok {
+		return 0, errors.New("failed to get the resource pools")
+	}
+	for _, pool := range pools.ResourcePools {
+		if pool.Name != resourcePool {
+			continue
+		}
+		capacity := int(pool.MaxAgents * pool.SlotsPerAgent)
+		if int(pool.SlotsAvailable) > capacity {
+			capacity = int(pool.SlotsAvailable)
+		}
+		return capacity, nil
+	}
+	return 0, errors.Errorf("cannot find resource pool: %s", resourcePool)
+}
+
+// syncReservations sends the reservations that have not ended to the resource manager, which holds
+// slots for them during their windows.
+func (m *Master) syncReservations() error {
+	if !sproto.UseAgentRM(m.system) {
+		return nil
+	}
+	reservations, err := m.db.Reservations(time.Now())
+	if err != nil {
+		return err
+	}
+	m.system.Tell(m.rm, sproto.SetReservations{Reservations: reservations})
+	return nil
+}
@@ -29,3 +29,13 @@ func (m *Master) getTask(c echo.Context) (interface{}, error) {
 	}
 	return resp.Get(), nil
 }
+
+func (m *Master) getTaskPreemptions(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID string `path:"task_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	return m.db.AllocationPreemptions(model.TaskID(args.TaskID))
+}
@@ -84,6 +84,7 @@ type DB interface {
 	TrialDetailsRaw(id int) ([]byte, error)
 	AddAllocation(a *model.Allocation) error
 	CompleteAllocation(a *model.Allocation) error
+	AddAllocationPreemption(p *model.AllocationPreemption) error
 	TrialRunIDAndRestarts(trialID int) (int, int, error)
 	UpdateTrialRunID(id, runID int) error
 	UpdateTrialRestarts(id, restarts int) error
@@ -171,6 +172,9 @@ type DB interface {
 	DeleteTrialLogs(ids []int) error
 	TrialLogsCount(trialID int, fs []api.Filter) (int, error)
 	TrialLogsFields(trialID int) (*apiv1.TrialLogsFieldsResponse, error)
+	AddUpdate(u *model.Update) error
+	LatestUpdateSeq() (int64, error)
+	DeleteUpdatesBefore(t time.Time) error
 }
 
 // ErrNotFound is returned if nothing is found.
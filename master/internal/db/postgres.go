@@ -212,6 +212,7 @@ func (db *PgDB) queryRowsWithParser(
 // Query returns the result of the query. Any placeholder parameters are replaced
 // with supplied params.
 func (db *PgDB) Query(queryName string, v interface{}, params ...interface{}) error {
+	defer timeQuery(queryName)()
 	parser := func(rows *sqlx.Rows, val interface{}) error { return rows.StructScan(val) }
 	return db.queryRowsWithParser(db.queries.getOrLoad(queryName), parser, v, params...)
 }
@@ -220,6 +221,7 @@ func (db *PgDB) Query(queryName string, v interface{}, params ...interface{}) er
 // with supplied params.
 func (db *PgDB) QueryF(
 	queryName string, args []interface{}, v interface{}, params ...interface{}) error {
+	defer timeQuery(queryName)()
 	parser := func(rows *sqlx.Rows, val interface{}) error { return rows.StructScan(val) }
 	query := db.queries.getOrLoad(queryName)
 	if len(args) > 0 {
@@ -231,6 +233,7 @@ func (db *PgDB) QueryF(
 // RawQuery returns the result of the query as a raw byte string. Any placeholder parameters are
 // replaced with supplied params.
 func (db *PgDB) RawQuery(queryName string, params ...interface{}) ([]byte, error) {
+	defer timeQuery(queryName)()
 	return db.rawQuery(db.queries.getOrLoad(queryName), params...)
 }
 
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddAuditLogEntry appends the entry to the audit log and sets its ID.
+func (db *PgDB) AddAuditLogEntry(e *model.AuditLogEntry) error {
+	if e.Targets == nil {
+		e.Targets = model.JSONObj{}
+	}
+	if e.Request == nil {
+		e.Request = model.JSONObj{}
+	}
+	return db.namedGet(&e.ID, `
+INSERT INTO audit_log
+	(time, user_id, username, allocation_id, method, targets, request, result_code, source_ip)
+VALUES (:time, :user_id, :username, :allocation_id, :method, :targets, :request, :result_code,
+	:source_ip)
+RETURNING id
+`, e)
+}
+
+// DeleteAuditLogBefore deletes the entries of the audit log older than the time.
+func (db *PgDB) DeleteAuditLogBefore(t time.Time) error {
+	if _, err := db.sql.Exec(`DELETE FROM audit_log WHERE time < $1`, t); err != nil {
+		return errors.Wrap(err, "error deleting old audit log entries")
+	}
+	return nil
+}
@@ -4,8 +4,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 // GetClusterID queries the master uuid in the database, first adding it if it doesn't exist.
@@ -85,9 +88,10 @@ func (db *PgDB) UpdateResourceAllocationAggregation() error {
 	for ; periodStart.Before(targetDate); periodStart = periodStart.AddDate(0, 0, 1) {
 		t0 := time.Now()
 
-		if _, err := db.sql.Exec(
-			db.queries.getOrLoad("update_aggregated_allocation"), periodStart,
-		); err != nil {
+		done := timeQuery("update_aggregated_allocation")
+		_, err := db.sql.Exec(db.queries.getOrLoad("update_aggregated_allocation"), periodStart)
+		done()
+		if err != nil {
 			return errors.Wrap(err, "failed to add aggregate")
 		}
 
@@ -99,3 +103,33 @@ func (db *PgDB) UpdateResourceAllocationAggregation() error {
 
 	return nil
 }
+
+// RecordProvisionedInstances records the start of the lifetimes of the instances that the
+// provisioner of a resource pool has running and ends the lifetimes of the other instances of the
+// resource pool at the time.
+func (db *PgDB) RecordProvisionedInstances(
+	resourcePool string, instances []model.ProvisionedInstance, t time.Time,
+) error {
+	return db.withTransaction("record provisioned instances", func(tx *sqlx.Tx) error {
+		instanceIDs := make([]string, 0, len(instances))
+		for _, inst := range instances {
+			if _, err := tx.NamedExec(`
+INSERT INTO provisioned_instances
+    (instance_id, resource_pool, instance_type, slots, hourly_price, start_time)
+VALUES (:instance_id, :resource_pool, :instance_type, :slots, :hourly_price, :start_time)
+ON CONFLICT (instance_id) DO NOTHING
+`, inst); err != nil {
+				return errors.Wrapf(err, "error recording instance %s", inst.InstanceID)
+			}
+			instanceIDs = append(instanceIDs, inst.InstanceID)
+		}
+		if _, err := tx.Exec(`
+UPDATE provisioned_instances
+SET end_time = $2
+WHERE resource_pool = $1 AND end_time IS NULL AND NOT (instance_id = ANY($3::text[]))
+`, resourcePool, t, instanceIDs); err != nil {
+			return errors.Wrap(err, "error ending instance lifetimes")
+		}
+		return nil
+	})
+}
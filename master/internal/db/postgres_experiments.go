@@ -415,6 +415,68 @@ ORDER BY batches;`, metricName, trialID, startBatches, endBatches, startTime)
 	return metricSeries, maxEndTime, nil
 }
 
+// TrainingMetricsSeriesByTrial returns the time-series of the specified training metric in each of
+// the specified trials, keyed by trial ID.
+func (db *PgDB) TrainingMetricsSeriesByTrial(trialIDs []int, metricName string,
+	startBatches int, endBatches int) (map[int][]lttb.Point, error) {
+	rows, err := db.sql.Query(`
+SELECT
+  s.trial_id,
+  s.total_batches AS batches,
+  s.metrics->'avg_metrics'->$1 AS value
+FROM steps s
+WHERE s.trial_id IN (SELECT unnest($2::int [])::int)
+  AND s.state = 'COMPLETED'
+  AND s.total_batches >= $3
+  AND s.total_batches <= $4
+  AND s.metrics->'avg_metrics'->$1 IS NOT NULL
+ORDER BY s.trial_id, batches;`, metricName, trialIDs, startBatches, endBatches)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get training metrics of trials %v", trialIDs)
+	}
+	defer rows.Close()
+	return scanMetricsSeriesByTrial(rows), nil
+}
+
+// ValidationMetricsSeriesByTrial returns the time-series of the specified validation metric in
+// each of the specified trials, keyed by trial ID.
+func (db *PgDB) ValidationMetricsSeriesByTrial(trialIDs []int, metricName string,
+	startBatches int, endBatches int) (map[int][]lttb.Point, error) {
+	rows, err := db.sql.Query(`
+SELECT
+  v.trial_id,
+  v.total_batches AS batches,
+  v.metrics->'validation_metrics'->$1 AS value
+FROM validations v
+WHERE v.trial_id IN (SELECT unnest($2::int [])::int)
+  AND v.state = 'COMPLETED'
+  AND v.total_batches >= $3
+  AND v.total_batches <= $4
+  AND v.metrics->'validation_metrics'->$1 IS NOT NULL
+ORDER BY v.trial_id, batches;`, metricName, trialIDs, startBatches, endBatches)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get validation metrics of trials %v", trialIDs)
+	}
+	defer rows.Close()
+	return scanMetricsSeriesByTrial(rows), nil
+}
+
+func scanMetricsSeriesByTrial(rows *sql.Rows) map[int][]lttb.Point {
+	metricSeries := make(map[int][]lttb.Point)
+	for rows.Next() {
+		var trialID int
+		var batches uint
+		var value float64
+		if err := rows.Scan(&trialID, &batches, &value); err != nil {
+			// Could be a bad metric name, sparse metric, nested type, etc.
+			continue
+		}
+		metricSeries[trialID] = append(
+			metricSeries[trialID], lttb.Point{X: float64(batches), Y: value})
+	}
+	return metricSeries
+}
+
 type hpImportanceDataWrapper struct {
 	TrialID int     `db:"trial_id"`
 	Hparams []byte  `db:"hparams"`
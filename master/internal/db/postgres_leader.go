@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+)
+
+// AdvisoryLock is a session-level Postgres advisory lock. It is held for as long as the database
+// session of the connection it was acquired on lives, so the connection is reserved for it.
+type AdvisoryLock struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// TryAdvisoryLock tries to acquire the advisory lock with the key without waiting. It returns nil
+// if another session holds the lock.
+func (db *PgDB) TryAdvisoryLock(ctx context.Context, key int64) (*AdvisoryLock, error) {
+	conn, err := db.sql.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reserving connection for advisory lock")
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(
+		ctx, `SELECT pg_try_advisory_lock($1)`, key,
+	).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrapf(err, "error acquiring advisory lock %d", key)
+	}
+	if !acquired {
+		return nil, conn.Close()
+	}
+	return &AdvisoryLock{conn: conn, key: key}, nil
+}
+
+// Check returns an error if the session holding the lock cannot be reached, in which case the lock
+// may have been released by Postgres.
+func (l *AdvisoryLock) Check(ctx context.Context) error {
+	var held bool
+	if err := l.conn.QueryRowContext(ctx, `
+SELECT EXISTS(
+  SELECT 1 FROM pg_locks
+  WHERE locktype = 'advisory' AND objsubid = 1 AND pid = pg_backend_pid() AND granted
+    AND ((classid::bigint << 32) | objid::bigint) = $1
+)`, l.key).Scan(&held); err != nil {
+		return errors.Wrapf(err, "error checking advisory lock %d", l.key)
+	}
+	if !held {
+		return errors.Errorf("advisory lock %d is no longer held", l.key)
+	}
+	return nil
+}
+
+// Release releases the lock and the connection it was held on.
+func (l *AdvisoryLock) Release() error {
+	if _, err := l.conn.ExecContext(
+		context.Background(), `SELECT pg_advisory_unlock($1)`, l.key,
+	); err != nil {
+		_ = l.conn.Close()
+		return errors.Wrapf(err, "error releasing advisory lock %d", l.key)
+	}
+	return l.conn.Close()
+}
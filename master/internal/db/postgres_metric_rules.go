@@ -0,0 +1,37 @@
+package db
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddMetricRuleFiring records the firing of a metric rule and sets its ID.
+func (db *PgDB) AddMetricRuleFiring(firing *model.MetricRuleFiring) error {
+	return db.namedGet(&firing.ID, `
+INSERT INTO metric_rule_firings
+	(experiment_id, trial_id, rule_name, metric_name, metric_type, condition, value, action,
+	 total_batches, fired_at)
+VALUES
+	(:experiment_id, :trial_id, :rule_name, :metric_name, :metric_type, :condition, :value,
+	 :action, :total_batches, :fired_at)
+RETURNING id
+`, firing)
+}
+
+// MetricRuleFirings returns the metric rule firings of an experiment, ordered by time, optionally
+// limited to those of one trial.
+func (db *PgDB) MetricRuleFirings(experimentID int, trialID *int) ([]model.MetricRuleFiring, error) {
+	var firings []model.MetricRuleFiring
+	if err := db.queryRows(`
+SELECT id, experiment_id, trial_id, rule_name, metric_name, metric_type, condition, value, action,
+	total_batches, fired_at
+FROM metric_rule_firings
+WHERE experiment_id = $1 AND ($2::int IS NULL OR trial_id = $2)
+ORDER BY fired_at, id
+`, &firings, experimentID, trialID); err != nil {
+		return nil, errors.Wrapf(err, "error querying metric rule firings of experiment %d",
+			experimentID)
+	}
+	return firings, nil
+}
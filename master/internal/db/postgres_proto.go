@@ -13,6 +13,7 @@ import (
 // QueryProto returns the result of the query. Any placeholder parameters are replaced
 // with supplied args. Enum values must be the full name of the enum.
 func (db *PgDB) QueryProto(queryName string, v interface{}, args ...interface{}) error {
+	defer timeQuery(queryName)()
 	return errors.Wrapf(
 		db.queryRowsWithParser(db.queries.getOrLoad(queryName), protoParser, v, args...),
 		"error running query: %v", queryName,
@@ -23,6 +24,7 @@ func (db *PgDB) QueryProto(queryName string, v interface{}, args ...interface{})
 // with supplied params.
 func (db *PgDB) QueryProtof(
 	queryName string, args []interface{}, v interface{}, params ...interface{}) error {
+	defer timeQuery(queryName)()
 	query := db.queries.getOrLoad(queryName)
 	if len(args) > 0 {
 		query = fmt.Sprintf(query, args...)
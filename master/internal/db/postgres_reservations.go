@@ -0,0 +1,115 @@
+package db
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Reservations returns the reservations that end after the given time, ordered by start time.
+func (db *PgDB) Reservations(after time.Time) ([]model.Reservation, error) {
+	var reservations []model.Reservation
+	if err := db.queryRows(`
+SELECT id, resource_pool, slots, username, label, start_time, end_time
+FROM reservations
+WHERE end_time > $1
+ORDER BY start_time, id`, &reservations, after); err != nil {
+		return nil, errors.Wrap(err, "error querying reservations")
+	}
+	return reservations, nil
+}
+
+// ReservationByID looks up a reservation by ID.
+func (db *PgDB) ReservationByID(id int) (*model.Reservation, error) {
+	var reservation model.Reservation
+	if err := db.query(`
+SELECT id, resource_pool, slots, username, label, start_time, end_time
+FROM reservations
+WHERE id = $1`, &reservation, id); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// AddReservation adds the reservation and sets its ID. The check is called with the other
+// reservations of its resource pool overlapping its window, and the reservation is only added if
+// the check passes.
+func (db *PgDB) AddReservation(
+	reservation *model.Reservation, check func(overlapping []model.Reservation) error,
+) error {
+	return db.withTransaction("add reservation", func(tx *sqlx.Tx) error {
+		if err := checkOverlappingReservations(tx, reservation, check); err != nil {
+			return err
+		}
+		return namedGet(tx, &reservation.ID, `
+INSERT INTO reservations (resource_pool, slots, username, label, start_time, end_time)
+VALUES (:resource_pool, :slots, :username, :label, :start_time, :end_time)
+RETURNING id`, reservation)
+	})
+}
+
+// UpdateReservation replaces the reservation with the same ID. The check is called with the other
+// reservations of its resource pool overlapping its window, and the reservation is only updated if
+// the check passes.
+func (db *PgDB) UpdateReservation(
+	reservation *model.Reservation, check func(overlapping []model.Reservation) error,
+) error {
+	return db.withTransaction("update reservation", func(tx *sqlx.Tx) error {
+		if err := checkOverlappingReservations(tx, reservation, check); err != nil {
+			return err
+		}
+		res, err := tx.NamedExec(`
+UPDATE reservations
+SET resource_pool = :resource_pool, slots = :slots, username = :username, label = :label,
+    start_time = :start_time, end_time = :end_time
+WHERE id = :id`, reservation)
+		if err != nil {
+			return errors.Wrapf(err, "error updating reservation %d", reservation.ID)
+		}
+		if num, err := res.RowsAffected(); err != nil {
+			return errors.Wrapf(err, "error updating reservation %d", reservation.ID)
+		} else if num != 1 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// checkOverlappingReservations locks the reservations against concurrent changes and calls the
+// check with the other reservations of the resource pool of the reservation overlapping its window.
+func checkOverlappingReservations(
+	tx *sqlx.Tx, reservation *model.Reservation, check func([]model.Reservation) error,
+) error {
+	if _, err := tx.Exec("LOCK TABLE reservations IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return errors.Wrap(err, "error locking reservations")
+	}
+	var overlapping []model.Reservation
+	if err := tx.Select(&overlapping, `
+SELECT id, resource_pool, slots, username, label, start_time, end_time
+FROM reservations
+WHERE resource_pool = $1 AND id != $2 AND start_time < $4 AND end_time > $3`,
+		reservation.ResourcePool, reservation.ID, reservation.StartTime, reservation.EndTime,
+	); err != nil {
+		return errors.Wrap(err, "error querying overlapping reservations")
+	}
+	return check(overlapping)
+}
+
+// DeleteReservation deletes the reservation with the ID.
+func (db *PgDB) DeleteReservation(id int) error {
+	res, err := db.sql.Exec(`DELETE FROM reservations WHERE id = $1`, id)
+	if err != nil {
+		return errors.Wrapf(err, "error deleting reservation %d", id)
+	}
+	num, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "error deleting reservation %d", id)
+	}
+	if num != 1 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -60,8 +60,8 @@ WHERE task_id = $1
 // AddAllocation persists the existence of an allocation.
 func (db *PgDB) AddAllocation(a *model.Allocation) error {
 	return db.namedExecOne(`
-INSERT INTO allocations (task_id, allocation_id, resource_pool, start_time)
-VALUES (:task_id, :allocation_id, :resource_pool, :start_time)
+INSERT INTO allocations (task_id, allocation_id, resource_pool, start_time, username)
+VALUES (:task_id, :allocation_id, :resource_pool, :start_time, NULLIF(:username, ''))
 `, a)
 }
 
@@ -74,6 +74,30 @@ WHERE allocation_id = :allocation_id
 `, a)
 }
 
+// AddAllocationPreemption records the preemption of an allocation.
+func (db *PgDB) AddAllocationPreemption(p *model.AllocationPreemption) error {
+	return db.namedGet(&p.ID, `
+INSERT INTO allocation_preemptions
+	(allocation_id, task_id, reason, preempted_by, preempted_by_job_id, preempted_at)
+VALUES (:allocation_id, :task_id, :reason, :preempted_by, :preempted_by_job_id, :preempted_at)
+RETURNING id
+`, p)
+}
+
+// AllocationPreemptions returns the preemptions of the allocations of a task.
+func (db *PgDB) AllocationPreemptions(taskID model.TaskID) ([]model.AllocationPreemption, error) {
+	var preemptions []model.AllocationPreemption
+	if err := db.queryRows(`
+SELECT id, allocation_id, task_id, reason, preempted_by, preempted_by_job_id, preempted_at
+FROM allocation_preemptions
+WHERE task_id = $1
+ORDER BY preempted_at
+`, &preemptions, taskID); err != nil {
+		return nil, errors.Wrapf(err, "querying preemptions of task %s", taskID)
+	}
+	return preemptions, nil
+}
+
 // StartAllocationSession creates a row in the allocation_sessions table.
 func (db *PgDB) StartAllocationSession(allocationID model.AllocationID) (string, error) {
 	taskSession := &model.AllocationSession{
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddUpdate appends the update to the update log and sets its sequence number.
+func (db *PgDB) AddUpdate(u *model.Update) error {
+	return db.namedGet(&u.Seq, `
+INSERT INTO update_log
+	(kind, experiment_id, trial_id, allocation_id, checkpoint_uuid, state, time)
+VALUES (:kind, :experiment_id, :trial_id, :allocation_id, :checkpoint_uuid, :state, :time)
+RETURNING seq
+`, u)
+}
+
+// LatestUpdateSeq returns the sequence number of the latest update in the update log, or 0 if the
+// log is empty.
+func (db *PgDB) LatestUpdateSeq() (int64, error) {
+	var seq int64
+	if err := db.sql.QueryRowx(`SELECT coalesce(max(seq), 0) FROM update_log`).Scan(&seq); err != nil {
+		return 0, errors.Wrap(err, "error querying latest update")
+	}
+	return seq, nil
+}
+
+// experimentLabels is a query for the labels of the experiment e, whose config may hold them as
+// an array or, in legacy configs, as the keys of an object.
+const experimentLabels = `
+SELECT jsonb_array_elements_text(CASE jsonb_typeof(e.config->'labels')
+	WHEN 'array' THEN e.config->'labels' ELSE '[]' END)
+UNION ALL
+SELECT jsonb_object_keys(CASE jsonb_typeof(e.config->'labels')
+	WHEN 'object' THEN e.config->'labels' ELSE '{}' END)`
+
+// ExperimentOwnerAndLabels returns the username of the owner and the labels of the experiment,
+// which subscriptions to updates filter on.
+func (db *PgDB) ExperimentOwnerAndLabels(id int) (string, []string, error) {
+	var row struct {
+		Owner  string
+		Labels []byte
+	}
+	if err := db.query(`
+SELECT coalesce(u.username, '') AS owner,
+	to_jsonb(ARRAY(`+experimentLabels+`)) AS labels
+FROM experiments e
+LEFT JOIN users u ON u.id = e.owner_id
+WHERE e.id = $1
+`, &row, id); err != nil {
+		return "", nil, errors.Wrapf(err, "error querying owner and labels of experiment %d", id)
+	}
+	var labels []string
+	if err := json.Unmarshal(row.Labels, &labels); err != nil {
+		return "", nil, errors.Wrapf(err, "error parsing labels of experiment %d", id)
+	}
+	return row.Owner, labels, nil
+}
+
+// Updates returns up to limit updates of the update log with sequence numbers in (after, until]
+// that match the filter, ordered by sequence number. The owners and labels of the experiments are
+// matched against their current values.
+func (db *PgDB) Updates(
+	filter model.UpdateFilter, after, until int64, limit int,
+) ([]model.Update, error) {
+	var updates []model.Update
+	if err := db.queryRows(`
+SELECT l.seq, l.kind, l.experiment_id, l.trial_id, l.allocation_id, l.checkpoint_uuid, l.state,
+	l.time
+FROM update_log l
+JOIN experiments e ON e.id = l.experiment_id
+LEFT JOIN users u ON u.id = e.owner_id
+WHERE l.seq > $1 AND l.seq <= $2
+  AND (coalesce(cardinality($3::int[]), 0) = 0 OR l.experiment_id = ANY($3::int[]))
+  AND (coalesce(cardinality($4::text[]), 0) = 0 OR u.username = ANY($4::text[]))
+  AND (coalesce(cardinality($5::text[]), 0) = 0 OR EXISTS (
+	SELECT 1 FROM (`+experimentLabels+`) labels(label)
+	WHERE label = ANY($5::text[])))
+ORDER BY l.seq
+LIMIT $6
+`, &updates, after, until, filter.ExperimentIDs, filter.Users, filter.Labels, limit); err != nil {
+		return nil, errors.Wrap(err, "error querying updates")
+	}
+	return updates, nil
+}
+
+// DeleteUpdatesBefore deletes the updates of the update log older than the time.
+func (db *PgDB) DeleteUpdatesBefore(t time.Time) error {
+	if _, err := db.sql.Exec(`DELETE FROM update_log WHERE time < $1`, t); err != nil {
+		return errors.Wrap(err, "error deleting old updates")
+	}
+	return nil
+}
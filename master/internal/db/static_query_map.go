@@ -3,7 +3,9 @@ package db
 import (
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/pkg/etc"
 )
 
@@ -22,3 +24,11 @@ func (q *staticQueryMap) getOrLoad(queryName string) string {
 	}
 	return query
 }
+
+// timeQuery starts timing a named query. The returned function records its duration.
+func timeQuery(queryName string) func() {
+	start := time.Now()
+	return func() {
+		prom.ObserveSince(prom.DBQueryDuration.WithLabelValues(queryName), start)
+	}
+}
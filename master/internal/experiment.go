@@ -16,6 +16,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/hpimportance"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/telemetry"
+	"github.com/determined-ai/determined/master/internal/updates"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/schemas"
@@ -76,6 +77,8 @@ type (
 		hpImportance        *actor.Ref
 		db                  *db.PgDB
 		searcher            *searcher.Searcher
+		metricRules         *metricRules
+		metricRuleRegistry  *metricRuleRegistry
 		warmStartCheckpoint *model.Checkpoint
 
 		taskSpec *tasks.TaskSpec
@@ -121,6 +124,17 @@ func newExperiment(master *Master, expModel *model.Experiment, taskSpec *tasks.T
 		}
 	}
 
+	owner, err := master.db.UserByID(*expModel.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	taskSpec.Owner = &model.User{
+		ID:       owner.ID,
+		Username: owner.Username,
+		Admin:    owner.Admin,
+		Active:   owner.Active,
+	}
+
 	agentUserGroup, err := master.db.AgentUserGroup(*expModel.OwnerID)
 	if err != nil {
 		return nil, err
@@ -138,6 +152,8 @@ func newExperiment(master *Master, expModel *model.Experiment, taskSpec *tasks.T
 		hpImportance:        master.hpImportance,
 		db:                  master.db,
 		searcher:            search,
+		metricRules:         newMetricRules(conf.MetricRules()),
+		metricRuleRegistry:  master.metricRules,
 		warmStartCheckpoint: checkpoint,
 
 		taskSpec: taskSpec,
@@ -166,11 +182,16 @@ func (e *experiment) Receive(ctx *actor.Context) error {
 			Handler:  ctx.Self(),
 		})
 
+		if len(e.metricRules.rules) > 0 {
+			e.metricRuleRegistry.register(e.ID)
+		}
+
 		if e.restored {
 			e.restoreTrials(ctx)
 			return nil
 		}
 
+		e.publishUpdate(ctx)
 		ops, err := e.searcher.InitialOperations()
 		if err != nil {
 			return errors.Wrap(err, "failed to generate initial operations")
@@ -219,6 +240,8 @@ func (e *experiment) Receive(ctx *actor.Context) error {
 			ctx.Log().WithError(err).Error("failed to save experiment progress")
 		}
 		ctx.Tell(e.hpImportance, hpimportance.ExperimentProgress{ID: e.ID, Progress: progress})
+	case trialReportMetrics:
+		e.evaluateMetricRules(ctx, msg)
 	case trialGetSearcherState:
 		state, ok := e.TrialSearcherState[msg.requestID]
 		if !ok {
@@ -258,6 +281,7 @@ func (e *experiment) Receive(ctx *actor.Context) error {
 
 	// Experiment shutdown logic.
 	case actor.PostStop:
+		e.metricRuleRegistry.unregister(e.ID)
 		if err := e.db.SaveExperimentProgress(e.ID, nil); err != nil {
 			ctx.Log().Error(err)
 		}
@@ -274,6 +298,7 @@ func (e *experiment) Receive(ctx *actor.Context) error {
 			return err
 		}
 		ctx.Log().Infof("experiment state changed to %s", e.State)
+		e.publishUpdate(ctx)
 		addr := actor.Addr(fmt.Sprintf("experiment-%d-checkpoint-gc", e.ID))
 
 		checkpoints, err := e.db.ExperimentCheckpointsToGCRaw(
@@ -478,6 +503,7 @@ func (e *experiment) updateState(ctx *actor.Context, state model.State) bool {
 	if err := e.db.SaveExperimentState(e.Experiment); err != nil {
 		ctx.Log().Errorf("error saving experiment state: %s", err)
 	}
+	e.publishUpdate(ctx)
 	if e.canTerminate(ctx) {
 		ctx.Self().Stop()
 	}
@@ -485,6 +511,22 @@ func (e *experiment) updateState(ctx *actor.Context, state model.State) bool {
 	return true
 }
 
+// publishUpdate publishes the current state of the experiment to the update log.
+func (e *experiment) publishUpdate(ctx *actor.Context) {
+	update := model.Update{
+		Kind:         model.UpdateKindExperiment,
+		ExperimentID: e.ID,
+		State:        string(e.State),
+	}
+	if e.taskSpec.Owner != nil {
+		update.Owner = e.taskSpec.Owner.Username
+	}
+	for label := range e.Config.Labels() {
+		update.Labels = append(update.Labels, label)
+	}
+	updates.Publish(ctx.Self().System(), update)
+}
+
 func (e *experiment) canTerminate(ctx *actor.Context) bool {
 	return model.StoppingStates[e.State] && len(ctx.Children()) == 0
 }
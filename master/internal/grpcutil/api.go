@@ -43,12 +43,14 @@ func NewGRPCServer(db *db.PgDB, srv proto.DeterminedServer, enablePrometheus boo
 
 	streamInterceptors := []grpc.StreamServerInterceptor{
 		grpclogrus.StreamServerInterceptor(logEntry, opts...),
+		streamAuditInterceptor(db, extConfig),
 		grpcrecovery.StreamServerInterceptor(),
 		streamAuthInterceptor(db, extConfig),
 	}
 
 	unaryInterceptors := []grpc.UnaryServerInterceptor{
 		grpclogrus.UnaryServerInterceptor(logEntry, opts...),
+		unaryAuditInterceptor(db, extConfig),
 		grpcrecovery.UnaryServerInterceptor(grpcrecovery.WithRecoveryHandler(
 			func(p interface{}) (err error) {
 				logEntry.Error(string(debug.Stack()))
@@ -119,6 +121,12 @@ func RegisterHTTPProxy(ctx context.Context, e *echo.Echo, port int, cert *tls.Ce
 		if _, ok := request.URL.Query()["pretty"]; ok {
 			request.Header.Set("Accept", jsonPretty)
 		}
+		if wantsEventStream(request) {
+			resumeEventStream(request)
+			w := &eventStreamWriter{ResponseWriter: c.Response()}
+			mux.ServeHTTP(w, request)
+			return w.close()
+		}
 		mux.ServeHTTP(c.Response(), request)
 		return nil
 	}
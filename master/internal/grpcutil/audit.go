@@ -0,0 +1,161 @@
+package grpcutil
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/determined-ai/determined/master/internal/audit"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// unauditedMethods are mutating methods that are not recorded in the audit log: tasks call them
+// many times to report their progress, and they do not act on behalf of users.
+var unauditedMethods = map[string]bool{
+	"/determined.api.v1.Determined/PostTrialProfilerMetricsBatch":   true,
+	"/determined.api.v1.Determined/AckAllocationPreemptionSignal":   true,
+	"/determined.api.v1.Determined/MarkAllocationReservationDaemon": true,
+	"/determined.api.v1.Determined/CompleteTrialSearcherValidation": true,
+	"/determined.api.v1.Determined/ReportTrialSearcherEarlyExit":    true,
+	"/determined.api.v1.Determined/ReportTrialProgress":             true,
+	"/determined.api.v1.Determined/PostTrialRunnerMetadata":         true,
+	"/determined.api.v1.Determined/ReportTrialTrainingMetrics":      true,
+	"/determined.api.v1.Determined/ReportTrialValidationMetrics":    true,
+	"/determined.api.v1.Determined/ReportTrialCheckpointMetadata":   true,
+	"/determined.api.v1.Determined/IdleNotebook":                    true,
+}
+
+// auditedMethods are the methods recorded in the audit log: those that are not mapped to GET
+// requests by the HTTP gateway, except the unaudited methods.
+var auditedMethods = func() map[string]bool {
+	methods := map[string]bool{}
+	service := apiv1.File_determined_api_v1_api_proto.Services().ByName("Determined")
+	for i := 0; i < service.Methods().Len(); i++ {
+		method := service.Methods().Get(i)
+		fullMethod := "/" + string(service.FullName()) + "/" + string(method.Name())
+		if mutatingMethod(method) && !unauditedMethods[fullMethod] {
+			methods[fullMethod] = true
+		}
+	}
+	return methods
+}()
+
+func mutatingMethod(method protoreflect.MethodDescriptor) bool {
+	opts, ok := method.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil {
+		return true
+	}
+	rule, ok := protov2.GetExtension(opts, annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return true
+	}
+	return rule.GetGet() == ""
+}
+
+// auditEntry builds the audit log entry of a call from its context, request, response and error.
+func auditEntry(
+	ctx context.Context, pgDB *db.PgDB, extConfig *model.ExternalSessions, fullMethod string,
+	req, resp interface{}, err error,
+) model.AuditLogEntry {
+	reqMsg, _ := req.(protov2.Message)
+	respMsg, _ := resp.(protov2.Message)
+	if err != nil {
+		respMsg = nil
+	}
+	e := model.AuditLogEntry{
+		Method:     fullMethod,
+		Targets:    audit.Targets(reqMsg, respMsg),
+		Request:    audit.Summarize(reqMsg),
+		ResultCode: status.Code(err).String(),
+		SourceIP:   sourceIP(ctx),
+	}
+
+	if login, ok := req.(*apiv1.LoginRequest); ok {
+		e.Username = login.Username
+	} else if user, _, uErr := GetUser(ctx, pgDB, extConfig); uErr == nil {
+		e.UserID = &user.ID
+		e.Username = user.Username
+	} else if session, sErr := GetAllocationSession(ctx, pgDB); sErr == nil {
+		e.AllocationID = &session.AllocationID
+	}
+	return e
+}
+
+// sourceIP returns the address of the client of a call. Calls through the HTTP gateway come from
+// the master itself, which appends the address of the HTTP client to the X-Forwarded-For header.
+func sourceIP(ctx context.Context) string {
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	if parsed := net.ParseIP(ip); parsed == nil || !parsed.IsLoopback() {
+		return ip
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			return strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+	return ip
+}
+
+func unaryAuditInterceptor(
+	pgDB *db.PgDB, extConfig *model.ExternalSessions,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !auditedMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		resp, err := handler(ctx, req)
+		audit.Record(pgDB, auditEntry(ctx, pgDB, extConfig, info.FullMethod, req, resp, err))
+		return resp, err
+	}
+}
+
+// auditedServerStream keeps the first request received by a stream, to record it.
+type auditedServerStream struct {
+	grpc.ServerStream
+	req interface{}
+}
+
+func (s *auditedServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.req == nil {
+		s.req = m
+	}
+	return err
+}
+
+func streamAuditInterceptor(
+	pgDB *db.PgDB, extConfig *model.ExternalSessions,
+) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+	) error {
+		if !auditedMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		stream := &auditedServerStream{ServerStream: ss}
+		err := handler(srv, stream)
+		audit.Record(pgDB,
+			auditEntry(ss.Context(), pgDB, extConfig, info.FullMethod, stream.req, nil, err))
+		return err
+	}
+}
@@ -0,0 +1,47 @@
+package grpcutil
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"gotest.tools/assert"
+)
+
+func TestAuditedMethods(t *testing.T) {
+	for method, audited := range map[string]bool{
+		"/determined.api.v1.Determined/KillExperiment":             true,
+		"/determined.api.v1.Determined/PatchExperiment":            true,
+		"/determined.api.v1.Determined/DeleteModel":                true,
+		"/determined.api.v1.Determined/Login":                      true,
+		"/determined.api.v1.Determined/SetUserPassword":            true,
+		"/determined.api.v1.Determined/GetExperiments":             false,
+		"/determined.api.v1.Determined/StreamUpdates":              false,
+		"/determined.api.v1.Determined/GetAuditLog":                false,
+		"/determined.api.v1.Determined/ReportTrialTrainingMetrics": false,
+	} {
+		assert.Equal(t, auditedMethods[method], audited, method)
+	}
+}
+
+func TestSourceIP(t *testing.T) {
+	withPeer := func(addr string) context.Context {
+		tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+		assert.NilError(t, err)
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: tcpAddr})
+	}
+
+	assert.Equal(t, sourceIP(withPeer("10.0.0.1:5678")), "10.0.0.1")
+
+	// Calls through the HTTP gateway take the address the gateway appended, not one a client set.
+	gateway := metadata.NewIncomingContext(withPeer("127.0.0.1:5678"),
+		metadata.Pairs("x-forwarded-for", "1.2.3.4, 10.0.0.2"))
+	assert.Equal(t, sourceIP(gateway), "10.0.0.2")
+
+	// Clients cannot claim another address when calling gRPC directly.
+	direct := metadata.NewIncomingContext(withPeer("10.0.0.3:5678"),
+		metadata.Pairs("x-forwarded-for", "1.2.3.4"))
+	assert.Equal(t, sourceIP(direct), "10.0.0.3")
+}
@@ -0,0 +1,121 @@
+package grpcutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	eventStreamMIME = "text/event-stream"
+	// updatesPath is the path of the StreamUpdates API, whose streams resume from the Last-Event-ID
+	// of server-sent events.
+	updatesPath = "/api/v1/updates"
+)
+
+// wantsEventStream returns whether the request asks the StreamUpdates API for a server-sent event
+// stream; other APIs are served as usual whatever they accept.
+func wantsEventStream(r *http.Request) bool {
+	return r.URL.Path == updatesPath && strings.Contains(r.Header.Get("Accept"), eventStreamMIME)
+}
+
+// resumeEventStream sets the since_seq of a request to the StreamUpdates API from the ID of the
+// last event a reconnecting EventSource received.
+func resumeEventStream(r *http.Request) {
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if r.URL.Path != updatesPath || lastEventID == "" {
+		return
+	}
+	query := r.URL.Query()
+	if query.Get("since_seq") == "" && query.Get("sinceSeq") == "" {
+		query.Set("since_seq", lastEventID)
+		r.URL.RawQuery = query.Encode()
+	}
+}
+
+// eventStreamWriter converts the newline-delimited JSON messages that grpc-gateway writes for
+// streaming responses into server-sent events. Each result becomes a message event, with the seq
+// of the result as the event ID if it has one, and a stream error becomes an error event.
+type eventStreamWriter struct {
+	http.ResponseWriter
+	buf         []byte
+	wroteHeader bool
+}
+
+func (w *eventStreamWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Header().Set("Content-Type", eventStreamMIME)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Del("Content-Length")
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *eventStreamWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.buf[:i]
+		w.buf = w.buf[i+1:]
+		if err := w.writeEvent(line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush implements http.Flusher, which grpc-gateway requires of the writers of streams.
+func (w *eventStreamWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// close writes any message not terminated by a newline, like the body of a non-streaming response.
+func (w *eventStreamWriter) close() error {
+	if len(bytes.TrimSpace(w.buf)) == 0 {
+		return nil
+	}
+	line := w.buf
+	w.buf = nil
+	return w.writeEvent(line)
+}
+
+func (w *eventStreamWriter) writeEvent(line []byte) error {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+
+	var event bytes.Buffer
+	var chunk struct {
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	switch err := json.Unmarshal(line, &chunk); {
+	case err == nil && chunk.Result != nil:
+		var result struct {
+			Seq json.RawMessage `json:"seq"`
+		}
+		if json.Unmarshal(chunk.Result, &result) == nil && result.Seq != nil {
+			event.WriteString("id: " + strings.Trim(string(result.Seq), `"`) + "\n")
+		}
+		line = chunk.Result
+	case err == nil && chunk.Error != nil:
+		event.WriteString("event: error\n")
+		line = chunk.Error
+	}
+	event.WriteString("data: ")
+	event.Write(line)
+	event.WriteString("\n\n")
+
+	_, err := w.ResponseWriter.Write(event.Bytes())
+	return err
+}
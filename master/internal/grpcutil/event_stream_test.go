@@ -0,0 +1,65 @@
+package grpcutil
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestEventStreamWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := &eventStreamWriter{ResponseWriter: rec}
+
+	// grpc-gateway may split a message and its delimiter across writes.
+	for _, chunk := range []string{
+		`{"result":{"seq":"7","state":"ACTIVE"}}`, "\n",
+		`{"result":{"name":"x"}}` + "\n" + `{"error":{"message":"boom"}}`, "\n",
+	} {
+		_, err := w.Write([]byte(chunk))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, w.close())
+
+	assert.Equal(t, rec.Header().Get("Content-Type"), eventStreamMIME)
+	assert.Equal(t, rec.Body.String(),
+		"id: 7\ndata: {\"seq\":\"7\",\"state\":\"ACTIVE\"}\n\n"+
+			"data: {\"name\":\"x\"}\n\n"+
+			"event: error\ndata: {\"message\":\"boom\"}\n\n")
+}
+
+func TestEventStreamWriterUnary(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := &eventStreamWriter{ResponseWriter: rec}
+	_, err := w.Write([]byte(`{"code":5,"message":"not found"}`))
+	assert.NilError(t, err)
+	assert.NilError(t, w.close())
+	assert.Equal(t, rec.Body.String(), "data: {\"code\":5,\"message\":\"not found\"}\n\n")
+}
+
+func TestResumeEventStream(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v1/updates?users=alice", nil)
+	r.Header.Set("Last-Event-ID", "42")
+	resumeEventStream(r)
+	assert.Equal(t, r.URL.Query().Get("since_seq"), "42")
+	assert.Equal(t, r.URL.Query().Get("users"), "alice")
+
+	r = httptest.NewRequest("GET", "/api/v1/updates?since_seq=10", nil)
+	r.Header.Set("Last-Event-ID", "42")
+	resumeEventStream(r)
+	assert.Equal(t, r.URL.Query().Get("since_seq"), "10")
+}
+
+func TestWantsEventStream(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v1/updates", nil)
+	r.Header.Set("Accept", "text/event-stream")
+	assert.Assert(t, wantsEventStream(r))
+
+	// Only the StreamUpdates API is served as an event stream.
+	r = httptest.NewRequest("GET", "/api/v1/experiments", nil)
+	r.Header.Set("Accept", "text/event-stream")
+	assert.Assert(t, !wantsEventStream(r))
+
+	r = httptest.NewRequest("GET", "/api/v1/updates", nil)
+	assert.Assert(t, !wantsEventStream(r))
+}
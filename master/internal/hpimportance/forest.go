@@ -0,0 +1,447 @@
+package hpimportance
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+const (
+	// Trees stop splitting nodes with fewer than twice this many rows or at this depth.
+	minLeafSize  = 2
+	maxTreeDepth = 16
+
+	// The z-score of two-sided 95% confidence intervals.
+	confidenceZ = 1.96
+
+	// Partial dependence for interactions is computed over a sample of the rows and, for each
+	// feature, a grid of at most this many of its values.
+	maxInteractionRows = 64
+	maxInteractionGrid = 6
+)
+
+// feature is an input column of a regression forest. Categorical features hold the index of their
+// value among the values of the hyperparameter.
+type feature struct {
+	name        string
+	categorical bool
+	// Whether the feature is a hyperparameter, as opposed to e.g. the number of batches trained on,
+	// which is used to fit the forest but whose importance is not reported.
+	hyperparameter bool
+}
+
+// dataset holds the rows a forest is fitted to.
+type dataset struct {
+	features []feature
+	x        [][]float64
+	y        []float64
+}
+
+type node struct {
+	feature   int
+	threshold float64
+	left      *node
+	right     *node
+	value     float64
+}
+
+// tree is a regression tree fitted to a bootstrap sample of a dataset.
+type tree struct {
+	root *node
+	// The rows that are not in the bootstrap sample.
+	oob []int
+	// The decrease of the sum of squared errors that the splits on each feature achieve.
+	impurity []float64
+}
+
+func goesLeft(f feature, v, threshold float64) bool {
+	if f.categorical {
+		return v == threshold
+	}
+	return v <= threshold
+}
+
+func (t *tree) predict(features []feature, x []float64) float64 {
+	n := t.root
+	for n.left != nil {
+		if goesLeft(features[n.feature], x[n.feature], n.threshold) {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return n.value
+}
+
+type split struct {
+	feature   int
+	threshold float64
+	sse       float64
+}
+
+type treeGrower struct {
+	data *dataset
+	rng  *rand.Rand
+	tree *tree
+	mtry int
+}
+
+// growTree fits a tree to a bootstrap sample of the data, considering a random third of the
+// features at each split as is customary for regression forests.
+func growTree(data *dataset, rng *rand.Rand) *tree {
+	n := len(data.y)
+	rows := make([]int, n)
+	inBag := make([]bool, n)
+	for i := range rows {
+		rows[i] = rng.Intn(n)
+		inBag[rows[i]] = true
+	}
+
+	t := &tree{impurity: make([]float64, len(data.features))}
+	for i, in := range inBag {
+		if !in {
+			t.oob = append(t.oob, i)
+		}
+	}
+	mtry := len(data.features) / 3
+	if mtry < 1 {
+		mtry = 1
+	}
+	g := treeGrower{data: data, rng: rng, tree: t, mtry: mtry}
+	t.root = g.grow(rows, 0)
+	return t
+}
+
+func meanAndSSE(y []float64, rows []int) (float64, float64) {
+	var sum, sumSq float64
+	for _, row := range rows {
+		sum += y[row]
+		sumSq += y[row] * y[row]
+	}
+	n := float64(len(rows))
+	return sum / n, sumSq - sum*sum/n
+}
+
+func (g *treeGrower) grow(rows []int, depth int) *node {
+	mean, sse := meanAndSSE(g.data.y, rows)
+	if depth >= maxTreeDepth || len(rows) < 2*minLeafSize || sse <= 0 {
+		return &node{feature: -1, value: mean}
+	}
+
+	// Consider mtry random features, and more if none of them can split the rows.
+	best := split{feature: -1, sse: math.Inf(1)}
+	for i, f := range g.rng.Perm(len(g.data.features)) {
+		if i >= g.mtry && best.feature >= 0 {
+			break
+		}
+		var s split
+		if g.data.features[f].categorical {
+			s = g.bestCategoricalSplit(f, rows)
+		} else {
+			s = g.bestNumericSplit(f, rows)
+		}
+		if s.feature >= 0 && s.sse < best.sse {
+			best = s
+		}
+	}
+	if best.feature < 0 {
+		return &node{feature: -1, value: mean}
+	}
+	g.tree.impurity[best.feature] += sse - best.sse
+
+	var left, right []int
+	for _, row := range rows {
+		if goesLeft(g.data.features[best.feature], g.data.x[row][best.feature], best.threshold) {
+			left = append(left, row)
+		} else {
+			right = append(right, row)
+		}
+	}
+	return &node{
+		feature:   best.feature,
+		threshold: best.threshold,
+		left:      g.grow(left, depth+1),
+		right:     g.grow(right, depth+1),
+		value:     mean,
+	}
+}
+
+// bestNumericSplit returns the threshold on the feature that minimizes the sum of squared errors
+// of the two sides, placed halfway between consecutive distinct values.
+func (g *treeGrower) bestNumericSplit(f int, rows []int) split {
+	x, y := g.data.x, g.data.y
+	sorted := append([]int(nil), rows...)
+	sort.SliceStable(sorted, func(i, j int) bool { return x[sorted[i]][f] < x[sorted[j]][f] })
+
+	var sum, sumSq float64
+	for _, row := range sorted {
+		sum += y[row]
+		sumSq += y[row] * y[row]
+	}
+
+	best := split{feature: -1, sse: math.Inf(1)}
+	var leftSum, leftSumSq float64
+	for i := 0; i < len(sorted)-1; i++ {
+		v := y[sorted[i]]
+		leftSum += v
+		leftSumSq += v * v
+		nLeft, nRight := i+1, len(sorted)-i-1
+		if nLeft < minLeafSize || nRight < minLeafSize {
+			continue
+		}
+		xv, xNext := x[sorted[i]][f], x[sorted[i+1]][f]
+		if xv == xNext {
+			continue
+		}
+		rightSum, rightSumSq := sum-leftSum, sumSq-leftSumSq
+		sse := leftSumSq - leftSum*leftSum/float64(nLeft) +
+			rightSumSq - rightSum*rightSum/float64(nRight)
+		if sse < best.sse {
+			best = split{feature: f, threshold: (xv + xNext) / 2, sse: sse}
+		}
+	}
+	return best
+}
+
+// bestCategoricalSplit returns the value of the feature that, split from the other values,
+// minimizes the sum of squared errors of the two sides.
+func (g *treeGrower) bestCategoricalSplit(f int, rows []int) split {
+	type stats struct {
+		n          int
+		sum, sumSq float64
+	}
+	byValue := make(map[float64]*stats)
+	var total stats
+	for _, row := range rows {
+		v, y := g.data.x[row][f], g.data.y[row]
+		s, ok := byValue[v]
+		if !ok {
+			s = &stats{}
+			byValue[v] = s
+		}
+		s.n++
+		s.sum += y
+		s.sumSq += y * y
+		total.n++
+		total.sum += y
+		total.sumSq += y * y
+	}
+
+	values := make([]float64, 0, len(byValue))
+	for v := range byValue {
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+
+	best := split{feature: -1, sse: math.Inf(1)}
+	for _, v := range values {
+		left := byValue[v]
+		right := stats{n: total.n - left.n, sum: total.sum - left.sum, sumSq: total.sumSq - left.sumSq}
+		if left.n < minLeafSize || right.n < minLeafSize {
+			continue
+		}
+		sse := left.sumSq - left.sum*left.sum/float64(left.n) +
+			right.sumSq - right.sum*right.sum/float64(right.n)
+		if sse < best.sse {
+			best = split{feature: f, threshold: v, sse: sse}
+		}
+	}
+	return best
+}
+
+// forest is a random forest of regression trees.
+type forest struct {
+	data  *dataset
+	trees []*tree
+}
+
+// growForest fits a forest of the given number of trees to the data, growing the trees on the
+// given number of goroutines. Each tree is grown from its own seed, so that forests are
+// reproducible regardless of the number of goroutines.
+func growForest(data *dataset, numTrees, workers int, seed int64) *forest {
+	f := &forest{data: data, trees: make([]*tree, numTrees)}
+	if workers < 1 {
+		workers = 1
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				// #nosec G404 // The forest does not need cryptographically secure randomness.
+				f.trees[i] = growTree(data, rand.New(rand.NewSource(seed+int64(i))))
+			}
+		}()
+	}
+	for i := 0; i < numTrees; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return f
+}
+
+func (f *forest) predict(x []float64) float64 {
+	var sum float64
+	for _, t := range f.trees {
+		sum += t.predict(f.data.features, x)
+	}
+	return sum / float64(len(f.trees))
+}
+
+// impurityImportance returns, for each feature, the decrease of the mean squared error that the
+// splits on it achieve in each tree.
+func (f *forest) impurityImportance() [][]float64 {
+	importance := make([][]float64, len(f.data.features))
+	n := float64(len(f.data.y))
+	for _, t := range f.trees {
+		for j, decrease := range t.impurity {
+			importance[j] = append(importance[j], decrease/n)
+		}
+	}
+	return importance
+}
+
+// permutationImportance returns, for each feature, the increase of the mean squared error of each
+// tree on its out-of-bag rows when the values of the feature are shuffled among them.
+func (f *forest) permutationImportance(rng *rand.Rand) [][]float64 {
+	importance := make([][]float64, len(f.data.features))
+	x := make([]float64, len(f.data.features))
+	for _, t := range f.trees {
+		if len(t.oob) < 2 {
+			continue
+		}
+		var base float64
+		for _, row := range t.oob {
+			d := t.predict(f.data.features, f.data.x[row]) - f.data.y[row]
+			base += d * d
+		}
+		for j := range f.data.features {
+			perm := rng.Perm(len(t.oob))
+			var mse float64
+			for k, row := range t.oob {
+				copy(x, f.data.x[row])
+				x[j] = f.data.x[t.oob[perm[k]]][j]
+				d := t.predict(f.data.features, x) - f.data.y[row]
+				mse += d * d
+			}
+			importance[j] = append(importance[j], (mse-base)/float64(len(t.oob)))
+		}
+	}
+	return importance
+}
+
+// interactions returns, for each pair of features, the share of the variance of the predictions of
+// the forest that is explained by the interaction of the two features beyond their individual
+// effects, in the manner of fANOVA, using partial dependence on a sample of the rows.
+func (f *forest) interactions(rng *rand.Rand) map[[2]int]float64 {
+	rows := rng.Perm(len(f.data.y))
+	if len(rows) > maxInteractionRows {
+		rows = rows[:maxInteractionRows]
+	}
+
+	total := variance(f.predictions(rows, nil, nil))
+	if total <= 0 {
+		return nil
+	}
+
+	grids := make([][]float64, len(f.data.features))
+	mainEffects := make([]float64, len(f.data.features))
+	for j := range f.data.features {
+		grids[j] = f.grid(j)
+		var pd []float64
+		for _, v := range grids[j] {
+			pd = append(pd, mean(f.predictions(rows, []int{j}, []float64{v})))
+		}
+		mainEffects[j] = variance(pd)
+	}
+
+	result := make(map[[2]int]float64)
+	for i := range f.data.features {
+		for j := i + 1; j < len(f.data.features); j++ {
+			var pd []float64
+			for _, v := range grids[i] {
+				for _, w := range grids[j] {
+					pd = append(pd, mean(f.predictions(rows, []int{i, j}, []float64{v, w})))
+				}
+			}
+			result[[2]int{i, j}] = math.Max(0, variance(pd)-mainEffects[i]-mainEffects[j]) / total
+		}
+	}
+	return result
+}
+
+// predictions returns the predictions of the forest for the rows with the given features set to the
+// given values.
+func (f *forest) predictions(rows []int, features []int, values []float64) []float64 {
+	x := make([]float64, len(f.data.features))
+	result := make([]float64, 0, len(rows))
+	for _, row := range rows {
+		copy(x, f.data.x[row])
+		for k, j := range features {
+			x[j] = values[k]
+		}
+		result = append(result, f.predict(x))
+	}
+	return result
+}
+
+// grid returns the distinct values of the feature, or evenly spaced quantiles of them if there are
+// too many.
+func (f *forest) grid(j int) []float64 {
+	seen := make(map[float64]bool)
+	var values []float64
+	for _, x := range f.data.x {
+		if !seen[x[j]] {
+			seen[x[j]] = true
+			values = append(values, x[j])
+		}
+	}
+	sort.Float64s(values)
+	if len(values) <= maxInteractionGrid {
+		return values
+	}
+	grid := make([]float64, maxInteractionGrid)
+	for k := range grid {
+		grid[k] = values[k*(len(values)-1)/(maxInteractionGrid-1)]
+	}
+	return grid
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func variance(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	m := mean(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return sum / float64(len(values))
+}
+
+// meanAndInterval returns the mean of the samples and the normal approximation of its confidence
+// interval.
+func meanAndInterval(samples []float64) (float64, float64, float64) {
+	if len(samples) == 0 {
+		return 0, 0, 0
+	}
+	m := mean(samples)
+	if len(samples) == 1 {
+		return m, m, m
+	}
+	sd := math.Sqrt(variance(samples) * float64(len(samples)) / float64(len(samples)-1))
+	margin := confidenceZ * sd / math.Sqrt(float64(len(samples)))
+	return m, m - margin, m + margin
+}
@@ -0,0 +1,74 @@
+package hpimportance
+
+import (
+	"math/rand"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func syntheticDataset(n int, metric func(x []float64) float64) *dataset {
+	rng := rand.New(rand.NewSource(0))
+	ds := &dataset{features: []feature{
+		{name: "strong", hyperparameter: true},
+		{name: "weak", hyperparameter: true},
+		{name: "noise", hyperparameter: true},
+		{name: "choice", categorical: true, hyperparameter: true},
+	}}
+	for i := 0; i < n; i++ {
+		x := []float64{rng.Float64(), rng.Float64(), rng.Float64(), float64(rng.Intn(2))}
+		ds.x = append(ds.x, x)
+		ds.y = append(ds.y, metric(x))
+	}
+	return ds
+}
+
+func TestForestImportance(t *testing.T) {
+	ds := syntheticDataset(200, func(x []float64) float64 {
+		return 10*x[0] + 4*x[1] + 3*x[3]
+	})
+	f := growForest(ds, 50, 4, forestSeed)
+
+	importance, intervals := summarizeImportance(
+		ds.features, f.permutationImportance(rand.New(rand.NewSource(0))))
+	assert.Assert(t, importance["strong"] > importance["choice"])
+	assert.Assert(t, importance["choice"] > importance["weak"])
+	assert.Assert(t, importance["weak"] > importance["noise"])
+	assert.Assert(t, importance["noise"] < 0.05)
+	for name, interval := range intervals {
+		assert.Assert(t, interval.Lower <= importance[name] && importance[name] <= interval.Upper,
+			"importance of %s is out of its interval", name)
+	}
+
+	// Impurity importance is biased toward features with many distinct values, e.g., noise, but
+	// still ranks the feature that matters most first.
+	importance, _ = summarizeImportance(ds.features, f.impurityImportance())
+	for _, name := range []string{"weak", "noise", "choice"} {
+		assert.Assert(t, importance["strong"] > importance[name])
+	}
+
+	// Forests grown from the same seed are the same regardless of the number of goroutines.
+	other := growForest(ds, 50, 1, forestSeed)
+	for _, x := range ds.x {
+		assert.Equal(t, f.predict(x), other.predict(x))
+	}
+}
+
+func TestForestInteractions(t *testing.T) {
+	ds := syntheticDataset(300, func(x []float64) float64 {
+		if (x[0] > 0.5) == (x[3] == 1) {
+			return 1
+		}
+		return 0
+	})
+	f := growForest(ds, 50, 4, forestSeed)
+	interactions := f.interactions(rand.New(rand.NewSource(0)))
+
+	// The metric only depends on the interaction of the first and last features.
+	assert.Assert(t, interactions[[2]int{0, 3}] > 0.5)
+	for pair, importance := range interactions {
+		if pair != [2]int{0, 3} {
+			assert.Assert(t, importance < 0.1, "interaction of %v is %v", pair, importance)
+		}
+	}
+}
@@ -1,29 +1,22 @@
 /**
 This file computes the HP importance for the HP visualizations.
-It uses the CloudForest utility (github.com/ryanbressler/CloudForest).
+It fits a random forest to the hyperparameters and metrics of the trials of an
+experiment (see forest.go) and measures how much each hyperparameter matters to it.
 
-The core steps are create the data file, run growforest which outputs
-the importance, read and return the values.
+The core steps are build the dataset, grow the forest, compute the importance
+of each hyperparameter and, optionally, of the interactions of pairs of them.
 **/
 
 package hpimportance
 
 import (
-	"bufio"
-	"encoding/csv"
 	"fmt"
-	"io"
 	"math"
-	"os"
-	"os/exec"
-	"path"
+	"math/rand"
 	"sort"
-	"strconv"
 
 	"github.com/pkg/errors"
 
-	log "github.com/sirupsen/logrus"
-
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 )
@@ -34,177 +27,185 @@ const (
 	idealBatchDiff   = 2
 	nReplications    = 2
 
-	arffFile       = "data.arff"
-	importanceFile = "importance.txt"
+	// The forest is fitted to the number of batches the trials trained on too, so that the
+	// metrics of trials trained for different lengths remain comparable.
+	batchesFeature = "numBatches"
+
+	// Seed the forest so that the results for the same trials are the same.
+	forestSeed = 42
+
+	permutationImportance = "permutation"
+	impurityImportance    = "impurity"
 )
 
-// The data needs to be put into an arff format.
-// Arff files require variable declaration at the top,
-// where order does matter. We need to keep define the Hps and
-// keep track of the order so the data columns will match.
-func createDataFile(data map[int][]model.HPImportanceTrialData,
-	experimentConfig expconf.ExperimentConfig, dataFile string) (int, error) {
-	f, err := os.Create(dataFile)
-	if err != nil {
-		return 0, err
-	}
-	defer func() {
-		err = f.Close()
-		if err != nil {
-			log.WithError(err).Error("failed to close arff file")
+// hpImportanceResults is the importance of the hyperparameters of an experiment with respect to a
+// metric.
+type hpImportanceResults struct {
+	importance   map[string]float64
+	intervals    map[string]model.ConfidenceInterval
+	interactions []model.HPInteraction
+}
+
+// encodeHP returns the value of a hyperparameter as a feature, or false if it cannot be encoded.
+func encodeHP(f feature, categories []interface{}, value interface{}) (float64, bool) {
+	if f.categorical {
+		for i, category := range categories {
+			if fmt.Sprint(category) == fmt.Sprint(value) {
+				return float64(i), true
+			}
 		}
-	}()
+		return 0, false
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, !math.IsNaN(v) && !math.IsInf(v, 0)
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
 
-	arff := bufio.NewWriter(f)
+// sortInteractions sorts interactions from the most to the least important, breaking ties by the
+// names of the hyperparameters so that the order does not depend on the order they were computed
+// in.
+func sortInteractions(interactions []model.HPInteraction) {
+	sort.SliceStable(interactions, func(i, j int) bool {
+		first, second := interactions[i], interactions[j]
+		if first.Importance != second.Importance {
+			return first.Importance > second.Importance
+		}
+		if first.Hyperparameters[0] != second.Hyperparameters[0] {
+			return first.Hyperparameters[0] < second.Hyperparameters[0]
+		}
+		return first.Hyperparameters[1] < second.Hyperparameters[1]
+	})
+}
 
-	// create top of file based on exp config
-	_, err = arff.WriteString("@relation data\n\n@attribute metric numeric\n")
-	if err != nil {
-		return 0, err
+// For the implementation, since we need to account for adaptive search
+// but we don't want to compare trials that have trained for 10,000 vs 100 batches
+// therefore, we continue to add trials till one of 2 conditions are met.
+// 1. There are at least 50 trials to train with and the ideal number of trial difference is met.
+// 2. The difference between the most(aka max) trained trial and the lowest batch are within a
+// defined range (maxDiffCompBatches).
+func createDataset(data map[int][]model.HPImportanceTrialData,
+	experimentConfig expconf.ExperimentConfig) *dataset {
+	// Constant hyperparameters cannot matter, so they are left out. The features are sorted by
+	// name so that the forest is the same for the same trials.
+	hps := expconf.FlattenHPs(experimentConfig.Hyperparameters())
+	var names []string
+	for name := range hps {
+		names = append(names, name)
 	}
-	var hpsOrder []string // HPs must be in the same order for the arff file
-	hps := experimentConfig.Hyperparameters()
-	hps = expconf.FlattenHPs(hps)
+	sort.Strings(names)
 
-	for key, element := range hps {
-		var st string
-		switch tHP := element.GetUnionMember().(type) {
+	ds := &dataset{}
+	categories := make(map[string][]interface{})
+	for _, name := range names {
+		switch tHP := hps[name].GetUnionMember().(type) {
 		case expconf.ConstHyperparameter:
 			continue
 		case expconf.CategoricalHyperparameter:
-			hpsOrder = append(hpsOrder, key)
-
-			var values string
-			for _, catVal := range tHP.Vals() {
-				values += fmt.Sprintf("%v,", catVal)
-			}
-			st = fmt.Sprintf("@attribute %s {%s}\n", key, values)
+			categories[name] = tHP.Vals()
+			ds.features = append(ds.features,
+				feature{name: name, categorical: true, hyperparameter: true})
 		default:
-			hpsOrder = append(hpsOrder, key)
-			st = fmt.Sprintf("@attribute %s numeric\n", key)
-		}
-		_, err = arff.WriteString(st)
-		if err != nil {
-			return 0, err
+			ds.features = append(ds.features, feature{name: name, hyperparameter: true})
 		}
 	}
-	_, err = arff.WriteString("@attribute numBatches numeric\n\n")
-	if err != nil {
-		return 0, err
-	}
+	ds.features = append(ds.features, feature{name: batchesFeature})
 
-	// Now we have to add the data to the file
-	_, err = arff.WriteString("\n@data\n")
-	if err != nil {
-		return 0, err
-	}
-
-	// First, get batch ids unless better way to get them in go?
 	var batches []int
 	for k := range data {
 		batches = append(batches, k)
 	}
 	sort.Sort(sort.Reverse(sort.IntSlice(batches)))
+	if len(batches) == 0 {
+		return ds
+	}
 
-	totalNumTrials := 0
 	maxNumBatches := batches[0]
 	for _, batchID := range batches {
 		if batchID < maxNumBatches/maxDiffCompBatch {
 			break
 		}
-		if totalNumTrials > minNumberTrials && batchID <= maxNumBatches/idealBatchDiff {
+		if len(ds.y) > minNumberTrials && batchID <= maxNumBatches/idealBatchDiff {
 			break
 		}
-		batchIDStr := fmt.Sprintf("%v", batchID)
-		for _, trial := range data[batchID] {
-			var st string
-			st += fmt.Sprintf("%v", trial.Metric)
-			hparamsVals := trial.Hparams
-			for _, hp := range hpsOrder {
-				st += fmt.Sprintf(",%v", hparamsVals[hp])
+		// Trials are sorted by ID so that the forest is the same for the same trials, whatever order
+		// they are fetched in. Trials with non-finite metrics cannot be fitted, so they are left out.
+		batchTrials := append([]model.HPImportanceTrialData(nil), data[batchID]...)
+		sort.SliceStable(batchTrials, func(i, j int) bool {
+			return batchTrials[i].TrialID < batchTrials[j].TrialID
+		})
+	trials:
+		for _, trial := range batchTrials {
+			if math.IsNaN(trial.Metric) || math.IsInf(trial.Metric, 0) {
+				continue
 			}
-			st += fmt.Sprintf(",%s\n", batchIDStr)
-			_, err = arff.WriteString(st)
-			if err != nil {
-				return 0, err
+			x := make([]float64, 0, len(ds.features))
+			for _, f := range ds.features[:len(ds.features)-1] {
+				v, ok := encodeHP(f, categories[f.name], trial.Hparams[f.name])
+				if !ok {
+					continue trials
+				}
+				x = append(x, v)
 			}
-
-			totalNumTrials++
+			ds.x = append(ds.x, append(x, float64(batchID)))
+			ds.y = append(ds.y, trial.Metric)
 		}
 	}
-	err = arff.Flush()
-	if err != nil {
-		return 0, err
-	}
-	return totalNumTrials, nil
+	return ds
 }
 
-// Read the data from the output importance file and return as a json.
-func parseImportanceOutput(filename string) (map[string]float64, error) {
-	// The importance file is the target, hpimportance, p-value, mean difference. We will use the
-	// p-value and ignore the target to target and batch features.
-
-	// #nosec G304 // Ignore security warning because none of this is user-provided input
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open HP importance file: %w", err)
-	}
-	defer func() {
-		err := file.Close()
-		if err != nil {
-			log.WithError(err).Error("failed to close HP importance file")
-		}
-	}()
-
-	hpi := make(map[string]float64)
-	r := csv.NewReader(file)
-	r.Comma = '\t'
-	for {
-		record, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to read HP importance file: %w", err)
+// summarizeImportance returns the mean importance of each hyperparameter and its confidence
+// interval, both scaled so that the importance of all hyperparameters sums to 1.
+func summarizeImportance(features []feature, samples [][]float64,
+) (map[string]float64, map[string]model.ConfidenceInterval) {
+	type summary struct{ mean, lower, upper float64 }
+	summaries := make(map[string]summary)
+	var total float64
+	for j, f := range features {
+		if !f.hyperparameter {
+			continue
 		}
+		m, lower, upper := meanAndInterval(samples[j])
+		summaries[f.name] = summary{m, lower, upper}
+		total += math.Max(0, m)
+	}
 
-		if record[1] == "metric" || record[1] == "numBatches" {
+	importance := make(map[string]float64)
+	intervals := make(map[string]model.ConfidenceInterval)
+	for name, s := range summaries {
+		if total <= 0 {
+			importance[name] = 0
+			intervals[name] = model.ConfidenceInterval{}
 			continue
 		}
-		value, err := strconv.ParseFloat(record[2], 64)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse HP importance value: %w", err)
-		}
-		if !math.IsNaN(value) && !math.IsInf(value, 0) {
-			hpi[record[1]] = value
+		importance[name] = math.Max(0, s.mean) / total
+		intervals[name] = model.ConfidenceInterval{
+			Lower: math.Max(0, s.lower) / total,
+			Upper: math.Max(0, s.upper) / total,
 		}
 	}
-	return hpi, nil
+	return importance, intervals
 }
 
-// For the implementation, since we need to account for adaptive search
-// but we don't want to compare trials that have trained for 10,000 vs 100 batches
-// therefore, we continue to add trials till one of 2 conditions are met.
-// 1. There are at least 50 trials to train with and the ideal number of trial difference is met.
-// 2. The difference between the most(aka max) trained trial and the lowest batch are within a
-// defined range (maxDiffCompBatches).
 func computeHPImportance(data map[int][]model.HPImportanceTrialData,
 	experimentConfig expconf.ExperimentConfig, masterConfig HPImportanceConfig,
-	growforest string, workingDir string) (map[string]float64, error) {
+) (*hpImportanceResults, error) {
 	if len(data) == 0 {
 		return nil, errors.New("not enough data to compute HP importance")
 	}
 
-	growforestInput := path.Join(workingDir, arffFile)
-	growforestOutput := path.Join(workingDir, importanceFile)
-
-	totalNumTrials, err := createDataFile(data, experimentConfig, growforestInput)
-	if err != nil {
-		return nil, fmt.Errorf("error writing ARFF file: %w", err)
-	}
-
-	nCores := strconv.FormatInt(int64(masterConfig.CoresPerWorker), 10)
-	maxNumTrees := int64(masterConfig.MaxTrees)
+	ds := createDataset(data, experimentConfig)
+	totalNumTrials := len(ds.y)
 
 	// random may be smaller because only 50 trials are ran
 	// where I'm not gonna calculate the random forest
@@ -213,41 +214,43 @@ func computeHPImportance(data map[int][]model.HPImportanceTrialData,
 		return nil, fmt.Errorf("not enough trials for HP importance: %d", totalNumTrials)
 	}
 
-	// For version one, we do half the number of trials up to 300
+	// For version one, we do half the number of trials up to MaxTrees.
 	// This may be overdoing and running too long for minimal performance inc.
-	// The paper uses 2 values replications and ntrees while the library,
-	// only uses --ace which is replications.
 	// TODO: use the bar chart from hp viz to improve hp importance
-	numTrees := int64(totalNumTrials / nReplications)
-
-	if numTrees > maxNumTrees {
-		numTrees = maxNumTrees
+	numTrees := totalNumTrials / nReplications
+	if numTrees > int(masterConfig.MaxTrees) {
+		numTrees = int(masterConfig.MaxTrees)
 	}
-	strNumTrees := strconv.FormatInt(numTrees, 10)
-
-	// Call Random Forest
-	// Ignore security warning because none of this is user-provided input
-	// #nosec G204
-	output, err := exec.Command(growforest,
-		// the data file created above
-		"-train", growforestInput,
-		// the y value used to predict. We use a generic metric so we don't have to keep track
-		// of current metric since name doesn't matter
-		"-target", "metric",
-		// number of CPUs to use
-		"-nCores", nCores,
-		// number of replications/permutations
-		"-ace", strNumTrees,
-		// output file
-		"-importance", growforestOutput,
-		// file name to output predictor forest in sf format.
-		"-rfpred", path.Join(workingDir, "rface.sf"),
-	).CombinedOutput()
-	if err != nil {
-		log.Error("growforest failed:\n " + string(output))
-		return nil, fmt.Errorf("random forest failed: %w", err)
+	if numTrees < 1 {
+		return nil, errors.New("max_trees must be positive to compute HP importance")
 	}
 
-	hpi, err := parseImportanceOutput(growforestOutput)
-	return hpi, err
+	f := growForest(ds, numTrees, int(masterConfig.CoresPerWorker), forestSeed)
+	// #nosec G404 // The importance does not need cryptographically secure randomness.
+	rng := rand.New(rand.NewSource(forestSeed))
+
+	var samples [][]float64
+	switch masterConfig.Importance {
+	case impurityImportance:
+		samples = f.impurityImportance()
+	default:
+		samples = f.permutationImportance(rng)
+	}
+	results := &hpImportanceResults{}
+	results.importance, results.intervals = summarizeImportance(ds.features, samples)
+
+	if masterConfig.Interactions {
+		for pair, importance := range f.interactions(rng) {
+			first, second := ds.features[pair[0]], ds.features[pair[1]]
+			if !first.hyperparameter || !second.hyperparameter {
+				continue
+			}
+			results.interactions = append(results.interactions, model.HPInteraction{
+				Hyperparameters: [2]string{first.name, second.name},
+				Importance:      importance,
+			})
+		}
+		sortInteractions(results.interactions)
+	}
+	return results, nil
 }
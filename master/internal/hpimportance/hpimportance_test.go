@@ -1,7 +1,7 @@
 package hpimportance
 
 import (
-	"os"
+	"math"
 	"testing"
 
 	"gotest.tools/assert"
@@ -174,9 +174,17 @@ func TestComputeHPImportance(t *testing.T) {
 			},
 		},
 	}
-	nTreesResults, err := createDataFile(data, expConfig, "data.arff")
-	assert.NilError(t, err)
-	assert.Equal(t, nTreesResults, 8)
+	ds := createDataset(data, expConfig)
+	assert.Equal(t, len(ds.y), 8)
+	assert.Equal(t, len(ds.features), 7)
+	assert.Equal(t, ds.features[0].name, "dropout1")
+	assert.Equal(t, ds.features[5].name, "n_filters3")
+	assert.Assert(t, ds.features[5].categorical)
+	assert.Equal(t, ds.features[6].name, batchesFeature)
+	assert.Assert(t, !ds.features[6].hyperparameter)
+	// Trials are ordered by the number of batches they trained on, and categorical values are
+	// encoded as their index.
+	assert.DeepEqual(t, ds.x[3], []float64{0.87, 0.23, 0.9338878822452688, 33, 51, 1, 10})
 
 	data[4] = []model.HPImportanceTrialData{
 		{
@@ -248,13 +256,52 @@ func TestComputeHPImportance(t *testing.T) {
 			Metric: 2.2999706268310547,
 		},
 	}
-	nTreesResults, err = createDataFile(data, expConfig, "data.arff")
-	assert.NilError(t, err)
-	assert.Equal(t, nTreesResults, 10)
+	ds = createDataset(data, expConfig)
+	assert.Equal(t, len(ds.y), 10)
 
-	_, err = computeHPImportance(data, expConfig, masterConfig, "growforest", ".")
-	assert.Assert(t, err != nil)
+	_, err := computeHPImportance(data, expConfig, masterConfig)
+	assert.ErrorContains(t, err, "not enough trials")
+}
+
+func TestCreateDatasetSkipsNonFiniteMetrics(t *testing.T) {
+	expConfig := expconf.ExperimentConfig{
+		RawHyperparameters: expconf.Hyperparameters{
+			"learning_rate": {
+				RawDoubleHyperparameter: &expconf.DoubleHyperparameter{
+					RawMinval: .0001,
+					RawMaxval: 1.0,
+				},
+			},
+		},
+	}
+	expConfig = schemas.WithDefaults(expConfig).(expconf.ExperimentConfig)
+
+	data := map[int][]model.HPImportanceTrialData{
+		10: {
+			{TrialID: 3, Hparams: map[string]interface{}{"learning_rate": 0.3}, Metric: 3},
+			{TrialID: 2, Hparams: map[string]interface{}{"learning_rate": 0.2}, Metric: math.NaN()},
+			{TrialID: 4, Hparams: map[string]interface{}{"learning_rate": 0.4}, Metric: math.Inf(1)},
+			{TrialID: 1, Hparams: map[string]interface{}{"learning_rate": 0.1}, Metric: 1},
+		},
+	}
+	ds := createDataset(data, expConfig)
+	// The trials are ordered by ID, without those whose metrics are not finite.
+	assert.DeepEqual(t, ds.y, []float64{1, 3})
+	assert.DeepEqual(t, ds.x, [][]float64{{0.1, 10}, {0.3, 10}})
+}
 
-	err = os.Remove("data.arff")
-	assert.Assert(t, err == nil)
+func TestSortInteractions(t *testing.T) {
+	interactions := []model.HPInteraction{
+		{Hyperparameters: [2]string{"b", "c"}, Importance: 0.1},
+		{Hyperparameters: [2]string{"a", "c"}, Importance: 0.1},
+		{Hyperparameters: [2]string{"a", "b"}, Importance: 0.1},
+		{Hyperparameters: [2]string{"c", "d"}, Importance: 0.5},
+	}
+	sortInteractions(interactions)
+	assert.DeepEqual(t, interactions, []model.HPInteraction{
+		{Hyperparameters: [2]string{"c", "d"}, Importance: 0.5},
+		{Hyperparameters: [2]string{"a", "b"}, Importance: 0.1},
+		{Hyperparameters: [2]string{"a", "c"}, Importance: 0.1},
+		{Hyperparameters: [2]string{"b", "c"}, Importance: 0.1},
+	})
 }
@@ -1,17 +1,12 @@
 package hpimportance
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
-	"path"
 	"time"
 
-	"github.com/pkg/errors"
-
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/actor/pool"
+	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/model"
 )
 
@@ -22,13 +17,6 @@ const (
 	// Evaluate after every 10%, but no more than every 10 minutes
 	minPause   = 10 * time.Minute
 	minPercent = 0.1
-
-	// Each worker will create a subdirectory of this for CloudForest's input & output.
-	// This directory is deleted on startup, so be cautious if it changes.
-	workingDir = "/tmp/determined/growforest"
-
-	// The name of the CloudForest executable to look for
-	growforestBin = "growforest"
 )
 
 // HPImportanceConfig is the configuration in the master for hyperparameter importance.
@@ -37,6 +25,20 @@ type HPImportanceConfig struct {
 	QueueLimit     uint `json:"queue_limit"`
 	CoresPerWorker uint `json:"cores_per_worker"`
 	MaxTrees       uint `json:"max_trees"`
+	// Importance is how the importance of hyperparameters is measured: "permutation" or
+	// "impurity".
+	Importance string `json:"importance"`
+	// Interactions enables measuring the importance of the interactions of pairs of
+	// hyperparameters.
+	Interactions bool `json:"interactions"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c HPImportanceConfig) Validate() []error {
+	return []error{
+		check.In(c.Importance, []string{permutationImportance, impurityImportance},
+			"importance must be permutation or impurity"),
+	}
 }
 
 // Messages handled by the HP importance manager.
@@ -82,30 +84,7 @@ type manager struct {
 }
 
 // NewManager initializes the master actor (of which there should only be one instance running).
-func NewManager(db *db.PgDB, system *actor.System, config HPImportanceConfig, masterRoot string,
-) (actor.Actor, error) {
-	// growforest should either be installed in PATH (when running from source) or package with the
-	// master (when running from binary packages).
-	growforest := path.Join(masterRoot, growforestBin)
-	_, err := os.Stat(growforest)
-	if os.IsNotExist(err) {
-		resolvedPath, pathErr := exec.LookPath(growforestBin)
-		if pathErr != nil {
-			return nil, fmt.Errorf("failed to find 'growforest' binary. Install it with " +
-				"'go install github.com/ryanbressler/CloudForest/growforest'")
-		}
-		growforest = resolvedPath
-	}
-
-	err = os.RemoveAll(workingDir)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to clean scratch space for HP importance computation")
-	}
-	err = os.MkdirAll(workingDir, 0700)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create scratch space for HP importance computation")
-	}
-
+func NewManager(db *db.PgDB, system *actor.System, config HPImportanceConfig) actor.Actor {
 	return &manager{
 		config:   config,
 		db:       db,
@@ -113,9 +92,9 @@ func NewManager(db *db.PgDB, system *actor.System, config HPImportanceConfig, ma
 		state:    make(map[int]stateRecord),
 		pool: pool.NewActorPool(
 			system, config.QueueLimit, config.WorkersLimit, "hp-importance-pool",
-			taskHandlerFactory(db, system, growforest, workingDir), nil,
+			taskHandlerFactory(db, system), nil,
 		),
-	}, nil
+	}
 }
 
 func (m *manager) Receive(ctx *actor.Context) error {
@@ -263,7 +242,9 @@ func (m *manager) workCompleted(ctx *actor.Context, msg workCompleted) {
 	metricData := hpi.GetMetricHPImportance(msg.metricName, msg.metricType)
 	metricData.Error = ""
 	metricData.ExperimentProgress = msg.progress
-	metricData.HpImportance = msg.results
+	metricData.HpImportance = msg.results.importance
+	metricData.HpImportanceIntervals = msg.results.intervals
+	metricData.HpInteractions = msg.results.interactions
 	metricData.InProgress = false
 	hpi.SetMetricHPImportance(metricData, msg.metricName, msg.metricType)
 	err = m.db.SetHPImportance(msg.experimentID, hpi)
@@ -1,10 +1,6 @@
 package hpimportance
 
 import (
-	"fmt"
-	"os"
-	"path"
-
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -30,7 +26,7 @@ type (
 		metricName   string
 		metricType   model.MetricType
 		progress     float64
-		results      map[string]float64
+		results      *hpImportanceResults
 	}
 
 	workFailed struct {
@@ -41,7 +37,7 @@ type (
 	}
 )
 
-func taskHandlerFactory(db *db.PgDB, system *actor.System, growforest string, workingDir string,
+func taskHandlerFactory(db *db.PgDB, system *actor.System,
 ) func(uint64, interface{}, *actor.Context) interface{} {
 	getManager := func() *actor.Ref {
 		return system.Get(actor.Addr(RootAddr))
@@ -65,7 +61,7 @@ func taskHandlerFactory(db *db.PgDB, system *actor.System, growforest string, wo
 	}
 
 	sendWorkCompleted := func(system *actor.System, work startWork, progress float64,
-		results map[string]float64) {
+		results *hpImportanceResults) {
 		system.Tell(getManager(), workCompleted{
 			experimentID: work.experimentID,
 			metricType:   work.metricType,
@@ -75,7 +71,7 @@ func taskHandlerFactory(db *db.PgDB, system *actor.System, growforest string, wo
 		})
 	}
 
-	return func(actorId uint64, task interface{}, ctx *actor.Context) interface{} {
+	return func(_ uint64, task interface{}, ctx *actor.Context) interface{} {
 		work, ok := task.(startWork)
 		if !ok {
 			panic("invalid task passed to hp importance actor pool")
@@ -117,18 +113,10 @@ func taskHandlerFactory(db *db.PgDB, system *actor.System, growforest string, wo
 			sendWorkFailed(system, work, "invalid metric type received in hyperparameter importance worker")
 			return nil
 		}
-		taskDir := path.Join(workingDir, fmt.Sprint(actorId))
-		err = os.Mkdir(taskDir, 0700)
-		if err != nil {
-			sendWorkFailed(system, work, err.Error())
-		}
-		results, err := computeHPImportance(trials, experimentConfig, masterConfig, growforest, taskDir)
+		results, err := computeHPImportance(trials, experimentConfig, masterConfig)
 		if err != nil {
 			sendWorkFailed(system, work, err.Error())
-		}
-		err = os.RemoveAll(taskDir)
-		if err != nil {
-			ctx.Log().WithError(err).Errorf("Failed to clean up temporary directory %s", taskDir)
+			return nil
 		}
 		sendWorkCompleted(system, work, progress, results)
 		return nil
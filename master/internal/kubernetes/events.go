@@ -27,13 +27,13 @@ type (
 )
 
 type eventListener struct {
-	clientSet   *k8sClient.Clientset
+	clientSet   k8sClient.Interface
 	namespace   string
 	podsHandler *actor.Ref
 }
 
 func newEventListener(
-	clientSet *k8sClient.Clientset,
+	clientSet k8sClient.Interface,
 	namespace string,
 	podsHandler *actor.Ref,
 ) *eventListener {
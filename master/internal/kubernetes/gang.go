@@ -0,0 +1,219 @@
+package kubernetes
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+
+	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+)
+
+const gpuResource = "nvidia.com/gpu"
+
+// GetPoolCapacity asks the pods actor for a snapshot of the free slots on the nodes of a
+// resource pool. The response is a *PoolCapacity or an error.
+type GetPoolCapacity struct {
+	ResourcePool string
+}
+
+// PoolCapacity is a snapshot of the free slots on the nodes of a resource pool. The resource
+// manager uses it to place all the pods of a multi-pod allocation at once, rather than letting
+// Kubernetes schedule them one at a time and leaving partial allocations around.
+type PoolCapacity struct {
+	// FreeSlots maps node names to the number of slots not requested by any pod on that node.
+	FreeSlots map[string]int
+	// BoundContainers holds the container IDs of the Determined pods that are already bound to a
+	// node and are therefore accounted for in FreeSlots.
+	BoundContainers map[cproto.ID]bool
+}
+
+// Reserve places numPods pods of slotsPerPod slots each onto the nodes of the snapshot, removing
+// the slots they use from it. Each pod goes to the node with the fewest free slots that can still
+// hold it. If the pods do not all fit, Reserve returns false and leaves the snapshot unchanged.
+func (c *PoolCapacity) Reserve(numPods, slotsPerPod int) bool {
+	if slotsPerPod == 0 {
+		return true
+	}
+
+	nodes := make([]string, 0, len(c.FreeSlots))
+	for node := range c.FreeSlots {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	placed := make(map[string]int)
+	for i := 0; i < numPods; i++ {
+		best := ""
+		for _, node := range nodes {
+			free := c.FreeSlots[node] - placed[node]
+			if free < slotsPerPod {
+				continue
+			}
+			if best == "" || free < c.FreeSlots[best]-placed[best] {
+				best = node
+			}
+		}
+		if best == "" {
+			return false
+		}
+		placed[best] += slotsPerPod
+	}
+
+	for node, slots := range placed {
+		c.FreeSlots[node] -= slots
+	}
+	return true
+}
+
+// clone returns a copy of the snapshot that can be reserved from independently of it.
+func (c *PoolCapacity) clone() *PoolCapacity {
+	clone := &PoolCapacity{
+		FreeSlots:       make(map[string]int, len(c.FreeSlots)),
+		BoundContainers: make(map[cproto.ID]bool, len(c.BoundContainers)),
+	}
+	for node, slots := range c.FreeSlots {
+		clone.FreeSlots[node] = slots
+	}
+	for id, bound := range c.BoundContainers {
+		clone.BoundContainers[id] = bound
+	}
+	return clone
+}
+
+// poolCapacityTTL is how long the capacity of a resource pool is cached for. Changes to the
+// Determined pods and the nodes drop the cache right away, but changes to other pods are not
+// watched, so they are only taken into account once it expires.
+const poolCapacityTTL = 30 * time.Second
+
+// poolCapacityCache caches the capacity of the resource pools, since computing it lists every pod
+// of the cluster, while the resource manager asks for it on every scheduling pass that holds back
+// a multi-pod allocation.
+type poolCapacityCache struct {
+	now     func() time.Time
+	entries map[string]cachedPoolCapacity
+}
+
+type cachedPoolCapacity struct {
+	capacity *PoolCapacity
+	at       time.Time
+}
+
+func newPoolCapacityCache() *poolCapacityCache {
+	return &poolCapacityCache{now: time.Now, entries: make(map[string]cachedPoolCapacity)}
+}
+
+// get returns a copy of the cached capacity of the resource pool, if it has not expired.
+func (c *poolCapacityCache) get(pool string) (*PoolCapacity, bool) {
+	entry, ok := c.entries[pool]
+	if !ok || c.now().Sub(entry.at) >= poolCapacityTTL {
+		return nil, false
+	}
+	return entry.capacity.clone(), true
+}
+
+// put caches a copy of the capacity of the resource pool.
+func (c *poolCapacityCache) put(pool string, capacity *PoolCapacity) {
+	c.entries[pool] = cachedPoolCapacity{capacity: capacity.clone(), at: c.now()}
+}
+
+// invalidate drops the capacities of every resource pool.
+func (c *poolCapacityCache) invalidate() {
+	if len(c.entries) > 0 {
+		c.entries = make(map[string]cachedPoolCapacity)
+	}
+}
+
+// podRequests returns the amount of the resource the pod requests, the way the Kubernetes
+// scheduler accounts for it: the larger of the sum over the containers and the largest request
+// of a single init container.
+func podRequests(pod *k8sV1.Pod, resource k8sV1.ResourceName) int64 {
+	request := func(c k8sV1.Container) int64 {
+		quantity, ok := c.Resources.Requests[resource]
+		if !ok {
+			quantity = c.Resources.Limits[resource]
+		}
+		if resource == k8sV1.ResourceCPU {
+			return quantity.MilliValue()
+		}
+		return quantity.Value()
+	}
+
+	var total int64
+	for _, container := range pod.Spec.Containers {
+		total += request(container)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if r := request(container); r > total {
+			total = r
+		}
+	}
+	return total
+}
+
+// poolCapacity computes the free slots of every schedulable node of the resource pool from the
+// node informer snapshot and the pods currently running or pending on those nodes. Pods that are
+// not Determined pods are taken into account as well, since they compete for the same devices.
+func poolCapacity(
+	clientSet k8sClient.Interface,
+	nodes map[string]*k8sV1.Node,
+	pool ResourcePoolConfig,
+	slotType device.Type,
+	slotResourceRequests PodSlotResourceRequests,
+	podNameToContainerID map[string]string,
+) (*PoolCapacity, error) {
+	resource := k8sV1.ResourceName(gpuResource)
+	if slotType == device.CPU {
+		resource = k8sV1.ResourceCPU
+	}
+
+	pods, err := clientSet.CoreV1().Pods("").List(metaV1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing pods")
+	}
+
+	capacity := &PoolCapacity{
+		FreeSlots:       make(map[string]int),
+		BoundContainers: make(map[cproto.ID]bool),
+	}
+
+	used := make(map[string]int64)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" ||
+			pod.Status.Phase == k8sV1.PodSucceeded || pod.Status.Phase == k8sV1.PodFailed {
+			continue
+		}
+		used[pod.Spec.NodeName] += podRequests(pod, resource)
+		if containerID, ok := podNameToContainerID[pod.Name]; ok {
+			capacity.BoundContainers[cproto.ID(containerID)] = true
+		}
+	}
+
+	for name, node := range nodes {
+		if node.Spec.Unschedulable || !pool.matchesNode(node) {
+			continue
+		}
+
+		allocatable := node.Status.Allocatable[resource]
+		var free int
+		switch slotType {
+		case device.CPU:
+			milliCPUs := allocatable.MilliValue() - used[name]
+			free = int(float32(milliCPUs) / (1000. * slotResourceRequests.CPU))
+		default:
+			free = int(allocatable.Value() - used[name])
+		}
+		if free > 0 {
+			capacity.FreeSlots[name] = free
+		}
+	}
+	return capacity, nil
+}
@@ -0,0 +1,116 @@
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+
+	k8sV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func gpuNode(name string, gpus int64, labels map[string]string) *k8sV1.Node {
+	return &k8sV1.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Labels: labels},
+		Status: k8sV1.NodeStatus{
+			Allocatable: k8sV1.ResourceList{
+				gpuResource: *resource.NewQuantity(gpus, resource.DecimalSI),
+			},
+		},
+	}
+}
+
+func gpuPod(name, namespace, node string, gpus int64, phase k8sV1.PodPhase) *k8sV1.Pod {
+	return &k8sV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: k8sV1.PodSpec{
+			NodeName: node,
+			Containers: []k8sV1.Container{{
+				Resources: k8sV1.ResourceRequirements{
+					Limits: k8sV1.ResourceList{
+						gpuResource: *resource.NewQuantity(gpus, resource.DecimalSI),
+					},
+				},
+			}},
+		},
+		Status: k8sV1.PodStatus{Phase: phase},
+	}
+}
+
+func TestPoolCapacity(t *testing.T) {
+	clientSet := fake.NewSimpleClientset(
+		gpuPod("determined-pod", "default", "node-1", 4, k8sV1.PodRunning),
+		gpuPod("other-pod", "other", "node-2", 2, k8sV1.PodRunning),
+		gpuPod("finished-pod", "other", "node-2", 6, k8sV1.PodSucceeded),
+		gpuPod("pending-pod", "default", "", 8, k8sV1.PodPending),
+	)
+	cordoned := gpuNode("node-4", 8, map[string]string{"gpu": "v100"})
+	cordoned.Spec.Unschedulable = true
+	nodes := map[string]*k8sV1.Node{
+		"node-1": gpuNode("node-1", 8, map[string]string{"gpu": "v100"}),
+		"node-2": gpuNode("node-2", 8, map[string]string{"gpu": "v100"}),
+		"node-3": gpuNode("node-3", 8, map[string]string{"gpu": "k80"}),
+		"node-4": cordoned,
+	}
+	pool := ResourcePoolConfig{PoolName: "v100", NodeSelector: map[string]string{"gpu": "v100"}}
+
+	capacity, err := poolCapacity(
+		clientSet, nodes, pool, device.GPU, PodSlotResourceRequests{},
+		map[string]string{"determined-pod": "container-1", "pending-pod": "container-2"},
+	)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, capacity.FreeSlots, map[string]int{"node-1": 4, "node-2": 6})
+	assert.DeepEqual(t, capacity.BoundContainers, map[cproto.ID]bool{"container-1": true})
+}
+
+func TestPoolCapacityReserve(t *testing.T) {
+	capacity := &PoolCapacity{FreeSlots: map[string]int{"node-1": 4, "node-2": 6, "node-3": 8}}
+
+	// The pods of a gang are placed all at once or not at all.
+	assert.Assert(t, !capacity.Reserve(3, 8))
+	assert.DeepEqual(t, capacity.FreeSlots, map[string]int{"node-1": 4, "node-2": 6, "node-3": 8})
+
+	// Pods go to the fullest node that can still hold them.
+	assert.Assert(t, capacity.Reserve(2, 4))
+	assert.DeepEqual(t, capacity.FreeSlots, map[string]int{"node-1": 0, "node-2": 2, "node-3": 8})
+
+	assert.Assert(t, capacity.Reserve(2, 4))
+	assert.DeepEqual(t, capacity.FreeSlots, map[string]int{"node-1": 0, "node-2": 2, "node-3": 0})
+
+	assert.Assert(t, !capacity.Reserve(2, 2))
+	assert.Assert(t, capacity.Reserve(1, 2))
+	assert.Assert(t, capacity.Reserve(4, 0))
+}
+
+func TestPoolCapacityCache(t *testing.T) {
+	now := time.Now()
+	cache := newPoolCapacityCache()
+	cache.now = func() time.Time { return now }
+
+	_, ok := cache.get("pool")
+	assert.Assert(t, !ok)
+
+	// Reserving from a cached capacity leaves the cache unchanged.
+	cache.put("pool", &PoolCapacity{FreeSlots: map[string]int{"node-1": 4}})
+	capacity, ok := cache.get("pool")
+	assert.Assert(t, ok)
+	assert.Assert(t, capacity.Reserve(1, 4))
+	capacity, ok = cache.get("pool")
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, capacity.FreeSlots, map[string]int{"node-1": 4})
+
+	// Cached capacities expire and are dropped when pods or nodes change.
+	now = now.Add(poolCapacityTTL)
+	_, ok = cache.get("pool")
+	assert.Assert(t, !ok)
+	cache.put("pool", capacity)
+	cache.invalidate()
+	_, ok = cache.get("pool")
+	assert.Assert(t, !ok)
+}
@@ -37,7 +37,7 @@ type pod struct {
 	cluster                  *actor.Ref
 	clusterID                string
 	taskActor                *actor.Ref
-	clientSet                *k8sClient.Clientset
+	clientSet                k8sClient.Interface
 	namespace                string
 	masterIP                 string
 	masterPort               int32
@@ -53,6 +53,8 @@ type pod struct {
 	scheduler                string
 	slotType                 device.Type
 	slotResourceRequests     PodSlotResourceRequests
+	resourcePool             ResourcePoolConfig
+	priority                 *int
 
 	pod              *k8sV1.Pod
 	podName          string
@@ -83,7 +85,7 @@ func newPod(
 	msg sproto.StartTaskPod,
 	cluster *actor.Ref,
 	clusterID string,
-	clientSet *k8sClient.Clientset,
+	clientSet k8sClient.Interface,
 	namespace string,
 	masterIP string,
 	masterPort int32,
@@ -97,6 +99,7 @@ func newPod(
 	slotType device.Type,
 	slotResourceRequests PodSlotResourceRequests,
 	scheduler string,
+	resourcePool ResourcePoolConfig,
 ) *pod {
 	podContainer := cproto.Container{
 		Parent: msg.TaskActor.Address(),
@@ -109,6 +112,11 @@ func newPod(
 	// As soon as one or more of them exits outs, the pod will be terminated.
 	containerNames := map[string]bool{model.DeterminedK8ContainerName: true}
 
+	priority := msg.Priority
+	if priority == nil {
+		priority = msg.Spec.ResourcesConfig.Priority()
+	}
+
 	return &pod{
 		cluster:                  cluster,
 		clusterID:                clusterID,
@@ -133,6 +141,8 @@ func newPod(
 		scheduler:                scheduler,
 		slotType:                 slotType,
 		slotResourceRequests:     slotResourceRequests,
+		resourcePool:             resourcePool,
+		priority:                 priority,
 	}
 }
 
@@ -320,6 +330,7 @@ func (p *pod) deleteKubernetesResources(ctx *actor.Context) {
 	ctx.Log().Infof("requesting to delete kubernetes resources")
 	ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
 		handler:       ctx.Self(),
+		namespace:     p.namespace,
 		podName:       p.podName,
 		configMapName: p.configMapName,
 	})
@@ -443,6 +454,17 @@ func (p *pod) preparePodUpdateMessage(msgText string) string {
 }
 
 func (p *pod) receivePodEventUpdate(ctx *actor.Context, msg podEventUpdate) {
+	if msg.event.Reason == preemptedEventReason {
+		// The Kubernetes scheduler evicts the pod by itself; releasing the resources lets the
+		// allocation preempt the task gracefully, e.g., so that trials checkpoint, in the
+		// meantime.
+		ctx.Log().Infof("pod preempted by kubernetes: %s", msg.event.Message)
+		p.insertLog(ctx, msg.event.CreationTimestamp.Time,
+			fmt.Sprintf("Pod %s: %s", msg.event.InvolvedObject.Name, msg.event.Message))
+		p.taskActor.System().Tell(p.taskActor, sproto.ReleaseResources{})
+		return
+	}
+
 	// We only forward messages while pods are starting up.
 	switch p.container.State {
 	case cproto.Running, cproto.Terminated:
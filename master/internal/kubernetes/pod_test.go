@@ -84,6 +84,7 @@ func createPod(
 		model.LoggingConfig{DefaultLoggingConfig: &model.DefaultLoggingConfig{}},
 		podInterface, configMapInterface, resourceRequestQueue, leaveKubernetesResources,
 		slotType, slotResourceRequests, "default-scheduler",
+		ResourcePoolConfig{PoolName: "default", Namespace: namespace},
 	)
 
 	return newPodHandler
@@ -608,6 +609,38 @@ func TestReceivePodEventUpdate(t *testing.T) {
 	assert.Equal(t, podMap["task"].GetLength(), 0)
 }
 
+func TestReceivePodPreemptedEvent(t *testing.T) {
+	setupEntrypoint(t)
+	defer cleanup(t)
+
+	system, newPod, ref, podMap, _ := createPodWithMockQueue()
+
+	object := k8sV1.ObjectReference{Kind: "Pod", Namespace: "test", Name: newPod.podName}
+	newEvent := k8sV1.Event{
+		InvolvedObject: object,
+		Reason:         preemptedEventReason,
+		Message:        "Preempted by test/high-priority-pod on node node-1",
+	}
+
+	// Preemptions are forwarded to the task even once the pod is running.
+	newPod.container.State = cproto.Running
+	podMap["task"].Purge()
+	system.Ask(ref, podEventUpdate{event: &newEvent})
+	time.Sleep(time.Second)
+
+	assert.Equal(t, podMap["task"].GetLength(), 2)
+	message, err := podMap["task"].Pop()
+	assert.NilError(t, err)
+	containerMsg, ok := message.(sproto.ContainerLog)
+	assert.Assert(t, ok, "expected sproto.ContainerLog but received %s", reflect.TypeOf(message))
+	assert.Equal(t, *containerMsg.AuxMessage,
+		fmt.Sprintf("Pod %s: %s", object.Name, newEvent.Message))
+
+	message, err = podMap["task"].Pop()
+	assert.NilError(t, err)
+	assert.Equal(t, message, sproto.ReleaseResources{})
+}
+
 func TestReceiveContainerLog(t *testing.T) {
 	setupEntrypoint(t)
 	defer cleanup(t)
@@ -661,6 +694,7 @@ func TestKillTaskPod(t *testing.T) {
 	}
 	assert.Equal(t, message, deleteKubernetesResources{
 		handler:       ref,
+		namespace:     newPod.namespace,
 		podName:       newPod.podName,
 		configMapName: newPod.configMapName,
 	},
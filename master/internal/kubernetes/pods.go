@@ -50,28 +50,30 @@ type pods struct {
 	scheduler                string
 	slotType                 device.Type
 	slotResourceRequests     PodSlotResourceRequests
+	resourcePools            []ResourcePoolConfig
 
-	clientSet        *k8sClient.Clientset
+	clientSet        k8sClient.Interface
 	masterIP         string
 	masterPort       int32
 	masterTLSConfig  model.TLSClientConfig
 	loggingTLSConfig model.TLSClientConfig
 	loggingConfig    model.LoggingConfig
 
-	informer                     *actor.Ref
+	informers                    map[string]*actor.Ref
 	nodeInformer                 *actor.Ref
-	eventListener                *actor.Ref
-	preemptionListener           *actor.Ref
+	eventListeners               map[string]*actor.Ref
+	preemptionListeners          map[string]*actor.Ref
 	resourceRequestQueue         *actor.Ref
 	podNameToPodHandler          map[string]*actor.Ref
 	containerIDToPodHandler      map[string]*actor.Ref
 	podHandlerToMetadata         map[*actor.Ref]podMetadata
 	nodeToSystemResourceRequests map[string]int64
 
-	currentNodes map[string]*k8sV1.Node
+	currentNodes   map[string]*k8sV1.Node
+	poolCapacities *poolCapacityCache
 
-	podInterface       typedV1.PodInterface
-	configMapInterface typedV1.ConfigMapInterface
+	podInterfaces       map[string]typedV1.PodInterface
+	configMapInterfaces map[string]typedV1.ConfigMapInterface
 }
 
 // Initialize creates a new global agent actor.
@@ -87,6 +89,7 @@ func Initialize(
 	scheduler string,
 	slotType device.Type,
 	slotResourceRequests PodSlotResourceRequests,
+	resourcePools []ResourcePoolConfig,
 ) *actor.Ref {
 	loggingTLSConfig := masterTLSConfig
 	if loggingConfig.ElasticLoggingConfig != nil {
@@ -107,7 +110,14 @@ func Initialize(
 		leaveKubernetesResources:     leaveKubernetesResources,
 		slotType:                     slotType,
 		slotResourceRequests:         slotResourceRequests,
+		resourcePools:                resourcePools,
+		informers:                    make(map[string]*actor.Ref),
+		eventListeners:               make(map[string]*actor.Ref),
+		preemptionListeners:          make(map[string]*actor.Ref),
+		podInterfaces:                make(map[string]typedV1.PodInterface),
+		configMapInterfaces:          make(map[string]typedV1.ConfigMapInterface),
 		currentNodes:                 make(map[string]*k8sV1.Node),
+		poolCapacities:               newPoolCapacityCache(),
 		nodeToSystemResourceRequests: make(map[string]int64),
 	})
 	check.Panic(check.True(ok, "pods address already taken"))
@@ -127,6 +137,9 @@ func (p *pods) Receive(ctx *actor.Context) error {
 		if err := p.getMasterIPAndPort(ctx); err != nil {
 			return err
 		}
+		if err := p.syncPriorityClasses(); err != nil {
+			return err
+		}
 		if err := p.getSystemResourceRequests(ctx); err != nil {
 			return err
 		}
@@ -158,6 +171,12 @@ func (p *pods) Receive(ctx *actor.Context) error {
 	case podPreemption:
 		p.receivePodPreemption(ctx, msg)
 
+	case SummarizeResourcePools:
+		ctx.Respond(p.summarizeResourcePools())
+
+	case GetPoolCapacity:
+		p.receiveGetPoolCapacity(ctx, msg)
+
 	case sproto.KillTaskPod:
 		p.receiveKillPod(ctx, msg)
 
@@ -173,17 +192,20 @@ func (p *pods) Receive(ctx *actor.Context) error {
 
 	case actor.ChildFailed:
 		switch msg.Child {
-		case p.informer:
-			return errors.Errorf("pod informer failed")
 		case p.nodeInformer:
 			return errors.Errorf("node informer failed")
-		case p.eventListener:
-			return errors.Errorf("event listener failed")
-		case p.preemptionListener:
-			return errors.Errorf("preemption listener failed")
 		case p.resourceRequestQueue:
 			return errors.Errorf("resource request actor failed")
 		}
+		if namespace, ok := namespaceOf(p.informers, msg.Child); ok {
+			return errors.Errorf("pod informer for namespace %s failed", namespace)
+		}
+		if namespace, ok := namespaceOf(p.eventListeners, msg.Child); ok {
+			return errors.Errorf("event listener for namespace %s failed", namespace)
+		}
+		if namespace, ok := namespaceOf(p.preemptionListeners, msg.Child); ok {
+			return errors.Errorf("preemption listener for namespace %s failed", namespace)
+		}
 
 		if err := p.cleanUpPodHandler(ctx, msg.Child); err != nil {
 			return err
@@ -213,8 +235,10 @@ func (p *pods) startClientSet(ctx *actor.Context) error {
 		return errors.Wrap(err, "failed to initialize kubernetes clientSet")
 	}
 
-	p.podInterface = p.clientSet.CoreV1().Pods(p.namespace)
-	p.configMapInterface = p.clientSet.CoreV1().ConfigMaps(p.namespace)
+	for _, namespace := range p.namespaces() {
+		p.podInterfaces[namespace] = p.clientSet.CoreV1().Pods(namespace)
+		p.configMapInterfaces[namespace] = p.clientSet.CoreV1().ConfigMaps(namespace)
+	}
 
 	ctx.Log().Infof("kubernetes clientSet initialized")
 	return nil
@@ -233,8 +257,16 @@ func (p *pods) getMasterIPAndPort(ctx *actor.Context) error {
 	return nil
 }
 
+func (p *pods) syncPriorityClasses() error {
+	classes, err := PriorityClasses(p.resourcePools)
+	if err != nil {
+		return err
+	}
+	return syncPriorityClasses(p.clientSet, classes)
+}
+
 func (p *pods) getSystemResourceRequests(ctx *actor.Context) error {
-	systemPods, err := p.podInterface.List(
+	systemPods, err := p.podInterfaces[p.namespace].List(
 		metaV1.ListOptions{LabelSelector: determinedSystemLabel})
 	if err != nil {
 		return errors.Wrap(err, "failed to get system pods")
@@ -250,39 +282,69 @@ func (p *pods) getSystemResourceRequests(ctx *actor.Context) error {
 }
 
 func (p *pods) deleteExistingKubernetesResources(ctx *actor.Context) error {
+	for _, namespace := range p.namespaces() {
+		if err := p.deleteExistingKubernetesResourcesInNamespace(ctx, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *pods) deleteExistingKubernetesResourcesInNamespace(
+	ctx *actor.Context, namespace string,
+) error {
 	listOptions := metaV1.ListOptions{LabelSelector: determinedLabel}
 
-	configMaps, err := p.configMapInterface.List(listOptions)
+	configMaps, err := p.configMapInterfaces[namespace].List(listOptions)
 	if err != nil {
 		return errors.Wrap(err, "error listing existing config maps")
 	}
 	for _, configMap := range configMaps.Items {
-		if configMap.Namespace != p.namespace {
+		if configMap.Namespace != namespace {
 			continue
 		}
 
 		ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
-			handler: ctx.Self(), configMapName: configMap.Name})
+			handler: ctx.Self(), namespace: namespace, configMapName: configMap.Name})
 	}
 
-	pods, err := p.podInterface.List(listOptions)
+	pods, err := p.podInterfaces[namespace].List(listOptions)
 	if err != nil {
 		return errors.Wrap(err, "error listing existing pod")
 	}
 	for _, pod := range pods.Items {
-		if pod.Namespace != p.namespace {
+		if pod.Namespace != namespace {
 			continue
 		}
 
 		ctx.Tell(p.resourceRequestQueue, deleteKubernetesResources{
-			handler: ctx.Self(), podName: pod.Name})
+			handler: ctx.Self(), namespace: namespace, podName: pod.Name})
 	}
 
 	return nil
 }
 
+// namespaces returns the namespace of the master along with every namespace used by a
+// resource pool.
+func (p *pods) namespaces() []string {
+	namespaces := []string{p.namespace}
+	seen := map[string]bool{p.namespace: true}
+	for _, pool := range p.resourcePools {
+		if !seen[pool.Namespace] {
+			seen[pool.Namespace] = true
+			namespaces = append(namespaces, pool.Namespace)
+		}
+	}
+	return namespaces
+}
+
 func (p *pods) startPodInformer(ctx *actor.Context) {
-	p.informer, _ = ctx.ActorOf("pod-informer", newInformer(p.podInterface, p.namespace, ctx.Self()))
+	for _, namespace := range p.namespaces() {
+		p.informers[namespace], _ = ctx.ActorOf(
+			fmt.Sprintf("pod-informer-%s", namespace),
+			newInformer(p.podInterfaces[namespace], namespace, ctx.Self()),
+		)
+	}
 }
 
 func (p *pods) startNodeInformer(ctx *actor.Context) {
@@ -290,28 +352,53 @@ func (p *pods) startNodeInformer(ctx *actor.Context) {
 }
 
 func (p *pods) startEventListener(ctx *actor.Context) {
-	p.eventListener, _ = ctx.ActorOf(
-		"event-listener", newEventListener(p.clientSet, p.namespace, ctx.Self()))
+	for _, namespace := range p.namespaces() {
+		p.eventListeners[namespace], _ = ctx.ActorOf(
+			fmt.Sprintf("event-listener-%s", namespace),
+			newEventListener(p.clientSet, namespace, ctx.Self()),
+		)
+	}
 }
 
 func (p *pods) startPreemptionListener(ctx *actor.Context) {
-	p.preemptionListener, _ = ctx.ActorOf(
-		"preemption-listener", newPreemptionListener(p.clientSet, p.namespace, ctx.Self()))
+	for _, namespace := range p.namespaces() {
+		p.preemptionListeners[namespace], _ = ctx.ActorOf(
+			fmt.Sprintf("preemption-listener-%s", namespace),
+			newPreemptionListener(p.clientSet, namespace, ctx.Self()),
+		)
+	}
 }
 
 func (p *pods) startResourceRequestQueue(ctx *actor.Context) {
 	p.resourceRequestQueue, _ = ctx.ActorOf(
 		"kubernetes-resource-request-queue",
-		newRequestQueue(p.podInterface, p.configMapInterface),
+		newRequestQueue(p.podInterfaces, p.configMapInterfaces),
 	)
 }
 
+func (p *pods) getResourcePool(name string) (ResourcePoolConfig, bool) {
+	for _, pool := range p.resourcePools {
+		if pool.PoolName == name {
+			return pool, true
+		}
+	}
+	return ResourcePoolConfig{}, false
+}
+
 func (p *pods) receiveStartTaskPod(ctx *actor.Context, msg sproto.StartTaskPod) error {
+	pool, ok := p.getResourcePool(msg.ResourcePool)
+	if !ok {
+		ctx.Log().WithField("resource-pool", msg.ResourcePool).Warn(
+			"starting pod for unknown resource pool in the master namespace")
+		pool = ResourcePoolConfig{PoolName: msg.ResourcePool, Namespace: p.namespace}
+	}
+
 	newPodHandler := newPod(
-		msg, p.cluster, msg.Spec.ClusterID, p.clientSet, p.namespace, p.masterIP, p.masterPort,
-		p.masterTLSConfig, p.loggingTLSConfig, p.loggingConfig, p.podInterface, p.configMapInterface,
+		msg, p.cluster, msg.Spec.ClusterID, p.clientSet, pool.Namespace, p.masterIP, p.masterPort,
+		p.masterTLSConfig, p.loggingTLSConfig, p.loggingConfig,
+		p.podInterfaces[pool.Namespace], p.configMapInterfaces[pool.Namespace],
 		p.resourceRequestQueue, p.leaveKubernetesResources,
-		p.slotType, p.slotResourceRequests, p.scheduler,
+		p.slotType, p.slotResourceRequests, p.scheduler, pool,
 	)
 	ref, ok := ctx.ActorOf(fmt.Sprintf("pod-%s", msg.Spec.ContainerID), newPodHandler)
 	if !ok {
@@ -332,6 +419,7 @@ func (p *pods) receiveStartTaskPod(ctx *actor.Context, msg sproto.StartTaskPod)
 		podName:     newPodHandler.podName,
 		containerID: msg.Spec.ContainerID,
 	}
+	p.poolCapacities.invalidate()
 
 	return nil
 }
@@ -344,10 +432,12 @@ func (p *pods) receivePodStatusUpdate(ctx *actor.Context, msg podStatusUpdate) {
 		return
 	}
 
+	p.poolCapacities.invalidate()
 	ctx.Tell(ref, msg)
 }
 
 func (p *pods) receiveNodeStatusUpdate(ctx *actor.Context, msg nodeStatusUpdate) {
+	p.poolCapacities.invalidate()
 	if msg.updatedNode != nil {
 		p.currentNodes[msg.updatedNode.Name] = msg.updatedNode
 	}
@@ -380,6 +470,33 @@ func (p *pods) receivePodPreemption(ctx *actor.Context, msg podPreemption) {
 	ctx.Tell(ref, msg)
 }
 
+func (p *pods) receiveGetPoolCapacity(ctx *actor.Context, msg GetPoolCapacity) {
+	pool, ok := p.getResourcePool(msg.ResourcePool)
+	if !ok {
+		ctx.Respond(errors.Errorf("cannot find resource pool %s", msg.ResourcePool))
+		return
+	}
+
+	if capacity, ok := p.poolCapacities.get(msg.ResourcePool); ok {
+		ctx.Respond(capacity)
+		return
+	}
+
+	podNameToContainerID := make(map[string]string, len(p.podHandlerToMetadata))
+	for _, metadata := range p.podHandlerToMetadata {
+		podNameToContainerID[metadata.podName] = metadata.containerID
+	}
+
+	capacity, err := poolCapacity(
+		p.clientSet, p.currentNodes, pool, p.slotType, p.slotResourceRequests, podNameToContainerID)
+	if err != nil {
+		ctx.Respond(err)
+		return
+	}
+	p.poolCapacities.put(msg.ResourcePool, capacity)
+	ctx.Respond(capacity)
+}
+
 func (p *pods) receiveKillPod(ctx *actor.Context, msg sproto.KillTaskPod) {
 	ref, ok := p.containerIDToPodHandler[string(msg.PodID)]
 	if !ok {
@@ -411,6 +528,7 @@ func (p *pods) cleanUpPodHandler(ctx *actor.Context, podHandler *actor.Ref) erro
 
 	ctx.Log().WithField("pod", podInfo.podName).WithField(
 		"handler", podHandler.Address()).Infof("de-registering pod handler")
+	p.poolCapacities.invalidate()
 	delete(p.podNameToPodHandler, podInfo.podName)
 	delete(p.containerIDToPodHandler, podInfo.containerID)
 	delete(p.podHandlerToMetadata, podHandler)
@@ -461,21 +579,7 @@ func (p *pods) summarize(ctx *actor.Context) map[string]model.AgentSummary {
 
 	summary := make(map[string]model.AgentSummary)
 	for _, node := range p.currentNodes {
-		var numSlots int64
-		var deviceType device.Type
-		switch p.slotType {
-		case device.CPU:
-			resources := node.Status.Allocatable["cpu"]
-			milliCPUs := resources.MilliValue() - p.nodeToSystemResourceRequests[node.Name]
-			numSlots = int64(float32(milliCPUs) / (1000. * p.slotResourceRequests.CPU))
-			deviceType = device.CPU
-		case device.GPU:
-			fallthrough
-		default:
-			resources := node.Status.Allocatable["nvidia.com/gpu"]
-			numSlots = resources.Value()
-			deviceType = device.GPU
-		}
+		numSlots, deviceType := p.nodeSlots(node)
 		if numSlots < 1 {
 			continue
 		}
@@ -517,10 +621,69 @@ func (p *pods) summarize(ctx *actor.Context) map[string]model.AgentSummary {
 			RegisteredTime: node.ObjectMeta.CreationTimestamp.Time,
 			Slots:          slotsSummary,
 			NumContainers:  len(podByNode[node.Name]),
-			ResourcePool:   "",
+			ResourcePool:   p.nodeResourcePool(node),
 			Addresses:      addrs,
 		}
 	}
 
 	return summary
 }
+
+// nodeSlots returns the number of slots the node offers to Determined pods.
+func (p *pods) nodeSlots(node *k8sV1.Node) (int64, device.Type) {
+	switch p.slotType {
+	case device.CPU:
+		resources := node.Status.Allocatable["cpu"]
+		milliCPUs := resources.MilliValue() - p.nodeToSystemResourceRequests[node.Name]
+		return int64(float32(milliCPUs) / (1000. * p.slotResourceRequests.CPU)), device.CPU
+	case device.GPU:
+		fallthrough
+	default:
+		resources := node.Status.Allocatable["nvidia.com/gpu"]
+		return resources.Value(), device.GPU
+	}
+}
+
+// nodeResourcePool returns the first resource pool whose pods can be scheduled on the node.
+func (p *pods) nodeResourcePool(node *k8sV1.Node) string {
+	for _, pool := range p.resourcePools {
+		if pool.matchesNode(node) {
+			return pool.PoolName
+		}
+	}
+	return ""
+}
+
+// summarizeResourcePools computes the capacity of each resource pool from the current nodes.
+// A node that matches several pools contributes its slots to each of them.
+func (p *pods) summarizeResourcePools() map[string]ResourcePoolCapacity {
+	capacities := make(map[string]ResourcePoolCapacity, len(p.resourcePools))
+	for _, pool := range p.resourcePools {
+		var capacity ResourcePoolCapacity
+		for _, node := range p.currentNodes {
+			if node.Spec.Unschedulable || !pool.matchesNode(node) {
+				continue
+			}
+			numSlots, _ := p.nodeSlots(node)
+			if numSlots < 1 {
+				continue
+			}
+			capacity.NumNodes++
+			capacity.Slots += int(numSlots)
+		}
+		if pool.MaxSlots > 0 && capacity.Slots > pool.MaxSlots {
+			capacity.Slots = pool.MaxSlots
+		}
+		capacities[pool.PoolName] = capacity
+	}
+	return capacities
+}
+
+func namespaceOf(refs map[string]*actor.Ref, ref *actor.Ref) (string, bool) {
+	for namespace, candidate := range refs {
+		if candidate == ref {
+			return namespace, true
+		}
+	}
+	return "", false
+}
@@ -22,13 +22,13 @@ type podPreemption struct {
 }
 
 type preemptionListener struct {
-	clientSet   *k8sClient.Clientset
+	clientSet   k8sClient.Interface
 	namespace   string
 	podsHandler *actor.Ref
 }
 
 func newPreemptionListener(
-	clientSet *k8sClient.Clientset,
+	clientSet k8sClient.Interface,
 	namespace string,
 	podsHandler *actor.Ref,
 ) *preemptionListener {
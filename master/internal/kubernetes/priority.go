@@ -0,0 +1,163 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+
+	k8sV1 "k8s.io/api/core/v1"
+	schedulingV1 "k8s.io/api/scheduling/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// determinedManagedLabel marks the Kubernetes objects that the master creates and deletes
+	// on its own, independently of any task.
+	determinedManagedLabel = "determined-managed"
+
+	// preemptedEventReason is the reason of the event the Kubernetes scheduler emits for a pod it
+	// preempts in order to schedule a pod with a higher priority.
+	preemptedEventReason = "Preempted"
+)
+
+// PriorityClassConfig maps a range of Determined priorities onto a Kubernetes PriorityClass that
+// the master creates and manages. Since smaller numbers denote higher Determined priorities,
+// classes covering smaller priorities are typically given larger values.
+type PriorityClassConfig struct {
+	Name             string                 `json:"name"`
+	Value            int32                  `json:"value"`
+	MinPriority      int                    `json:"min_priority"`
+	MaxPriority      int                    `json:"max_priority"`
+	PreemptionPolicy k8sV1.PreemptionPolicy `json:"preemption_policy"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c PriorityClassConfig) Validate() []error {
+	var checkPolicy error
+	switch c.PreemptionPolicy {
+	case "", k8sV1.PreemptLowerPriority, k8sV1.PreemptNever:
+	default:
+		checkPolicy = errors.Errorf(
+			"preemption_policy must be either %s or %s", k8sV1.PreemptLowerPriority, k8sV1.PreemptNever)
+	}
+	return []error{
+		check.True(len(c.Name) != 0, "priority class name cannot be empty"),
+		check.LessThanOrEqualTo(c.MinPriority, c.MaxPriority,
+			"min_priority must be <= max_priority"),
+		checkPolicy,
+	}
+}
+
+// preemptionPolicy returns the preemption policy of the class, defaulting to preempting pods with
+// a lower priority like Kubernetes does.
+func (c PriorityClassConfig) preemptionPolicy() k8sV1.PreemptionPolicy {
+	if c.PreemptionPolicy == "" {
+		return k8sV1.PreemptLowerPriority
+	}
+	return c.PreemptionPolicy
+}
+
+func (c PriorityClassConfig) matches(other PriorityClassConfig) bool {
+	return c.Value == other.Value && c.preemptionPolicy() == other.preemptionPolicy()
+}
+
+// priorityClass returns the name of the PriorityClass that pods of tasks with the given
+// Determined priority are assigned in the resource pool.
+func (r ResourcePoolConfig) priorityClass(priority int) (string, bool) {
+	for _, class := range r.PriorityClasses {
+		if class.MinPriority <= priority && priority <= class.MaxPriority {
+			return class.Name, true
+		}
+	}
+	return "", false
+}
+
+// PriorityClasses returns the PriorityClasses configured across the resource pools. A class
+// may be shared by several pools as long as it is configured identically in all of them.
+func PriorityClasses(pools []ResourcePoolConfig) ([]PriorityClassConfig, error) {
+	var classes []PriorityClassConfig
+	byName := make(map[string]PriorityClassConfig)
+	for _, pool := range pools {
+		for _, class := range pool.PriorityClasses {
+			existing, ok := byName[class.Name]
+			switch {
+			case !ok:
+				byName[class.Name] = class
+				classes = append(classes, class)
+			case !existing.matches(class):
+				return nil, errors.Errorf(
+					"priority class %s is configured with different values in resource pools",
+					class.Name)
+			}
+		}
+	}
+	return classes, nil
+}
+
+// syncPriorityClasses makes the PriorityClasses managed by the master match the configured ones.
+// The value and preemption policy of a PriorityClass cannot be updated, so classes that changed
+// are deleted and created again; pods that already use them are not affected. Classes that are
+// no longer configured are deleted.
+func syncPriorityClasses(clientSet k8sClient.Interface, classes []PriorityClassConfig) error {
+	client := clientSet.SchedulingV1().PriorityClasses()
+
+	managed, err := client.List(metaV1.ListOptions{LabelSelector: determinedManagedLabel})
+	if err != nil {
+		return errors.Wrap(err, "error listing priority classes")
+	}
+	existing := make(map[string]schedulingV1.PriorityClass)
+	for _, class := range managed.Items {
+		existing[class.Name] = class
+	}
+
+	configured := make(map[string]bool)
+	for _, class := range classes {
+		configured[class.Name] = true
+		policy := class.preemptionPolicy()
+
+		if current, ok := existing[class.Name]; ok {
+			if current.Value == class.Value &&
+				current.PreemptionPolicy != nil && *current.PreemptionPolicy == policy {
+				continue
+			}
+			if err := client.Delete(class.Name, &metaV1.DeleteOptions{}); err != nil {
+				return errors.Wrapf(err, "error deleting outdated priority class %s", class.Name)
+			}
+		}
+
+		_, err := client.Create(&schedulingV1.PriorityClass{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:   class.Name,
+				Labels: map[string]string{determinedManagedLabel: "true"},
+			},
+			Value:            class.Value,
+			PreemptionPolicy: &policy,
+			Description: fmt.Sprintf(
+				"Determined priorities %d to %d", class.MinPriority, class.MaxPriority),
+		})
+		switch {
+		case k8sErrors.IsAlreadyExists(err):
+			return errors.Errorf(
+				"priority class %s already exists and is not managed by Determined", class.Name)
+		case err != nil:
+			return errors.Wrapf(err, "error creating priority class %s", class.Name)
+		}
+		log.Infof("created priority class %s with value %d", class.Name, class.Value)
+	}
+
+	for name := range existing {
+		if configured[name] {
+			continue
+		}
+		if err := client.Delete(name, &metaV1.DeleteOptions{}); err != nil {
+			return errors.Wrapf(err, "error deleting priority class %s", name)
+		}
+		log.Infof("deleted priority class %s", name)
+	}
+	return nil
+}
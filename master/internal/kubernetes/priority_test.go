@@ -0,0 +1,105 @@
+package kubernetes
+
+import (
+	"sort"
+	"testing"
+
+	"gotest.tools/assert"
+
+	k8sV1 "k8s.io/api/core/v1"
+	schedulingV1 "k8s.io/api/scheduling/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResourcePoolPriorityClass(t *testing.T) {
+	pool := ResourcePoolConfig{
+		PoolName: "default",
+		PriorityClasses: []PriorityClassConfig{
+			{Name: "determined-high", Value: 1000, MinPriority: 1, MaxPriority: 20},
+			{Name: "determined-low", Value: 10, MinPriority: 60, MaxPriority: 99},
+		},
+	}
+
+	name, ok := pool.priorityClass(1)
+	assert.Assert(t, ok)
+	assert.Equal(t, name, "determined-high")
+
+	name, ok = pool.priorityClass(99)
+	assert.Assert(t, ok)
+	assert.Equal(t, name, "determined-low")
+
+	_, ok = pool.priorityClass(42)
+	assert.Assert(t, !ok)
+
+	pool.PriorityClasses = append(pool.PriorityClasses,
+		PriorityClassConfig{Name: "determined-medium", MinPriority: 20, MaxPriority: 59})
+	assert.Equal(t, len(pool.Validate()), 3)
+	assert.ErrorContains(t, pool.Validate()[2], "overlapping priorities")
+}
+
+func TestPriorityClassesConflict(t *testing.T) {
+	pools := []ResourcePoolConfig{
+		{PoolName: "a", PriorityClasses: []PriorityClassConfig{{Name: "high", Value: 1000}}},
+		{PoolName: "b", PriorityClasses: []PriorityClassConfig{
+			{Name: "high", Value: 1000, PreemptionPolicy: k8sV1.PreemptLowerPriority},
+		}},
+	}
+	classes, err := PriorityClasses(pools)
+	assert.NilError(t, err)
+	assert.Equal(t, len(classes), 1)
+
+	pools[1].PriorityClasses[0].Value = 10
+	_, err = PriorityClasses(pools)
+	assert.ErrorContains(t, err, "configured with different values")
+}
+
+func TestSyncPriorityClasses(t *testing.T) {
+	managed := map[string]string{determinedManagedLabel: "true"}
+	never := k8sV1.PreemptNever
+	clientSet := fake.NewSimpleClientset(
+		&schedulingV1.PriorityClass{
+			ObjectMeta:       metaV1.ObjectMeta{Name: "unchanged", Labels: managed},
+			Value:            100,
+			PreemptionPolicy: &never,
+		},
+		&schedulingV1.PriorityClass{
+			ObjectMeta: metaV1.ObjectMeta{Name: "changed", Labels: managed},
+			Value:      10,
+		},
+		&schedulingV1.PriorityClass{
+			ObjectMeta: metaV1.ObjectMeta{Name: "stale", Labels: managed},
+			Value:      1,
+		},
+		&schedulingV1.PriorityClass{
+			ObjectMeta: metaV1.ObjectMeta{Name: "system-cluster-critical"},
+			Value:      2000000000,
+		},
+	)
+
+	err := syncPriorityClasses(clientSet, []PriorityClassConfig{
+		{Name: "unchanged", Value: 100, PreemptionPolicy: k8sV1.PreemptNever},
+		{Name: "changed", Value: 20},
+		{Name: "new", Value: 30},
+	})
+	assert.NilError(t, err)
+
+	classes, err := clientSet.SchedulingV1().PriorityClasses().List(metaV1.ListOptions{})
+	assert.NilError(t, err)
+	values := make(map[string]int32)
+	var names []string
+	for _, class := range classes.Items {
+		values[class.Name] = class.Value
+		names = append(names, class.Name)
+	}
+	sort.Strings(names)
+	assert.DeepEqual(t, names, []string{"changed", "new", "system-cluster-critical", "unchanged"})
+	assert.Equal(t, values["changed"], int32(20))
+	assert.Equal(t, values["new"], int32(30))
+
+	// Classes that are not managed by the master are never taken over.
+	err = syncPriorityClasses(clientSet, []PriorityClassConfig{
+		{Name: "system-cluster-critical", Value: 10},
+	})
+	assert.ErrorContains(t, err, "not managed by Determined")
+}
@@ -8,8 +8,8 @@ import (
 )
 
 type requestProcessingWorker struct {
-	podInterface       typedV1.PodInterface
-	configMapInterface typedV1.ConfigMapInterface
+	podInterfaces       map[string]typedV1.PodInterface
+	configMapInterfaces map[string]typedV1.ConfigMapInterface
 }
 
 func (r *requestProcessingWorker) Receive(ctx *actor.Context) error {
@@ -39,7 +39,8 @@ func (r *requestProcessingWorker) receiveCreateKubernetesResources(
 	ctx *actor.Context,
 	msg createKubernetesResources,
 ) {
-	configMap, err := r.configMapInterface.Create(msg.configMapSpec)
+	namespace := msg.podSpec.Namespace
+	configMap, err := r.configMapInterfaces[namespace].Create(msg.configMapSpec)
 	if err != nil {
 		ctx.Log().WithField("handler", msg.handler.Address()).WithError(err).Errorf(
 			"error creating configMap %s", msg.configMapSpec.Name)
@@ -50,7 +51,7 @@ func (r *requestProcessingWorker) receiveCreateKubernetesResources(
 		"created configMap %s", configMap.Name)
 
 	ctx.Log().Debugf("launching pod with spec %v", msg.podSpec)
-	pod, err := r.podInterface.Create(msg.podSpec)
+	pod, err := r.podInterfaces[namespace].Create(msg.podSpec)
 	if err != nil {
 		ctx.Log().WithField("handler", msg.handler.Address()).WithError(err).Errorf(
 			"error creating pod %s", msg.podSpec.Name)
@@ -70,7 +71,8 @@ func (r *requestProcessingWorker) receiveDeleteKubernetesResources(
 	// If resource creation failed, we will still try to delete those resources which
 	// will also result in a failure.
 	if len(msg.podName) > 0 {
-		err = r.podInterface.Delete(msg.podName, &metaV1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+		err = r.podInterfaces[msg.namespace].Delete(
+			msg.podName, &metaV1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
 		if err != nil {
 			ctx.Log().WithField("handler", msg.handler.Address()).WithError(err).Errorf(
 				"failed to delete pod %s", msg.podName)
@@ -81,8 +83,8 @@ func (r *requestProcessingWorker) receiveDeleteKubernetesResources(
 	}
 
 	if len(msg.configMapName) > 0 {
-		errDeletingConfigMap := r.configMapInterface.Delete(msg.configMapName, &metaV1.DeleteOptions{
-			GracePeriodSeconds: &gracePeriod})
+		errDeletingConfigMap := r.configMapInterfaces[msg.namespace].Delete(
+			msg.configMapName, &metaV1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
 		if errDeletingConfigMap != nil {
 			ctx.Log().WithField("handler", msg.handler.Address()).WithError(err).Errorf(
 				"failed to delete configMap %s", msg.configMapName)
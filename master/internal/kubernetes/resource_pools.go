@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+
+	k8sV1 "k8s.io/api/core/v1"
+)
+
+// ResourcePoolConfig describes a named subset of a Kubernetes cluster that tasks can be
+// scheduled onto. Pods for a pool are created in its namespace and are restricted to the nodes
+// selected by its node selector and tolerations.
+type ResourcePoolConfig struct {
+	PoolName      string             `json:"pool_name"`
+	Description   string             `json:"description"`
+	Namespace     string             `json:"namespace"`
+	NodeSelector  map[string]string  `json:"node_selector"`
+	Tolerations   []k8sV1.Toleration `json:"tolerations"`
+	MaxSlots      int                `json:"max_slots"`
+	PriorityClass string             `json:"priority_class"`
+
+	PriorityClasses []PriorityClassConfig `json:"priority_classes"`
+}
+
+// Validate implements the check.Validatable interface.
+func (r ResourcePoolConfig) Validate() []error {
+	errs := []error{
+		check.True(len(r.PoolName) != 0, "resource pool name cannot be empty"),
+		check.GreaterThanOrEqualTo(r.MaxSlots, 0, "max_slots must be >= 0"),
+	}
+	for i, a := range r.PriorityClasses {
+		for _, b := range r.PriorityClasses[i+1:] {
+			if a.MinPriority <= b.MaxPriority && b.MinPriority <= a.MaxPriority {
+				errs = append(errs, errors.Errorf(
+					"priority classes %s and %s have overlapping priorities", a.Name, b.Name))
+			}
+		}
+	}
+	return errs
+}
+
+// ResourcePoolCapacity summarizes the nodes that are able to run the pods of a resource pool.
+type ResourcePoolCapacity struct {
+	NumNodes int
+	Slots    int
+}
+
+// SummarizeResourcePools asks the pods actor for the capacity of each configured resource pool,
+// as seen by the node informer. The response is a map[string]ResourcePoolCapacity.
+type SummarizeResourcePools struct{}
+
+// matchesNode returns whether pods of the resource pool could be scheduled on the node.
+func (r ResourcePoolConfig) matchesNode(node *k8sV1.Node) bool {
+	for key, value := range r.NodeSelector {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+
+	for i := range node.Spec.Taints {
+		taint := node.Spec.Taints[i]
+		if taint.Effect != k8sV1.TaintEffectNoSchedule && taint.Effect != k8sV1.TaintEffectNoExecute {
+			continue
+		}
+		tolerated := false
+		for j := range r.Tolerations {
+			if r.Tolerations[j].ToleratesTaint(&taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// configurePod restricts the pod to the nodes of the resource pool. Node selector entries of the
+// pool take precedence over the ones in the user provided pod spec.
+func (r ResourcePoolConfig) configurePod(pod *k8sV1.Pod) {
+	if len(r.NodeSelector) > 0 && pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = make(map[string]string)
+	}
+	for key, value := range r.NodeSelector {
+		pod.Spec.NodeSelector[key] = value
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, r.Tolerations...)
+}
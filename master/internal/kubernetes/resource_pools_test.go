@@ -0,0 +1,61 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	k8sV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResourcePoolMatchesNode(t *testing.T) {
+	pool := ResourcePoolConfig{
+		PoolName:     "a100",
+		NodeSelector: map[string]string{"gpu": "a100"},
+		Tolerations: []k8sV1.Toleration{{
+			Key:      "dedicated",
+			Operator: k8sV1.TolerationOpEqual,
+			Value:    "ml",
+			Effect:   k8sV1.TaintEffectNoSchedule,
+		}},
+	}
+
+	node := &k8sV1.Node{ObjectMeta: metaV1.ObjectMeta{Labels: map[string]string{"gpu": "a100"}}}
+	assert.Assert(t, pool.matchesNode(node))
+
+	node.Labels["gpu"] = "t4"
+	assert.Assert(t, !pool.matchesNode(node))
+
+	node.Labels["gpu"] = "a100"
+	node.Spec.Taints = []k8sV1.Taint{
+		{Key: "dedicated", Value: "ml", Effect: k8sV1.TaintEffectNoSchedule},
+		{Key: "flaky", Effect: k8sV1.TaintEffectPreferNoSchedule},
+	}
+	assert.Assert(t, pool.matchesNode(node))
+
+	node.Spec.Taints = append(node.Spec.Taints, k8sV1.Taint{
+		Key: "maintenance", Effect: k8sV1.TaintEffectNoExecute,
+	})
+	assert.Assert(t, !pool.matchesNode(node))
+}
+
+func TestResourcePoolConfigurePod(t *testing.T) {
+	pool := ResourcePoolConfig{
+		PoolName:     "t4",
+		NodeSelector: map[string]string{"gpu": "t4"},
+		Tolerations:  []k8sV1.Toleration{{Key: "dedicated", Operator: k8sV1.TolerationOpExists}},
+	}
+
+	pod := &k8sV1.Pod{}
+	pod.Spec.NodeSelector = map[string]string{"gpu": "a100", "zone": "us-east-1a"}
+	pod.Spec.Tolerations = []k8sV1.Toleration{{Key: "spot", Operator: k8sV1.TolerationOpExists}}
+	pool.configurePod(pod)
+
+	assert.DeepEqual(t, pod.Spec.NodeSelector, map[string]string{"gpu": "t4", "zone": "us-east-1a"})
+	assert.Equal(t, len(pod.Spec.Tolerations), 2)
+
+	pod = &k8sV1.Pod{}
+	pool.configurePod(pod)
+	assert.DeepEqual(t, pod.Spec.NodeSelector, map[string]string{"gpu": "t4"})
+}
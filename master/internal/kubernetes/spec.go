@@ -219,8 +219,18 @@ func (p *pod) modifyPodSpec(newPod *k8sV1.Pod, scheduler string) {
 		p.configureCoscheduler(newPod, scheduler)
 	}
 
-	if newPod.Spec.PriorityClassName == "" && p.taskSpec.ResourcesConfig.Priority() != nil {
-		priority := int32(*p.taskSpec.ResourcesConfig.Priority())
+	if newPod.Spec.PriorityClassName == "" && p.priority != nil {
+		if name, ok := p.resourcePool.priorityClass(*p.priority); ok {
+			newPod.Spec.PriorityClassName = name
+		}
+	}
+
+	if newPod.Spec.PriorityClassName == "" && p.resourcePool.PriorityClass != "" {
+		newPod.Spec.PriorityClassName = p.resourcePool.PriorityClass
+	}
+
+	if newPod.Spec.PriorityClassName == "" && p.priority != nil {
+		priority := int32(*p.priority)
 		name := fmt.Sprintf("%s-priorityclass", p.taskSpec.ContainerID)
 
 		err := p.createPriorityClass(name, priority)
@@ -305,6 +315,7 @@ func (p *pod) configurePodSpec(
 	}
 	podSpec.ObjectMeta.Labels[determinedLabel] = p.taskSpec.AllocationID
 
+	p.resourcePool.configurePod(podSpec)
 	p.modifyPodSpec(podSpec, scheduler)
 
 	nonDeterminedContainers := make([]k8sV1.Container, 0)
@@ -0,0 +1,42 @@
+package leader
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// DefaultLockID is the key of the Postgres advisory lock held by the leader by default.
+const DefaultLockID = 0x6465746d // "detm"
+
+// Config configures electing a leader among masters that share a database.
+type Config struct {
+	// LockID is the key of the Postgres advisory lock held by the leader. Masters of different
+	// clusters sharing a Postgres server must use different keys.
+	LockID int64 `json:"lock_id"`
+	// CheckInterval is how often a standby tries to acquire the lock and how often the leader
+	// checks that it still holds it.
+	CheckInterval model.Duration `json:"check_interval"`
+}
+
+var defaultConfig = Config{
+	LockID:        DefaultLockID,
+	CheckInterval: model.Duration(5 * time.Second),
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	*c = defaultConfig
+	type DefaultParser *Config
+	return json.Unmarshal(data, DefaultParser(c))
+}
+
+// Validate implements the check.Validatable interface.
+func (c Config) Validate() []error {
+	return []error{
+		check.GreaterThan(int64(c.CheckInterval), int64(0),
+			"high availability check_interval must be greater than 0"),
+	}
+}
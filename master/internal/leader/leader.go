@@ -0,0 +1,116 @@
+// Package leader elects a leader among masters that share a database. The leader holds a Postgres
+// advisory lock; standbys poll for it and take over when the session of the leader ends.
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// Leadership is held by the master that acquired the leader lock, until it resigns or the lock is
+// lost.
+type Leadership struct {
+	db   *db.PgDB
+	lock *db.AdvisoryLock
+	lost chan error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	resign sync.Once
+}
+
+// Campaign blocks until this master becomes the leader or the context is canceled. The lock is held
+// on a connection of its own, so that it is not released by migrations or other queries.
+func Campaign(ctx context.Context, dbConfig *db.Config, config Config) (*Leadership, error) {
+	pgDB, err := db.Connect(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(config.CheckInterval)
+	waiting := false
+	for {
+		lock, err := pgDB.TryAdvisoryLock(ctx, config.LockID)
+		switch {
+		case err != nil:
+			log.WithError(err).Warnf("failed to campaign for leadership, retrying in %s", interval)
+		case lock != nil:
+			log.Infof("acquired leader lock %d; this master is the leader", config.LockID)
+			return newLeadership(pgDB, lock, interval), nil
+		case !waiting:
+			log.Infof("another master holds leader lock %d; waiting as a standby", config.LockID)
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			if cErr := pgDB.Close(); cErr != nil {
+				log.WithError(cErr).Error("error closing leader election database connection")
+			}
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func newLeadership(pgDB *db.PgDB, lock *db.AdvisoryLock, interval time.Duration) *Leadership {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &Leadership{
+		db:     pgDB,
+		lock:   lock,
+		lost:   make(chan error, 1),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go l.monitor(ctx, interval)
+	return l
+}
+
+// monitor checks that the lock is still held every interval. If the session holding it ends,
+// Postgres releases the lock and a standby may take over, so the leader must step down.
+func (l *Leadership) monitor(ctx context.Context, interval time.Duration) {
+	defer close(l.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		err := l.lock.Check(checkCtx)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			l.lost <- errors.Wrap(err, "lost leadership")
+			return
+		}
+	}
+}
+
+// Lost returns a channel that receives an error if this master stops being the leader.
+func (l *Leadership) Lost() <-chan error {
+	return l.lost
+}
+
+// Resign releases the lock so that a standby can take over.
+func (l *Leadership) Resign() {
+	l.resign.Do(func() {
+		l.cancel()
+		<-l.done
+		if err := l.lock.Release(); err != nil {
+			log.WithError(err).Error("error releasing leader lock")
+		}
+		if err := l.db.Close(); err != nil {
+			log.WithError(err).Error("error closing leader election database connection")
+		}
+		log.Info("resigned leadership")
+	})
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/metricexport"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// maxExportedTrials bounds the number of trials whose labels are cached by the metric exporter.
+const maxExportedTrials = 10000
+
+// metricExporter forwards the metrics that trials report to the configured metrics backend,
+// labeled with the experiment, trial, owner and resource pool of the trial. A nil metricExporter
+// exports nothing.
+type metricExporter struct {
+	db       *db.PgDB
+	exporter *metricexport.Exporter
+
+	mu     sync.Mutex
+	labels map[int]map[string]string
+}
+
+func newMetricExporter(db *db.PgDB, config *metricexport.Config) (*metricExporter, error) {
+	if config == nil {
+		return nil, nil
+	}
+	exporter, err := metricexport.New(*config)
+	if err != nil {
+		return nil, err
+	}
+	return &metricExporter{db: db, exporter: exporter, labels: map[int]map[string]string{}}, nil
+}
+
+func (e *metricExporter) close() {
+	if e != nil {
+		e.exporter.Close()
+	}
+}
+
+// trialLabels returns the labels of the metrics of a trial, which are shared by every sample; they
+// must not be modified. The lock is not held while the labels are loaded, so trials whose labels
+// are cached are not held up by the database; concurrent reports of a new trial may load them twice.
+func (e *metricExporter) trialLabels(trialID int) (map[string]string, error) {
+	e.mu.Lock()
+	labels, ok := e.labels[trialID]
+	e.mu.Unlock()
+	if ok {
+		return labels, nil
+	}
+
+	exp, err := e.db.ExperimentByTrialID(trialID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading experiment of trial %d", trialID)
+	}
+	labels = map[string]string{
+		"experiment_id": strconv.Itoa(exp.ID),
+		"trial_id":      strconv.Itoa(trialID),
+		"resource_pool": exp.Config.Resources().ResourcePool(),
+	}
+	if exp.OwnerID != nil {
+		owner, err := e.db.UserByID(*exp.OwnerID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading owner of experiment %d", exp.ID)
+		}
+		labels["user"] = owner.Username
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.labels) >= maxExportedTrials {
+		e.labels = map[int]map[string]string{}
+	}
+	e.labels[trialID] = labels
+	return labels, nil
+}
+
+// exportTrialMetrics exports the averaged metrics of a training or validation report, with the
+// given kind of metric ("training" or "validation") in their names.
+func (e *metricExporter) exportTrialMetrics(kind string, m *trialv1.TrialMetrics) {
+	if e == nil {
+		return
+	}
+	labels, err := e.trialLabels(int(m.TrialId))
+	if err != nil {
+		log.WithError(err).Warn("failed to export trial metrics")
+		return
+	}
+
+	now := time.Now()
+	samples := []metricexport.Sample{{
+		Name:   "determined_" + kind + "_batches",
+		Labels: labels,
+		Value:  float64(m.LatestBatch),
+		Time:   now,
+	}}
+	for name, value := range m.Metrics.GetFields() {
+		number, ok := value.GetKind().(*structpb.Value_NumberValue)
+		if !ok {
+			continue
+		}
+		samples = append(samples, metricexport.Sample{
+			Name:   "determined_" + kind + "_" + name,
+			Labels: labels,
+			Value:  number.NumberValue,
+			Time:   now,
+		})
+	}
+	e.exporter.Export(samples...)
+}
+
+// exportProfilerMetrics exports a batch of profiler metrics.
+func (e *metricExporter) exportProfilerMetrics(batch *trialv1.TrialProfilerMetricsBatch) {
+	if e == nil {
+		return
+	}
+	trialLabels, err := e.trialLabels(int(batch.Labels.TrialId))
+	if err != nil {
+		log.WithError(err).Warn("failed to export profiler metrics")
+		return
+	}
+
+	metricType := strings.TrimPrefix(batch.Labels.MetricType.String(), "PROFILER_METRIC_TYPE_")
+	labels := map[string]string{
+		"agent_id":    batch.Labels.AgentId,
+		"gpu_uuid":    batch.Labels.GpuUuid,
+		"metric_type": strings.ToLower(metricType),
+	}
+	for k, v := range trialLabels {
+		labels[k] = v
+	}
+	samples := make([]metricexport.Sample, 0, len(batch.Values))
+	for i, value := range batch.Values {
+		samples = append(samples, metricexport.Sample{
+			Name:   "determined_profiler_" + batch.Labels.Name,
+			Labels: labels,
+			Value:  float64(value),
+			Time:   batch.Timestamps[i].AsTime(),
+		})
+	}
+	e.exporter.Export(samples...)
+}
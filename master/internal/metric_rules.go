@@ -0,0 +1,315 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	structpb "github.com/golang/protobuf/ptypes/struct"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// Metric rule conditions, actions and metric types, as named in the experiment config.
+const (
+	metricRuleNaN           = "nan"
+	metricRuleGreaterThan   = "greater_than"
+	metricRuleLessThan      = "less_than"
+	metricRuleNoImprovement = "no_improvement"
+
+	metricRuleKillTrial       = "kill_trial"
+	metricRulePauseExperiment = "pause_experiment"
+
+	trainingMetricType   = "training"
+	validationMetricType = "validation"
+)
+
+// trialReportMetrics tells an experiment about the training or validation metrics that one of its
+// trials reported, to evaluate its metric rules.
+type trialReportMetrics struct {
+	requestID    model.RequestID
+	trialID      int
+	metricType   string
+	totalBatches int
+	metrics      *structpb.Struct
+}
+
+// metricRuleState is the state of a metric rule for one trial.
+type metricRuleState struct {
+	// met is whether the condition of the rule was met by the last reported value, so that a rule
+	// fires only when its condition becomes met rather than on every report.
+	met bool
+	// best and sinceBest track the best value of the metric and the number of reports since, for
+	// the no_improvement condition.
+	best      *float64
+	sinceBest int
+}
+
+// metricHistory returns the values of a metric of the given type that a trial reported before
+// the given number of batches, in the order they were reported.
+type metricHistory func(trialID int, metricType, metric string, beforeBatches int) ([]float64, error)
+
+// metricRules evaluates the metric rules of an experiment against the metrics that its trials
+// report, keeping the state of every rule per trial.
+type metricRules struct {
+	rules  []expconf.MetricRule
+	trials map[int][]metricRuleState
+}
+
+func newMetricRules(rules []expconf.MetricRule) *metricRules {
+	return &metricRules{rules: rules, trials: map[int][]metricRuleState{}}
+}
+
+// evaluate updates the state of the rules with the metrics reported by a trial and returns the
+// rules that fired, with the values of their metrics.
+func (r *metricRules) evaluate(
+	trialID int, metricType string, metrics *structpb.Struct,
+) (fired []expconf.MetricRule, values []float64) {
+	if len(r.rules) == 0 {
+		return nil, nil
+	}
+	states, ok := r.trials[trialID]
+	if !ok {
+		states = make([]metricRuleState, len(r.rules))
+		r.trials[trialID] = states
+	}
+
+	for i, rule := range r.rules {
+		if rule.MetricType() != metricType {
+			continue
+		}
+		value, ok := metricValue(metrics.GetFields()[rule.Metric()])
+		if !ok {
+			continue
+		}
+		met := states[i].update(rule, value)
+		if met && !states[i].met {
+			fired = append(fired, rule)
+			values = append(values, value)
+		}
+		states[i].met = met
+	}
+	return fired, values
+}
+
+// tracking returns whether the rules have a state for the trial.
+func (r *metricRules) tracking(trialID int) bool {
+	_, ok := r.trials[trialID]
+	return ok
+}
+
+// rebuild restores the state of the rules for a trial from the metrics it reported before the
+// given number of batches, since the state is only kept in memory: after the master restarts,
+// no_improvement keeps counting from where it was, and rules whose condition was already met do
+// not fire again. Rules never fire while their state is rebuilt.
+func (r *metricRules) rebuild(trialID, totalBatches int, history metricHistory) error {
+	states := make([]metricRuleState, len(r.rules))
+	r.trials[trialID] = states
+	for i, rule := range r.rules {
+		values, err := history(trialID, rule.MetricType(), rule.Metric(), totalBatches)
+		if err != nil {
+			return err
+		}
+		for _, value := range values {
+			states[i].met = states[i].update(rule, value)
+		}
+	}
+	return nil
+}
+
+// update records a reported value of the metric of the rule and returns whether it meets the
+// condition of the rule.
+func (s *metricRuleState) update(rule expconf.MetricRule, value float64) bool {
+	switch rule.Condition() {
+	case metricRuleNaN:
+		return math.IsNaN(value) || math.IsInf(value, 0)
+	case metricRuleGreaterThan:
+		return value > *rule.Threshold()
+	case metricRuleLessThan:
+		return value < *rule.Threshold()
+	case metricRuleNoImprovement:
+		improved := s.best == nil ||
+			(rule.SmallerIsBetter() && value < *s.best) ||
+			(!rule.SmallerIsBetter() && value > *s.best)
+		if improved && !math.IsNaN(value) {
+			s.best = ptrs.Float64Ptr(value)
+			s.sinceBest = 0
+			return false
+		}
+		s.sinceBest++
+		return s.sinceBest >= *rule.Patience()
+	default:
+		return false
+	}
+}
+
+// metricValue returns the numeric value of a reported metric. Non-finite values are reported by
+// the harness as the strings "NaN", "Infinity" and "-Infinity".
+func metricValue(v *structpb.Value) (float64, bool) {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_NumberValue:
+		return kind.NumberValue, true
+	case *structpb.Value_StringValue:
+		switch kind.StringValue {
+		case "NaN":
+			return math.NaN(), true
+		case "Infinity":
+			return math.Inf(1), true
+		case "-Infinity":
+			return math.Inf(-1), true
+		}
+	}
+	return 0, false
+}
+
+// evaluateMetricRules evaluates the metric rules of the experiment against the metrics reported by
+// a trial, and records and acts on the rules that fire.
+func (e *experiment) evaluateMetricRules(ctx *actor.Context, msg trialReportMetrics) {
+	if !e.metricRules.tracking(msg.trialID) {
+		if err := e.metricRules.rebuild(msg.trialID, msg.totalBatches, e.metricHistory); err != nil {
+			ctx.Log().WithError(err).Errorf(
+				"failed to restore the state of the metric rules of trial %d", msg.trialID)
+		}
+	}
+	fired, values := e.metricRules.evaluate(msg.trialID, msg.metricType, msg.metrics)
+	trial := ctx.Child(msg.requestID)
+	for i, rule := range fired {
+		firing := model.MetricRuleFiring{
+			ExperimentID: e.ID,
+			TrialID:      msg.trialID,
+			RuleName:     rule.Name(),
+			MetricName:   rule.Metric(),
+			MetricType:   rule.MetricType(),
+			Condition:    rule.Condition(),
+			Value:        values[i],
+			Action:       rule.Action(),
+			TotalBatches: msg.totalBatches,
+			FiredAt:      time.Now().UTC(),
+		}
+		if err := e.db.AddMetricRuleFiring(&firing); err != nil {
+			ctx.Log().WithError(err).Error("failed to record metric rule firing")
+		}
+
+		message := fmt.Sprintf(
+			"metric rule %q fired on %s metric %s = %v of trial %d after %d batches, action: %s",
+			firing.RuleName, firing.MetricType, firing.MetricName, firing.Value, firing.TrialID,
+			firing.TotalBatches, firing.Action)
+		ctx.Log().Warn(message)
+		if trial != nil {
+			ctx.Tell(trial, model.TrialLog{
+				Log:   ptrs.StringPtr(message),
+				Level: ptrs.StringPtr("WARNING"),
+			})
+		}
+
+		switch rule.Action() {
+		case metricRuleKillTrial:
+			if trial != nil {
+				ctx.Tell(trial, model.StoppingKilledState)
+			}
+		case metricRulePauseExperiment:
+			e.updateState(ctx, model.PausedState)
+		}
+	}
+}
+
+// metricHistory returns the values of a metric that a trial of the experiment reported before the
+// given number of batches.
+func (e *experiment) metricHistory(
+	trialID int, metricType, metric string, beforeBatches int,
+) ([]float64, error) {
+	series := e.db.TrainingMetricsSeries
+	if metricType == validationMetricType {
+		series = e.db.ValidationMetricsSeries
+	}
+	points, _, err := series(int32(trialID), time.Time{}, metric, 0, beforeBatches-1)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, 0, len(points))
+	for _, p := range points {
+		values = append(values, p.Y)
+	}
+	return values, nil
+}
+
+// maxMetricRuleTrials bounds the number of trials whose experiments are cached by the metric rule
+// registry.
+const maxMetricRuleTrials = 10000
+
+// metricRuleTrial identifies the experiment and the request of a trial.
+type metricRuleTrial struct {
+	experimentID int
+	requestID    model.RequestID
+}
+
+// metricRuleRegistry tracks the running experiments that have metric rules, so that the metrics
+// reported by the trials of other experiments are not sent to be evaluated, and caches the
+// experiments of the trials, so that they are looked up once per trial rather than per report.
+type metricRuleRegistry struct {
+	mu          sync.Mutex
+	experiments map[int]bool
+	trials      map[int]metricRuleTrial
+}
+
+func newMetricRuleRegistry() *metricRuleRegistry {
+	return &metricRuleRegistry{experiments: map[int]bool{}, trials: map[int]metricRuleTrial{}}
+}
+
+func (r *metricRuleRegistry) register(experimentID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.experiments[experimentID] = true
+}
+
+func (r *metricRuleRegistry) unregister(experimentID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.experiments, experimentID)
+	for trialID, trial := range r.trials {
+		if trial.experimentID == experimentID {
+			delete(r.trials, trialID)
+		}
+	}
+}
+
+// empty returns whether no running experiment has metric rules.
+func (r *metricRuleRegistry) empty() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.experiments) == 0
+}
+
+// trial returns the experiment and the request of a trial, looking them up with lookup the first
+// time, and whether the experiment has metric rules.
+func (r *metricRuleRegistry) trial(
+	trialID int, lookup func(int) (int, model.RequestID, error),
+) (metricRuleTrial, bool, error) {
+	r.mu.Lock()
+	trial, ok := r.trials[trialID]
+	r.mu.Unlock()
+	if !ok {
+		experimentID, requestID, err := lookup(trialID)
+		if err != nil {
+			return metricRuleTrial{}, false, err
+		}
+		trial = metricRuleTrial{experimentID: experimentID, requestID: requestID}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.experiments[trial.experimentID] {
+		return trial, false, nil
+	}
+	if !ok {
+		if len(r.trials) >= maxMetricRuleTrials {
+			r.trials = map[int]metricRuleTrial{}
+		}
+		r.trials[trialID] = trial
+	}
+	return trial, true, nil
+}
@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"crypto/rand"
+	"math"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func metricRule(rule expconf.MetricRule) expconf.MetricRule {
+	return schemas.WithDefaults(rule).(expconf.MetricRule)
+}
+
+func reportedMetrics(name string, value *structpb.Value) *structpb.Struct {
+	return &structpb.Struct{Fields: map[string]*structpb.Value{name: value}}
+}
+
+// firedValues reports a sequence of values of the metric of the rules and returns the values on
+// which any rule fired.
+func firedValues(
+	rules *metricRules, metricType, metric string, values ...*structpb.Value,
+) []float64 {
+	var fired []float64
+	for _, v := range values {
+		_, firedOn := rules.evaluate(1, metricType, reportedMetrics(metric, v))
+		fired = append(fired, firedOn...)
+	}
+	return fired
+}
+
+func TestMetricRulesThreshold(t *testing.T) {
+	rules := newMetricRules([]expconf.MetricRule{metricRule(expconf.MetricRule{
+		RawName:       "slow",
+		RawMetric:     "samples_per_second",
+		RawMetricType: ptrs.StringPtr(trainingMetricType),
+		RawCondition:  metricRuleLessThan,
+		RawThreshold:  ptrs.Float64Ptr(100),
+	})})
+
+	// The rule fires when its condition becomes met, and again only after it stops being met.
+	assert.DeepEqual(t, firedValues(rules, trainingMetricType, "samples_per_second",
+		structpb.NewNumberValue(150),
+		structpb.NewNumberValue(90),
+		structpb.NewNumberValue(80),
+		structpb.NewNumberValue(120),
+		structpb.NewNumberValue(70),
+	), []float64{90, 70})
+
+	// Metrics of the other type or with other names are ignored.
+	assert.Equal(t, len(firedValues(rules, validationMetricType, "samples_per_second",
+		structpb.NewNumberValue(150), structpb.NewNumberValue(10))), 0)
+	assert.Equal(t, len(firedValues(rules, trainingMetricType, "loss",
+		structpb.NewNumberValue(150), structpb.NewNumberValue(10))), 0)
+}
+
+func TestMetricRulesNaN(t *testing.T) {
+	rules := newMetricRules([]expconf.MetricRule{metricRule(expconf.MetricRule{
+		RawName:      "diverged",
+		RawMetric:    "loss",
+		RawCondition: metricRuleNaN,
+		RawAction:    ptrs.StringPtr(metricRuleKillTrial),
+	})})
+
+	fired, values := rules.evaluate(1, validationMetricType,
+		reportedMetrics("loss", structpb.NewStringValue("NaN")))
+	assert.Equal(t, len(fired), 1)
+	assert.Equal(t, fired[0].Action(), metricRuleKillTrial)
+	assert.Assert(t, math.IsNaN(values[0]))
+
+	// The state of the rule is kept per trial.
+	fired, _ = rules.evaluate(2, validationMetricType,
+		reportedMetrics("loss", structpb.NewStringValue("Infinity")))
+	assert.Equal(t, len(fired), 1)
+	fired, _ = rules.evaluate(1, validationMetricType,
+		reportedMetrics("loss", structpb.NewStringValue("Infinity")))
+	assert.Equal(t, len(fired), 0)
+}
+
+func TestMetricRulesNoImprovement(t *testing.T) {
+	rules := newMetricRules([]expconf.MetricRule{metricRule(expconf.MetricRule{
+		RawName:            "plateau",
+		RawMetric:          "accuracy",
+		RawCondition:       metricRuleNoImprovement,
+		RawPatience:        ptrs.IntPtr(2),
+		RawSmallerIsBetter: ptrs.BoolPtr(false),
+		RawAction:          ptrs.StringPtr(metricRulePauseExperiment),
+	})})
+
+	// The rule fires on the second report without improvement, and again after the next
+	// improvement; NaN never counts as an improvement.
+	fired := firedValues(rules, validationMetricType, "accuracy",
+		structpb.NewNumberValue(0.5),
+		structpb.NewNumberValue(0.6),
+		structpb.NewNumberValue(0.6),
+		structpb.NewNumberValue(0.4),
+		structpb.NewNumberValue(0.5),
+		structpb.NewNumberValue(0.7),
+		structpb.NewNumberValue(0.7),
+		structpb.NewStringValue("NaN"),
+	)
+	assert.Equal(t, len(fired), 2)
+	assert.Equal(t, fired[0], 0.4)
+	assert.Assert(t, math.IsNaN(fired[1]))
+}
+
+func TestMetricRulesRebuild(t *testing.T) {
+	rules := newMetricRules([]expconf.MetricRule{metricRule(expconf.MetricRule{
+		RawName:            "plateau",
+		RawMetric:          "accuracy",
+		RawCondition:       metricRuleNoImprovement,
+		RawPatience:        ptrs.IntPtr(3),
+		RawSmallerIsBetter: ptrs.BoolPtr(false),
+	})})
+	history := func(trialID int, metricType, metric string, beforeBatches int) ([]float64, error) {
+		assert.Equal(t, trialID, 1)
+		assert.Equal(t, metricType, validationMetricType)
+		assert.Equal(t, metric, "accuracy")
+		assert.Equal(t, beforeBatches, 400)
+		return []float64{0.5, 0.7, 0.6, 0.6}, nil
+	}
+
+	// The reports before the restart count toward the patience of the rule.
+	assert.Assert(t, !rules.tracking(1))
+	assert.NilError(t, rules.rebuild(1, 400, history))
+	assert.Assert(t, rules.tracking(1))
+	assert.DeepEqual(t, firedValues(rules, validationMetricType, "accuracy",
+		structpb.NewNumberValue(0.65),
+		structpb.NewNumberValue(0.65),
+	), []float64{0.65})
+}
+
+func TestMetricRuleRegistry(t *testing.T) {
+	registry := newMetricRuleRegistry()
+	lookups := 0
+	lookup := func(trialID int) (int, model.RequestID, error) {
+		lookups++
+		return trialID * 10, model.NewRequestID(rand.Reader), nil
+	}
+	assert.Assert(t, registry.empty())
+
+	// Trials of experiments without metric rules are not cached.
+	registry.register(10)
+	assert.Assert(t, !registry.empty())
+	_, hasRules, err := registry.trial(2, lookup)
+	assert.NilError(t, err)
+	assert.Assert(t, !hasRules)
+
+	// The experiments of the other trials are looked up once, until their experiment stops.
+	for i := 0; i < 2; i++ {
+		trial, hasRules, err := registry.trial(1, lookup)
+		assert.NilError(t, err)
+		assert.Assert(t, hasRules)
+		assert.Equal(t, trial.experimentID, 10)
+	}
+	assert.Equal(t, lookups, 2)
+
+	registry.unregister(10)
+	assert.Assert(t, registry.empty())
+	_, hasRules, err = registry.trial(1, lookup)
+	assert.NilError(t, err)
+	assert.Assert(t, !hasRules)
+	assert.Equal(t, lookups, 3)
+}
@@ -0,0 +1,99 @@
+package metricexport
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// PrometheusRemoteWrite sends metrics with the Prometheus remote write protocol.
+	PrometheusRemoteWrite = "prometheus_remote_write"
+	// OTLP sends metrics with the OpenTelemetry protocol over HTTP, encoded as JSON.
+	OTLP = "otlp"
+)
+
+// Config configures exporting trial metrics to an external metrics backend.
+type Config struct {
+	Type     string `json:"type"`
+	Endpoint string `json:"endpoint"`
+	// Headers are added to every request, e.g., to authenticate with the endpoint.
+	Headers map[string]string `json:"headers"`
+	Timeout model.Duration    `json:"timeout"`
+
+	// Samples are sent in batches of at most BatchSize samples, at least every FlushInterval.
+	BatchSize     int            `json:"batch_size"`
+	FlushInterval model.Duration `json:"flush_interval"`
+
+	// At most QueueSize samples wait to be sent. When the queue is full, reporting metrics blocks
+	// for up to EnqueueTimeout before the samples are dropped.
+	QueueSize      int            `json:"queue_size"`
+	EnqueueTimeout model.Duration `json:"enqueue_timeout"`
+
+	// Failed requests are retried up to MaxRetries times, backing off exponentially from
+	// MinBackoff to MaxBackoff.
+	MaxRetries int            `json:"max_retries"`
+	MinBackoff model.Duration `json:"min_backoff"`
+	MaxBackoff model.Duration `json:"max_backoff"`
+}
+
+var defaultConfig = Config{
+	Timeout:        model.Duration(30 * time.Second),
+	BatchSize:      500,
+	FlushInterval:  model.Duration(5 * time.Second),
+	QueueSize:      10000,
+	EnqueueTimeout: model.Duration(time.Second),
+	MaxRetries:     5,
+	MinBackoff:     model.Duration(500 * time.Millisecond),
+	MaxBackoff:     model.Duration(30 * time.Second),
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	*c = defaultConfig
+	type DefaultParser *Config
+	return json.Unmarshal(data, DefaultParser(c))
+}
+
+// Validate implements the check.Validatable interface.
+func (c Config) Validate() []error {
+	errs := []error{
+		check.In(c.Type, []string{PrometheusRemoteWrite, OTLP},
+			"metrics export type must be prometheus_remote_write or otlp"),
+		check.GreaterThan(int64(c.Timeout), int64(0), "metrics export timeout must be greater than 0"),
+		check.GreaterThan(c.BatchSize, 0, "metrics export batch_size must be greater than 0"),
+		check.GreaterThan(int64(c.FlushInterval), int64(0),
+			"metrics export flush_interval must be greater than 0"),
+		check.GreaterThanOrEqualTo(c.QueueSize, c.BatchSize,
+			"metrics export queue_size must be at least batch_size"),
+		check.GreaterThanOrEqualTo(c.MaxRetries, 0,
+			"metrics export max_retries must be non-negative"),
+		check.LessThanOrEqualTo(int64(c.MinBackoff), int64(c.MaxBackoff),
+			"metrics export min_backoff must be at most max_backoff"),
+	}
+	parsed, err := url.Parse(c.Endpoint)
+	switch {
+	case err != nil:
+		errs = append(errs, errors.Wrap(err, "cannot parse metrics export endpoint"))
+	case parsed.Scheme != "http" && parsed.Scheme != "https":
+		errs = append(errs, errors.Errorf(
+			"metrics export endpoint must be an http or https URL: %s", c.Endpoint))
+	}
+	return errs
+}
+
+// Printable returns a copy of the config that is safe to print.
+func (c Config) Printable() Config {
+	const hiddenValue = "********"
+	headers := make(map[string]string, len(c.Headers))
+	for k := range c.Headers {
+		headers[k] = hiddenValue
+	}
+	c.Headers = headers
+	return c
+}
@@ -0,0 +1,211 @@
+// Package metricexport forwards the metrics that trials report to an external metrics backend,
+// such as Prometheus or an OpenTelemetry collector.
+package metricexport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Sample is a reading of a metric.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+	Time   time.Time
+}
+
+// encoder encodes batches of samples into request bodies for a metrics backend.
+type encoder interface {
+	encode(samples []Sample) ([]byte, error)
+	headers() map[string]string
+}
+
+// Exporter sends samples to a metrics backend in batches, in the background.
+type Exporter struct {
+	config  Config
+	client  *http.Client
+	encoder encoder
+
+	queue   chan Sample
+	closed  chan struct{}
+	done    chan struct{}
+	closing sync.Once
+
+	// The number of samples that were dropped because the queue was full or the backend failed.
+	dropped int64
+}
+
+// New creates an exporter for the configuration and starts sending samples.
+func New(config Config) (*Exporter, error) {
+	var enc encoder
+	switch config.Type {
+	case PrometheusRemoteWrite:
+		enc = remoteWriteEncoder{}
+	case OTLP:
+		enc = otlpEncoder{}
+	default:
+		return nil, errors.Errorf("unknown metrics export type: %s", config.Type)
+	}
+	e := &Exporter{
+		config:  config,
+		client:  &http.Client{Timeout: time.Duration(config.Timeout)},
+		encoder: enc,
+		queue:   make(chan Sample, config.QueueSize),
+		closed:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+// Export queues samples to be sent. If the queue is full, it blocks until there is room for the
+// samples, for up to the configured enqueue timeout, after which the remaining samples are dropped.
+func (e *Exporter) Export(samples ...Sample) {
+	var timeout <-chan time.Time
+	for i, s := range samples {
+		select {
+		case e.queue <- s:
+			continue
+		case <-e.closed:
+		default:
+			if timeout == nil {
+				timer := time.NewTimer(time.Duration(e.config.EnqueueTimeout))
+				defer timer.Stop()
+				timeout = timer.C
+			}
+			select {
+			case e.queue <- s:
+				continue
+			case <-e.closed:
+			case <-timeout:
+			}
+		}
+		e.drop(len(samples)-i, "the queue is full")
+		return
+	}
+}
+
+// Dropped returns the number of samples that were dropped so far.
+func (e *Exporter) Dropped() int64 {
+	return atomic.LoadInt64(&e.dropped)
+}
+
+// Close sends the queued samples and stops the exporter.
+func (e *Exporter) Close() {
+	e.closing.Do(func() { close(e.closed) })
+	<-e.done
+}
+
+func (e *Exporter) drop(n int, reason string) {
+	if atomic.AddInt64(&e.dropped, int64(n)) == int64(n) {
+		log.Warnf("dropping exported metrics because %s, further drops are not logged", reason)
+	}
+}
+
+func (e *Exporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(time.Duration(e.config.FlushInterval))
+	defer ticker.Stop()
+
+	batch := make([]Sample, 0, e.config.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			e.send(batch)
+			batch = make([]Sample, 0, e.config.BatchSize)
+		}
+	}
+	for {
+		select {
+		case s := <-e.queue:
+			batch = append(batch, s)
+			if len(batch) >= e.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.closed:
+			for {
+				select {
+				case s := <-e.queue:
+					batch = append(batch, s)
+					if len(batch) >= e.config.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send sends a batch of samples, retrying with exponential backoff. While it retries, the queue
+// fills up and applies back-pressure to the callers of Export.
+func (e *Exporter) send(batch []Sample) {
+	body, err := e.encoder.encode(batch)
+	if err != nil {
+		log.WithError(err).Error("failed to encode exported metrics")
+		e.drop(len(batch), "they cannot be encoded")
+		return
+	}
+
+	backoff := time.Duration(e.config.MinBackoff)
+	for attempt := 0; ; attempt++ {
+		retryable, err := e.post(body)
+		if err == nil {
+			return
+		}
+		if !retryable || attempt >= e.config.MaxRetries {
+			log.WithError(err).Errorf("failed to export %d metric samples", len(batch))
+			e.drop(len(batch), "the metrics backend failed")
+			return
+		}
+		log.WithError(err).Debugf("retrying export of metrics in %s", backoff)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > time.Duration(e.config.MaxBackoff) {
+			backoff = time.Duration(e.config.MaxBackoff)
+		}
+	}
+}
+
+// post sends a request body to the endpoint and returns whether failures may be retried.
+func (e *Exporter) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, e.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for k, v := range e.encoder.headers() {
+		req.Header.Set(k, v)
+	}
+	for k, v := range e.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Error("failed to close metrics export response body")
+		}
+	}()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	// Like Prometheus, only retry on server errors and rate limiting.
+	retryable := resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests
+	return retryable, fmt.Errorf("metrics backend returned %s: %s", resp.Status, msg)
+}
@@ -0,0 +1,278 @@
+package metricexport
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// receiver is a local metrics backend that records the requests it receives.
+type receiver struct {
+	mu       sync.Mutex
+	bodies   [][]byte
+	headers  []http.Header
+	statuses []int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	if status == http.StatusOK {
+		r.bodies = append(r.bodies, body)
+		r.headers = append(r.headers, req.Header.Clone())
+	}
+	w.WriteHeader(status)
+}
+
+func (r *receiver) received() ([][]byte, []http.Header) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bodies, r.headers
+}
+
+func testConfig(typ, endpoint string) Config {
+	c := defaultConfig
+	c.Type = typ
+	c.Endpoint = endpoint
+	c.Headers = map[string]string{"Authorization": "Bearer token"}
+	c.FlushInterval = model.Duration(10 * time.Millisecond)
+	c.MinBackoff = model.Duration(time.Millisecond)
+	c.MaxBackoff = model.Duration(10 * time.Millisecond)
+	return c
+}
+
+var testSamples = []Sample{
+	{
+		Name:   "determined_training_loss",
+		Labels: map[string]string{"trial_id": "1", "experiment_id": "2"},
+		Value:  0.5,
+		Time:   time.Unix(100, 0),
+	},
+	{
+		Name:   "determined_training_loss",
+		Labels: map[string]string{"trial_id": "1", "experiment_id": "2"},
+		Value:  0.25,
+		Time:   time.Unix(101, 0),
+	},
+}
+
+type decodedSeries struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp int64
+}
+
+// decodeRemoteWrite decodes a WriteRequest, consuming the fields that remoteWriteEncoder emits.
+func decodeRemoteWrite(t *testing.T, body []byte) []decodedSeries {
+	data, err := snappy.Decode(nil, body)
+	assert.NilError(t, err)
+	fields := func(b []byte, f func(protowire.Number, protowire.Type, []byte) int) {
+		for len(b) > 0 {
+			num, typ, n := protowire.ConsumeTag(b)
+			assert.Assert(t, n > 0)
+			b = b[n:]
+			n = f(num, typ, b)
+			assert.Assert(t, n > 0)
+			b = b[n:]
+		}
+	}
+	var result []decodedSeries
+	fields(data, func(_ protowire.Number, _ protowire.Type, b []byte) int {
+		series, n := protowire.ConsumeBytes(b)
+		s := decodedSeries{Labels: map[string]string{}}
+		fields(series, func(num protowire.Number, _ protowire.Type, b []byte) int {
+			msg, n := protowire.ConsumeBytes(b)
+			switch num {
+			case timeSeriesLabels:
+				var name, value string
+				fields(msg, func(num protowire.Number, _ protowire.Type, b []byte) int {
+					v, n := protowire.ConsumeString(b)
+					if num == labelName {
+						name = v
+					} else {
+						value = v
+					}
+					return n
+				})
+				s.Labels[name] = value
+			case timeSeriesSamples:
+				fields(msg, func(num protowire.Number, _ protowire.Type, b []byte) int {
+					if num == sampleValue {
+						v, n := protowire.ConsumeFixed64(b)
+						s.Value = math.Float64frombits(v)
+						return n
+					}
+					v, n := protowire.ConsumeVarint(b)
+					s.Timestamp = int64(v)
+					return n
+				})
+			}
+			return n
+		})
+		result = append(result, s)
+		return n
+	})
+	return result
+}
+
+func TestRemoteWrite(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	e, err := New(testConfig(PrometheusRemoteWrite, server.URL))
+	assert.NilError(t, err)
+	e.Export(testSamples...)
+	e.Close()
+
+	bodies, headers := r.received()
+	assert.Equal(t, len(bodies), 1)
+	assert.Equal(t, headers[0].Get("Content-Encoding"), "snappy")
+	assert.Equal(t, headers[0].Get("Authorization"), "Bearer token")
+	labels := map[string]string{
+		"__name__":      "determined_training_loss",
+		"trial_id":      "1",
+		"experiment_id": "2",
+	}
+	assert.DeepEqual(t, decodeRemoteWrite(t, bodies[0]), []decodedSeries{
+		{Labels: labels, Value: 0.5, Timestamp: 100000},
+		{Labels: labels, Value: 0.25, Timestamp: 101000},
+	})
+	assert.Equal(t, e.Dropped(), int64(0))
+}
+
+func TestOTLP(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	e, err := New(testConfig(OTLP, server.URL))
+	assert.NilError(t, err)
+	e.Export(testSamples...)
+	e.Close()
+
+	bodies, headers := r.received()
+	assert.Equal(t, len(bodies), 1)
+	assert.Equal(t, headers[0].Get("Content-Type"), "application/json")
+	var req otlpRequest
+	assert.NilError(t, json.Unmarshal(bodies[0], &req))
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	assert.Equal(t, len(metrics), 1)
+	assert.Equal(t, metrics[0].Name, "determined_training_loss")
+	points := metrics[0].Gauge.DataPoints
+	assert.Equal(t, len(points), 2)
+	assert.Equal(t, points[1].AsDouble, otlpDouble(0.25))
+	assert.Equal(t, points[1].TimeUnixNano, "101000000000")
+	assert.DeepEqual(t, points[1].Attributes, []otlpAttribute{
+		{Key: "experiment_id", Value: otlpAnyValue{StringValue: "2"}},
+		{Key: "trial_id", Value: otlpAnyValue{StringValue: "1"}},
+	})
+}
+
+func TestOTLPNonFinite(t *testing.T) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	e, err := New(testConfig(OTLP, server.URL))
+	assert.NilError(t, err)
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 0.5} {
+		e.Export(Sample{Name: "determined_training_loss", Value: v, Time: time.Unix(100, 0)})
+	}
+	e.Close()
+
+	bodies, _ := r.received()
+	assert.Equal(t, len(bodies), 1)
+	assert.Assert(t, strings.Contains(string(bodies[0]),
+		`"asDouble":"NaN"`), string(bodies[0]))
+	var req otlpRequest
+	assert.NilError(t, json.Unmarshal(bodies[0], &req))
+	points := req.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].Gauge.DataPoints
+	assert.Equal(t, len(points), 4)
+	assert.Assert(t, math.IsNaN(float64(points[0].AsDouble)))
+	assert.Equal(t, float64(points[1].AsDouble), math.Inf(1))
+	assert.Equal(t, float64(points[2].AsDouble), math.Inf(-1))
+	assert.Equal(t, points[3].AsDouble, otlpDouble(0.5))
+	assert.Equal(t, e.Dropped(), int64(0))
+}
+
+func TestRetry(t *testing.T) {
+	r := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	e, err := New(testConfig(PrometheusRemoteWrite, server.URL))
+	assert.NilError(t, err)
+	e.Export(testSamples...)
+	e.Close()
+
+	bodies, _ := r.received()
+	assert.Equal(t, len(bodies), 1)
+	assert.Equal(t, e.Dropped(), int64(0))
+
+	// Client errors are not retried.
+	r.statuses = []int{http.StatusBadRequest}
+	e, err = New(testConfig(PrometheusRemoteWrite, server.URL))
+	assert.NilError(t, err)
+	e.Export(testSamples...)
+	e.Close()
+
+	bodies, _ = r.received()
+	assert.Equal(t, len(bodies), 1)
+	assert.Equal(t, e.Dropped(), int64(len(testSamples)))
+}
+
+func TestBackPressure(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-unblock
+	}))
+	defer server.Close()
+
+	config := testConfig(PrometheusRemoteWrite, server.URL)
+	config.BatchSize = 1
+	config.QueueSize = 1
+	config.EnqueueTimeout = model.Duration(10 * time.Millisecond)
+	e, err := New(config)
+	assert.NilError(t, err)
+
+	// The first sample is being sent and the second fills the queue, so the rest are dropped after
+	// the enqueue timeout.
+	start := time.Now()
+	e.Export(testSamples[0])
+	for e.queueLen() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	e.Export(testSamples[0], testSamples[1], testSamples[0])
+	assert.Assert(t, time.Since(start) >= 10*time.Millisecond)
+	assert.Equal(t, e.Dropped(), int64(2))
+
+	close(unblock)
+	e.Close()
+	assert.Equal(t, e.Dropped(), int64(2))
+}
+
+func (e *Exporter) queueLen() int {
+	return len(e.queue)
+}
@@ -0,0 +1,131 @@
+package metricexport
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// The subset of the OTLP/HTTP JSON encoding of metrics that gauges need.
+type (
+	otlpRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpMetric struct {
+		Name  string    `json:"name"`
+		Gauge otlpGauge `json:"gauge"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpDataPoint `json:"dataPoints"`
+	}
+	otlpDataPoint struct {
+		Attributes   []otlpAttribute `json:"attributes"`
+		TimeUnixNano string          `json:"timeUnixNano"`
+		AsDouble     otlpDouble      `json:"asDouble"`
+	}
+	otlpAttribute struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+// otlpDouble is a double in the OTLP JSON encoding, which follows the JSON mapping of protobuf:
+// non-finite values, which JSON numbers cannot represent, are encoded as strings.
+type otlpDouble float64
+
+func (d otlpDouble) MarshalJSON() ([]byte, error) {
+	switch f := float64(d); {
+	case math.IsNaN(f):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(f, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(f, -1):
+		return []byte(`"-Infinity"`), nil
+	default:
+		return json.Marshal(f)
+	}
+}
+
+func (d *otlpDouble) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return json.Unmarshal(b, (*float64)(d))
+	}
+	switch s {
+	case "Infinity":
+		*d = otlpDouble(math.Inf(1))
+	case "-Infinity":
+		*d = otlpDouble(math.Inf(-1))
+	default:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		*d = otlpDouble(f)
+	}
+	return nil
+}
+
+const otlpScopeName = "determined-master"
+
+// otlpEncoder encodes samples as an OTLP ExportMetricsServiceRequest in JSON, grouping the data
+// points of each metric into a gauge.
+type otlpEncoder struct{}
+
+func (otlpEncoder) encode(samples []Sample) ([]byte, error) {
+	var metrics []otlpMetric
+	byName := map[string]int{}
+	for _, s := range samples {
+		i, ok := byName[s.Name]
+		if !ok {
+			i = len(metrics)
+			byName[s.Name] = i
+			metrics = append(metrics, otlpMetric{Name: s.Name})
+		}
+		metrics[i].Gauge.DataPoints = append(metrics[i].Gauge.DataPoints, otlpDataPoint{
+			Attributes:   otlpAttributes(s.Labels),
+			TimeUnixNano: strconv.FormatInt(s.Time.UnixNano(), 10),
+			AsDouble:     otlpDouble(s.Value),
+		})
+	}
+	return json.Marshal(otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: otlpAttributes(map[string]string{
+			"service.name": otlpScopeName,
+		})},
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: otlpScopeName},
+			Metrics: metrics,
+		}},
+	}}})
+}
+
+func (otlpEncoder) headers() map[string]string {
+	return map[string]string{"Content-Type": "application/json"}
+}
+
+func otlpAttributes(labels map[string]string) []otlpAttribute {
+	attrs := make([]otlpAttribute, 0, len(labels))
+	for k, v := range labels {
+		attrs = append(attrs, otlpAttribute{Key: k, Value: otlpAnyValue{StringValue: v}})
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	return attrs
+}
@@ -0,0 +1,82 @@
+package metricexport
+
+import (
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the messages in the Prometheus remote write protocol (prompb).
+const (
+	writeRequestTimeseries = 1
+	timeSeriesLabels       = 1
+	timeSeriesSamples      = 2
+	labelName              = 1
+	labelValue             = 2
+	sampleValue            = 1
+	sampleTimestamp        = 2
+)
+
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+// sanitizeMetricName replaces the characters that Prometheus does not allow in metric names.
+func sanitizeMetricName(name string) string {
+	name = invalidMetricChars.ReplaceAllString(name, "_")
+	if len(name) > 0 && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// remoteWriteEncoder encodes samples as a snappy-compressed Prometheus WriteRequest. The message
+// is small enough that it is encoded by hand rather than pulling in the Prometheus protos.
+type remoteWriteEncoder struct{}
+
+func (remoteWriteEncoder) encode(samples []Sample) ([]byte, error) {
+	var req []byte
+	for _, s := range samples {
+		var series []byte
+		for _, l := range sortedLabels(s) {
+			var label []byte
+			label = protowire.AppendTag(label, labelName, protowire.BytesType)
+			label = protowire.AppendString(label, l[0])
+			label = protowire.AppendTag(label, labelValue, protowire.BytesType)
+			label = protowire.AppendString(label, l[1])
+			series = protowire.AppendTag(series, timeSeriesLabels, protowire.BytesType)
+			series = protowire.AppendBytes(series, label)
+		}
+		var sample []byte
+		sample = protowire.AppendTag(sample, sampleValue, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(s.Value))
+		sample = protowire.AppendTag(sample, sampleTimestamp, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(s.Time.UnixNano()/1e6))
+		series = protowire.AppendTag(series, timeSeriesSamples, protowire.BytesType)
+		series = protowire.AppendBytes(series, sample)
+
+		req = protowire.AppendTag(req, writeRequestTimeseries, protowire.BytesType)
+		req = protowire.AppendBytes(req, series)
+	}
+	return snappy.Encode(nil, req), nil
+}
+
+func (remoteWriteEncoder) headers() map[string]string {
+	return map[string]string{
+		"Content-Encoding":                  "snappy",
+		"Content-Type":                      "application/x-protobuf",
+		"X-Prometheus-Remote-Write-Version": "0.1.0",
+	}
+}
+
+// sortedLabels returns the labels of a sample, including its name, sorted by label name as the
+// remote write protocol requires.
+func sortedLabels(s Sample) [][2]string {
+	labels := [][2]string{{"__name__", sanitizeMetricName(s.Name)}}
+	for k, v := range s.Labels {
+		labels = append(labels, [2]string{sanitizeMetricName(k), v})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i][0] < labels[j][0] })
+	return labels
+}
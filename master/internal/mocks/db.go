@@ -42,6 +42,20 @@ func (_m *DB) AddAllocation(a *model.Allocation) error {
 	return r0
 }
 
+// AddAllocationPreemption provides a mock function with given fields: p
+func (_m *DB) AddAllocationPreemption(p *model.AllocationPreemption) error {
+	ret := _m.Called(p)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.AllocationPreemption) error); ok {
+		r0 = rf(p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddAuthTokenKeypair provides a mock function with given fields: tokenKeypair
 func (_m *DB) AddAuthTokenKeypair(tokenKeypair *model.AuthTokenKeypair) error {
 	ret := _m.Called(tokenKeypair)
@@ -126,6 +140,20 @@ func (_m *DB) AddTrialLogs(logs []*model.TrialLog) error {
 	return r0
 }
 
+// AddUpdate provides a mock function with given fields: u
+func (_m *DB) AddUpdate(u *model.Update) error {
+	ret := _m.Called(u)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Update) error); ok {
+		r0 = rf(u)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddUser provides a mock function with given fields: user, ug
 func (_m *DB) AddUser(user *model.User, ug *model.AgentUserGroup) error {
 	ret := _m.Called(user, ug)
@@ -423,6 +451,20 @@ func (_m *DB) DeleteTrialLogs(ids []int) error {
 	return r0
 }
 
+// DeleteUpdatesBefore provides a mock function with given fields: t
+func (_m *DB) DeleteUpdatesBefore(t time.Time) error {
+	ret := _m.Called(t)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(t)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteUserSessionByID provides a mock function with given fields: sessionID
 func (_m *DB) DeleteUserSessionByID(sessionID model.SessionID) error {
 	ret := _m.Called(sessionID)
@@ -1190,6 +1232,27 @@ func (_m *DB) LatestCheckpointForTrial(trialID int) (*model.Checkpoint, error) {
 	return r0, r1
 }
 
+// LatestUpdateSeq provides a mock function with given fields:
+func (_m *DB) LatestUpdateSeq() (int64, error) {
+	ret := _m.Called()
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LegacyExperimentConfigByID provides a mock function with given fields: id
 func (_m *DB) LegacyExperimentConfigByID(id int) (expconf.LegacyConfig, error) {
 	ret := _m.Called(id)
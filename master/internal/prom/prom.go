@@ -0,0 +1,132 @@
+// Package prom defines the Prometheus metrics that the master exports about its own operation at
+// /debug/prom/metrics when prometheus_enabled is set. docs/sysadmin-basics/master-metrics.txt
+// catalogs them; keep it in sync when adding metrics.
+package prom
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+)
+
+const namespace = "determined"
+
+var (
+	// QueueDepth is the number of tasks waiting for resources in each resource pool.
+	QueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "queue_depth",
+		Help:      "Number of tasks waiting for resources in the resource pool.",
+	}, []string{"resource_pool"})
+
+	// QueueWait is the time tasks waited for resources before they were allocated.
+	QueueWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "queue_wait_seconds",
+		Help:      "Time tasks waited for resources in the resource pool before they were allocated.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+	}, []string{"resource_pool"})
+
+	// ScheduleDuration is the latency of the scheduler of each resource pool.
+	ScheduleDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "schedule_duration_seconds",
+		Help:      "Time taken by each scheduler pass of the resource pool.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+	}, []string{"resource_pool"})
+
+	// ScheduledAllocations is the number of tasks each scheduler pass allocates resources to.
+	ScheduledAllocations = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "allocations_per_pass",
+		Help:      "Number of tasks allocated resources by each scheduler pass of the resource pool.",
+		Buckets:   countBuckets,
+	}, []string{"resource_pool"})
+
+	// ScheduledPreemptions is the number of tasks each scheduler pass preempts.
+	ScheduledPreemptions = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "preemptions_per_pass",
+		Help:      "Number of tasks preempted by each scheduler pass of the resource pool.",
+		Buckets:   countBuckets,
+	}, []string{"resource_pool"})
+
+	// DBQueryDuration is the latency of each named query in the static query map.
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Time taken by each named database query.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 10),
+	}, []string{"query"})
+
+	// TrialLogFlushSize is the number of trial logs written by each flush of the trial logger.
+	TrialLogFlushSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "trial_logger",
+		Name:      "flush_batch_size",
+		Help:      "Number of trial logs written to the log backend by each flush.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 11),
+	})
+
+	// InstancesLaunched is the number of instances each provisioner requested to launch.
+	InstancesLaunched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provisioner",
+		Name:      "instances_launched_total",
+		Help:      "Number of instances the provisioner of the resource pool requested to launch.",
+	}, []string{"resource_pool"})
+
+	// InstancesTerminated is the number of instances each provisioner requested to terminate.
+	InstancesTerminated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "provisioner",
+		Name:      "instances_terminated_total",
+		Help:      "Number of instances the provisioner of the resource pool requested to terminate.",
+	}, []string{"resource_pool"})
+)
+
+// countBuckets are the buckets of histograms of small counts, like the tasks of a scheduler pass.
+var countBuckets = []float64{0, 1, 2, 5, 10, 20, 50, 100}
+
+// ObserveSince records the time elapsed since start in a histogram.
+func ObserveSince(o prometheus.Observer, start time.Time) {
+	o.Observe(time.Since(start).Seconds())
+}
+
+var inboxLengthDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "actor", "inbox_messages"),
+	"Number of messages waiting in the inboxes of the actors of each type.",
+	[]string{"actor_type"}, nil,
+)
+
+// actorCollector collects the inbox lengths of the actors of a system when scraped.
+type actorCollector struct {
+	system *actor.System
+}
+
+// NewActorCollector returns a collector of the inbox lengths of the actors of the system.
+func NewActorCollector(system *actor.System) prometheus.Collector {
+	return actorCollector{system: system}
+}
+
+// Describe implements prometheus.Collector.
+func (c actorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- inboxLengthDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c actorCollector) Collect(ch chan<- prometheus.Metric) {
+	for typeName, length := range c.system.InboxLengths() {
+		ch <- prometheus.MustNewConstMetric(
+			inboxLengthDesc, prometheus.GaugeValue, float64(length), typeName)
+	}
+}
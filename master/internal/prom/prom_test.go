@@ -0,0 +1,47 @@
+package prom
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+)
+
+type blockingActor struct {
+	unblock chan struct{}
+}
+
+func (a *blockingActor) Receive(ctx *actor.Context) error {
+	if _, ok := ctx.Message().(string); ok {
+		<-a.unblock
+	}
+	return nil
+}
+
+func TestActorCollector(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	blocking := &blockingActor{unblock: make(chan struct{})}
+	ref, _ := system.ActorOf(actor.Addr("blocking"), blocking)
+	system.Tell(ref, "block")
+	system.Tell(ref, "block")
+
+	collector := NewActorCollector(system)
+	expected := `
+# HELP determined_actor_inbox_messages Number of messages waiting in the inboxes of the actors of each type.
+# TYPE determined_actor_inbox_messages gauge
+determined_actor_inbox_messages{actor_type="blockingActor"} 1
+determined_actor_inbox_messages{actor_type="rootActor"} 0
+`
+	// Wait for the first message to block the actor, so that only the second is queued.
+	for system.InboxLengths()["blockingActor"] != 1 {
+		runtime.Gosched()
+	}
+	assert.NilError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	close(blocking.unblock)
+	assert.NilError(t, system.StopAndAwaitTermination())
+}
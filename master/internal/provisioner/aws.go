@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -17,6 +18,23 @@ import (
 	"github.com/determined-ai/determined/master/pkg/actor"
 )
 
+// insufficientCapacityBackoff is how long the provisioner falls back to the next instance type
+// after an instance type fails to launch for lack of capacity.
+const insufficientCapacityBackoff = 10 * time.Minute
+
+// ec2CapacityErrorCodes are the codes of EC2 API errors for launches that may succeed with a
+// different instance type.
+var ec2CapacityErrorCodes = map[string]bool{
+	"InsufficientInstanceCapacity": true,
+	"InsufficientCapacity":         true,
+	"Unsupported":                  true,
+}
+
+func isEC2CapacityError(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && ec2CapacityErrorCodes[awsErr.Code()]
+}
+
 func getEC2MetadataSess() (*ec2metadata.EC2Metadata, error) {
 	sess, err := session.NewSession(&aws.Config{})
 	if err != nil {
@@ -51,8 +69,14 @@ type awsCluster struct {
 	ec2UserData  []byte
 	client       *ec2.EC2
 
+	// Instance types that recently failed to launch for lack of capacity, mapped to the time until
+	// which the provisioner launches the next instance type instead.
+	exhaustedTypes map[string]time.Time
+
 	// State that is only used if spot instances are enabled
 	spot *spotState
+	// The spot prices recently looked up for cost accounting.
+	spotPrices map[spotPriceKey]cachedSpotPrice
 }
 
 func newAWSCluster(
@@ -115,6 +139,8 @@ func newAWSCluster(
 		AWSClusterConfig: config.AWS,
 		masterURL:        *masterURL,
 		client:           ec2.New(sess),
+		exhaustedTypes:   make(map[string]time.Time),
+		spotPrices:       make(map[spotPriceKey]cachedSpotPrice),
 		ec2UserData: mustMakeAgentSetupScript(agentSetupScriptConfig{
 			MasterHost:                   masterURL.Hostname(),
 			MasterPort:                   masterURL.Port(),
@@ -145,11 +171,41 @@ func newAWSCluster(
 }
 
 func (c *awsCluster) instanceType() instanceType {
-	return c.InstanceType
+	return c.launchType()
 }
 
 func (c *awsCluster) slotsPerInstance() int {
-	return c.AWSClusterConfig.SlotsPerInstance()
+	return c.AWSClusterConfig.slotsPerInstance(c.launchType())
+}
+
+func (c *awsCluster) instanceSlots() []int {
+	return c.AWSClusterConfig.InstanceSlots()
+}
+
+// launchType returns the most preferred instance type that has not recently run out of capacity.
+func (c *awsCluster) launchType() ec2InstanceTypeConfig {
+	types := c.LaunchTypes()
+	now := time.Now()
+	for _, t := range types {
+		if until, ok := c.exhaustedTypes[t.Name]; !ok || now.After(until) {
+			return t
+		}
+	}
+	return types[0]
+}
+
+// fallBack makes the provisioner launch the next instance type for a while instead of the given
+// one, which ran out of capacity. It returns false if there is no other instance type.
+func (c *awsCluster) fallBack(ctx *actor.Context, name string) bool {
+	if len(c.LaunchTypes()) < 2 {
+		return false
+	}
+	c.exhaustedTypes[name] = time.Now().Add(insufficientCapacityBackoff)
+	ctx.Log().Warnf(
+		"EC2 instance type %s has insufficient capacity, launching %s instead for %s",
+		name, c.launchType().name(), insufficientCapacityBackoff,
+	)
+	return true
 }
 
 func (c *awsCluster) agentNameFromInstance(inst *ec2.Instance) string {
@@ -229,9 +285,13 @@ func (c *awsCluster) launchOnDemand(ctx *actor.Context, instanceNum int) {
 	if instanceNum <= 0 {
 		return
 	}
-	instances, err := c.launchInstances(instanceNum, false)
+	launchType := c.launchType()
+	instances, err := c.launchInstances(launchType, instanceNum, false)
 	if err != nil {
-		ctx.Log().WithError(err).Error("cannot launch EC2 instances")
+		ctx.Log().WithError(err).Errorf("cannot launch EC2 instances (type %s)", launchType.name())
+		if isEC2CapacityError(err) {
+			c.fallBack(ctx, launchType.name())
+		}
 		return
 	}
 	launched := c.newInstances(instances.Instances)
@@ -270,6 +330,8 @@ func (c *awsCluster) newInstances(input []*ec2.Instance) []*Instance {
 			LaunchTime: *inst.LaunchTime,
 			AgentName:  c.agentNameFromInstance(inst),
 			State:      c.stateFromEC2State(inst.State),
+			Slots:      c.slotsForInstanceType(aws.StringValue(inst.InstanceType)),
+			Type:       aws.StringValue(inst.InstanceType),
 		})
 	}
 	return output
@@ -347,7 +409,9 @@ func (c *awsCluster) describeInstancesByID(
 	return instances, nil
 }
 
-func (c *awsCluster) launchInstances(instanceNum int, dryRun bool) (*ec2.Reservation, error) {
+func (c *awsCluster) launchInstances(
+	instanceType ec2InstanceTypeConfig, instanceNum int, dryRun bool,
+) (*ec2.Reservation, error) {
 	input := &ec2.RunInstancesInput{
 		BlockDeviceMappings: []*ec2.BlockDeviceMapping{
 			{
@@ -361,7 +425,7 @@ func (c *awsCluster) launchInstances(instanceNum int, dryRun bool) (*ec2.Reserva
 		},
 		DryRun:       aws.Bool(dryRun),
 		ImageId:      aws.String(c.ImageID),
-		InstanceType: aws.String(c.AWSClusterConfig.InstanceType.name()),
+		InstanceType: aws.String(instanceType.name()),
 		KeyName:      aws.String(c.SSHKeyName),
 		MaxCount:     aws.Int64(int64(instanceNum)),
 		MinCount:     aws.Int64(1),
@@ -30,13 +30,15 @@ type AWSClusterConfig struct {
 	NetworkInterface      ec2NetworkInterface `json:"network_interface"`
 	IamInstanceProfileArn string              `json:"iam_instance_profile_arn"`
 
-	InstanceType ec2InstanceType `json:"instance_type"`
+	InstanceType  ec2InstanceType         `json:"instance_type"`
+	InstanceTypes []ec2InstanceTypeConfig `json:"instance_types"`
 
 	LogGroup  string `json:"log_group"`
 	LogStream string `json:"log_stream"`
 
-	SpotEnabled  bool   `json:"spot"`
-	SpotMaxPrice string `json:"spot_max_price"`
+	SpotEnabled       bool   `json:"spot"`
+	SpotMaxPrice      string `json:"spot_max_price"`
+	SpotPriceTracking bool   `json:"spot_price_tracking"`
 
 	CustomTags []*ec2Tag `json:"custom_tags"`
 
@@ -130,16 +132,48 @@ func (c AWSClusterConfig) Validate() []error {
 	if c.SpotEnabled && c.SpotMaxPrice != spotPriceNotSetPlaceholder {
 		spotPriceIsNotValidNumberErr = validateMaxSpotPrice(c.SpotMaxPrice)
 	}
+	var mixedSlotTypesErr error
+	for _, t := range c.InstanceTypes {
+		if (t.Slots() > 0) != (c.InstanceTypes[0].Slots() > 0) {
+			mixedSlotTypesErr = errors.New(
+				"ec2 instance types must either all have GPU slots or all have no GPU slots")
+		}
+	}
 	return []error{
 		check.GreaterThan(len(c.SSHKeyName), 0, "ec2 key name must be non-empty"),
 		check.GreaterThanOrEqualTo(c.RootVolumeSize, 100, "ec2 root volume size must be >= 100"),
 		spotPriceIsNotValidNumberErr,
+		mixedSlotTypesErr,
+	}
+}
+
+// LaunchTypes returns the instance types the provisioner may launch in the order of preference.
+// If instance_types is set, it takes precedence over instance_type.
+func (c AWSClusterConfig) LaunchTypes() []ec2InstanceTypeConfig {
+	if len(c.InstanceTypes) > 0 {
+		return c.InstanceTypes
 	}
+	return []ec2InstanceTypeConfig{{Name: c.InstanceType.name()}}
 }
 
-// SlotsPerInstance returns the number of slots per instance.
+// SlotsPerInstance returns the number of slots per instance of the preferred instance type.
 func (c AWSClusterConfig) SlotsPerInstance() int {
-	slots := c.InstanceType.Slots()
+	return c.slotsPerInstance(c.LaunchTypes()[0])
+}
+
+// InstanceSlots returns the number of slots per instance of each instance type the provisioner
+// may launch, in the order of preference.
+func (c AWSClusterConfig) InstanceSlots() []int {
+	types := c.LaunchTypes()
+	slots := make([]int, 0, len(types))
+	for _, t := range types {
+		slots = append(slots, c.slotsPerInstance(t))
+	}
+	return slots
+}
+
+func (c AWSClusterConfig) slotsPerInstance(t ec2InstanceTypeConfig) int {
+	slots := t.Slots()
 	if slots == 0 && c.CPUSlotsAllowed {
 		slots = 1
 	}
@@ -147,9 +181,20 @@ func (c AWSClusterConfig) SlotsPerInstance() int {
 	return slots
 }
 
+// slotsForInstanceType returns the number of slots of an instance of the named type. Types that
+// the provisioner no longer launches have the number of slots of the EC2 instance type.
+func (c AWSClusterConfig) slotsForInstanceType(name string) int {
+	for _, t := range c.LaunchTypes() {
+		if t.Name == name {
+			return c.slotsPerInstance(t)
+		}
+	}
+	return c.slotsPerInstance(ec2InstanceTypeConfig{Name: name})
+}
+
 // SlotType returns the type of the slot.
 func (c AWSClusterConfig) SlotType() device.Type {
-	slots := c.InstanceType.Slots()
+	slots := c.LaunchTypes()[0].Slots()
 	if slots > 0 {
 		return device.GPU
 	}
@@ -220,10 +265,49 @@ func (t ec2InstanceType) Validate() []error {
 	}
 }
 
+// ec2InstanceTypeConfig is an entry of the instance_types list. The number of slots defaults to
+// the number of GPUs of the instance type, which must be known unless the slots are given.
+type ec2InstanceTypeConfig struct {
+	Name     string `json:"name"`
+	NumSlots *int   `json:"slots,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. An entry may be just the name of the
+// instance type.
+func (t *ec2InstanceTypeConfig) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.Name); err == nil {
+		return nil
+	}
+	type DefaultParser *ec2InstanceTypeConfig
+	return errors.Wrap(json.Unmarshal(data, DefaultParser(t)), "failed to parse ec2 instance type")
+}
+
+func (t ec2InstanceTypeConfig) name() string {
+	return t.Name
+}
+
+func (t ec2InstanceTypeConfig) Slots() int {
+	if t.NumSlots != nil {
+		return *t.NumSlots
+	}
+	return ec2InstanceType(t.Name).Slots()
+}
+
+func (t ec2InstanceTypeConfig) Validate() []error {
+	if t.NumSlots == nil {
+		return ec2InstanceType(t.Name).Validate()
+	}
+	return []error{
+		check.NotEmpty(t.Name, "ec2 instance type name must be non-empty"),
+		check.GreaterThanOrEqualTo(*t.NumSlots, 0, "ec2 instance type slots must be >= 0"),
+	}
+}
+
 // This map tracks how many slots are available in each instance type. It also
 // serves as the list of instance types that the provisioner may provision - if
 // the master.yaml is configured with an instance type not on this list, the
-// provisioner will consider it an error.
+// provisioner will consider it an error, unless the slots of the type are given
+// in instance_types.
 var ec2InstanceSlots = map[ec2InstanceType]int{
 	"g4dn.xlarge":   1,
 	"g4dn.2xlarge":  1,
@@ -3,6 +3,7 @@ package provisioner
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"gotest.tools/assert"
@@ -95,3 +96,58 @@ func TestAWSClusterConfigMissingFields(t *testing.T) {
 	err = check.Validate(&config)
 	assert.ErrorContains(t, err, "non-empty")
 }
+
+func TestAWSClusterConfigInstanceTypes(t *testing.T) {
+	var config AWSClusterConfig
+	err := yaml.Unmarshal([]byte(`
+ssh_key_name: test-key
+instance_types:
+  - p3.8xlarge
+  - name: p3.2xlarge
+  - name: p4d.24xlarge
+    slots: 8
+`), &config, yaml.DisallowUnknownFields)
+	assert.NilError(t, err)
+	assert.NilError(t, check.Validate(&config))
+
+	eight := 8
+	assert.DeepEqual(t, config.LaunchTypes(), []ec2InstanceTypeConfig{
+		{Name: "p3.8xlarge"},
+		{Name: "p3.2xlarge"},
+		{Name: "p4d.24xlarge", NumSlots: &eight},
+	})
+	assert.DeepEqual(t, config.InstanceSlots(), []int{4, 1, 8})
+	assert.Equal(t, config.SlotsPerInstance(), 4)
+	assert.Equal(t, config.slotsForInstanceType("p4d.24xlarge"), 8)
+	assert.Equal(t, config.slotsForInstanceType("p2.xlarge"), 1)
+
+	cluster := awsCluster{AWSClusterConfig: &config, exhaustedTypes: map[string]time.Time{}}
+	assert.Equal(t, cluster.instanceType().name(), "p3.8xlarge")
+	cluster.exhaustedTypes["p3.8xlarge"] = time.Now().Add(time.Minute)
+	assert.Equal(t, cluster.instanceType().name(), "p3.2xlarge")
+	assert.Equal(t, cluster.slotsPerInstance(), 1)
+	cluster.exhaustedTypes["p3.2xlarge"] = time.Now().Add(time.Minute)
+	cluster.exhaustedTypes["p4d.24xlarge"] = time.Now().Add(time.Minute)
+	assert.Equal(t, cluster.instanceType().name(), "p3.8xlarge")
+	cluster.exhaustedTypes["p3.8xlarge"] = time.Now().Add(-time.Minute)
+	assert.Equal(t, cluster.instanceType().name(), "p3.8xlarge")
+
+	var legacy AWSClusterConfig
+	assert.NilError(t, json.Unmarshal([]byte(`{"instance_type": "p2.16xlarge"}`), &legacy))
+	assert.DeepEqual(t, legacy.InstanceSlots(), []int{16})
+}
+
+func TestAWSClusterConfigInvalidInstanceTypes(t *testing.T) {
+	var config AWSClusterConfig
+	err := yaml.Unmarshal([]byte(`
+ssh_key_name: test-key
+instance_types:
+  - p3.8xlarge
+  - t2.medium
+  - unknown.xlarge
+`), &config, yaml.DisallowUnknownFields)
+	assert.NilError(t, err)
+	err = check.Validate(&config)
+	assert.ErrorContains(t, err, "ec2 instance type must be valid type")
+	assert.ErrorContains(t, err, "must either all have GPU slots or all have no GPU slots")
+}
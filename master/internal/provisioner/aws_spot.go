@@ -3,6 +3,7 @@ package provisioner
 import (
 	"encoding/base64"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +19,20 @@ import (
 const spotRequestIDPrefix = "sir-"
 const launchTimeOffsetGrowth = time.Second * 10
 
+// spotPriceRefreshPeriod is how long a spot price that was looked up is reused for instances of the
+// same type in the same availability zone.
+const spotPriceRefreshPeriod = time.Hour
+
+type spotPriceKey struct {
+	instanceType     string
+	availabilityZone string
+}
+
+type cachedSpotPrice struct {
+	price     float64
+	fetchTime time.Time
+}
+
 type spotRequest struct {
 	SpotRequestID string
 	State         string
@@ -25,6 +40,14 @@ type spotRequest struct {
 	StatusMessage *string
 	InstanceID    *string
 	CreationTime  time.Time
+	InstanceType  string
+}
+
+// spotCapacityStatusCodes are the status codes of open spot requests that may be fulfilled with
+// a different instance type.
+var spotCapacityStatusCodes = map[string]bool{
+	"capacity-not-available":  true,
+	"capacity-oversubscribed": true,
 }
 
 // How Spot Works:
@@ -129,6 +152,8 @@ func (c *awsCluster) listSpot(ctx *actor.Context) ([]*Instance, error) {
 			Error("unable to create tags on ec2 instances created by spot")
 	}
 
+	c.fallBackFromUnfulfillableSpotRequests(ctx, activeReqsInAPI)
+
 	reqsToNotifyUserAbout := newSetOfSpotRequests()
 	for _, req := range activeReqsInAPI.iter() {
 		switch *req.StatusCode {
@@ -276,12 +301,18 @@ func (c *awsCluster) launchSpot(
 		return
 	}
 
+	launchType := c.launchType()
 	ctx.Log().
 		WithField("log-type", "launchSpot.start").
-		Infof("launching %d EC2 spot requests", instanceNum)
-	resp, err := c.createSpotInstanceRequestsCorrectingForClockSkew(ctx, instanceNum, false)
+		Infof("launching %d EC2 spot requests (type %s)", instanceNum, launchType.name())
+	resp, err := c.createSpotInstanceRequestsCorrectingForClockSkew(
+		ctx, instanceNum, launchType, false,
+	)
 	if err != nil {
 		ctx.Log().WithError(err).Error("cannot launch EC2 spot requests")
+		if isEC2CapacityError(err) {
+			c.fallBack(ctx, launchType.name())
+		}
 		return
 	}
 
@@ -296,6 +327,7 @@ func (c *awsCluster) launchSpot(
 			StatusMessage: request.Status.Message,
 			CreationTime:  *request.CreateTime,
 			InstanceID:    nil,
+			InstanceType:  launchType.name(),
 		})
 
 		ctx.Log().
@@ -350,8 +382,7 @@ func (c *awsCluster) attemptToApproximateClockSkew(ctx *actor.Context) {
 	ctx.Log().Debug("new AWS spot provisioner. launching spot request to determined approximate " +
 		"clock skew between local machine and AWS API.")
 	localCreateTime := time.Now()
-	resp, err := c.createSpotInstanceRequest(ctx, 1, c.AWSClusterConfig.InstanceType,
-		time.Hour*100, false)
+	resp, err := c.createSpotInstanceRequest(ctx, 1, c.launchType(), time.Hour*100, false)
 	if err != nil {
 		ctx.Log().
 			WithError(err).
@@ -406,6 +437,7 @@ func (c *awsCluster) buildInstanceListFromTrackedReqs(ctx *actor.Context) ([]*In
 				LaunchTime: activeRequest.CreationTime,
 				AgentName:  activeRequest.SpotRequestID,
 				State:      SpotRequestPendingAWS,
+				Slots:      c.slotsForInstanceType(activeRequest.InstanceType),
 			})
 		}
 	}
@@ -435,6 +467,9 @@ func (c *awsCluster) buildInstanceListFromTrackedReqs(ctx *actor.Context) ([]*In
 			ctx.Log().Errorf("unknown instance state for instance %v", inst.ID)
 		}
 	}
+	if c.SpotPriceTracking {
+		c.setSpotPrices(ctx, nonTerminalInstances, realInstances)
+	}
 
 	combined := append(realInstances, pendingSpotRequestsAsInstances...)
 	ctx.Log().
@@ -444,6 +479,57 @@ func (c *awsCluster) buildInstanceListFromTrackedReqs(ctx *actor.Context) ([]*In
 	return combined, nil
 }
 
+// fallBackFromUnfulfillableSpotRequests cancels the open spot requests that cannot be fulfilled for
+// lack of capacity, so that the provisioner requests the next instance type instead. It does
+// nothing if there is only one instance type to launch.
+func (c *awsCluster) fallBackFromUnfulfillableSpotRequests(
+	ctx *actor.Context, activeReqs *setOfSpotRequests,
+) {
+	if len(c.LaunchTypes()) < 2 {
+		return
+	}
+
+	unfulfillable := newSetOfSpotRequests()
+	for _, req := range activeReqs.iter() {
+		if req.InstanceID == nil && req.StatusCode != nil && spotCapacityStatusCodes[*req.StatusCode] {
+			unfulfillable.add(req)
+		}
+	}
+	if unfulfillable.numReqs() == 0 {
+		return
+	}
+
+	ctx.Log().Infof(
+		"canceling spot requests without capacity: %s",
+		strings.Join(unfulfillable.idsAsList(), ","),
+	)
+	_, err := c.terminateSpotInstanceRequests(ctx, unfulfillable.idsAsListOfPointers(), false)
+	if err != nil {
+		ctx.Log().WithError(err).Error("cannot cancel spot requests without capacity")
+		return
+	}
+
+	exhausted := newSetOfStrings()
+	for _, req := range unfulfillable.iter() {
+		c.spot.trackedReqs.delete(req)
+		activeReqs.delete(req)
+		exhausted.add(req.InstanceType)
+	}
+	for _, name := range exhausted.asList() {
+		if name != "" {
+			c.fallBack(ctx, name)
+		}
+	}
+}
+
+// spotRequestInstanceType returns the instance type requested by a spot request.
+func spotRequestInstanceType(req *ec2.SpotInstanceRequest) string {
+	if req.LaunchSpecification == nil {
+		return ""
+	}
+	return aws.StringValue(req.LaunchSpecification.InstanceType)
+}
+
 func roundDurationUp(d time.Duration) time.Duration {
 	roundInterval := time.Second * 10
 	rounded := d.Round(roundInterval)
@@ -463,12 +549,13 @@ func roundDurationUp(d time.Duration) time.Duration {
 func (c *awsCluster) createSpotInstanceRequestsCorrectingForClockSkew(
 	ctx *actor.Context,
 	numInstances int,
+	instanceType ec2InstanceTypeConfig,
 	dryRun bool,
 ) (resp *ec2.RequestSpotInstancesOutput, err error) {
 	maxRetries := 5
 	for numRetries := 0; numRetries <= maxRetries; numRetries++ {
 		offset := c.spot.approximateClockSkew + c.spot.launchTimeOffset
-		resp, err = c.createSpotInstanceRequest(ctx, numInstances, c.InstanceType, offset, dryRun)
+		resp, err = c.createSpotInstanceRequest(ctx, numInstances, instanceType, offset, dryRun)
 		if err == nil {
 			return resp, nil
 		}
@@ -495,7 +582,7 @@ func (c *awsCluster) createSpotInstanceRequestsCorrectingForClockSkew(
 func (c *awsCluster) createSpotInstanceRequest(
 	ctx *actor.Context,
 	numInstances int,
-	instanceType ec2InstanceType,
+	instanceType ec2InstanceTypeConfig,
 	launchTimeOffset time.Duration,
 	dryRun bool,
 ) (*ec2.RequestSpotInstancesOutput, error) {
@@ -629,6 +716,7 @@ func (c *awsCluster) listCanceledButInstanceRunningSpotRequests(
 			StatusMessage: req.Status.Message,
 			InstanceID:    req.InstanceId,
 			CreationTime:  *req.CreateTime,
+			InstanceType:  spotRequestInstanceType(req),
 		})
 	}
 
@@ -680,6 +768,7 @@ func (c *awsCluster) listActiveSpotInstanceRequests(
 			StatusMessage: req.Status.Message,
 			InstanceID:    req.InstanceId,
 			CreationTime:  *req.CreateTime,
+			InstanceType:  spotRequestInstanceType(req),
 		})
 	}
 
@@ -738,6 +827,7 @@ func (c *awsCluster) listSpotRequestsByID(
 			StatusMessage: req.Status.Message,
 			InstanceID:    req.InstanceId,
 			CreationTime:  *req.CreateTime,
+			InstanceType:  spotRequestInstanceType(req),
 		})
 	}
 
@@ -759,3 +849,53 @@ func (c *awsCluster) terminateSpotInstanceRequests(
 
 	return c.client.CancelSpotInstanceRequests(input)
 }
+
+// setSpotPrices sets the hourly price of the spot instances to the current spot price of their
+// instance type in their availability zone.
+func (c *awsCluster) setSpotPrices(
+	ctx *actor.Context, input []*ec2.Instance, instances []*Instance,
+) {
+	for i, inst := range input {
+		if aws.StringValue(inst.InstanceLifecycle) != ec2.InstanceLifecycleTypeSpot ||
+			inst.Placement == nil {
+			continue
+		}
+		key := spotPriceKey{
+			instanceType:     aws.StringValue(inst.InstanceType),
+			availabilityZone: aws.StringValue(inst.Placement.AvailabilityZone),
+		}
+		cached, ok := c.spotPrices[key]
+		if !ok || time.Since(cached.fetchTime) > spotPriceRefreshPeriod {
+			price, err := c.describeSpotPrice(key)
+			if err != nil {
+				ctx.Log().WithError(err).Warnf("cannot look up the spot price of %s in %s",
+					key.instanceType, key.availabilityZone)
+				continue
+			}
+			cached = cachedSpotPrice{price: price, fetchTime: time.Now()}
+			c.spotPrices[key] = cached
+		}
+		instances[i].HourlyPrice = cached.price
+	}
+}
+
+func (c *awsCluster) describeSpotPrice(key spotPriceKey) (float64, error) {
+	input := &ec2.DescribeSpotPriceHistoryInput{
+		AvailabilityZone:    aws.String(key.availabilityZone),
+		InstanceTypes:       []*string{aws.String(key.instanceType)},
+		ProductDescriptions: []*string{aws.String("Linux/UNIX")},
+		StartTime:           aws.Time(time.Now()),
+	}
+	result, err := c.client.DescribeSpotPriceHistory(input)
+	if err != nil {
+		return 0, err
+	}
+	if len(result.SpotPriceHistory) == 0 {
+		return 0, errors.New("no spot price history found")
+	}
+	price, err := strconv.ParseFloat(aws.StringValue(result.SpotPriceHistory[0].SpotPrice), 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot parse spot price")
+	}
+	return price, nil
+}
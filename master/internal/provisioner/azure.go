@@ -0,0 +1,322 @@
+package provisioner
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+)
+
+// maxAzureScaleSetNameLen is the max length of a virtual machine scale set name.
+const maxAzureScaleSetNameLen = 64
+
+var invalidAzureNameChars = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+// azureCluster wraps an Azure Resource Manager client. Each resource pool is backed by a virtual
+// machine scale set that the provisioner scales out to launch agents and scales in by deleting
+// specific VMs. Determined recognizes the scale set by:
+// 1. A specific key/value pair tag.
+// 2. Names of agents that are equal to the VM names.
+type azureCluster struct {
+	*AzureClusterConfig
+	resourcePool string
+	scaleSetName string
+	masterURL    url.URL
+	customData   string
+
+	client *azureClient
+}
+
+func newAzureCluster(
+	resourcePool string, config *Config, cert *tls.Certificate,
+) (*azureCluster, error) {
+	if err := config.Azure.initDefaultValues(); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize auto configuration")
+	}
+
+	masterURL, err := url.Parse(config.MasterURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse master url")
+	}
+
+	startupScriptBase64 := base64.StdEncoding.EncodeToString([]byte(config.StartupScript))
+	containerScriptBase64 := base64.StdEncoding.EncodeToString([]byte(config.ContainerStartupScript))
+
+	var certBytes []byte
+	if masterURL.Scheme == secureScheme && cert != nil {
+		for _, c := range cert.Certificate {
+			b := pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: c,
+			})
+			certBytes = append(certBytes, b...)
+		}
+	}
+	masterCertBase64 := base64.StdEncoding.EncodeToString(certBytes)
+
+	startupScript := mustMakeAgentSetupScript(agentSetupScriptConfig{
+		MasterHost:                   masterURL.Hostname(),
+		MasterPort:                   masterURL.Port(),
+		MasterCertName:               config.MasterCertName,
+		SlotType:                     config.Azure.SlotType(),
+		AgentNetwork:                 config.AgentDockerNetwork,
+		AgentDockerRuntime:           config.AgentDockerRuntime,
+		AgentDockerImage:             config.AgentDockerImage,
+		AgentFluentImage:             config.AgentFluentImage,
+		StartupScriptBase64:          startupScriptBase64,
+		ContainerStartupScriptBase64: containerScriptBase64,
+		MasterCertBase64:             masterCertBase64,
+		AgentID: `$(curl -s -H Metadata:true "http://169.254.169.254/metadata/instance/` +
+			`compute/name?api-version=2021-02-01&format=text")`,
+		ResourcePool: resourcePool,
+	})
+
+	scaleSetName := config.Azure.ScaleSetName
+	if len(scaleSetName) == 0 {
+		scaleSetName = defaultAzureScaleSetName(config.Azure.TagValue, resourcePool)
+	}
+
+	return &azureCluster{
+		AzureClusterConfig: config.Azure,
+		resourcePool:       resourcePool,
+		scaleSetName:       scaleSetName,
+		masterURL:          *masterURL,
+		customData:         base64.StdEncoding.EncodeToString(startupScript),
+		client:             newAzureClient(config.Azure),
+	}, nil
+}
+
+// defaultAzureScaleSetName derives a scale set name that is unique per master and resource pool.
+func defaultAzureScaleSetName(identifier, resourcePool string) string {
+	name := invalidAzureNameChars.ReplaceAllString(identifier+"-"+resourcePool, "-")
+	if len(name) > maxAzureScaleSetNameLen {
+		name = name[:maxAzureScaleSetNameLen]
+	}
+	return strings.Trim(name, "-")
+}
+
+func (c *azureCluster) instanceType() instanceType {
+	return c.InstanceType
+}
+
+func (c *azureCluster) slotsPerInstance() int {
+	return c.AzureClusterConfig.SlotsPerInstance()
+}
+
+func (c *azureCluster) instanceSlots() []int {
+	return []int{c.slotsPerInstance()}
+}
+
+func (c *azureCluster) prestart(ctx *actor.Context) {}
+
+func (c *azureCluster) tags() map[string]string {
+	tags := map[string]string{}
+	for k, v := range c.CustomTags {
+		tags[k] = v
+	}
+	tags[c.TagKey] = c.TagValue
+	tags["determined-resource-pool"] = c.resourcePool
+	tags["determined-master-address"] = c.masterURL.Host
+	return tags
+}
+
+func (c *azureCluster) ownsScaleSet(scaleSet *azureScaleSet) bool {
+	return scaleSet.Tags[c.TagKey] == c.TagValue &&
+		scaleSet.Tags["determined-resource-pool"] == c.resourcePool
+}
+
+func (c *azureCluster) list(ctx *actor.Context) ([]*Instance, error) {
+	vms, err := c.client.listVMs(c.scaleSetName)
+	switch {
+	case isAzureNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "cannot list Azure VMs")
+	}
+	res := c.newInstances(vms)
+	for i, inst := range res {
+		if inst.State == Unknown {
+			ctx.Log().Errorf("unknown instance state for instance %v: %v",
+				inst.ID, vms[i].Properties.ProvisioningState)
+		}
+	}
+	return res, nil
+}
+
+func (c *azureCluster) launch(ctx *actor.Context, instanceNum int) {
+	if instanceNum <= 0 {
+		return
+	}
+
+	scaleSet, err := c.client.getScaleSet(c.scaleSetName)
+	if err != nil {
+		ctx.Log().WithError(err).Errorf("cannot get Azure scale set %s", c.scaleSetName)
+		return
+	}
+
+	if scaleSet == nil {
+		if err := c.client.createScaleSet(c.scaleSetName, c.scaleSetSpec(instanceNum)); err != nil {
+			ctx.Log().WithError(err).Errorf("cannot create Azure scale set %s", c.scaleSetName)
+			return
+		}
+		ctx.Log().Infof("created Azure scale set %s with %d VMs", c.scaleSetName, instanceNum)
+		return
+	}
+
+	if !c.ownsScaleSet(scaleSet) {
+		ctx.Log().Errorf("Azure scale set %s exists but is not tagged with %s=%s for "+
+			"resource pool %s", c.scaleSetName, c.TagKey, c.TagValue, c.resourcePool)
+		return
+	}
+	capacity := scaleSet.SKU.Capacity + instanceNum
+	if err := c.client.setCapacity(c.scaleSetName, capacity); err != nil {
+		ctx.Log().WithError(err).Errorf("cannot scale Azure scale set %s", c.scaleSetName)
+		return
+	}
+	ctx.Log().Infof("scaled Azure scale set %s out by %d VMs to %d",
+		c.scaleSetName, instanceNum, capacity)
+}
+
+func (c *azureCluster) terminate(ctx *actor.Context, instanceIDs []string) {
+	if len(instanceIDs) == 0 {
+		return
+	}
+
+	// Deleting specific VMs also lowers the capacity of the scale set, so that it does not replace
+	// them.
+	if err := c.client.deleteVMs(c.scaleSetName, instanceIDs); err != nil {
+		ctx.Log().WithError(err).Errorf("cannot delete Azure VMs: %s", strings.Join(instanceIDs, ", "))
+		return
+	}
+	ctx.Log().Infof("deleted %d Azure VMs: %s", len(instanceIDs), strings.Join(instanceIDs, ", "))
+}
+
+func (c *azureCluster) scaleSetSpec(capacity int) *azureScaleSet {
+	nic := azureNICConfig{Name: "determined-agent-nic"}
+	nic.Properties.Primary = true
+	ipConfig := azureIPConfig{Name: "determined-agent-ip"}
+	ipConfig.Properties.Subnet.ID = c.SubnetID
+	if c.PublicIP {
+		ipConfig.Properties.PublicIPAddressConfiguration = &azurePublicIPConfig{
+			Name: "determined-agent-public-ip",
+		}
+	}
+	nic.Properties.IPConfigurations = []azureIPConfig{ipConfig}
+
+	linux := azureLinuxSettings{DisablePasswordAuthentication: true}
+	linux.SSH.PublicKeys = []azureSSHPublicKey{{
+		Path:    "/home/" + c.AdminUsername + "/.ssh/authorized_keys",
+		KeyData: c.SSHPublicKey,
+	}}
+
+	image := c.Image
+	if len(image.ID) != 0 {
+		image = azureImage{ID: image.ID}
+	}
+
+	profile := azureVMProfile{
+		OSProfile: azureOSProfile{
+			ComputerNamePrefix: "determined",
+			AdminUsername:      c.AdminUsername,
+			CustomData:         c.customData,
+			LinuxConfiguration: linux,
+		},
+		StorageProfile: azureStorageProfile{
+			ImageReference: image,
+			OSDisk: azureOSDisk{
+				CreateOption: "FromImage",
+				DiskSizeGB:   c.OSDiskSize,
+				Caching:      "ReadWrite",
+			},
+		},
+		NetworkProfile: azureNetworkProfile{
+			NetworkInterfaceConfigurations: []azureNICConfig{nic},
+		},
+	}
+	if c.SpotEnabled {
+		profile.Priority = "Spot"
+		profile.EvictionPolicy = "Delete"
+		profile.BillingProfile = &azureBillingProfile{MaxPrice: c.SpotMaxPrice}
+	}
+
+	scaleSet := &azureScaleSet{
+		Location: c.Location,
+		Tags:     c.tags(),
+		SKU: azureSKU{
+			Name:     c.InstanceType.name(),
+			Tier:     "Standard",
+			Capacity: capacity,
+		},
+		Properties: azureScaleSetProperties{
+			UpgradePolicy:         map[string]string{"mode": "Manual"},
+			Overprovision:         false,
+			VirtualMachineProfile: profile,
+		},
+	}
+	if len(c.ManagedIdentityID) != 0 {
+		scaleSet.Identity = &azureIdentity{
+			Type:                   "UserAssigned",
+			UserAssignedIdentities: map[string]interface{}{c.ManagedIdentityID: struct{}{}},
+		}
+	}
+	return scaleSet
+}
+
+// See https://docs.microsoft.com/en-us/azure/virtual-machines/states-billing.
+var azurePowerStates = map[string]InstanceState{
+	"PowerState/starting":     Starting,
+	"PowerState/running":      Running,
+	"PowerState/stopping":     Stopping,
+	"PowerState/stopped":      Stopped,
+	"PowerState/deallocating": Stopping,
+	"PowerState/deallocated":  Stopped,
+}
+
+func (c *azureCluster) stateFromVM(vm azureScaleSetVM) InstanceState {
+	switch vm.Properties.ProvisioningState {
+	case "Creating":
+		return Starting
+	case "Deleting":
+		return Terminating
+	case "Failed":
+		return Unknown
+	}
+	if vm.Properties.InstanceView == nil {
+		return Starting
+	}
+	for _, status := range vm.Properties.InstanceView.Statuses {
+		if state, ok := azurePowerStates[status.Code]; ok {
+			return state
+		}
+	}
+	return Starting
+}
+
+func (c *azureCluster) newInstances(vms []azureScaleSetVM) []*Instance {
+	output := make([]*Instance, 0, len(vms))
+	for _, vm := range vms {
+		// VMs created before timeCreated was reported fall back to their earliest status time.
+		launchTime := vm.Properties.TimeCreated
+		if launchTime.IsZero() && vm.Properties.InstanceView != nil {
+			for _, status := range vm.Properties.InstanceView.Statuses {
+				if !status.Time.IsZero() && (launchTime.IsZero() || status.Time.Before(launchTime)) {
+					launchTime = status.Time
+				}
+			}
+		}
+		output = append(output, &Instance{
+			ID:         vm.InstanceID,
+			LaunchTime: launchTime,
+			AgentName:  vm.Name,
+			State:      c.stateFromVM(vm),
+			Slots:      c.slotsPerInstance(),
+		})
+	}
+	return output
+}
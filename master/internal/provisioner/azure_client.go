@@ -0,0 +1,403 @@
+package provisioner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	azureComputeAPIVersion  = "2021-11-01"
+	azureMetadataAPIVersion = "2021-02-01"
+	azureIdentityAPIVersion = "2018-02-01"
+	azureRequestTimeout     = 30 * time.Second
+)
+
+// azureMetadataEndpoint is the Azure Instance Metadata Service. It is a variable so that tests
+// can point it at a fake server.
+var azureMetadataEndpoint = "http://169.254.169.254"
+
+type azureComputeMetadata struct {
+	Name              string `json:"name"`
+	Location          string `json:"location"`
+	ResourceGroupName string `json:"resourceGroupName"`
+	SubscriptionID    string `json:"subscriptionId"`
+}
+
+func getAzureMetadata(path string, query url.Values, out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, azureMetadataEndpoint+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("azure instance metadata returned %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func getAzureComputeMetadata() (*azureComputeMetadata, error) {
+	var compute azureComputeMetadata
+	if err := getAzureMetadata("/metadata/instance/compute", url.Values{
+		"api-version": {azureMetadataAPIVersion},
+	}, &compute); err != nil {
+		return nil, errors.Wrap(err, "cannot get azure instance metadata")
+	}
+	return &compute, nil
+}
+
+func getAzureIPAddress(public bool) (string, error) {
+	var ipAddresses []struct {
+		PrivateIPAddress string `json:"privateIpAddress"`
+		PublicIPAddress  string `json:"publicIpAddress"`
+	}
+	if err := getAzureMetadata("/metadata/instance/network/interface/0/ipv4/ipAddress", url.Values{
+		"api-version": {azureMetadataAPIVersion},
+	}, &ipAddresses); err != nil {
+		return "", err
+	}
+	if len(ipAddresses) == 0 {
+		return "", errors.New("no ip address found in the azure instance metadata")
+	}
+	if public {
+		if len(ipAddresses[0].PublicIPAddress) == 0 {
+			return "", errors.New("the azure instance does not have a public ip address")
+		}
+		return ipAddresses[0].PublicIPAddress, nil
+	}
+	return ipAddresses[0].PrivateIPAddress, nil
+}
+
+func onAzure() bool {
+	_, err := getAzureComputeMetadata()
+	return err == nil
+}
+
+// azureIdentityTokenSource fetches access tokens for the managed identity of the VM the master
+// runs on from the instance metadata service.
+type azureIdentityTokenSource struct {
+	resource string
+	clientID string
+}
+
+func (s azureIdentityTokenSource) Token() (*oauth2.Token, error) {
+	query := url.Values{
+		"api-version": {azureIdentityAPIVersion},
+		"resource":    {s.resource},
+	}
+	if len(s.clientID) != 0 {
+		query.Set("client_id", s.clientID)
+	}
+	var token struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+		TokenType   string      `json:"token_type"`
+	}
+	if err := getAzureMetadata("/metadata/identity/oauth2/token", query, &token); err != nil {
+		return nil, errors.Wrap(err, "cannot get azure managed identity token")
+	}
+	expiresIn, err := token.ExpiresIn.Int64()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse azure managed identity token expiry")
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(expiresIn) * time.Second),
+	}, nil
+}
+
+// azureError is the error body returned by Azure Resource Manager.
+type azureError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *azureError) Error() string {
+	return fmt.Sprintf("azure returned %d (%s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+func isAzureNotFound(err error) bool {
+	azErr, ok := errors.Cause(err).(*azureError)
+	return ok && azErr.StatusCode == http.StatusNotFound
+}
+
+// azureClient is a minimal Azure Resource Manager client for the virtual machine scale set
+// operations the provisioner needs.
+type azureClient struct {
+	endpoint       string
+	subscriptionID string
+	resourceGroup  string
+	http           *http.Client
+}
+
+func newAzureClient(config *AzureClusterConfig) *azureClient {
+	// Without explicitly configured credentials, the client authenticates as the managed identity
+	// of the VM that the master runs on. Either way, the identity needs permissions to manage
+	// virtual machine scale sets in the resource group and to join VMs to the subnet, e.g., the
+	// "Virtual Machine Contributor" and "Network Contributor" roles.
+	resource := strings.TrimSuffix(config.ManagementEndpoint, "/") + "/"
+	var tokens oauth2.TokenSource
+	if len(config.ClientSecret) != 0 {
+		tokens = (&clientcredentials.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			TokenURL: fmt.Sprintf("%s/%s/oauth2/v2.0/token",
+				strings.TrimSuffix(config.AuthorityHost, "/"), config.TenantID),
+			Scopes:    []string{resource + ".default"},
+			AuthStyle: oauth2.AuthStyleInParams,
+		}).TokenSource(context.Background())
+	} else {
+		tokens = oauth2.ReuseTokenSource(nil, azureIdentityTokenSource{
+			resource: resource,
+			clientID: config.ClientID,
+		})
+	}
+
+	httpClient := oauth2.NewClient(context.Background(), tokens)
+	httpClient.Timeout = azureRequestTimeout
+	return &azureClient{
+		endpoint:       strings.TrimSuffix(config.ManagementEndpoint, "/"),
+		subscriptionID: config.SubscriptionID,
+		resourceGroup:  config.ResourceGroup,
+		http:           httpClient,
+	}
+}
+
+func (c *azureClient) scaleSetURL(name string, subpath ...string) string {
+	parts := append([]string{
+		c.endpoint,
+		"subscriptions", url.PathEscape(c.subscriptionID),
+		"resourceGroups", url.PathEscape(c.resourceGroup),
+		"providers/Microsoft.Compute/virtualMachineScaleSets", url.PathEscape(name),
+	}, subpath...)
+	return strings.Join(parts, "/") + "?api-version=" + azureComputeAPIVersion
+}
+
+func (c *azureClient) do(method, target string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var armErr struct {
+			Error azureError `json:"error"`
+		}
+		if err := json.Unmarshal(respBody, &armErr); err != nil || len(armErr.Error.Code) == 0 {
+			armErr.Error = azureError{Code: resp.Status, Message: string(respBody)}
+		}
+		armErr.Error.StatusCode = resp.StatusCode
+		return &armErr.Error
+	}
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// getScaleSet returns the scale set with the given name, or nil if it does not exist.
+func (c *azureClient) getScaleSet(name string) (*azureScaleSet, error) {
+	var scaleSet azureScaleSet
+	switch err := c.do(http.MethodGet, c.scaleSetURL(name), nil, &scaleSet); {
+	case isAzureNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return &scaleSet, nil
+}
+
+func (c *azureClient) createScaleSet(name string, scaleSet *azureScaleSet) error {
+	return c.do(http.MethodPut, c.scaleSetURL(name), scaleSet, nil)
+}
+
+func (c *azureClient) setCapacity(name string, capacity int) error {
+	update := map[string]interface{}{
+		"sku": map[string]interface{}{"capacity": capacity},
+	}
+	return c.do(http.MethodPatch, c.scaleSetURL(name), update, nil)
+}
+
+func (c *azureClient) listVMs(name string) ([]azureScaleSetVM, error) {
+	var vms []azureScaleSetVM
+	target := c.scaleSetURL(name, "virtualMachines") + "&$expand=instanceView"
+	for target != "" {
+		var page struct {
+			Value    []azureScaleSetVM `json:"value"`
+			NextLink string            `json:"nextLink"`
+		}
+		if err := c.do(http.MethodGet, target, nil, &page); err != nil {
+			return nil, err
+		}
+		vms = append(vms, page.Value...)
+		target = page.NextLink
+	}
+	return vms, nil
+}
+
+func (c *azureClient) deleteVMs(name string, instanceIDs []string) error {
+	return c.do(http.MethodPost, c.scaleSetURL(name, "delete"), map[string]interface{}{
+		"instanceIds": instanceIDs,
+	}, nil)
+}
+
+// The following types are the subset of the Azure Resource Manager virtual machine scale set
+// model that the provisioner uses.
+// See https://docs.microsoft.com/en-us/rest/api/compute/virtual-machine-scale-sets.
+type (
+	azureScaleSet struct {
+		Location   string                  `json:"location"`
+		Tags       map[string]string       `json:"tags,omitempty"`
+		SKU        azureSKU                `json:"sku"`
+		Identity   *azureIdentity          `json:"identity,omitempty"`
+		Properties azureScaleSetProperties `json:"properties"`
+	}
+
+	azureSKU struct {
+		Name     string `json:"name"`
+		Tier     string `json:"tier,omitempty"`
+		Capacity int    `json:"capacity"`
+	}
+
+	azureIdentity struct {
+		Type                   string                 `json:"type"`
+		UserAssignedIdentities map[string]interface{} `json:"userAssignedIdentities,omitempty"`
+	}
+
+	azureScaleSetProperties struct {
+		UpgradePolicy         map[string]string `json:"upgradePolicy"`
+		Overprovision         bool              `json:"overprovision"`
+		VirtualMachineProfile azureVMProfile    `json:"virtualMachineProfile"`
+	}
+
+	azureVMProfile struct {
+		Priority       string               `json:"priority,omitempty"`
+		EvictionPolicy string               `json:"evictionPolicy,omitempty"`
+		BillingProfile *azureBillingProfile `json:"billingProfile,omitempty"`
+		OSProfile      azureOSProfile       `json:"osProfile"`
+		StorageProfile azureStorageProfile  `json:"storageProfile"`
+		NetworkProfile azureNetworkProfile  `json:"networkProfile"`
+	}
+
+	azureBillingProfile struct {
+		MaxPrice float64 `json:"maxPrice"`
+	}
+
+	azureOSProfile struct {
+		ComputerNamePrefix string             `json:"computerNamePrefix"`
+		AdminUsername      string             `json:"adminUsername"`
+		CustomData         string             `json:"customData"`
+		LinuxConfiguration azureLinuxSettings `json:"linuxConfiguration"`
+	}
+
+	azureLinuxSettings struct {
+		DisablePasswordAuthentication bool `json:"disablePasswordAuthentication"`
+		SSH                           struct {
+			PublicKeys []azureSSHPublicKey `json:"publicKeys"`
+		} `json:"ssh"`
+	}
+
+	azureSSHPublicKey struct {
+		Path    string `json:"path"`
+		KeyData string `json:"keyData"`
+	}
+
+	azureStorageProfile struct {
+		ImageReference azureImage  `json:"imageReference"`
+		OSDisk         azureOSDisk `json:"osDisk"`
+	}
+
+	azureOSDisk struct {
+		CreateOption string `json:"createOption"`
+		DiskSizeGB   int    `json:"diskSizeGB"`
+		Caching      string `json:"caching,omitempty"`
+	}
+
+	azureNetworkProfile struct {
+		NetworkInterfaceConfigurations []azureNICConfig `json:"networkInterfaceConfigurations"`
+	}
+
+	azureNICConfig struct {
+		Name       string `json:"name"`
+		Properties struct {
+			Primary          bool            `json:"primary"`
+			IPConfigurations []azureIPConfig `json:"ipConfigurations"`
+		} `json:"properties"`
+	}
+
+	azureIPConfig struct {
+		Name       string `json:"name"`
+		Properties struct {
+			Subnet                       azureSubResource     `json:"subnet"`
+			PublicIPAddressConfiguration *azurePublicIPConfig `json:"publicIPAddressConfiguration,omitempty"`
+		} `json:"properties"`
+	}
+
+	azureSubResource struct {
+		ID string `json:"id"`
+	}
+
+	azurePublicIPConfig struct {
+		Name string `json:"name"`
+	}
+
+	azureScaleSetVM struct {
+		InstanceID string `json:"instanceId"`
+		Name       string `json:"name"`
+		Properties struct {
+			ProvisioningState string    `json:"provisioningState"`
+			TimeCreated       time.Time `json:"timeCreated"`
+			InstanceView      *struct {
+				Statuses []azureInstanceViewStatus `json:"statuses"`
+			} `json:"instanceView"`
+		} `json:"properties"`
+	}
+
+	azureInstanceViewStatus struct {
+		Code string    `json:"code"`
+		Time time.Time `json:"time"`
+	}
+)
@@ -0,0 +1,265 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+const (
+	defaultAzureManagementEndpoint = "https://management.azure.com"
+	defaultAzureAuthorityHost      = "https://login.microsoftonline.com"
+
+	// azureSpotMaxPriceOnDemand lets spot VMs be paid up to the on-demand price, in which case
+	// they are only evicted for capacity reasons.
+	azureSpotMaxPriceOnDemand = -1
+)
+
+// AzureClusterConfig describes the configuration for an Azure virtual machine scale set managed
+// by Determined. Each resource pool uses its own scale set.
+type AzureClusterConfig struct {
+	SubscriptionID string `json:"subscription_id"`
+	ResourceGroup  string `json:"resource_group"`
+	Location       string `json:"location"`
+
+	TenantID     string `json:"tenant_id"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+
+	ScaleSetName string            `json:"scale_set_name"`
+	TagKey       string            `json:"tag_key"`
+	TagValue     string            `json:"tag_value"`
+	CustomTags   map[string]string `json:"custom_tags"`
+
+	Image         azureImage `json:"image"`
+	OSDiskSize    int        `json:"os_disk_size"`
+	AdminUsername string     `json:"admin_username"`
+	SSHPublicKey  string     `json:"ssh_public_key"`
+
+	SubnetID          string `json:"subnet_id"`
+	PublicIP          bool   `json:"public_ip"`
+	ManagedIdentityID string `json:"managed_identity_id"`
+
+	InstanceType azureVMSize `json:"instance_type"`
+
+	SpotEnabled  bool    `json:"spot"`
+	SpotMaxPrice float64 `json:"spot_max_price"`
+
+	CPUSlotsAllowed bool `json:"cpu_slots_allowed"`
+
+	ManagementEndpoint string `json:"management_endpoint"`
+	AuthorityHost      string `json:"authority_host"`
+}
+
+var defaultAzureClusterConfig = AzureClusterConfig{
+	TagKey: "managed-by",
+	Image: azureImage{
+		Publisher: "microsoft-dsvm",
+		Offer:     "ubuntu-1804",
+		SKU:       "1804-gen2",
+		Version:   "latest",
+	},
+	OSDiskSize:         200,
+	AdminUsername:      "determined",
+	InstanceType:       "Standard_NC24s_v3",
+	SpotEnabled:        false,
+	SpotMaxPrice:       azureSpotMaxPriceOnDemand,
+	CPUSlotsAllowed:    false,
+	ManagementEndpoint: defaultAzureManagementEndpoint,
+	AuthorityHost:      defaultAzureAuthorityHost,
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *AzureClusterConfig) UnmarshalJSON(data []byte) error {
+	*c = defaultAzureClusterConfig
+	type DefaultParser *AzureClusterConfig
+	return json.Unmarshal(data, DefaultParser(c))
+}
+
+// Validate implements the check.Validatable interface.
+func (c AzureClusterConfig) Validate() []error {
+	var checkSpotPrice error
+	if c.SpotMaxPrice != azureSpotMaxPriceOnDemand && c.SpotMaxPrice <= 0 {
+		checkSpotPrice = errors.New("azure spot max price must be -1 or greater than 0")
+	}
+	var checkCredentials error
+	if (len(c.ClientID) == 0) != (len(c.ClientSecret) == 0) ||
+		(len(c.ClientSecret) != 0 && len(c.TenantID) == 0) {
+		checkCredentials = errors.New(
+			"azure tenant_id, client_id and client_secret must be configured together")
+	}
+	return []error{
+		check.NotEmpty(c.SubnetID, "azure subnet id must be non-empty"),
+		check.NotEmpty(c.SSHPublicKey, "azure ssh public key must be non-empty"),
+		check.NotEmpty(c.AdminUsername, "azure admin username must be non-empty"),
+		check.GreaterThanOrEqualTo(c.OSDiskSize, 100, "azure os disk size must be >= 100"),
+		checkSpotPrice,
+		checkCredentials,
+	}
+}
+
+func (c *AzureClusterConfig) initDefaultValues() error {
+	// One common reason that the instance metadata is unavailable is that the master is not
+	// running in Azure, in which case the subscription, resource group and location must be
+	// configured explicitly.
+	identifier := pkg.DeterminedIdentifier
+	if onAzure() {
+		compute, err := getAzureComputeMetadata()
+		if err != nil {
+			return err
+		}
+		if len(c.SubscriptionID) == 0 {
+			c.SubscriptionID = compute.SubscriptionID
+		}
+		if len(c.ResourceGroup) == 0 {
+			c.ResourceGroup = compute.ResourceGroupName
+		}
+		if len(c.Location) == 0 {
+			c.Location = compute.Location
+		}
+		identifier = compute.Name
+	}
+
+	switch {
+	case len(c.SubscriptionID) == 0:
+		return errors.New("cannot find the azure subscription id")
+	case len(c.ResourceGroup) == 0:
+		return errors.New("cannot find the azure resource group")
+	case len(c.Location) == 0:
+		return errors.New("cannot find the azure location")
+	}
+
+	if len(c.TagValue) == 0 {
+		c.TagValue = identifier
+	}
+	return nil
+}
+
+// SlotsPerInstance returns the number of slots per instance.
+func (c AzureClusterConfig) SlotsPerInstance() int {
+	slots := c.InstanceType.Slots()
+	if slots == 0 && c.CPUSlotsAllowed {
+		slots = 1
+	}
+
+	return slots
+}
+
+// SlotType returns the type of the slot.
+func (c AzureClusterConfig) SlotType() device.Type {
+	slots := c.InstanceType.Slots()
+	if slots > 0 {
+		return device.GPU
+	}
+	if c.CPUSlotsAllowed {
+		return device.CPU
+	}
+	return device.ZeroSlot
+}
+
+// azureImage references either a custom image by its resource ID or a marketplace image. The
+// image must provide Docker and, for GPU instances, the NVIDIA drivers and container toolkit.
+type azureImage struct {
+	ID        string `json:"id,omitempty"`
+	Publisher string `json:"publisher,omitempty"`
+	Offer     string `json:"offer,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+// Validate implements the check.Validatable interface.
+func (i azureImage) Validate() []error {
+	marketplace := len(i.Publisher) != 0 && len(i.Offer) != 0 && len(i.SKU) != 0
+	return []error{
+		check.True(len(i.ID) != 0 || marketplace,
+			"azure image must set either an id or a publisher, offer and sku"),
+	}
+}
+
+// String returns the image ID or the URN of the marketplace image.
+func (i azureImage) String() string {
+	if len(i.ID) != 0 {
+		return i.ID
+	}
+	return strings.Join([]string{i.Publisher, i.Offer, i.SKU, i.Version}, ":")
+}
+
+type azureVMSize string
+
+func (t azureVMSize) name() string {
+	return string(t)
+}
+
+func (t azureVMSize) Slots() int {
+	if s, ok := azureVMSizeSlots[t]; ok {
+		return s
+	}
+	return 0
+}
+
+func (t azureVMSize) Validate() []error {
+	if _, ok := azureVMSizeSlots[t]; ok {
+		return nil
+	}
+	strs := make([]string, 0, len(azureVMSizeSlots))
+	for t := range azureVMSizeSlots {
+		strs = append(strs, t.name())
+	}
+	return []error{
+		errors.Errorf("azure vm size must be valid type: %s", strings.Join(strs, ", ")),
+	}
+}
+
+// This map tracks how many slots are available in each VM size. It also serves as the list of
+// VM sizes that the provisioner may provision.
+var azureVMSizeSlots = map[azureVMSize]int{
+	"Standard_NC6":          1,
+	"Standard_NC12":         2,
+	"Standard_NC24":         4,
+	"Standard_NC24r":        4,
+	"Standard_NC6s_v2":      1,
+	"Standard_NC12s_v2":     2,
+	"Standard_NC24s_v2":     4,
+	"Standard_NC24rs_v2":    4,
+	"Standard_NC6s_v3":      1,
+	"Standard_NC12s_v3":     2,
+	"Standard_NC24s_v3":     4,
+	"Standard_NC24rs_v3":    4,
+	"Standard_NC4as_T4_v3":  1,
+	"Standard_NC8as_T4_v3":  1,
+	"Standard_NC16as_T4_v3": 1,
+	"Standard_NC64as_T4_v3": 4,
+	"Standard_ND6s":         1,
+	"Standard_ND12s":        2,
+	"Standard_ND24s":        4,
+	"Standard_ND24rs":       4,
+	"Standard_ND40rs_v2":    8,
+	"Standard_ND96asr_v4":   8,
+	"Standard_D2s_v3":       0,
+	"Standard_D4s_v3":       0,
+	"Standard_D8s_v3":       0,
+	"Standard_D16s_v3":      0,
+	"Standard_D32s_v3":      0,
+	"Standard_D48s_v3":      0,
+	"Standard_D64s_v3":      0,
+	"Standard_D2s_v4":       0,
+	"Standard_D4s_v4":       0,
+	"Standard_D8s_v4":       0,
+	"Standard_D16s_v4":      0,
+	"Standard_D32s_v4":      0,
+	"Standard_D48s_v4":      0,
+	"Standard_D64s_v4":      0,
+	"Standard_F2s_v2":       0,
+	"Standard_F4s_v2":       0,
+	"Standard_F8s_v2":       0,
+	"Standard_F16s_v2":      0,
+	"Standard_F32s_v2":      0,
+	"Standard_F48s_v2":      0,
+	"Standard_F64s_v2":      0,
+	"Standard_F72s_v2":      0,
+}
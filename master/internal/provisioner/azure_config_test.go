@@ -0,0 +1,115 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestDefaultAzureClusterConfig(t *testing.T) {
+	var config AzureClusterConfig
+	err := json.Unmarshal([]byte(`
+{
+	"subscription_id": "test-subscription",
+	"resource_group": "test-group",
+	"location": "eastus",
+	"subnet_id": "test-subnet",
+	"ssh_public_key": "ssh-rsa test"
+}`), &config)
+	assert.NilError(t, err)
+	err = check.Validate(&config)
+	assert.NilError(t, err)
+	expected := defaultAzureClusterConfig
+	expected.SubscriptionID = "test-subscription"
+	expected.ResourceGroup = "test-group"
+	expected.Location = "eastus"
+	expected.SubnetID = "test-subnet"
+	expected.SSHPublicKey = "ssh-rsa test"
+	assert.DeepEqual(t, config, expected)
+	assert.Equal(t, config.SlotsPerInstance(), 4)
+	assert.Equal(t, config.SlotType(), device.GPU)
+}
+
+func TestUnmarshalAzureClusterConfig(t *testing.T) {
+	var config AzureClusterConfig
+	err := json.Unmarshal([]byte(`
+{
+	"subscription_id": "test-subscription",
+	"resource_group": "test-group",
+	"location": "westus2",
+	"tenant_id": "test-tenant",
+	"client_id": "test-client",
+	"client_secret": "test-secret",
+	"scale_set_name": "test-scale-set",
+	"tag_key": "dai",
+	"tag_value": "agent",
+	"custom_tags": {"team": "ml"},
+	"image": {"id": "test-image"},
+	"os_disk_size": 120,
+	"subnet_id": "test-subnet",
+	"ssh_public_key": "ssh-rsa test",
+	"public_ip": true,
+	"instance_type": "Standard_D4s_v3",
+	"spot": true,
+	"spot_max_price": 0.5,
+	"cpu_slots_allowed": true
+}`), &config)
+	assert.NilError(t, err)
+	err = check.Validate(&config)
+	assert.NilError(t, err)
+
+	expected := defaultAzureClusterConfig
+	expected.SubscriptionID = "test-subscription"
+	expected.ResourceGroup = "test-group"
+	expected.Location = "westus2"
+	expected.TenantID = "test-tenant"
+	expected.ClientID = "test-client"
+	expected.ClientSecret = "test-secret"
+	expected.ScaleSetName = "test-scale-set"
+	expected.TagKey = "dai"
+	expected.TagValue = "agent"
+	expected.CustomTags = map[string]string{"team": "ml"}
+	expected.Image.ID = "test-image"
+	expected.OSDiskSize = 120
+	expected.SubnetID = "test-subnet"
+	expected.SSHPublicKey = "ssh-rsa test"
+	expected.PublicIP = true
+	expected.InstanceType = "Standard_D4s_v3"
+	expected.SpotEnabled = true
+	expected.SpotMaxPrice = 0.5
+	expected.CPUSlotsAllowed = true
+	assert.DeepEqual(t, config, expected)
+	assert.Equal(t, config.Image.String(), "test-image")
+	assert.Equal(t, config.SlotsPerInstance(), 1)
+	assert.Equal(t, config.SlotType(), device.CPU)
+}
+
+func TestAzureClusterConfigMissingFields(t *testing.T) {
+	var config AzureClusterConfig
+	err := json.Unmarshal([]byte(`{"client_id": "test-client", "spot_max_price": 0}`), &config)
+	assert.NilError(t, err)
+	err = check.Validate(&config)
+	assert.ErrorContains(t, err, "azure subnet id must be non-empty")
+	assert.ErrorContains(t, err, "azure ssh public key must be non-empty")
+	assert.ErrorContains(t, err, "azure spot max price must be -1 or greater than 0")
+	assert.ErrorContains(t, err,
+		"azure tenant_id, client_id and client_secret must be configured together")
+
+	config.InstanceType = "Standard_Unknown"
+	err = check.Validate(&config)
+	assert.ErrorContains(t, err, "azure vm size must be valid type")
+}
+
+func TestPrintableAzureConfig(t *testing.T) {
+	azure := defaultAzureClusterConfig
+	azure.ClientSecret = "test-secret"
+	config := Config{Azure: &azure}
+
+	printable := config.Printable()
+	assert.Equal(t, printable.Azure.ClientSecret, "********")
+	assert.Equal(t, config.Azure.ClientSecret, "test-secret")
+}
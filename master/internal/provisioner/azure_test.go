@@ -0,0 +1,166 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+// fakeAzure serves the token and scale set endpoints used by the Azure client.
+type fakeAzure struct {
+	t        *testing.T
+	scaleSet *azureScaleSet
+	deleted  []string
+}
+
+func (f *fakeAzure) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const scaleSetPath = "/subscriptions/test-subscription/resourceGroups/test-group/" +
+		"providers/Microsoft.Compute/virtualMachineScaleSets/test-scale-set"
+
+	if r.URL.Path != "/test-tenant/oauth2/v2.0/token" {
+		assert.Assert(f.t, r.URL.Query().Get("api-version") != "")
+		assert.Equal(f.t, r.Header.Get("Authorization"), "Bearer test-token")
+	}
+
+	switch {
+	case r.URL.Path == "/test-tenant/oauth2/v2.0/token":
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(
+			`{"access_token": "test-token", "token_type": "Bearer", "expires_in": 3600}`))
+	case r.URL.Path == scaleSetPath && r.Method == http.MethodGet:
+		if f.scaleSet == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": "ResourceNotFound", "message": "not found"}}`))
+			return
+		}
+		assert.NilError(f.t, json.NewEncoder(w).Encode(f.scaleSet))
+	case r.URL.Path == scaleSetPath && r.Method == http.MethodPut:
+		var scaleSet azureScaleSet
+		assert.NilError(f.t, json.NewDecoder(r.Body).Decode(&scaleSet))
+		f.scaleSet = &scaleSet
+	case r.URL.Path == scaleSetPath && r.Method == http.MethodPatch:
+		var update azureScaleSet
+		assert.NilError(f.t, json.NewDecoder(r.Body).Decode(&update))
+		f.scaleSet.SKU.Capacity = update.SKU.Capacity
+	case r.URL.Path == scaleSetPath+"/virtualMachines" && r.URL.Query().Get("page") == "":
+		_, _ = w.Write([]byte(`{
+			"value": [{
+				"instanceId": "0",
+				"name": "test-scale-set_0",
+				"properties": {
+					"provisioningState": "Succeeded",
+					"timeCreated": "2021-01-01T00:00:00Z",
+					"instanceView": {"statuses": [{"code": "PowerState/running"}]}
+				}
+			}],
+			"nextLink": "http://` + r.Host + r.URL.Path + `?api-version=x&page=2"
+		}`))
+	case r.URL.Path == scaleSetPath+"/virtualMachines":
+		_, _ = w.Write([]byte(`{
+			"value": [{
+				"instanceId": "1",
+				"name": "test-scale-set_1",
+				"properties": {"provisioningState": "Creating"}
+			}]
+		}`))
+	case r.URL.Path == scaleSetPath+"/delete" && r.Method == http.MethodPost:
+		var body struct {
+			InstanceIDs []string `json:"instanceIds"`
+		}
+		assert.NilError(f.t, json.NewDecoder(r.Body).Decode(&body))
+		f.deleted = append(f.deleted, body.InstanceIDs...)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func newTestAzureCluster(t *testing.T) (*azureCluster, *fakeAzure) {
+	fake := &fakeAzure{t: t}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := defaultAzureClusterConfig
+	config.SubscriptionID = "test-subscription"
+	config.ResourceGroup = "test-group"
+	config.Location = "eastus"
+	config.TenantID = "test-tenant"
+	config.ClientID = "test-client"
+	config.ClientSecret = "test-secret"
+	config.TagValue = "test-master"
+	config.SubnetID = "test-subnet"
+	config.SSHPublicKey = "ssh-rsa test"
+	config.SpotEnabled = true
+	config.ManagementEndpoint = server.URL
+	config.AuthorityHost = server.URL
+
+	return &azureCluster{
+		AzureClusterConfig: &config,
+		resourcePool:       "default",
+		scaleSetName:       "test-scale-set",
+		customData:         "c2NyaXB0",
+		client:             newAzureClient(&config),
+	}, fake
+}
+
+func TestAzureClusterScaleSet(t *testing.T) {
+	cluster, fake := newTestAzureCluster(t)
+
+	scaleSet, err := cluster.client.getScaleSet(cluster.scaleSetName)
+	assert.NilError(t, err)
+	assert.Assert(t, scaleSet == nil)
+
+	err = cluster.client.createScaleSet(cluster.scaleSetName, cluster.scaleSetSpec(2))
+	assert.NilError(t, err)
+	assert.Equal(t, fake.scaleSet.SKU.Name, "Standard_NC24s_v3")
+	assert.Equal(t, fake.scaleSet.SKU.Capacity, 2)
+	assert.Equal(t, fake.scaleSet.Properties.VirtualMachineProfile.Priority, "Spot")
+	assert.Equal(t, fake.scaleSet.Properties.VirtualMachineProfile.OSProfile.CustomData, "c2NyaXB0")
+
+	scaleSet, err = cluster.client.getScaleSet(cluster.scaleSetName)
+	assert.NilError(t, err)
+	assert.Assert(t, cluster.ownsScaleSet(scaleSet))
+	cluster.resourcePool = "other"
+	assert.Assert(t, !cluster.ownsScaleSet(scaleSet))
+
+	assert.NilError(t, cluster.client.setCapacity(cluster.scaleSetName, 5))
+	assert.Equal(t, fake.scaleSet.SKU.Capacity, 5)
+
+	assert.NilError(t, cluster.client.deleteVMs(cluster.scaleSetName, []string{"0", "3"}))
+	assert.DeepEqual(t, fake.deleted, []string{"0", "3"})
+}
+
+func TestAzureClusterListInstances(t *testing.T) {
+	cluster, _ := newTestAzureCluster(t)
+
+	vms, err := cluster.client.listVMs(cluster.scaleSetName)
+	assert.NilError(t, err)
+	instances := cluster.newInstances(vms)
+	assert.DeepEqual(t, instances, []*Instance{
+		{
+			ID:         "0",
+			LaunchTime: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			AgentName:  "test-scale-set_0",
+			State:      Running,
+			Slots:      4,
+		},
+		{
+			ID:        "1",
+			AgentName: "test-scale-set_1",
+			State:     Starting,
+			Slots:     4,
+		},
+	})
+}
+
+func TestDefaultAzureScaleSetName(t *testing.T) {
+	assert.Equal(t, defaultAzureScaleSetName("determined-master", "gpu pool"),
+		"determined-master-gpu-pool")
+	name := defaultAzureScaleSetName(strings.Repeat("a", 60), "default")
+	assert.Equal(t, name, strings.Repeat("a", 60)+"-def")
+}
@@ -19,20 +19,24 @@ const defaultMasterPort = "8080"
 
 // Config describes config for provisioner.
 type Config struct {
-	MasterURL              string            `json:"master_url"`
-	MasterCertName         string            `json:"master_cert_name"`
-	StartupScript          string            `json:"startup_script"`
-	ContainerStartupScript string            `json:"container_startup_script"`
-	AgentDockerNetwork     string            `json:"agent_docker_network"`
-	AgentDockerRuntime     string            `json:"agent_docker_runtime"`
-	AgentDockerImage       string            `json:"agent_docker_image"`
-	AgentFluentImage       string            `json:"agent_fluent_image"`
-	AWS                    *AWSClusterConfig `union:"type,aws" json:"-"`
-	GCP                    *GCPClusterConfig `union:"type,gcp" json:"-"`
-	MaxIdleAgentPeriod     model.Duration    `json:"max_idle_agent_period"`
-	MaxAgentStartingPeriod model.Duration    `json:"max_agent_starting_period"`
-	MinInstances           int               `json:"min_instances"`
-	MaxInstances           int               `json:"max_instances"`
+	MasterURL              string                `json:"master_url"`
+	MasterCertName         string                `json:"master_cert_name"`
+	StartupScript          string                `json:"startup_script"`
+	ContainerStartupScript string                `json:"container_startup_script"`
+	AgentDockerNetwork     string                `json:"agent_docker_network"`
+	AgentDockerRuntime     string                `json:"agent_docker_runtime"`
+	AgentDockerImage       string                `json:"agent_docker_image"`
+	AgentFluentImage       string                `json:"agent_fluent_image"`
+	AWS                    *AWSClusterConfig     `union:"type,aws" json:"-"`
+	GCP                    *GCPClusterConfig     `union:"type,gcp" json:"-"`
+	Azure                  *AzureClusterConfig   `union:"type,azure" json:"-"`
+	Webhook                *WebhookClusterConfig `union:"type,webhook" json:"-"`
+	MaxIdleAgentPeriod     model.Duration        `json:"max_idle_agent_period"`
+	MaxAgentStartingPeriod model.Duration        `json:"max_agent_starting_period"`
+	MinInstances           int                   `json:"min_instances"`
+	MaxInstances           int                   `json:"max_instances"`
+	ScalingSchedules       []ScalingSchedule     `json:"scaling_schedules"`
+	InstancePrices         map[string]float64    `json:"instance_prices"`
 }
 
 // DefaultConfig returns the default configuration of the provisioner.
@@ -78,11 +82,19 @@ func (c Config) Validate() []error {
 		errs = append(errs, check.In(masterURL.Scheme, []string{"http", "https"},
 			"master url scheme must be within [http, https]"))
 	}
+	var clusters int
+	for _, configured := range []bool{
+		c.AWS != nil, c.GCP != nil, c.Azure != nil, c.Webhook != nil,
+	} {
+		if configured {
+			clusters++
+		}
+	}
 	errs = append(errs, []error{
 		masterURLErr,
 		check.NotEmpty(c.AgentDockerImage, "must configure an agent docker image"),
-		check.False(c.AWS != nil && c.GCP != nil, "must configure only one cluster"),
-		check.False(c.AWS == nil && c.GCP == nil, "must configure aws or gcp cluster"),
+		check.LessThanOrEqualTo(clusters, 1, "must configure only one cluster"),
+		check.GreaterThan(clusters, 0, "must configure an aws, gcp, azure or webhook cluster"),
 		check.GreaterThan(
 			int64(c.MaxIdleAgentPeriod), int64(0), "max idle agent period must be greater than 0"),
 		check.GreaterThan(
@@ -93,9 +105,49 @@ func (c Config) Validate() []error {
 		check.GreaterThanOrEqualTo(int64(c.MaxInstances), int64(c.MinInstances),
 			"max instance must be greater than or equal to min instance"),
 	}...)
+	for _, schedule := range c.ScalingSchedules {
+		minInstances, maxInstances := c.MinInstances, c.MaxInstances
+		if schedule.MinInstances != nil {
+			minInstances = *schedule.MinInstances
+		}
+		if schedule.MaxInstances != nil {
+			maxInstances = *schedule.MaxInstances
+		}
+		errs = append(errs, check.GreaterThanOrEqualTo(maxInstances, minInstances,
+			"scaling schedule %q max instance must be greater than or equal to min instance",
+			schedule.Schedule))
+	}
+	for instanceType, price := range c.InstancePrices {
+		errs = append(errs, check.GreaterThanOrEqualTo(price, float64(0),
+			"instance price of %q must be greater than or equal to 0", instanceType))
+	}
 	return errs
 }
 
+// InstanceLimits returns the minimum and maximum number of instances at the time, taking the
+// scaling schedules into account.
+func (c Config) InstanceLimits(t time.Time) (int, int) {
+	return instanceLimits(c.MinInstances, c.MaxInstances, c.ScalingSchedules, t)
+}
+
+// Printable returns a copy of the config with secrets hidden.
+func (c Config) Printable() *Config {
+	if c.Azure != nil && len(c.Azure.ClientSecret) != 0 {
+		azure := *c.Azure
+		azure.ClientSecret = "********"
+		c.Azure = &azure
+	}
+	if c.Webhook != nil && len(c.Webhook.Headers) != 0 {
+		webhook := *c.Webhook
+		webhook.Headers = make(map[string]string, len(c.Webhook.Headers))
+		for k := range c.Webhook.Headers {
+			webhook.Headers[k] = "********"
+		}
+		c.Webhook = &webhook
+	}
+	return &c
+}
+
 func (c Config) mustParseMasterURL() url.URL {
 	masterURL, err := url.Parse(c.MasterURL)
 	if err != nil {
@@ -122,6 +174,10 @@ func (c *Config) initMasterAddress() error {
 		host, err = getEC2Metadata("local-ipv4")
 	case host == "public-ipv4" && onEC2():
 		host, err = getEC2Metadata("public-ipv4")
+	case (host == "internal-ip" || host == "") && onAzure():
+		host, err = getAzureIPAddress(false)
+	case host == "external-ip" && onAzure():
+		host, err = getAzureIPAddress(true)
 	case host == "local-hostname" && onEC2():
 		host, err = getEC2Metadata("local-hostname")
 	case host == "public-hostname" && onEC2():
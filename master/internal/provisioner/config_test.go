@@ -20,7 +20,7 @@ func TestProvisionerConfigMissingFields(t *testing.T) {
 	err := json.Unmarshal([]byte(`{}`), &config)
 	assert.NilError(t, err)
 	err = check.Validate(&config)
-	assert.ErrorContains(t, err, "must configure aws or gcp cluster")
+	assert.ErrorContains(t, err, "must configure an aws, gcp, azure or webhook cluster")
 	expected := Config{
 		MaxIdleAgentPeriod:     model.Duration(20 * time.Minute),
 		MaxAgentStartingPeriod: model.Duration(20 * time.Minute),
@@ -214,3 +214,22 @@ boot_disk_source_image: test-source_image3
 	}
 	assert.DeepEqual(t, expected, unmarshaled)
 }
+
+func TestProvisionerConfigInstancePrices(t *testing.T) {
+	configRaw := `{
+"master_url": "http://test.master",
+"type": "aws",
+"region": "test.region3",
+"image_id": "test.image3",
+"ssh_key_name": "test-key3",
+"instance_prices": {"p3.8xlarge": 12.24, "p3.2xlarge": -3.06}
+}`
+	config := Config{}
+	assert.NilError(t, json.Unmarshal([]byte(configRaw), &config))
+	assert.DeepEqual(t, config.InstancePrices, map[string]float64{
+		"p3.8xlarge": 12.24,
+		"p3.2xlarge": -3.06,
+	})
+	err := check.Validate(config)
+	assert.ErrorContains(t, err, `instance price of "p3.2xlarge" must be greater than or equal to 0`)
+}
@@ -0,0 +1,136 @@
+package provisioner
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cronSchedule is a parsed cron expression with the five standard fields: minute, hour, day of
+// month, month and day of week. Each field is a bitset of the values it matches.
+type cronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// As in cron, if both day fields are restricted, a day matches if either field matches.
+	dayOfMonthRestricted, dayOfWeekRestricted bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	// Both 0 and 7 are Sunday.
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}},
+}
+
+// parseCronSchedule parses a cron expression. Each field is a comma-separated list of values,
+// ranges (MON-FRI) and steps (*/15, 8-18/2); months and days of the week may be named.
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, errors.Errorf("cron schedule must have %d fields: %q", len(cronFields), expr)
+	}
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		var err error
+		if bits[i], err = cronFields[i].parse(field); err != nil {
+			return nil, errors.Wrapf(err, "invalid cron schedule %q", expr)
+		}
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &cronSchedule{
+		minute:               bits[0],
+		hour:                 bits[1],
+		dayOfMonth:           bits[2],
+		month:                bits[3],
+		dayOfWeek:            bits[4],
+		dayOfMonthRestricted: !strings.HasPrefix(fields[2], "*"),
+		dayOfWeekRestricted:  !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		values, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			values = part[:i]
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, errors.Errorf("%s step must be a positive integer: %s", f.name, part)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		switch bounds := strings.SplitN(values, "-", 2); {
+		case values == "*":
+		case len(bounds) == 2:
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, errors.Errorf("%s range must be increasing: %s", f.name, values)
+			}
+		default:
+			var err error
+			if lo, err = f.value(values); err != nil {
+				return 0, err
+			}
+			// A single value with a step, e.g. 5/15, starts the step at the value.
+			if step == 1 {
+				hi = lo
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, errors.Errorf("%s must be between %d and %d: %s", f.name, f.min, f.max, s)
+	}
+	return v, nil
+}
+
+// matches returns true if the schedule fires at the minute of the time, in the time's location.
+func (c cronSchedule) matches(t time.Time) bool {
+	if c.minute&(1<<uint(t.Minute())) == 0 ||
+		c.hour&(1<<uint(t.Hour())) == 0 ||
+		c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	dayOfMonth := c.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := c.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if c.dayOfMonthRestricted && c.dayOfWeekRestricted {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
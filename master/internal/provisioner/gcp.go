@@ -118,6 +118,10 @@ func (c *gcpCluster) slotsPerInstance() int {
 	return c.GCPClusterConfig.SlotsPerInstance()
 }
 
+func (c *gcpCluster) instanceSlots() []int {
+	return []int{c.slotsPerInstance()}
+}
+
 func (c *gcpCluster) idFromInstance(inst *compute.Instance) string {
 	return fmt.Sprintf("%v", inst.Name)
 }
@@ -298,6 +302,7 @@ func (c *gcpCluster) newInstances(input []*compute.Instance) []*Instance {
 			LaunchTime: t,
 			AgentName:  c.agentNameFromInstance(inst),
 			State:      c.stateFromInstance(inst),
+			Slots:      c.slotsPerInstance(),
 		})
 	}
 	return output
@@ -38,6 +38,12 @@ type Instance struct {
 	LaunchTime time.Time
 	AgentName  string
 	State      InstanceState
+	// Slots is the number of slots of the instance, which providers record from its instance type.
+	Slots int
+	// Type is the instance type and HourlyPrice is the market price of the instance, if the
+	// provider tracks it. Both are only used for cost accounting.
+	Type        string
+	HourlyPrice float64
 }
 
 func (inst Instance) String() string {
@@ -6,9 +6,11 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/actor/actors"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 const (
@@ -30,13 +32,15 @@ type provisionerTick struct{}
 //    2.2 It checks recently launched instances and avoids provisioning more than needed.
 // 3. The instance providers take actions to launch/terminate instances.
 type Provisioner struct {
-	provider     provider
-	scaleDecider *scaleDecider
+	provider       provider
+	scaleDecider   *scaleDecider
+	instancePrices map[string]float64
 }
 
 type provider interface {
 	instanceType() instanceType
 	slotsPerInstance() int
+	instanceSlots() []int
 	prestart(ctx *actor.Context)
 	list(ctx *actor.Context) ([]*Instance, error)
 	launch(ctx *actor.Context, instanceNum int)
@@ -60,6 +64,16 @@ func New(resourcePool string, config *Config, cert *tls.Certificate) (*Provision
 		if cluster, err = newGCPCluster(resourcePool, config, cert); err != nil {
 			return nil, errors.Wrap(err, "cannot create a GCP cluster")
 		}
+	case config.Azure != nil:
+		var err error
+		if cluster, err = newAzureCluster(resourcePool, config, cert); err != nil {
+			return nil, errors.Wrap(err, "cannot create an Azure cluster")
+		}
+	case config.Webhook != nil:
+		var err error
+		if cluster, err = newWebhookCluster(resourcePool, config, cert); err != nil {
+			return nil, errors.Wrap(err, "cannot create a webhook cluster")
+		}
 	}
 
 	return &Provisioner{
@@ -70,7 +84,9 @@ func New(resourcePool string, config *Config, cert *tls.Certificate) (*Provision
 			maxDisconnectPeriod,
 			config.MinInstances,
 			config.MaxInstances,
+			config.ScalingSchedules,
 		),
+		instancePrices: config.InstancePrices,
 	}, nil
 }
 
@@ -96,9 +112,10 @@ func (p *Provisioner) Receive(ctx *actor.Context) error {
 	return nil
 }
 
-// SlotsPerInstance returns the number of Slots per instance the provisioner launches.
-func (p *Provisioner) SlotsPerInstance() int {
-	return p.provider.slotsPerInstance()
+// InstanceSlots returns the number of slots per instance of each instance type the provisioner
+// may launch, in the order of preference.
+func (p *Provisioner) InstanceSlots() []int {
+	return p.provider.instanceSlots()
 }
 
 func (p *Provisioner) provision(ctx *actor.Context) {
@@ -110,6 +127,7 @@ func (p *Provisioner) provision(ctx *actor.Context) {
 	if p.scaleDecider.updateInstanceSnapshot(instances) {
 		ctx.Log().Infof("found state changes in %d instances: %s",
 			len(instances), fmtInstances(instances))
+		p.recordInstances(ctx, instances)
 	}
 
 	p.scaleDecider.calculateInstanceStates()
@@ -118,11 +136,59 @@ func (p *Provisioner) provision(ctx *actor.Context) {
 		ctx.Log().Infof("decided to terminate %d instances: %s",
 			len(toTerminate.InstanceIDs), toTerminate.String())
 		p.provider.terminate(ctx, toTerminate.InstanceIDs)
+		prom.InstancesTerminated.WithLabelValues(ctx.Self().Parent().Address().Local()).Add(
+			float64(len(toTerminate.InstanceIDs)))
 	}
 
-	if numToLaunch := p.scaleDecider.calculateNumInstancesToLaunch(); numToLaunch > 0 {
+	numToLaunch := p.scaleDecider.calculateNumInstancesToLaunch(p.provider.slotsPerInstance())
+	if numToLaunch > 0 {
 		ctx.Log().Infof("decided to launch %d instances (type %s)",
 			numToLaunch, p.provider.instanceType().name())
 		p.provider.launch(ctx, numToLaunch)
+		prom.InstancesLaunched.WithLabelValues(ctx.Self().Parent().Address().Local()).Add(
+			float64(numToLaunch))
+	}
+}
+
+// recordInstances sends the running instances to the instance recorder, which records their
+// lifetimes and prices for cost accounting.
+func (p *Provisioner) recordInstances(ctx *actor.Context, instances []*Instance) {
+	recorder := ctx.Self().System().Get(sproto.InstanceRecorderAddr)
+	if recorder == nil {
+		return
+	}
+	msg := sproto.ProvisionedInstances{
+		ResourcePool: ctx.Self().Parent().Address().Local(),
+		Time:         time.Now(),
+	}
+	for _, inst := range instances {
+		switch inst.State {
+		case Stopped, Terminating, SpotRequestPendingAWS:
+			continue
+		}
+		instanceType, slots := inst.Type, inst.Slots
+		if instanceType == "" {
+			instanceType = p.provider.instanceType().name()
+		}
+		if slots == 0 {
+			slots = p.provider.slotsPerInstance()
+		}
+		price := inst.HourlyPrice
+		if price == 0 {
+			price = p.instancePrices[instanceType]
+		}
+		startTime := inst.LaunchTime
+		if startTime.IsZero() {
+			startTime = msg.Time
+		}
+		msg.Instances = append(msg.Instances, model.ProvisionedInstance{
+			InstanceID:   inst.ID,
+			ResourcePool: msg.ResourcePool,
+			InstanceType: instanceType,
+			Slots:        slots,
+			HourlyPrice:  price,
+			StartTime:    startTime,
+		})
 	}
+	ctx.Tell(recorder, msg)
 }
@@ -1,6 +1,7 @@
 package provisioner
 
 import (
+	"sort"
 	"testing"
 	"time"
 
@@ -57,7 +58,9 @@ func newMockEnvironment(t *testing.T, setup *mockConfig) *mockEnvironment {
 			setup.maxDisconnectPeriod,
 			setup.MinInstances,
 			setup.MaxInstances,
+			setup.ScalingSchedules,
 		),
+		instancePrices: setup.InstancePrices,
 	}
 	provisioner, created := system.ActorOf(actor.Addr("provisioner"), p)
 	assert.Assert(t, created)
@@ -112,6 +115,10 @@ func (c *mockProvider) slotsPerInstance() int {
 	return c.mockInstanceType.Slots()
 }
 
+func (c *mockProvider) instanceSlots() []int {
+	return []int{c.slotsPerInstance()}
+}
+
 func (c *mockProvider) list(ctx *actor.Context) ([]*Instance, error) {
 	c.history = append(c.history, newMockFuncCall("list"))
 	instances := make([]*Instance, 0, len(c.instances))
@@ -346,3 +353,67 @@ func TestProvisionerTerminateDisconnectedInstances(t *testing.T) {
 		})),
 	})
 }
+
+func TestProvisionerRecordInstances(t *testing.T) {
+	launchTime := time.Now().Add(-time.Hour).UTC()
+	setup := &mockConfig{
+		maxDisconnectPeriod: 5 * time.Minute,
+		instanceType: TestInstanceType{
+			Name:     "test.instanceType",
+			NumSlots: 4,
+		},
+		Config: &Config{
+			MaxInstances:   100,
+			InstancePrices: map[string]float64{"test.instanceType": 3.06},
+		},
+		initInstances: []*Instance{
+			{ID: "runningInstance", LaunchTime: launchTime, AgentName: "agent1", State: Running},
+			{
+				ID:          "spotInstance",
+				LaunchTime:  launchTime,
+				AgentName:   "agent2",
+				State:       Running,
+				Type:        "test.spotInstanceType",
+				Slots:       8,
+				HourlyPrice: 1.2,
+			},
+			{ID: "stoppedInstance", LaunchTime: launchTime, AgentName: "agent3", State: Stopped},
+		},
+	}
+	mock := newMockEnvironment(t, setup)
+	recorded := make(chan sproto.ProvisionedInstances, 1)
+	recorder := func(ctx *actor.Context) error {
+		if msg, ok := ctx.Message().(sproto.ProvisionedInstances); ok {
+			recorded <- msg
+		}
+		return nil
+	}
+	mock.system.MustActorOf(sproto.InstanceRecorderAddr, actor.ActorFunc(recorder))
+
+	mock.system.Ask(mock.provisioner, sproto.ScalingInfo{}).Get()
+	mock.system.Ask(mock.provisioner, provisionerTick{}).Get()
+	msg := <-recorded
+	assert.NilError(t, mock.system.StopAndAwaitTermination())
+
+	sort.Slice(msg.Instances, func(i, j int) bool {
+		return msg.Instances[i].InstanceID < msg.Instances[j].InstanceID
+	})
+	assert.DeepEqual(t, msg.Instances, []model.ProvisionedInstance{
+		{
+			InstanceID:   "runningInstance",
+			ResourcePool: msg.ResourcePool,
+			InstanceType: "test.instanceType",
+			Slots:        4,
+			HourlyPrice:  3.06,
+			StartTime:    launchTime,
+		},
+		{
+			InstanceID:   "spotInstance",
+			ResourcePool: msg.ResourcePool,
+			InstanceType: "test.spotInstanceType",
+			Slots:        8,
+			HourlyPrice:  1.2,
+			StartTime:    launchTime,
+		},
+	})
+}
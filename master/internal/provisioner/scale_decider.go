@@ -30,11 +30,16 @@ type scaleDecider struct {
 	maxDisconnectPeriod time.Duration
 	minInstanceNum      int
 	maxInstanceNum      int
+	scalingSchedules    []ScalingSchedule
+	// clock returns the current time, which tests may fake.
+	clock func() time.Time
 
 	instanceSnapshot       map[string]*Instance
 	connectedAgentSnapshot map[string]sproto.AgentSummary
 	idleAgentSnapshot      map[string]sproto.AgentSummary
 	desiredNewInstances    int
+	desiredNewSlots        int
+	desiredZeroSlotNum     int
 
 	instances        map[string]*Instance
 	pending          map[string]bool
@@ -51,6 +56,7 @@ func newScaleDecider(
 	maxDisconnectPeriod time.Duration,
 	minInstanceNum int,
 	maxInstanceNum int,
+	scalingSchedules []ScalingSchedule,
 ) *scaleDecider {
 	return &scaleDecider{
 		maxStartingPeriod:      maxStartingPeriod,
@@ -58,6 +64,8 @@ func newScaleDecider(
 		maxDisconnectPeriod:    maxDisconnectPeriod,
 		minInstanceNum:         minInstanceNum,
 		maxInstanceNum:         maxInstanceNum,
+		scalingSchedules:       scalingSchedules,
+		clock:                  time.Now,
 		instanceSnapshot:       make(map[string]*Instance),
 		connectedAgentSnapshot: make(map[string]sproto.AgentSummary),
 		idleAgentSnapshot:      make(map[string]sproto.AgentSummary),
@@ -72,8 +80,23 @@ func newScaleDecider(
 	}
 }
 
+func (s *scaleDecider) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock()
+}
+
+// instanceLimits returns the minimum and maximum number of instances, which the scaling schedules
+// may override depending on the time.
+func (s *scaleDecider) instanceLimits() (int, int) {
+	return instanceLimits(s.minInstanceNum, s.maxInstanceNum, s.scalingSchedules, s.now())
+}
+
 func (s *scaleDecider) updateScalingInfo(info *sproto.ScalingInfo) {
 	s.desiredNewInstances = info.DesiredNewInstances
+	s.desiredNewSlots = info.DesiredNewSlots
+	s.desiredZeroSlotNum = info.DesiredNewZeroSlotInstances
 	s.idleAgentSnapshot = make(map[string]sproto.AgentSummary)
 	s.connectedAgentSnapshot = make(map[string]sproto.AgentSummary)
 	for _, agent := range info.Agents {
@@ -107,7 +130,7 @@ func (s *scaleDecider) updateInstanceSnapshot(instances []*Instance) bool {
 }
 
 func (s *scaleDecider) calculateInstanceStates() {
-	now := time.Now()
+	now := s.now()
 	pastDisconnected := s.disconnected
 	pastIdle := s.idle
 	s.instances = make(map[string]*Instance)
@@ -166,6 +189,7 @@ func (s *scaleDecider) calculateInstanceStates() {
 }
 
 func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
+	minInstanceNum, maxInstanceNum := s.instanceLimits()
 	toTerminate := make(map[string]string)
 
 	// Terminate stopped instances and find idle and disconnected instances.
@@ -182,7 +206,7 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 
 	// Terminate instances that are idle for a long time.
 	for id := range s.longIdle {
-		if len(s.instances)-len(toTerminate) > s.minInstanceNum {
+		if len(s.instances)-len(toTerminate) > minInstanceNum {
 			toTerminate[id] = sproto.TerminateLongIdleInstances
 			delete(s.idle, id)
 		} else {
@@ -194,7 +218,7 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 	// We start by terminating unfulfilled spot requests, then idle instances, then
 	// disconnected instances, then the most recently provisioned instances
 	for id := range s.pending {
-		if len(s.instances)-len(toTerminate) > s.maxInstanceNum {
+		if len(s.instances)-len(toTerminate) > maxInstanceNum {
 			toTerminate[id] = sproto.InstanceNumberExceedsMaximum
 			delete(s.pending, id)
 		} else {
@@ -202,7 +226,7 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 		}
 	}
 	for id := range s.idle {
-		if len(s.instances)-len(toTerminate) > s.maxInstanceNum {
+		if len(s.instances)-len(toTerminate) > maxInstanceNum {
 			toTerminate[id] = sproto.InstanceNumberExceedsMaximum
 			delete(s.idle, id)
 		} else {
@@ -210,7 +234,7 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 		}
 	}
 	for id := range s.disconnected {
-		if len(s.instances)-len(toTerminate) > s.maxInstanceNum {
+		if len(s.instances)-len(toTerminate) > maxInstanceNum {
 			toTerminate[id] = sproto.InstanceNumberExceedsMaximum
 			delete(s.disconnected, id)
 		} else {
@@ -224,7 +248,7 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 	sort.Slice(instances, func(i, j int) bool {
 		return instances[i].LaunchTime.After(instances[j].LaunchTime)
 	})
-	for i := 0; i < len(instances) && len(instances)-len(toTerminate) > s.maxInstanceNum; i++ {
+	for i := 0; i < len(instances) && len(instances)-len(toTerminate) > maxInstanceNum; i++ {
 		toTerminate[instances[i].ID] = sproto.InstanceNumberExceedsMaximum
 	}
 
@@ -237,10 +261,29 @@ func (s *scaleDecider) findInstancesToTerminate() sproto.TerminateDecision {
 	return res
 }
 
-func (s *scaleDecider) calculateNumInstancesToLaunch() int {
+// calculateNumInstancesToLaunch returns the number of instances to launch, given the number of
+// slots of the instance type that the provider launches next. Instances in a pool may have
+// different numbers of slots, so when pending tasks need slots, the number of instances is
+// recomputed from the slots they need, less the slots that recently launched instances bring,
+// rather than taken from the desired number of instances of the preferred type. Pending zero-slot
+// tasks still need their own number of instances.
+func (s *scaleDecider) calculateNumInstancesToLaunch(slotsPerInstance int) int {
+	minInstanceNum, maxInstanceNum := s.instanceLimits()
 	desiredNum := s.desiredNewInstances - len(s.recentlyLaunched)
-	desiredNum = min(desiredNum, s.maxInstanceNum-len(s.instances))
-	desiredNum = max(desiredNum, s.minInstanceNum-len(s.instances))
+	if s.desiredNewSlots > 0 && slotsPerInstance > 0 {
+		desiredSlots := s.desiredNewSlots
+		for id := range s.recentlyLaunched {
+			if inst, ok := s.instances[id]; ok {
+				desiredSlots -= inst.Slots
+			}
+		}
+		desiredNum = max(
+			s.desiredZeroSlotNum-len(s.recentlyLaunched),
+			(desiredSlots+slotsPerInstance-1)/slotsPerInstance,
+		)
+	}
+	desiredNum = min(desiredNum, maxInstanceNum-len(s.instances))
+	desiredNum = max(desiredNum, minInstanceNum-len(s.instances))
 	return max(0, desiredNum)
 }
 
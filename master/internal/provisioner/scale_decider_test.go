@@ -1,6 +1,7 @@
 package provisioner
 
 import (
+	"encoding/json"
 	"runtime/debug"
 	"testing"
 	"time"
@@ -302,9 +303,10 @@ func TestFindInstancesToTerminate(t *testing.T) {
 
 func TestCalculateNumInstancesToLaunch(t *testing.T) {
 	type testcase struct {
-		name         string
-		scaleDecider scaleDecider
-		numToLaunch  int
+		name             string
+		scaleDecider     scaleDecider
+		slotsPerInstance int
+		numToLaunch      int
 	}
 	var tcs = []testcase{
 		{
@@ -387,13 +389,139 @@ func TestCalculateNumInstancesToLaunch(t *testing.T) {
 			},
 			numToLaunch: 0,
 		},
+		{
+			name: "launch more instances of a smaller fallback type",
+			scaleDecider: scaleDecider{
+				maxStartingPeriod:   10 * time.Minute,
+				maxInstanceNum:      10,
+				desiredNewInstances: 1,
+				desiredNewSlots:     4,
+			},
+			slotsPerInstance: 1,
+			numToLaunch:      4,
+		},
+		{
+			name: "launch fewer instances of a larger fallback type",
+			scaleDecider: scaleDecider{
+				maxStartingPeriod:   10 * time.Minute,
+				maxInstanceNum:      10,
+				desiredNewInstances: 4,
+				desiredNewSlots:     4,
+			},
+			slotsPerInstance: 8,
+			numToLaunch:      1,
+		},
+		{
+			name: "count pending spot requests of the preferred type by their own slots",
+			scaleDecider: scaleDecider{
+				maxStartingPeriod: 10 * time.Minute,
+				maxInstanceNum:    10,
+				instances: map[string]*Instance{
+					"request1": {
+						ID:    "request1",
+						State: SpotRequestPendingAWS,
+						Slots: 1,
+					},
+					"request2": {
+						ID:    "request2",
+						State: SpotRequestPendingAWS,
+						Slots: 1,
+					},
+				},
+				recentlyLaunched: map[string]bool{
+					"request1": true,
+					"request2": true,
+				},
+				desiredNewInstances: 10,
+				desiredNewSlots:     10,
+			},
+			slotsPerInstance: 8,
+			numToLaunch:      1,
+		},
+		{
+			name: "launch instances for both zero-slot and slot demand",
+			scaleDecider: scaleDecider{
+				maxStartingPeriod:   10 * time.Minute,
+				maxInstanceNum:      10,
+				desiredNewInstances: 3,
+				desiredNewSlots:     1,
+				desiredZeroSlotNum:  3,
+			},
+			slotsPerInstance: 1,
+			numToLaunch:      3,
+		},
+		{
+			name: "launch instances for zero-slot demand with a larger fallback type",
+			scaleDecider: scaleDecider{
+				maxStartingPeriod:   10 * time.Minute,
+				maxInstanceNum:      10,
+				desiredNewInstances: 4,
+				desiredNewSlots:     4,
+				desiredZeroSlotNum:  2,
+			},
+			slotsPerInstance: 8,
+			numToLaunch:      2,
+		},
+		{
+			name: "count slots of starting instances of another type",
+			scaleDecider: scaleDecider{
+				maxStartingPeriod: 10 * time.Minute,
+				maxInstanceNum:    10,
+				instances: map[string]*Instance{
+					"instance1": {
+						ID:         "instance1",
+						LaunchTime: time.Now().Add(-time.Minute),
+						AgentName:  "agent1",
+						State:      Running,
+						Slots:      4,
+					},
+				},
+				recentlyLaunched: map[string]bool{
+					"instance1": true,
+				},
+				desiredNewInstances: 2,
+				desiredNewSlots:     8,
+			},
+			slotsPerInstance: 1,
+			numToLaunch:      4,
+		},
 	}
 
 	for idx := range tcs {
 		tc := tcs[idx]
 		t.Run(tc.name, func(t *testing.T) {
-			actual := tc.scaleDecider.calculateNumInstancesToLaunch()
+			actual := tc.scaleDecider.calculateNumInstancesToLaunch(tc.slotsPerInstance)
 			assert.Equal(t, actual, tc.numToLaunch)
 		})
 	}
 }
+
+func TestScaleDeciderScalingSchedules(t *testing.T) {
+	var schedule ScalingSchedule
+	err := json.Unmarshal([]byte(`{
+	"schedule": "0 9 * * *",
+	"duration": "8h",
+	"min_instances": 2,
+	"max_instances": 3
+}`), &schedule)
+	assert.NilError(t, err)
+
+	now := time.Date(2021, 6, 7, 12, 0, 0, 0, time.UTC)
+	s := newScaleDecider(
+		time.Minute, time.Minute, time.Minute, 0, 10, []ScalingSchedule{schedule},
+	)
+	s.clock = func() time.Time { return now }
+	s.updateScalingInfo(&sproto.ScalingInfo{DesiredNewInstances: 5})
+
+	s.calculateInstanceStates()
+	assert.Equal(t, s.calculateNumInstancesToLaunch(0), 3)
+
+	now = time.Date(2021, 6, 7, 18, 0, 0, 0, time.UTC)
+	s.calculateInstanceStates()
+	assert.Equal(t, s.calculateNumInstancesToLaunch(0), 5)
+
+	s.updateScalingInfo(&sproto.ScalingInfo{})
+	assert.Equal(t, s.calculateNumInstancesToLaunch(0), 0)
+	now = time.Date(2021, 6, 8, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, s.calculateNumInstancesToLaunch(0), 2)
+}
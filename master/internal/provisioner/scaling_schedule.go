@@ -0,0 +1,103 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// maxScalingWindow bounds the duration of the windows of a scaling schedule, since the
+// provisioner looks for the start of a window minute by minute.
+const maxScalingWindow = 7 * 24 * time.Hour
+
+// ScalingSchedule overrides the minimum and maximum number of instances of a resource pool in
+// time windows that start at the times of a cron schedule and last for a duration.
+type ScalingSchedule struct {
+	Schedule     string         `json:"schedule"`
+	Duration     model.Duration `json:"duration"`
+	Timezone     string         `json:"timezone"`
+	MinInstances *int           `json:"min_instances"`
+	MaxInstances *int           `json:"max_instances"`
+
+	cron     *cronSchedule
+	location *time.Location
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *ScalingSchedule) UnmarshalJSON(data []byte) error {
+	type DefaultParser *ScalingSchedule
+	if err := json.Unmarshal(data, DefaultParser(s)); err != nil {
+		return errors.Wrap(err, "failed to parse scaling schedule")
+	}
+	var err error
+	if s.cron, err = parseCronSchedule(s.Schedule); err != nil {
+		return err
+	}
+	if s.location, err = time.LoadLocation(s.Timezone); err != nil {
+		return errors.Wrapf(err, "invalid scaling schedule timezone %q", s.Timezone)
+	}
+	return nil
+}
+
+// Validate implements the check.Validatable interface.
+func (s ScalingSchedule) Validate() []error {
+	errs := []error{
+		check.True(s.cron != nil, "scaling schedule must have a cron schedule"),
+		check.GreaterThan(int64(s.Duration), int64(0),
+			"scaling schedule duration must be greater than 0"),
+		check.LessThanOrEqualTo(int64(s.Duration), int64(maxScalingWindow),
+			"scaling schedule duration must be at most 7 days"),
+	}
+	if s.MinInstances != nil {
+		errs = append(errs, check.GreaterThanOrEqualTo(*s.MinInstances, 0,
+			"scaling schedule min instances must be greater than or equal to 0"))
+	}
+	if s.MaxInstances != nil {
+		errs = append(errs, check.GreaterThan(*s.MaxInstances, 0,
+			"scaling schedule max instances must be greater than 0"))
+	}
+	return errs
+}
+
+// active returns true if a window of the schedule contains the time.
+func (s ScalingSchedule) active(t time.Time) bool {
+	if s.cron == nil {
+		return false
+	}
+	location := s.location
+	if location == nil {
+		location = time.UTC
+	}
+	// Look back for the start of a window that has not ended yet.
+	window := time.Duration(s.Duration)
+	for start := t.Truncate(time.Minute); start.Add(window).After(t); {
+		if s.cron.matches(start.In(location)) {
+			return true
+		}
+		start = start.Add(-time.Minute)
+	}
+	return false
+}
+
+// instanceLimits returns the minimum and maximum number of instances at the time. The first
+// scaling schedule with a window that contains the time overrides the limits that it sets.
+func instanceLimits(
+	minInstances, maxInstances int, schedules []ScalingSchedule, t time.Time,
+) (int, int) {
+	for _, s := range schedules {
+		if s.active(t) {
+			if s.MinInstances != nil {
+				minInstances = *s.MinInstances
+			}
+			if s.MaxInstances != nil {
+				maxInstances = *s.MaxInstances
+			}
+			break
+		}
+	}
+	return minInstances, maxInstances
+}
@@ -0,0 +1,119 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	for _, expr := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * FOO *",
+		"* * * * MON-XYZ",
+		"*/0 * * * *",
+		"5-1 * * * *",
+	} {
+		_, err := parseCronSchedule(expr)
+		assert.ErrorContains(t, err, "cron schedule", expr)
+	}
+
+	type testCase struct {
+		expr    string
+		time    string
+		matches bool
+	}
+	for _, tc := range []testCase{
+		{"0 8 * * MON-FRI", "2021-06-07T08:00:00Z", true},
+		{"0 8 * * MON-FRI", "2021-06-07T08:01:00Z", false},
+		{"0 8 * * MON-FRI", "2021-06-05T08:00:00Z", false},
+		{"*/15 9-17 1,15 * *", "2021-06-15T09:45:00Z", true},
+		{"*/15 9-17 1,15 * *", "2021-06-14T09:45:00Z", false},
+		{"*/15 9-17 1,15 * *", "2021-06-15T18:00:00Z", false},
+		{"5/20 * * jun *", "2021-06-15T10:45:00Z", true},
+		{"5/20 * * jun *", "2021-07-15T10:45:00Z", false},
+		{"0 0 1 * SUN", "2021-06-06T00:00:00Z", true},
+		{"0 0 1 * SUN", "2021-06-01T00:00:00Z", true},
+		{"0 0 1 * SUN", "2021-06-02T00:00:00Z", false},
+		{"0 0 * * 7", "2021-06-06T00:00:00Z", true},
+	} {
+		schedule, err := parseCronSchedule(tc.expr)
+		assert.NilError(t, err)
+		at, err := time.Parse(time.RFC3339, tc.time)
+		assert.NilError(t, err)
+		assert.Equal(t, schedule.matches(at), tc.matches, "%s at %s", tc.expr, tc.time)
+	}
+}
+
+func TestScalingScheduleInstanceLimits(t *testing.T) {
+	var config Config
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+type: aws
+ssh_key_name: test-key
+min_instances: 0
+max_instances: 5
+scaling_schedules:
+  - schedule: "0 8 * * MON-FRI"
+    duration: 10h
+    timezone: America/New_York
+    min_instances: 2
+  - schedule: "0 22 * * *"
+    duration: 4h
+    max_instances: 1
+`), &config))
+	assert.NilError(t, check.Validate(&config))
+
+	type testCase struct {
+		time                       string
+		minInstances, maxInstances int
+	}
+	for _, tc := range []testCase{
+		// Working hours in New York on a Monday.
+		{"2021-06-07T11:59:00Z", 0, 5},
+		{"2021-06-07T12:00:00Z", 2, 5},
+		{"2021-06-07T21:59:59Z", 2, 5},
+		// The nightly window in UTC, which continues past midnight.
+		{"2021-06-07T22:00:00Z", 0, 1},
+		{"2021-06-08T01:30:00Z", 0, 1},
+		{"2021-06-08T02:00:00Z", 0, 5},
+		// Saturday.
+		{"2021-06-05T14:00:00Z", 0, 5},
+	} {
+		at, err := time.Parse(time.RFC3339, tc.time)
+		assert.NilError(t, err)
+		minInstances, maxInstances := config.InstanceLimits(at)
+		assert.Equal(t, minInstances, tc.minInstances, tc.time)
+		assert.Equal(t, maxInstances, tc.maxInstances, tc.time)
+	}
+}
+
+func TestScalingScheduleValidation(t *testing.T) {
+	var schedule ScalingSchedule
+	err := json.Unmarshal([]byte(`{"schedule": "0 8 * *", "duration": "1h"}`), &schedule)
+	assert.ErrorContains(t, err, "cron schedule must have 5 fields")
+	err = json.Unmarshal([]byte(`{"schedule": "0 8 * * *", "timezone": "Mars/Olympus"}`), &schedule)
+	assert.ErrorContains(t, err, "invalid scaling schedule timezone")
+
+	var config Config
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+type: aws
+ssh_key_name: test-key
+max_instances: 5
+scaling_schedules:
+  - schedule: "0 8 * * *"
+    duration: 192h
+    min_instances: 10
+`), &config))
+	err = check.Validate(&config)
+	assert.ErrorContains(t, err, "scaling schedule duration must be at most 7 days")
+	assert.ErrorContains(t, err,
+		`scaling schedule "0 8 * * *" max instance must be greater than or equal to min instance`)
+}
@@ -22,6 +22,12 @@ func Setup(
 	if config.GCP != nil {
 		ctx.Log().Info("connecting to GCP")
 	}
+	if config.Azure != nil {
+		ctx.Log().Info("connecting to Azure")
+	}
+	if config.Webhook != nil {
+		ctx.Log().Infof("connecting to %s", config.Webhook.ListURL)
+	}
 	provisioner, err := New(resourcePool, config, cert)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "error creating provisioner")
@@ -0,0 +1,282 @@
+package provisioner
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+)
+
+// maxWebhookErrorBodyLen bounds how much of an error response is included in the error message.
+const maxWebhookErrorBodyLen = 512
+
+// webhookInstance is the JSON representation of an instance returned by the endpoints.
+type webhookInstance struct {
+	ID         string     `json:"id"`
+	AgentName  string     `json:"agent_name,omitempty"`
+	State      string     `json:"state"`
+	LaunchTime *time.Time `json:"launch_time,omitempty"`
+}
+
+// webhookInstances is the response of the list endpoint, and optionally of the launch endpoint.
+type webhookInstances struct {
+	Instances []webhookInstance `json:"instances"`
+}
+
+// webhookLaunchRequest is the body of the request to the launch endpoint. The endpoint must create
+// the instances and run the agent setup script on them, e.g., as cloud-init user data.
+type webhookLaunchRequest struct {
+	ResourcePool     string `json:"resource_pool"`
+	InstanceType     string `json:"instance_type"`
+	NumInstances     int    `json:"num_instances"`
+	MasterURL        string `json:"master_url"`
+	AgentSetupScript string `json:"agent_setup_script"`
+}
+
+// webhookTerminateRequest is the body of the request to the terminate endpoint.
+type webhookTerminateRequest struct {
+	ResourcePool string   `json:"resource_pool"`
+	InstanceIDs  []string `json:"instance_ids"`
+}
+
+// webhookCluster delegates managing instances to user-provided HTTP endpoints:
+// 1. GET list_url?resource_pool=<pool> returns the instances of the resource pool.
+// 2. POST launch_url creates instances that run the agent setup script.
+// 3. POST terminate_url deletes instances by ID.
+// Any response status other than 2xx is treated as a failure.
+type webhookCluster struct {
+	*WebhookClusterConfig
+	resourcePool string
+	masterURL    url.URL
+	setupScript  string
+
+	client *http.Client
+	// firstSeen records when instances were first listed, as a launch time for the instances that
+	// the list endpoint returns without one.
+	firstSeen map[string]time.Time
+}
+
+func newWebhookCluster(
+	resourcePool string, config *Config, cert *tls.Certificate,
+) (*webhookCluster, error) {
+	masterURL, err := url.Parse(config.MasterURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse master url")
+	}
+
+	startupScriptBase64 := base64.StdEncoding.EncodeToString([]byte(config.StartupScript))
+	containerScriptBase64 := base64.StdEncoding.EncodeToString([]byte(config.ContainerStartupScript))
+
+	var certBytes []byte
+	if masterURL.Scheme == secureScheme && cert != nil {
+		for _, c := range cert.Certificate {
+			b := pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: c,
+			})
+			certBytes = append(certBytes, b...)
+		}
+	}
+	masterCertBase64 := base64.StdEncoding.EncodeToString(certBytes)
+
+	setupScript := mustMakeAgentSetupScript(agentSetupScriptConfig{
+		MasterHost:                   masterURL.Hostname(),
+		MasterPort:                   masterURL.Port(),
+		MasterCertName:               config.MasterCertName,
+		SlotType:                     config.Webhook.SlotType(),
+		AgentNetwork:                 config.AgentDockerNetwork,
+		AgentDockerRuntime:           config.AgentDockerRuntime,
+		AgentDockerImage:             config.AgentDockerImage,
+		AgentFluentImage:             config.AgentFluentImage,
+		StartupScriptBase64:          startupScriptBase64,
+		ContainerStartupScriptBase64: containerScriptBase64,
+		MasterCertBase64:             masterCertBase64,
+		AgentID:                      config.Webhook.AgentID,
+		ResourcePool:                 resourcePool,
+	})
+
+	return &webhookCluster{
+		WebhookClusterConfig: config.Webhook,
+		resourcePool:         resourcePool,
+		masterURL:            *masterURL,
+		setupScript:          base64.StdEncoding.EncodeToString(setupScript),
+		client:               &http.Client{Timeout: time.Duration(config.Webhook.Timeout)},
+		firstSeen:            make(map[string]time.Time),
+	}, nil
+}
+
+func (c *webhookCluster) instanceType() instanceType {
+	return c.InstanceType
+}
+
+func (c *webhookCluster) slotsPerInstance() int {
+	return c.WebhookClusterConfig.SlotsPerInstance()
+}
+
+func (c *webhookCluster) instanceSlots() []int {
+	return []int{c.slotsPerInstance()}
+}
+
+func (c *webhookCluster) prestart(ctx *actor.Context) {}
+
+func (c *webhookCluster) list(ctx *actor.Context) ([]*Instance, error) {
+	instances, err := c.listInstances()
+	if err != nil {
+		return nil, err
+	}
+	for _, inst := range instances {
+		if inst.State == Unknown {
+			ctx.Log().Errorf("unknown instance state for instance %v", inst.ID)
+		}
+	}
+	return instances, nil
+}
+
+func (c *webhookCluster) launch(ctx *actor.Context, instanceNum int) {
+	if instanceNum <= 0 {
+		return
+	}
+	launched, err := c.launchInstances(instanceNum)
+	if err != nil {
+		ctx.Log().WithError(err).Errorf("cannot launch %d instances", instanceNum)
+		return
+	}
+	ctx.Log().Infof("launched %d instances: %s", instanceNum, fmtInstances(launched))
+}
+
+func (c *webhookCluster) terminate(ctx *actor.Context, instanceIDs []string) {
+	if len(instanceIDs) == 0 {
+		return
+	}
+	if err := c.terminateInstances(instanceIDs); err != nil {
+		ctx.Log().WithError(err).Errorf("cannot terminate %d instances", len(instanceIDs))
+		return
+	}
+	ctx.Log().Infof("terminated %d instances: %s", len(instanceIDs), instanceIDs)
+}
+
+func (c *webhookCluster) listInstances() ([]*Instance, error) {
+	target, err := url.Parse(c.ListURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse webhook list url")
+	}
+	query := target.Query()
+	query.Set("resource_pool", c.resourcePool)
+	target.RawQuery = query.Encode()
+
+	var resp webhookInstances
+	if err := c.do(http.MethodGet, target.String(), nil, &resp); err != nil {
+		return nil, errors.Wrap(err, "cannot list instances")
+	}
+
+	seen := make(map[string]time.Time, len(resp.Instances))
+	instances := c.newInstances(resp.Instances)
+	for _, inst := range instances {
+		seen[inst.ID] = c.firstSeen[inst.ID]
+		if seen[inst.ID].IsZero() {
+			seen[inst.ID] = time.Now()
+		}
+		if inst.LaunchTime.IsZero() {
+			inst.LaunchTime = seen[inst.ID]
+		}
+	}
+	c.firstSeen = seen
+	return instances, nil
+}
+
+func (c *webhookCluster) launchInstances(instanceNum int) ([]*Instance, error) {
+	var resp webhookInstances
+	if err := c.do(http.MethodPost, c.LaunchURL, webhookLaunchRequest{
+		ResourcePool:     c.resourcePool,
+		InstanceType:     c.InstanceType.name(),
+		NumInstances:     instanceNum,
+		MasterURL:        c.masterURL.String(),
+		AgentSetupScript: c.setupScript,
+	}, &resp); err != nil {
+		return nil, err
+	}
+	return c.newInstances(resp.Instances), nil
+}
+
+func (c *webhookCluster) terminateInstances(instanceIDs []string) error {
+	return c.do(http.MethodPost, c.TerminateURL, webhookTerminateRequest{
+		ResourcePool: c.resourcePool,
+		InstanceIDs:  instanceIDs,
+	}, nil)
+}
+
+func (c *webhookCluster) do(method, target string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if len(respBody) > maxWebhookErrorBodyLen {
+			respBody = respBody[:maxWebhookErrorBodyLen]
+		}
+		return errors.Errorf("%s %s returned %s: %s", method, req.URL.Redacted(), resp.Status,
+			bytes.TrimSpace(respBody))
+	}
+	if out == nil || len(bytes.TrimSpace(respBody)) == 0 {
+		return nil
+	}
+	return errors.Wrap(json.Unmarshal(respBody, out), "cannot parse webhook response")
+}
+
+func (c *webhookCluster) newInstances(input []webhookInstance) []*Instance {
+	output := make([]*Instance, 0, len(input))
+	for _, inst := range input {
+		agentName := inst.AgentName
+		if len(agentName) == 0 {
+			agentName = inst.ID
+		}
+		var launchTime time.Time
+		if inst.LaunchTime != nil {
+			launchTime = *inst.LaunchTime
+		}
+		output = append(output, &Instance{
+			ID:         inst.ID,
+			LaunchTime: launchTime,
+			AgentName:  agentName,
+			State:      c.stateOf(inst.State),
+			Slots:      c.slotsPerInstance(),
+		})
+	}
+	return output
+}
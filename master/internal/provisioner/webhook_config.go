@@ -0,0 +1,145 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// WebhookClusterConfig describes the configuration of a cluster whose instances are listed,
+// launched and terminated by calling user-provided HTTP endpoints. This lets Determined scale
+// agents on infrastructure that is not natively supported.
+type WebhookClusterConfig struct {
+	ListURL      string `json:"list_url"`
+	LaunchURL    string `json:"launch_url"`
+	TerminateURL string `json:"terminate_url"`
+
+	// Headers are added to every request, e.g., to authenticate with the endpoints.
+	Headers map[string]string `json:"headers"`
+	Timeout model.Duration    `json:"timeout"`
+
+	InstanceType webhookInstanceType `json:"instance_type"`
+
+	// AgentID is a shell expression evaluated on the instance to name its agent. The name must
+	// match the agent name the list endpoint returns for the instance.
+	AgentID string `json:"agent_id"`
+
+	// StateMapping maps the instance states returned by the list endpoint onto instance states,
+	// in addition to the default mapping.
+	StateMapping map[string]InstanceState `json:"state_mapping"`
+}
+
+var defaultWebhookClusterConfig = WebhookClusterConfig{
+	Timeout: model.Duration(30 * time.Second),
+	InstanceType: webhookInstanceType{
+		NumSlots: 1,
+		SlotType: device.GPU,
+	},
+	AgentID: "$(hostname)",
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *WebhookClusterConfig) UnmarshalJSON(data []byte) error {
+	*c = defaultWebhookClusterConfig
+	type DefaultParser *WebhookClusterConfig
+	return json.Unmarshal(data, DefaultParser(c))
+}
+
+// Validate implements the check.Validatable interface.
+func (c WebhookClusterConfig) Validate() []error {
+	errs := []error{
+		validateWebhookURL(c.ListURL, "list_url"),
+		validateWebhookURL(c.LaunchURL, "launch_url"),
+		validateWebhookURL(c.TerminateURL, "terminate_url"),
+		check.GreaterThan(int64(c.Timeout), int64(0), "webhook timeout must be greater than 0"),
+		check.NotEmpty(c.AgentID, "webhook agent id must be non-empty"),
+	}
+	for state, mapped := range c.StateMapping {
+		if _, ok := webhookInstanceStates[strings.ToLower(string(mapped))]; !ok {
+			errs = append(errs, errors.Errorf(
+				"webhook state %s is mapped to an invalid instance state %s", state, mapped))
+		}
+	}
+	return errs
+}
+
+func validateWebhookURL(rawURL, name string) error {
+	if len(rawURL) == 0 {
+		return errors.Errorf("webhook %s must be non-empty", name)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse webhook %s", name)
+	}
+	return check.In(parsed.Scheme, []string{"http", "https"},
+		"webhook %s scheme must be within [http, https]", name)
+}
+
+// SlotsPerInstance returns the number of slots per instance.
+func (c WebhookClusterConfig) SlotsPerInstance() int {
+	return c.InstanceType.Slots()
+}
+
+// SlotType returns the type of the slot.
+func (c WebhookClusterConfig) SlotType() device.Type {
+	if c.InstanceType.Slots() == 0 {
+		return device.ZeroSlot
+	}
+	return c.InstanceType.SlotType
+}
+
+// stateOf maps an instance state returned by the list endpoint onto an instance state.
+func (c WebhookClusterConfig) stateOf(state string) InstanceState {
+	if mapped, ok := c.StateMapping[state]; ok {
+		state = string(mapped)
+	}
+	if mapped, ok := webhookInstanceStates[strings.ToLower(state)]; ok {
+		return mapped
+	}
+	return Unknown
+}
+
+// webhookInstanceStates is the default mapping of the states returned by the list endpoint. The
+// names of the instance states themselves are always recognized, regardless of case.
+var webhookInstanceStates = map[string]InstanceState{
+	"unknown":      Unknown,
+	"starting":     Starting,
+	"pending":      Starting,
+	"provisioning": Starting,
+	"running":      Running,
+	"stopping":     Stopping,
+	"stopped":      Stopped,
+	"terminating":  Terminating,
+}
+
+// webhookInstanceType describes the instances the launch endpoint creates. The name is passed to
+// the endpoint as is.
+type webhookInstanceType struct {
+	Name     string      `json:"name"`
+	NumSlots int         `json:"slots"`
+	SlotType device.Type `json:"slot_type"`
+}
+
+func (t webhookInstanceType) name() string {
+	return t.Name
+}
+
+func (t webhookInstanceType) Slots() int {
+	return t.NumSlots
+}
+
+// Validate implements the check.Validatable interface.
+func (t webhookInstanceType) Validate() []error {
+	return []error{
+		check.GreaterThanOrEqualTo(t.NumSlots, 0, "webhook instance slots must be >= 0"),
+		check.In(string(t.SlotType), []string{string(device.GPU), string(device.CPU)},
+			"webhook instance slot_type must be within [gpu, cpu]"),
+	}
+}
@@ -0,0 +1,75 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestUnmarshalWebhookClusterConfig(t *testing.T) {
+	var config WebhookClusterConfig
+	err := json.Unmarshal([]byte(`
+{
+	"list_url": "https://vms.example.com/list",
+	"launch_url": "https://vms.example.com/launch",
+	"terminate_url": "https://vms.example.com/terminate",
+	"headers": {"Authorization": "Bearer token"},
+	"timeout": "10s",
+	"instance_type": {"name": "gpu-large", "slots": 8},
+	"state_mapping": {"ACTIVE": "Running", "BUILD": "starting"}
+}`), &config)
+	assert.NilError(t, err)
+	err = check.Validate(&config)
+	assert.NilError(t, err)
+
+	expected := defaultWebhookClusterConfig
+	expected.ListURL = "https://vms.example.com/list"
+	expected.LaunchURL = "https://vms.example.com/launch"
+	expected.TerminateURL = "https://vms.example.com/terminate"
+	expected.Headers = map[string]string{"Authorization": "Bearer token"}
+	expected.Timeout = model.Duration(10 * time.Second)
+	expected.InstanceType = webhookInstanceType{Name: "gpu-large", NumSlots: 8, SlotType: device.GPU}
+	expected.StateMapping = map[string]InstanceState{"ACTIVE": Running, "BUILD": "starting"}
+	assert.DeepEqual(t, config, expected)
+	assert.Equal(t, config.SlotsPerInstance(), 8)
+	assert.Equal(t, config.SlotType(), device.GPU)
+
+	assert.Equal(t, config.stateOf("ACTIVE"), Running)
+	assert.Equal(t, config.stateOf("BUILD"), Starting)
+	assert.Equal(t, config.stateOf("Stopped"), Stopped)
+	assert.Equal(t, config.stateOf("terminating"), Terminating)
+	assert.Equal(t, config.stateOf("ERROR"), Unknown)
+}
+
+func TestWebhookClusterConfigMissingFields(t *testing.T) {
+	var config WebhookClusterConfig
+	err := json.Unmarshal([]byte(`
+{
+	"list_url": "ftp://vms.example.com/list",
+	"instance_type": {"slot_type": "tpu"},
+	"state_mapping": {"ACTIVE": "Up"}
+}`), &config)
+	assert.NilError(t, err)
+	err = check.Validate(&config)
+	assert.ErrorContains(t, err, "webhook list_url scheme must be within [http, https]")
+	assert.ErrorContains(t, err, "webhook launch_url must be non-empty")
+	assert.ErrorContains(t, err, "webhook terminate_url must be non-empty")
+	assert.ErrorContains(t, err, "webhook instance slot_type must be within [gpu, cpu]")
+	assert.ErrorContains(t, err, "webhook state ACTIVE is mapped to an invalid instance state Up")
+}
+
+func TestPrintableWebhookConfig(t *testing.T) {
+	webhook := defaultWebhookClusterConfig
+	webhook.Headers = map[string]string{"Authorization": "Bearer token"}
+	config := Config{Webhook: &webhook}
+
+	printable := config.Printable()
+	assert.DeepEqual(t, printable.Webhook.Headers, map[string]string{"Authorization": "********"})
+	assert.DeepEqual(t, config.Webhook.Headers, map[string]string{"Authorization": "Bearer token"})
+}
@@ -0,0 +1,156 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+// fakeWebhook is an in-memory VM platform implementing the webhook contract.
+type fakeWebhook struct {
+	t         *testing.T
+	instances []webhookInstance
+	launches  []webhookLaunchRequest
+	fail      bool
+}
+
+func (f *fakeWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if f.fail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("platform unavailable"))
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/list":
+		assert.Equal(f.t, r.URL.Query().Get("resource_pool"), "default")
+		assert.Equal(f.t, r.URL.Query().Get("zone"), "a")
+		assert.NilError(f.t, json.NewEncoder(w).Encode(webhookInstances{Instances: f.instances}))
+	case r.Method == http.MethodPost && r.URL.Path == "/launch":
+		var req webhookLaunchRequest
+		assert.NilError(f.t, json.NewDecoder(r.Body).Decode(&req))
+		f.launches = append(f.launches, req)
+		var launched []webhookInstance
+		for i := 0; i < req.NumInstances; i++ {
+			inst := webhookInstance{ID: "vm-" + string(rune('a'+len(f.instances))), State: "BUILD"}
+			f.instances = append(f.instances, inst)
+			launched = append(launched, inst)
+		}
+		assert.NilError(f.t, json.NewEncoder(w).Encode(webhookInstances{Instances: launched}))
+	case r.Method == http.MethodPost && r.URL.Path == "/terminate":
+		var req webhookTerminateRequest
+		assert.NilError(f.t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(f.t, req.ResourcePool, "default")
+		terminated := make(map[string]bool)
+		for _, id := range req.InstanceIDs {
+			terminated[id] = true
+		}
+		var remaining []webhookInstance
+		for _, inst := range f.instances {
+			if !terminated[inst.ID] {
+				remaining = append(remaining, inst)
+			}
+		}
+		f.instances = remaining
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestWebhookCluster(t *testing.T) (*webhookCluster, *fakeWebhook) {
+	fake := &fakeWebhook{t: t}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := defaultWebhookClusterConfig
+	config.ListURL = server.URL + "/list?zone=a"
+	config.LaunchURL = server.URL + "/launch"
+	config.TerminateURL = server.URL + "/terminate"
+	config.Headers = map[string]string{"Authorization": "Bearer token"}
+	config.InstanceType.Name = "gpu-large"
+	config.StateMapping = map[string]InstanceState{"BUILD": Starting, "ACTIVE": Running}
+
+	cluster, err := newWebhookCluster("default", &Config{
+		MasterURL: "http://master.example.com:8080",
+		Webhook:   &config,
+	}, nil)
+	assert.NilError(t, err)
+	return cluster, fake
+}
+
+func TestWebhookClusterLifecycle(t *testing.T) {
+	cluster, fake := newTestWebhookCluster(t)
+
+	instances, err := cluster.listInstances()
+	assert.NilError(t, err)
+	assert.Equal(t, len(instances), 0)
+
+	launched, err := cluster.launchInstances(2)
+	assert.NilError(t, err)
+	assert.Equal(t, len(launched), 2)
+	assert.Equal(t, len(fake.launches), 1)
+	assert.Equal(t, fake.launches[0].ResourcePool, "default")
+	assert.Equal(t, fake.launches[0].InstanceType, "gpu-large")
+	assert.Equal(t, fake.launches[0].NumInstances, 2)
+	assert.Equal(t, fake.launches[0].MasterURL, "http://master.example.com:8080")
+	assert.Assert(t, len(fake.launches[0].AgentSetupScript) > 0)
+
+	launchTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake.instances[0].State = "ACTIVE"
+	fake.instances[0].AgentName = "agent-a"
+	fake.instances[0].LaunchTime = &launchTime
+
+	instances, err = cluster.listInstances()
+	assert.NilError(t, err)
+	assert.Equal(t, len(instances), 2)
+	assert.DeepEqual(t, *instances[0], Instance{
+		ID: "vm-a", AgentName: "agent-a", LaunchTime: launchTime, State: Running, Slots: 1,
+	})
+	assert.Equal(t, instances[1].ID, "vm-b")
+	assert.Equal(t, instances[1].AgentName, "vm-b")
+	assert.Equal(t, instances[1].State, Starting)
+	assert.Assert(t, !instances[1].LaunchTime.IsZero())
+
+	// Instances listed without a launch time keep the time they were first seen.
+	firstSeen := instances[1].LaunchTime
+	instances, err = cluster.listInstances()
+	assert.NilError(t, err)
+	assert.Equal(t, instances[1].LaunchTime, firstSeen)
+
+	assert.NilError(t, cluster.terminateInstances([]string{"vm-a"}))
+	instances, err = cluster.listInstances()
+	assert.NilError(t, err)
+	assert.Equal(t, len(instances), 1)
+	assert.Equal(t, instances[0].ID, "vm-b")
+}
+
+func TestWebhookClusterErrors(t *testing.T) {
+	cluster, fake := newTestWebhookCluster(t)
+
+	fake.fail = true
+	_, err := cluster.listInstances()
+	assert.ErrorContains(t, err, "503 Service Unavailable: platform unavailable")
+	_, err = cluster.launchInstances(1)
+	assert.ErrorContains(t, err, "503 Service Unavailable")
+	err = cluster.terminateInstances([]string{"vm-a"})
+	assert.ErrorContains(t, err, "503 Service Unavailable")
+
+	fake.fail = false
+	cluster.Headers = nil
+	_, err = cluster.listInstances()
+	assert.ErrorContains(t, err, "401 Unauthorized")
+
+	cluster.Headers = map[string]string{"Authorization": "Bearer token"}
+	cluster.client.Timeout = time.Nanosecond
+	_, err = cluster.listInstances()
+	assert.ErrorContains(t, err, "cannot list instances")
+}
@@ -6,22 +6,31 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
+// authCookieName is the name of the cookie holding the master session token of the user.
+const authCookieName = "auth"
+
 // Proxy-specific actor messages.
 type (
 	// Register registers the service name with the associated target URL. All requests with the
-	// format ".../:service-name/*" are forwarded to the service via the target URL.
+	// format ".../:service-name/*" from the owner of the service, the users it is shared with or
+	// admins are forwarded to the service via the target URL.
 	Register struct {
-		ServiceID string
-		URL       *url.URL
-		ProxyTCP  bool
+		ServiceID  string
+		URL        *url.URL
+		ProxyTCP   bool
+		Owner      string
+		SharedWith []string
 	}
 	// Unregister removes the service from the proxy. All future requests until the service name is
 	// registered again will be responded with a 404 response. If the service is not registered with
@@ -41,6 +50,34 @@ type Service struct {
 	URL           *url.URL
 	LastRequested time.Time
 	ProxyTCP      bool
+	Owner         string
+	SharedWith    []string
+}
+
+// copy returns a copy of the service that can be used outside of the lock of the proxy.
+func (s *Service) copy() Service {
+	sURL := *s.URL
+	return Service{
+		URL:           &sURL,
+		LastRequested: s.LastRequested,
+		ProxyTCP:      s.ProxyTCP,
+		Owner:         s.Owner,
+		SharedWith:    append([]string(nil), s.SharedWith...),
+	}
+}
+
+// canAccess returns whether the user may access the service: admins may access any service, and
+// other users only the services they own or that are shared with them.
+func (s *Service) canAccess(user model.User) bool {
+	if user.Admin || (s.Owner != "" && user.Username == s.Owner) {
+		return true
+	}
+	for _, username := range s.SharedWith {
+		if user.Username == username {
+			return true
+		}
+	}
+	return false
 }
 
 // Proxy is an actor that proxies requests to registered services.
@@ -61,7 +98,13 @@ func (p *Proxy) Receive(ctx *actor.Context) error {
 		p.lock.Lock()
 		defer p.lock.Unlock()
 		ctx.Log().Infof("registering service: %s (%v)", msg.ServiceID, msg.URL)
-		p.services[msg.ServiceID] = &Service{msg.URL, time.Now(), msg.ProxyTCP}
+		p.services[msg.ServiceID] = &Service{
+			URL:           msg.URL,
+			LastRequested: time.Now(),
+			ProxyTCP:      msg.ProxyTCP,
+			Owner:         msg.Owner,
+			SharedWith:    msg.SharedWith,
+		}
 
 		if ctx.ExpectingResponse() {
 			ctx.Respond(nil)
@@ -93,8 +136,8 @@ func (p *Proxy) getService(serviceName string) *Service {
 	service.LastRequested = time.Now()
 
 	// Make a copy to avoid callers mutating the object outside of this locked method.
-	sURL := *service.URL
-	return &Service{&sURL, service.LastRequested, service.ProxyTCP}
+	sCopy := service.copy()
+	return &sCopy
 }
 
 // Service an HTTP request through the /proxy/:service/* route.
@@ -108,8 +151,26 @@ func (p *Proxy) newProxyHandler(serviceID string) echo.HandlerFunc {
 				fmt.Sprintf("service not found: %s", serviceName))
 		}
 
+		// Only proxy requests from users allowed to access the service. The user is set by the
+		// authentication middleware of the route.
+		user, ok := c.Get("user").(model.User)
+		if !ok {
+			return echo.NewHTTPError(http.StatusUnauthorized)
+		}
+		if !service.canAccess(user) {
+			log.WithFields(log.Fields{
+				"service": serviceName,
+				"owner":   service.Owner,
+				"user":    user.Username,
+				"remote":  c.RealIP(),
+			}).Warn("denied access to proxied service")
+			return echo.NewHTTPError(http.StatusForbidden,
+				fmt.Sprintf("user %s may not access service: %s", user.Username, serviceName))
+		}
+
 		// Set proxy headers.
 		req := c.Request()
+		stripCredentials(req)
 		if req.Header.Get(echo.HeaderXRealIP) == "" {
 			req.Header.Set(echo.HeaderXRealIP, c.RealIP())
 		}
@@ -136,14 +197,28 @@ func (p *Proxy) newProxyHandler(serviceID string) echo.HandlerFunc {
 	}
 }
 
+// stripCredentials removes the master credentials of the user from the request, so that they are
+// not exposed to services owned by other users.
+func stripCredentials(req *http.Request) {
+	if strings.HasPrefix(req.Header.Get(echo.HeaderAuthorization), "Bearer ") {
+		req.Header.Del(echo.HeaderAuthorization)
+	}
+	cookies := req.Cookies()
+	req.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != authCookieName {
+			req.AddCookie(cookie)
+		}
+	}
+}
+
 func (p *Proxy) getSummary() map[string]Service {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 	snapshot := make(map[string]Service)
 
 	for id, service := range p.services {
-		sURL := *service.URL
-		snapshot[id] = Service{&sURL, service.LastRequested, service.ProxyTCP}
+		snapshot[id] = service.copy()
 	}
 
 	return snapshot
@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const testUserHeader = "X-Test-User"
+
+// newTestProxy starts a service that echoes the cookies it receives behind a proxy owned by alice
+// and shared with bob. The user of each request is taken from the testUserHeader header.
+func newTestProxy(t *testing.T) *httptest.Server {
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Cookie")))
+	}))
+	t.Cleanup(service.Close)
+	serviceURL, err := url.Parse(service.URL)
+	assert.NilError(t, err)
+
+	p := &Proxy{services: map[string]*Service{
+		"notebook": {
+			URL:           serviceURL,
+			LastRequested: time.Now(),
+			Owner:         "alice",
+			SharedWith:    []string{"bob"},
+		},
+	}}
+
+	users := map[string]model.User{
+		"alice":   {Username: "alice", Active: true},
+		"bob":     {Username: "bob", Active: true},
+		"mallory": {Username: "mallory", Active: true},
+		"admin":   {Username: "admin", Active: true, Admin: true},
+	}
+	e := echo.New()
+	e.Any("/proxy/:service/*", p.newProxyHandler("service"),
+		func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				if user, ok := users[c.Request().Header.Get(testUserHeader)]; ok {
+					c.Set("user", user)
+				}
+				return next(c)
+			}
+		})
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func proxyRequest(t *testing.T, server *httptest.Server, service, user string) (int, string) {
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/proxy/"+service+"/lab", nil)
+	assert.NilError(t, err)
+	req.Header.Set(testUserHeader, user)
+	req.AddCookie(&http.Cookie{Name: authCookieName, Value: "secret"})
+	req.AddCookie(&http.Cookie{Name: "_xsrf", Value: "xsrf"})
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestProxyAccess(t *testing.T) {
+	server := newTestProxy(t)
+
+	for _, tc := range []struct {
+		user   string
+		status int
+	}{
+		{"alice", http.StatusOK},
+		{"bob", http.StatusOK},
+		{"admin", http.StatusOK},
+		{"mallory", http.StatusForbidden},
+		{"", http.StatusUnauthorized},
+	} {
+		status, _ := proxyRequest(t, server, "notebook", tc.user)
+		assert.Equal(t, status, tc.status, "user %q", tc.user)
+	}
+
+	status, _ := proxyRequest(t, server, "missing", "alice")
+	assert.Equal(t, status, http.StatusNotFound)
+}
+
+func TestProxyStripsCredentials(t *testing.T) {
+	server := newTestProxy(t)
+
+	status, cookies := proxyRequest(t, server, "notebook", "bob")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Assert(t, !strings.Contains(cookies, "secret"), cookies)
+	assert.Assert(t, strings.Contains(cookies, "_xsrf=xsrf"), cookies)
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+// instanceRecorder records the lifetimes of the instances that the provisioners launch, which
+// the cost reports are computed from.
+type instanceRecorder struct {
+	db *db.PgDB
+}
+
+func (r *instanceRecorder) Receive(ctx *actor.Context) error {
+	switch msg := ctx.Message().(type) {
+	case actor.PreStart, actor.PostStop:
+
+	case sproto.ProvisionedInstances:
+		// Don't return the error, since the next change of the instances records them again.
+		if err := r.db.RecordProvisionedInstances(msg.ResourcePool, msg.Instances, msg.Time); err != nil {
+			ctx.Log().WithError(err).Errorf(
+				"failed to record provisioned instances of resource pool %s", msg.ResourcePool)
+		}
+
+	default:
+		return actor.ErrUnexpectedMessage(ctx)
+	}
+	return nil
+}
+
+func (m *Master) fetchResourceCost(start, end time.Time) (*apiv1.ResourceCostResponse, error) {
+	if start.After(end) {
+		return nil, errors.New("start time cannot be after end time")
+	}
+	resp := &apiv1.ResourceCostResponse{}
+	if err := m.db.QueryProto(
+		"get_resource_cost", &resp.CostEntries, start.UTC(), end.UTC(),
+	); err != nil {
+		return nil, errors.Wrap(err, "error fetching resource cost data")
+	}
+	resp.Report = resourceCostReport(resp.CostEntries)
+	return resp, nil
+}
+
+// resourceCostReport sums the cost entries by experiment, user, label and resource pool. The cost
+// of an experiment with several labels counts toward each of them.
+func resourceCostReport(entries []*masterv1.ResourceCostEntry) *masterv1.ResourceCostReport {
+	report := &masterv1.ResourceCostReport{
+		ByExperiment:       map[int32]float64{},
+		ByUsername:         map[string]float64{},
+		ByExperimentLabel:  map[string]float64{},
+		ByResourcePool:     map[string]float64{},
+		IdleByResourcePool: map[string]float64{},
+	}
+	for _, entry := range entries {
+		report.TotalCost += entry.Cost
+		report.ByResourcePool[entry.ResourcePool] += entry.Cost
+		if entry.Kind == "idle" {
+			report.IdleCost += entry.Cost
+			report.IdleByResourcePool[entry.ResourcePool] += entry.Cost
+			continue
+		}
+		if entry.ExperimentId != 0 {
+			report.ByExperiment[entry.ExperimentId] += entry.Cost
+		}
+		if entry.Username != "" {
+			report.ByUsername[entry.Username] += entry.Cost
+		}
+		for _, label := range entry.Labels {
+			report.ByExperimentLabel[label] += entry.Cost
+		}
+	}
+	return report
+}
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/testing/protocmp"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+func TestResourceCostReport(t *testing.T) {
+	report := resourceCostReport([]*masterv1.ResourceCostEntry{
+		{
+			ResourcePool: "gpu", Kind: "trial", ExperimentId: 1, Username: "alice",
+			Labels: []string{"vision", "prod"}, SlotSeconds: 7200, Cost: 6,
+		},
+		{
+			ResourcePool: "gpu", Kind: "trial", ExperimentId: 2, Username: "bob",
+			Labels: []string{"vision"}, SlotSeconds: 3600, Cost: 3,
+		},
+		{ResourcePool: "gpu", Kind: "notebook", Username: "carol", SlotSeconds: 1200, Cost: 1},
+		{ResourcePool: "gpu", Kind: "idle", SlotSeconds: 2400, Cost: 2},
+		{ResourcePool: "cpu", Kind: "idle", SlotSeconds: 0, Cost: 0.5},
+	})
+	assert.DeepEqual(t, report, &masterv1.ResourceCostReport{
+		TotalCost:          12.5,
+		IdleCost:           2.5,
+		ByExperiment:       map[int32]float64{1: 6, 2: 3},
+		ByUsername:         map[string]float64{"alice": 6, "bob": 3, "carol": 1},
+		ByExperimentLabel:  map[string]float64{"vision": 9, "prod": 6},
+		ByResourcePool:     map[string]float64{"gpu": 12, "cpu": 0.5},
+		IdleByResourcePool: map[string]float64{"gpu": 2, "cpu": 0.5},
+	}, protocmp.Transform())
+}
@@ -1,6 +1,9 @@
 package resourcemanagers
 
 import (
+	"math"
+	"sort"
+
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/check"
@@ -14,6 +17,7 @@ type agentState struct {
 	handler  *actor.Ref
 	devices  map[device.Device]*cproto.ID
 	label    string
+	labels   map[string]string
 	enabled  bool
 	draining bool
 
@@ -22,6 +26,31 @@ type agentState struct {
 	// We need this field to know if the agent is idle.
 	zeroSlotContainers    map[cproto.ID]bool
 	maxZeroSlotContainers int
+
+	// cpus and memory are the capacity of the host of the agent; they are zero when the agent does
+	// not report them, in which case tasks may request any amount of them.
+	cpus   float64
+	memory int64
+	// hostResources holds the CPUs and memory allocated to the containers on the agent that
+	// requested them.
+	hostResources map[cproto.ID]hostResources
+}
+
+// hostResources are the CPUs and memory (in bytes) allocated to a container.
+type hostResources struct {
+	cpus   float64
+	memory int64
+}
+
+// physicalDevice identifies a physical device of an agent. An agent that shares its devices among
+// tasks reports every physical device as several devices, its shares.
+type physicalDevice struct {
+	uuid string
+	id   int
+}
+
+func physicalDeviceOf(d device.Device) physicalDevice {
+	return physicalDevice{uuid: d.UUID, id: d.PhysicalID()}
 }
 
 // newAgentState returns a new agent empty agent state backed by the handler.
@@ -29,10 +58,14 @@ func newAgentState(msg sproto.AddAgent, maxZeroSlotContainers int) *agentState {
 	return &agentState{
 		handler:               msg.Agent,
 		label:                 msg.Label,
+		labels:                msg.Labels,
 		devices:               make(map[device.Device]*cproto.ID),
 		zeroSlotContainers:    make(map[cproto.ID]bool),
 		maxZeroSlotContainers: maxZeroSlotContainers,
 		enabled:               true,
+		cpus:                  float64(msg.HostInfo.CPUs),
+		memory:                msg.HostInfo.Memory,
+		hostResources:         make(map[cproto.ID]hostResources),
 	}
 }
 
@@ -43,7 +76,11 @@ func (a *agentState) numSlots() int {
 	case !a.enabled:
 		return 0
 	default:
-		return len(a.devices)
+		physicalDevices := make(map[physicalDevice]bool)
+		for d := range a.devices {
+			physicalDevices[physicalDeviceOf(d)] = true
+		}
+		return len(physicalDevices)
 	}
 }
 
@@ -57,16 +94,80 @@ func (a *agentState) numEmptySlots() (slots int) {
 	}
 }
 
-// numUsedSlots returns the number of slots that have been allocated to containers.
+// numUsedSlots returns the number of slots that have been allocated to containers. A slot is used
+// as soon as any of its shares is.
 func (a *agentState) numUsedSlots() (slots int) {
-	for _, id := range a.devices {
+	used := make(map[physicalDevice]bool)
+	for d, id := range a.devices {
 		if id != nil {
-			slots++
+			used[physicalDeviceOf(d)] = true
+		}
+	}
+	return len(used)
+}
+
+// numEmptySlotFraction returns the number of slots that have not been allocated to containers,
+// counting the free shares of partially used slots as fractions of a slot.
+func (a *agentState) numEmptySlotFraction() (slots float64) {
+	if a.draining || !a.enabled {
+		return 0
+	}
+	for d, id := range a.devices {
+		if id == nil {
+			slots += d.SlotFraction()
 		}
 	}
 	return slots
 }
 
+// freeShares returns the shares of every physical device of the agent that have not been allocated
+// to containers, sorted by ID.
+func (a *agentState) freeShares() map[physicalDevice][]device.Device {
+	free := make(map[physicalDevice][]device.Device)
+	for d, id := range a.devices {
+		if id == nil {
+			free[physicalDeviceOf(d)] = append(free[physicalDeviceOf(d)], d)
+		}
+	}
+	for _, shares := range free {
+		sort.Slice(shares, func(i, j int) bool { return shares[i].ID < shares[j].ID })
+	}
+	return free
+}
+
+// sharesNeeded returns the number of shares of a device that make up the fraction of a slot.
+// Devices that are not shared are allocated whole.
+func sharesNeeded(d device.Device, fraction float64) int {
+	if d.Sharing <= 1 {
+		return 1
+	}
+	// Tolerate rounding errors, e.g., a third of a device split into three shares is one share.
+	return int(math.Ceil(fraction*float64(d.Sharing) - 1e-9))
+}
+
+// fractionShares returns the free shares of the single physical device to allocate to a container
+// requesting a fraction of a slot, or nil if no device has enough free shares. It prefers the
+// devices with the fewest free shares to leave whole devices to tasks requesting whole slots.
+func (a *agentState) fractionShares(fraction float64) []device.Device {
+	if a.draining || !a.enabled {
+		return nil
+	}
+	var best []device.Device
+	for _, shares := range a.freeShares() {
+		if len(shares) < sharesNeeded(shares[0], fraction) {
+			continue
+		}
+		if best == nil || len(shares) < len(best) ||
+			(len(shares) == len(best) && shares[0].ID < best[0].ID) {
+			best = shares
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return best[:sharesNeeded(best[0], fraction)]
+}
+
 func (a *agentState) numUsedZeroSlots() int {
 	return len(a.zeroSlotContainers)
 }
@@ -91,6 +192,23 @@ func (a *agentState) numEmptyZeroSlots() int {
 	}
 }
 
+// usedHostResources returns the CPUs and memory that have been allocated to containers.
+func (a *agentState) usedHostResources() (cpus float64, memory int64) {
+	for _, r := range a.hostResources {
+		cpus += r.cpus
+		memory += r.memory
+	}
+	return cpus, memory
+}
+
+// hostResourcesFit returns whether the CPUs and memory of the agent that have not been allocated
+// to containers fit another container.
+func (a *agentState) hostResourcesFit(cpus float64, memory int64) bool {
+	usedCPUs, usedMemory := a.usedHostResources()
+	return (a.cpus == 0 || usedCPUs+cpus <= a.cpus) &&
+		(a.memory == 0 || usedMemory+memory <= a.memory)
+}
+
 func (a *agentState) idle() bool {
 	return a.numUsedZeroSlots() == 0 && a.numUsedSlots() == 0
 }
@@ -100,23 +218,49 @@ func (a *agentState) allocateFreeDevices(slots int, id cproto.ID) []device.Devic
 		a.zeroSlotContainers[id] = true
 		return nil
 	}
+	// Allocate every share of the free physical devices.
 	cid := id
-	devices := make([]device.Device, 0, slots)
-	for d, dcid := range a.devices {
-		if dcid == nil {
-			a.devices[d] = &cid
-			devices = append(devices, d)
-		}
-		if len(devices) == slots {
+	var devices []device.Device
+	allocated := 0
+	for _, shares := range a.freeShares() {
+		if allocated == slots {
 			break
 		}
+		if len(shares) < shares[0].Sharing {
+			continue
+		}
+		for _, d := range shares {
+			a.devices[d] = &cid
+		}
+		devices = append(devices, shares...)
+		allocated++
 	}
-	check.Panic(check.True(len(devices) == slots, "not enough devices"))
+	check.Panic(check.True(allocated == slots, "not enough devices"))
 	return devices
 }
 
+// allocateSlotFraction allocates shares of a single physical device making up the fraction of a
+// slot to the container.
+func (a *agentState) allocateSlotFraction(fraction float64, id cproto.ID) []device.Device {
+	cid := id
+	devices := a.fractionShares(fraction)
+	check.Panic(check.True(len(devices) > 0, "not enough free shares of devices"))
+	for _, d := range devices {
+		a.devices[d] = &cid
+	}
+	return devices
+}
+
+func (a *agentState) allocateHostResources(cpus float64, memory int64, id cproto.ID) {
+	if cpus == 0 && memory == 0 {
+		return
+	}
+	a.hostResources[id] = hostResources{cpus: cpus, memory: memory}
+}
+
 func (a *agentState) deallocateContainer(id cproto.ID) {
 	delete(a.zeroSlotContainers, id)
+	delete(a.hostResources, id)
 	for d, cid := range a.devices {
 		if cid != nil && *cid == id {
 			a.devices[d] = nil
@@ -128,19 +272,24 @@ func (a *agentState) deepCopy() *agentState {
 	copiedAgent := &agentState{
 		handler:               a.handler,
 		label:                 a.label,
+		labels:                a.labels,
 		devices:               make(map[device.Device]*cproto.ID),
 		zeroSlotContainers:    make(map[cproto.ID]bool),
 		maxZeroSlotContainers: a.maxZeroSlotContainers,
 		enabled:               a.enabled,
 		draining:              a.draining,
+		cpus:                  a.cpus,
+		memory:                a.memory,
+		hostResources:         make(map[cproto.ID]hostResources),
 	}
 
 	for originalDevice, id := range a.devices {
 		copiedDevice := device.Device{
-			ID:    originalDevice.ID,
-			Brand: originalDevice.Brand,
-			UUID:  originalDevice.UUID,
-			Type:  originalDevice.Type,
+			ID:      originalDevice.ID,
+			Brand:   originalDevice.Brand,
+			UUID:    originalDevice.UUID,
+			Type:    originalDevice.Type,
+			Sharing: originalDevice.Sharing,
 		}
 		copiedAgent.devices[copiedDevice] = id
 	}
@@ -149,5 +298,9 @@ func (a *agentState) deepCopy() *agentState {
 		copiedAgent.zeroSlotContainers[originalKey] = originalValue
 	}
 
+	for originalKey, originalValue := range a.hostResources {
+		copiedAgent.hostResources[originalKey] = originalValue
+	}
+
 	return copiedAgent
 }
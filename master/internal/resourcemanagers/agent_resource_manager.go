@@ -3,6 +3,7 @@ package resourcemanagers
 import (
 	"crypto/tls"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -51,7 +52,7 @@ func (a *agentResourceManager) Receive(ctx *actor.Context) error {
 		// the introduction of resource pools could have no resource pool associated with
 		// them and so we need to handle that case gracefully.
 		if len(msg.ResourcePool) == 0 {
-			if msg.SlotsNeeded == 0 {
+			if isZeroSlot(&msg) {
 				msg.ResourcePool = a.config.DefaultAuxResourcePool
 			} else {
 				msg.ResourcePool = a.config.DefaultComputeResourcePool
@@ -80,7 +81,7 @@ func (a *agentResourceManager) Receive(ctx *actor.Context) error {
 	case sproto.GetTaskSummaries:
 		ctx.Respond(a.aggregateTaskSummaries(a.forwardToAllPools(ctx, msg)))
 
-	case sproto.SetTaskName:
+	case sproto.SetTaskName, sproto.SetReservations:
 		a.forwardToAllPools(ctx, msg)
 
 	case sproto.GetDefaultComputeResourcePoolRequest:
@@ -249,7 +250,7 @@ func (a *agentResourceManager) createResourcePoolSummary(
 			preemptible = pool.Provider.AWS.SpotEnabled
 			location = pool.Provider.AWS.Region
 			imageID = pool.Provider.AWS.ImageID
-			instanceType = string(pool.Provider.AWS.InstanceType)
+			instanceType = pool.Provider.AWS.LaunchTypes()[0].Name
 			slotsPerAgent = pool.Provider.AWS.SlotsPerInstance()
 			slotType = pool.Provider.AWS.SlotType()
 		}
@@ -270,6 +271,23 @@ func (a *agentResourceManager) createResourcePoolSummary(
 				)
 			}
 		}
+		if pool.Provider.Azure != nil {
+			poolType = resourcepoolv1.ResourcePoolType_RESOURCE_POOL_TYPE_AZURE
+			preemptible = pool.Provider.Azure.SpotEnabled
+			location = pool.Provider.Azure.Location
+			imageID = pool.Provider.Azure.Image.String()
+			instanceType = string(pool.Provider.Azure.InstanceType)
+			slotsPerAgent = pool.Provider.Azure.SlotsPerInstance()
+			slotType = pool.Provider.Azure.SlotType()
+		}
+		if pool.Provider.Webhook != nil {
+			if launchURL, err := url.Parse(pool.Provider.Webhook.LaunchURL); err == nil {
+				location = launchURL.Hostname()
+			}
+			instanceType = pool.Provider.Webhook.InstanceType.Name
+			slotsPerAgent = pool.Provider.Webhook.SlotsPerInstance()
+			slotType = pool.Provider.Webhook.SlotType()
+		}
 	}
 
 	var schedulerType resourcepoolv1.SchedulerType
@@ -309,8 +327,9 @@ func (a *agentResourceManager) createResourcePoolSummary(
 		SlotType:                     slotType.Proto(),
 	}
 	if pool.Provider != nil {
-		resp.MinAgents = int32(pool.Provider.MinInstances)
-		resp.MaxAgents = int32(pool.Provider.MaxInstances)
+		minInstances, maxInstances := pool.Provider.InstanceLimits(time.Now())
+		resp.MinAgents = int32(minInstances)
+		resp.MaxAgents = int32(maxInstances)
 		resp.MasterUrl = pool.Provider.MasterURL
 		resp.MasterCertName = pool.Provider.MasterCertName
 		resp.StartupScript = pool.Provider.StartupScript
@@ -350,7 +369,7 @@ func (a *agentResourceManager) createResourcePoolSummary(
 			SubnetId:              aws.NetworkInterface.SubnetID,
 			SecurityGroupId:       aws.NetworkInterface.SecurityGroupID,
 			IamInstanceProfileArn: aws.IamInstanceProfileArn,
-			InstanceType:          string(aws.InstanceType),
+			InstanceType:          aws.LaunchTypes()[0].Name,
 			LogGroup:              aws.LogGroup,
 			LogStream:             aws.LogStream,
 			SpotEnabled:           aws.SpotEnabled,
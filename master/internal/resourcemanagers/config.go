@@ -30,6 +30,9 @@ func (r *ResourceConfig) ResolveResource() error {
 		defaultPool.PoolName = defaultResourcePoolName
 		r.ResourcePools = []ResourcePoolConfig{defaultPool}
 	}
+	if r.ResourceManager.KubernetesRM != nil {
+		r.ResourceManager.KubernetesRM.resolveResourcePools()
+	}
 	return nil
 }
 
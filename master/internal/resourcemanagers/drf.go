@@ -0,0 +1,164 @@
+package resourcemanagers
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+)
+
+type drfScheduler struct{}
+
+// NewDRFScheduler creates a new scheduler that schedules tasks according to the dominant resource
+// fairness of groups: the next task to start belongs to the group whose largest share of the slots,
+// CPUs or memory of the resource pool, divided by its weight, is the smallest. It never preempts
+// tasks.
+func NewDRFScheduler() Scheduler {
+	return &drfScheduler{}
+}
+
+// drfResources holds an amount of each resource that the scheduler shares among groups.
+type drfResources struct {
+	slots  float64
+	cpus   float64
+	memory float64
+}
+
+type drfGroupState struct {
+	*group
+
+	// used holds the resources in use by running tasks and by tasks being scheduled.
+	used        drfResources
+	usedSlots   int
+	pendingReqs []*sproto.AllocateRequest
+}
+
+// dominantShare returns the largest share of the capacity used by the group, divided by its weight.
+// Resources that no agent reports are left out.
+func (s *drfGroupState) dominantShare(capacity drfResources) float64 {
+	share := 0.0
+	for _, dimension := range []struct{ used, capacity float64 }{
+		{s.used.slots, capacity.slots},
+		{s.used.cpus, capacity.cpus},
+		{s.used.memory, capacity.memory},
+	} {
+		if dimension.capacity > 0 {
+			share = math.Max(share, dimension.used/dimension.capacity)
+		}
+	}
+	if s.weight > 0 {
+		share /= s.weight
+	}
+	return share
+}
+
+// use adds the resources of a task running on the given number of containers to the group.
+func (s *drfGroupState) use(req *sproto.AllocateRequest, containers int) {
+	s.usedSlots += req.SlotsNeeded
+	s.used.slots += float64(req.SlotsNeeded) + req.SlotFraction
+	s.used.cpus += req.CPUsNeeded * float64(containers)
+	s.used.memory += float64(req.MemoryNeeded) * float64(containers)
+}
+
+func (d *drfScheduler) Schedule(rp *ResourcePool) ([]*sproto.AllocateRequest, []*actor.Ref) {
+	return drfSchedule(rp.taskList, rp.groups, rp.agents, rp.fittingMethod)
+}
+
+func drfSchedule(
+	taskList *taskList,
+	groups map[*actor.Ref]*group,
+	agents map[*actor.Ref]*agentState,
+	fittingMethod SoftConstraint,
+) ([]*sproto.AllocateRequest, []*actor.Ref) {
+	taskList.ClearPendingReasons()
+	var states []*drfGroupState
+	groupMapping := make(map[*group]*drfGroupState)
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		group := groups[req.Group]
+		state, ok := groupMapping[group]
+		if !ok {
+			state = &drfGroupState{group: group}
+			states = append(states, state)
+			groupMapping[group] = state
+		}
+		assigned := taskList.GetAllocations(req.TaskActor)
+		switch {
+		case assigned == nil || len(assigned.Reservations) == 0:
+			state.pendingReqs = append(state.pendingReqs, req)
+		default:
+			state.use(req, len(assigned.Reservations))
+		}
+	}
+
+	// Tasks are placed on a local copy of the agents so that later tasks only see the resources
+	// that earlier ones leave.
+	capacity := drfCapacity(agents)
+	localAgentsState := deepCopyAgents(agents)
+	toAllocate := make([]*sproto.AllocateRequest, 0)
+	for {
+		state := nextDRFGroup(states, capacity)
+		if state == nil {
+			break
+		}
+		req := state.pendingReqs[0]
+		state.pendingReqs = state.pendingReqs[1:]
+
+		if state.maxSlots != nil && state.usedSlots+req.SlotsNeeded > *state.maxSlots {
+			taskList.SetPendingReason(req.TaskActor, sproto.PendingReason{
+				Code: sproto.PendingGroupMaxSlots,
+				Message: fmt.Sprintf("needs %d slots but its group uses %d of its max_slots of %d",
+					req.SlotsNeeded, state.usedSlots, *state.maxSlots),
+			})
+			continue
+		}
+		fits := findFits(req, localAgentsState, fittingMethod)
+		if len(fits) == 0 {
+			continue
+		}
+		addTaskToAgents(fits)
+		state.use(req, len(fits))
+		toAllocate = append(toAllocate, req)
+	}
+
+	recordPendingReasons(taskList, newPendingExplainer(agents, fittingMethod), toAllocate)
+	return toAllocate, make([]*actor.Ref, 0)
+}
+
+// drfCapacity returns the slots, CPUs and memory of the agents that tasks can be scheduled on.
+func drfCapacity(agents map[*actor.Ref]*agentState) drfResources {
+	var capacity drfResources
+	for _, agent := range agents {
+		if !agent.enabled || agent.draining {
+			continue
+		}
+		capacity.slots += float64(agent.numSlots())
+		capacity.cpus += agent.cpus
+		capacity.memory += float64(agent.memory)
+	}
+	return capacity
+}
+
+// nextDRFGroup returns the group with pending tasks that has the smallest dominant share, breaking
+// ties by registration time, or nil if no group has pending tasks.
+func nextDRFGroup(states []*drfGroupState, capacity drfResources) *drfGroupState {
+	var candidates []*drfGroupState
+	for _, state := range states {
+		if len(state.pendingReqs) > 0 {
+			candidates = append(candidates, state)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		first, second := candidates[i], candidates[j]
+		if a, b := first.dominantShare(capacity), second.dominantShare(capacity); a != b {
+			return a < b
+		}
+		return first.handler.RegisteredTime().Before(second.handler.RegisteredTime())
+	})
+	return candidates[0]
+}
@@ -0,0 +1,69 @@
+package resourcemanagers
+
+import (
+	"testing"
+
+	"github.com/determined-ai/determined/master/pkg/actor"
+)
+
+func TestDRFSchedulerSharesDominantResource(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent", slots: 8, cpus: 8, memory: 64, maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", weight: 1},
+		{id: "group2", weight: 1},
+	}
+	// Tasks of the first group are dominated by slots and tasks of the second group by CPUs. Both
+	// groups end up with half of their dominant resource once the CPUs run out.
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 1, cpusNeeded: 1, group: groups[0]},
+		{id: "task2", slotsNeeded: 1, cpusNeeded: 1, group: groups[0]},
+		{id: "task3", slotsNeeded: 1, cpusNeeded: 1, group: groups[0]},
+		{id: "task4", slotsNeeded: 1, cpusNeeded: 1, group: groups[0]},
+		{id: "task5", slotsNeeded: 1, cpusNeeded: 1, group: groups[0]},
+		{id: "task6", slotsNeeded: 0, cpusNeeded: 2, group: groups[1]},
+		{id: "task7", slotsNeeded: 0, cpusNeeded: 2, group: groups[1]},
+		{id: "task8", slotsNeeded: 0, cpusNeeded: 2, group: groups[1]},
+	}
+
+	expectedToAllocate := []*mockTask{tasks[0], tasks[1], tasks[2], tasks[3], tasks[5], tasks[6]}
+	expectedToRelease := []*mockTask{}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	toAllocate, toRelease := drfSchedule(taskList, groupMap, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+}
+
+func TestDRFSchedulerRunningTasks(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent", slots: 4, cpus: 16, memory: 64, maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", maxSlots: newMaxSlot(3), weight: 1},
+		{id: "group2", weight: 1},
+	}
+	tasks := []*mockTask{
+		{
+			id: "task1", slotsNeeded: 2, memoryNeeded: 8, group: groups[0],
+			allocatedAgent: agents[0], containerStarted: true,
+		},
+		{id: "task2", slotsNeeded: 2, memoryNeeded: 8, group: groups[0]},
+		{id: "task3", slotsNeeded: 1, memoryNeeded: 40, group: groups[1]},
+		{id: "task4", slotsNeeded: 1, memoryNeeded: 40, group: groups[1]},
+	}
+
+	// The running task of the first group leaves the first task of the second group the smallest
+	// dominant share, the second group then uses more memory than the first one uses slots, and the
+	// max slots of the first group and the memory left keep the remaining tasks pending.
+	expectedToAllocate := []*mockTask{tasks[2]}
+	expectedToRelease := []*mockTask{}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	toAllocate, toRelease := drfSchedule(taskList, groupMap, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+}
@@ -2,6 +2,7 @@ package resourcemanagers
 
 import (
 	"fmt"
+	"math"
 	"sort"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -31,6 +32,9 @@ type groupState struct {
 	presubscribedSlots int
 	// offered is the number of slots that were offered to the group for scheduling.
 	offered int
+	// fractionalSlots is the sum of the slot fractions of the running tasks of the group needing a
+	// fraction of a slot, which take up wholeSlots(fractionalSlots) of its active slots.
+	fractionalSlots float64
 
 	// reqs contains the contents of both pendingReqs and allocatedReqs.
 	reqs          []*sproto.AllocateRequest
@@ -59,11 +63,13 @@ func fairshareSchedule(
 ) ([]*sproto.AllocateRequest, []*actor.Ref) {
 	allToAllocate := make([]*sproto.AllocateRequest, 0)
 	allToRelease := make([]*actor.Ref, 0)
+	taskList.ClearPendingReasons()
 
 	for it := taskList.iterator(); it.next(); {
 		req := it.value()
 		allocations := taskList.GetAllocations(req.TaskActor)
-		if req.SlotsNeeded == 0 && allocations == nil {
+		// Tasks needing no slots are not given slot offers and start as soon as they fit.
+		if isZeroSlot(req) && allocations == nil {
 			if fits := findFits(req, agents, fittingMethod); len(fits) == 0 {
 				continue
 			}
@@ -77,31 +83,64 @@ func fairshareSchedule(
 	// 3) Allocate slot offers to each group.
 	// 4) Get scheduler decisions for each group based on its slot demand.
 
-	// TODO (sidneyw): temporarily we partition the cluster by agent label as a
+	// TODO (sidneyw): temporarily we partition the cluster by agent constraints as a
 	// work around for DET-1997. Tasks are given slot offers but may fail to
 	// fit on any agent due to hard contraints. This may cause the scheduler to
 	// not schedule any tasks and therefore not make progress. Slot offers and
 	// reclaiming slots should be rethought in scheduler v2.
-	capacity := capacityByAgentLabel(agents)
+	capacity, offerable := capacityByAgentConstraints(taskList, agents)
+	explainer := newPendingExplainer(agents, fittingMethod)
 	states := calculateGroupStates(taskList, groups, capacity)
 
-	for label, groupStates := range states {
-		allocateSlotOffers(groupStates, capacity[label])
-		toAllocate, toRelease := assignTasks(agents, groupStates, fittingMethod)
+	for key, groupStates := range states {
+		allocateSlotOffers(groupStates, offerable[key])
+		toAllocate, toRelease := assignTasks(taskList, agents, groupStates, fittingMethod, explainer)
 		allToAllocate = append(allToAllocate, toAllocate...)
 		allToRelease = append(allToRelease, toRelease...)
 	}
+	recordPendingReasons(taskList, explainer, allToAllocate)
 	return allToAllocate, allToRelease
 }
 
-func capacityByAgentLabel(agents map[*actor.Ref]*agentState) map[string]int {
-	agentCap := map[string]int{}
+// capacityByAgentConstraints returns the slots of the agents satisfying the hard agent constraints
+// of the tasks and the slots to offer to the tasks of each constraint, both keyed by
+// agentConstraintsKey. Agents may satisfy several constraints; since the slots of every agent must
+// be offered only once, they are offered to the tasks of the constraint with the fewest slots to
+// offer so far.
+func capacityByAgentConstraints(
+	taskList *taskList, agents map[*actor.Ref]*agentState,
+) (capacity map[string]int, offerable map[string]int) {
+	capacity = map[string]int{}
+	offerable = map[string]int{}
+
+	keysByAgent := make(map[*actor.Ref][]string)
+	for key, agentsSatisfying := range splitAgentsByConstraints(taskList, agents) {
+		for ref, agent := range agentsSatisfying {
+			capacity[key] += agent.numSlots()
+			keysByAgent[ref] = append(keysByAgent[ref], key)
+		}
+	}
 
-	for _, agent := range agents {
-		agentCap[agent.label] += agent.numSlots()
+	refs := make([]*actor.Ref, 0, len(keysByAgent))
+	for ref := range keysByAgent {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Address().String() < refs[j].Address().String()
+	})
+	for _, ref := range refs {
+		keys := keysByAgent[ref]
+		sort.Strings(keys)
+		chosen := keys[0]
+		for _, key := range keys[1:] {
+			if offerable[key] < offerable[chosen] {
+				chosen = key
+			}
+		}
+		offerable[chosen] += agents[ref].numSlots()
 	}
 
-	return agentCap
+	return capacity, offerable
 }
 
 func calculateGroupStates(
@@ -113,7 +152,8 @@ func calculateGroupStates(
 	groupMapping := make(map[*group]*groupState)
 	for it := taskList.iterator(); it.next(); {
 		req := it.value()
-		if req.SlotsNeeded == 0 || req.SlotsNeeded > capacities[req.Label] {
+		key := agentConstraintsKey(req)
+		if isZeroSlot(req) || req.SlotsNeeded > capacities[key] {
 			continue
 		}
 		group := groups[req.Group]
@@ -123,7 +163,7 @@ func calculateGroupStates(
 				group:    group,
 				disabled: false,
 			}
-			states[req.Label] = append(states[req.Label], state)
+			states[key] = append(states[key], state)
 			groupMapping[group] = state
 		}
 		state.reqs = append(state.reqs, req)
@@ -131,20 +171,29 @@ func calculateGroupStates(
 	for _, group := range states {
 		for _, state := range group {
 			check.Panic(check.True(state.group != nil, "the group of a task must not be nil"))
+			// Tasks needing a fraction of a slot are counted by their fraction, rounded up to whole
+			// slots over the group.
+			var fractionalDemand, presubscribedFractions float64
 			for _, req := range state.reqs {
 				allocated := taskList.GetAllocations(req.TaskActor)
 				state.slotDemand += req.SlotsNeeded
+				fractionalDemand += req.SlotFraction
 				switch {
 				case allocated == nil || len(allocated.Reservations) == 0:
 					state.pendingReqs = append(state.pendingReqs, req)
 				case len(allocated.Reservations) > 0:
 					if !req.Preemptible {
 						state.presubscribedSlots += req.SlotsNeeded
+						presubscribedFractions += req.SlotFraction
 					}
 					state.allocatedReqs = append(state.allocatedReqs, req)
 					state.activeSlots += req.SlotsNeeded
+					state.fractionalSlots += req.SlotFraction
 				}
 			}
+			state.slotDemand += wholeSlots(fractionalDemand)
+			state.presubscribedSlots += wholeSlots(presubscribedFractions)
+			state.activeSlots += wholeSlots(state.fractionalSlots)
 			if state.maxSlots != nil {
 				state.slotDemand = min(state.slotDemand, *state.maxSlots)
 			}
@@ -280,6 +329,30 @@ func allocateSlotOffers(states []*groupState, capacity int) {
 	}
 }
 
+// wholeSlots returns the number of whole slots that tasks needing the sum of fractions of slots
+// take up, ignoring floating point error in the sum.
+func wholeSlots(fractions float64) int {
+	return int(math.Ceil(fractions - 1e-9))
+}
+
+// slotsTaken returns the number of whole slots of the offer of a group that starting the task
+// takes up, given the sum of the slot fractions of the running tasks of the group.
+func slotsTaken(req *sproto.AllocateRequest, fractionalSlots float64) int {
+	if req.SlotFraction == 0 {
+		return req.SlotsNeeded
+	}
+	return wholeSlots(fractionalSlots+req.SlotFraction) - wholeSlots(fractionalSlots)
+}
+
+// slotsFreed returns the number of whole slots of the offer of a group that releasing the task
+// frees, given the sum of the slot fractions of the running tasks of the group.
+func slotsFreed(req *sproto.AllocateRequest, fractionalSlots float64) int {
+	if req.SlotFraction == 0 {
+		return req.SlotsNeeded
+	}
+	return wholeSlots(fractionalSlots) - wholeSlots(fractionalSlots-req.SlotFraction)
+}
+
 func calculateSmallestAllocatableTask(state *groupState) (smallest *sproto.AllocateRequest) {
 	for _, req := range state.pendingReqs {
 		if smallest == nil || req.SlotsNeeded < smallest.SlotsNeeded {
@@ -290,12 +363,29 @@ func calculateSmallestAllocatableTask(state *groupState) (smallest *sproto.Alloc
 }
 
 func assignTasks(
-	agents map[*actor.Ref]*agentState, states []*groupState, fittingMethod SoftConstraint,
+	taskList *taskList,
+	agents map[*actor.Ref]*agentState,
+	states []*groupState,
+	fittingMethod SoftConstraint,
+	explainer *pendingExplainer,
 ) ([]*sproto.AllocateRequest, []*actor.Ref) {
 	toAllocate := make([]*sproto.AllocateRequest, 0)
 	toRelease := make([]*actor.Ref, 0)
+	setPendingReason := func(
+		state *groupState, req *sproto.AllocateRequest, usedSlots, remainingOffer int,
+	) {
+		// Tasks that cannot fit the agents at all are explained as such, regardless of their offer.
+		reason := explainer.explain(req)
+		switch reason.Code {
+		case sproto.PendingNoMatchingAgents, sproto.PendingInsufficientAgentCapacity:
+		default:
+			reason = fairShareReason(state, req, usedSlots, remainingOffer)
+		}
+		taskList.SetPendingReason(req.TaskActor, reason)
+	}
 
 	for _, state := range states {
+		usedSlots := state.activeSlots
 		if state.activeSlots > state.offered {
 			// Terminate tasks while the count of slots consumed by active tasks is greater than
 			// the count of offered slots.
@@ -303,7 +393,8 @@ func assignTasks(
 			for _, req := range state.allocatedReqs {
 				if req.Preemptible {
 					toRelease = append(toRelease, req.TaskActor)
-					state.activeSlots -= req.SlotsNeeded
+					state.activeSlots -= slotsFreed(req, state.fractionalSlots)
+					state.fractionalSlots -= req.SlotFraction
 					if state.activeSlots <= state.offered {
 						break
 					}
@@ -314,15 +405,45 @@ func assignTasks(
 			// freed immediately, we cannot terminate and start tasks in the same scheduling call.
 			state.offered -= state.activeSlots
 			for _, req := range state.pendingReqs {
-				if req.SlotsNeeded <= state.offered {
+				if taken := slotsTaken(req, state.fractionalSlots); taken <= state.offered {
 					if fits := findFits(req, agents, fittingMethod); len(fits) == 0 {
 						continue
 					}
 					toAllocate = append(toAllocate, req)
-					state.offered -= req.SlotsNeeded
+					state.offered -= taken
+					state.fractionalSlots += req.SlotFraction
+					usedSlots += taken
+					continue
 				}
+				setPendingReason(state, req, usedSlots, state.offered)
 			}
+			continue
+		}
+		for _, req := range state.pendingReqs {
+			setPendingReason(state, req, usedSlots, 0)
 		}
 	}
 	return toAllocate, toRelease
 }
+
+// fairShareReason explains why a pending task of a group was not offered enough slots to start.
+func fairShareReason(
+	state *groupState, req *sproto.AllocateRequest, usedSlots, remainingOffer int,
+) sproto.PendingReason {
+	needs := fmt.Sprintf("%d slots", req.SlotsNeeded)
+	if req.SlotFraction > 0 {
+		needs = fmt.Sprintf("%g of a slot", req.SlotFraction)
+	}
+	if state.maxSlots != nil && usedSlots+slotsTaken(req, state.fractionalSlots) > *state.maxSlots {
+		return sproto.PendingReason{
+			Code: sproto.PendingGroupMaxSlots,
+			Message: fmt.Sprintf("needs %s but its group uses %d of its max_slots of %d",
+				needs, usedSlots, *state.maxSlots),
+		}
+	}
+	return sproto.PendingReason{
+		Code: sproto.PendingFairShare,
+		Message: fmt.Sprintf("needs %s but its group's fair share leaves %d slots for it",
+			needs, remainingOffer),
+	}
+}
@@ -3,7 +3,10 @@ package resourcemanagers
 import (
 	"testing"
 
+	"gotest.tools/assert"
+
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestFairShareMaxSlots(t *testing.T) {
@@ -263,6 +266,69 @@ func TestFairShareLabels(t *testing.T) {
 	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
 }
 
+func TestFairShareAgentSelectors(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, labels: map[string]string{"zone": "a"}, maxZeroSlotContainers: 100},
+		{id: "agent2", slots: 2, labels: map[string]string{"zone": "b"}, maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", maxSlots: newMaxSlot(4), weight: 1},
+	}
+	zoneB := model.AgentSelectorsConfig{
+		{Key: "zone", Operator: model.SelectorNotIn, Values: []string{"a"}},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 4, group: groups[0], agentSelectors: zoneB},
+		{id: "task2", slotsNeeded: 2, group: groups[0], agentSelectors: zoneB},
+	}
+
+	expectedToAllocate := []*mockTask{tasks[1]}
+	expectedToRelease := []*mockTask{}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	toAllocate, toRelease := fairshareSchedule(taskList, groupMap, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+}
+
+func TestFairShareOverlappingAgentSelectors(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, labels: map[string]string{"zone": "a"}, maxZeroSlotContainers: 100},
+		{id: "agent2", slots: 4, labels: map[string]string{"zone": "a", "gpu": "a100"},
+			maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", maxSlots: newMaxSlot(8), weight: 1},
+		{id: "group2", maxSlots: newMaxSlot(8), weight: 1},
+	}
+	zoneA := model.AgentSelectorsConfig{
+		{Key: "zone", Operator: model.SelectorIn, Values: []string{"a"}},
+	}
+	a100 := model.AgentSelectorsConfig{
+		{Key: "gpu", Operator: model.SelectorIn, Values: []string{"a100"}},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 4, group: groups[0], agentSelectors: zoneA},
+		{id: "task2", slotsNeeded: 4, group: groups[0], agentSelectors: zoneA},
+		{id: "task3", slotsNeeded: 4, group: groups[1], agentSelectors: a100},
+	}
+
+	// Both constraints select agent2, whose slots are offered to only one of them.
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	capacity, offerable := capacityByAgentConstraints(taskList, agentMap)
+	total := 0
+	for _, slots := range offerable {
+		total += slots
+	}
+	assert.Equal(t, total, 8)
+	assert.Equal(t, capacity[agentConstraintsKey(taskList.taskByID["task1"])], 8)
+
+	toAllocate, _ := fairshareSchedule(taskList, groupMap, agentMap, BestFit)
+	assert.Equal(t, len(toAllocate), 2)
+}
+
 func TestFairSharePreemptible(t *testing.T) {
 	agents := []*mockAgent{
 		{id: "agent", slots: 1, label: ""},
@@ -344,3 +410,67 @@ func TestFairShareHonorsNonPreemptibleNilGroup(t *testing.T) {
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
 }
+
+func TestFairShareSlotFractions(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent", slots: 2, maxZeroSlotContainers: 1},
+	}
+	groups := []*mockGroup{
+		{id: "group1"},
+		{id: "group2"},
+		{id: "group3"},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotFraction: 0.5, group: groups[0]},
+		{id: "task2", slotFraction: 0.5, group: groups[0]},
+		{id: "task3", slotFraction: 0.5, group: groups[0]},
+		{id: "task4", slotFraction: 0.5, group: groups[0]},
+		{id: "task5", slotsNeeded: 1, group: groups[1]},
+		{id: "task6", slotsNeeded: 1, group: groups[1]},
+		{id: "task7", slotsNeeded: 0, group: groups[2]},
+	}
+
+	// The fractional tasks of the first group share its fair share of one slot, and the task
+	// needing no slots starts regardless.
+	expectedToAllocate := []*mockTask{tasks[0], tasks[1], tasks[4], tasks[6]}
+	expectedToRelease := []*mockTask{}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	toAllocate, toRelease := fairshareSchedule(taskList, groupMap, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+}
+
+func TestFairShareReleaseSlotFractions(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent", slots: 2},
+	}
+	groups := []*mockGroup{
+		{id: "group1"},
+		{id: "group2"},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotFraction: 0.5, group: groups[0], allocatedAgent: agents[0]},
+		{
+			id: "task2", slotFraction: 0.5, group: groups[0], allocatedAgent: agents[0],
+			nonPreemptible: true,
+		},
+		{
+			id: "task3", slotFraction: 0.5, group: groups[0], allocatedAgent: agents[0],
+			nonPreemptible: true,
+		},
+		{id: "task4", slotsNeeded: 1, group: groups[1]},
+	}
+
+	// The running fractional tasks take up both slots, so the preemptible one is released to free
+	// a slot for the second group.
+	expectedToAllocate := []*mockTask{tasks[3]}
+	expectedToRelease := []*mockTask{tasks[0]}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	toAllocate, toRelease := fairshareSchedule(taskList, groupMap, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
+}
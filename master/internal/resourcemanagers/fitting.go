@@ -9,6 +9,8 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
 )
 
 // HardConstraint returns true if the task can be assigned to the agent and false otherwise.
@@ -28,6 +30,24 @@ type fittingState struct {
 	// as an deterministic pseudorandom function for load balance.
 	HashDistance uint64
 	Slots        int
+	SlotFraction float64
+	CPUs         float64
+	Memory       int64
+}
+
+// allocate allocates the slots, or the fraction of a slot, and the host resources of the fit to the
+// container with the given ID and returns the allocated devices.
+func (f *fittingState) allocate(id cproto.ID) []device.Device {
+	f.Agent.allocateHostResources(f.CPUs, f.Memory, id)
+	if f.SlotFraction > 0 {
+		return f.Agent.allocateSlotFraction(f.SlotFraction, id)
+	}
+	return f.Agent.allocateFreeDevices(f.Slots, id)
+}
+
+// isZeroSlot returns whether the task needs neither slots nor a fraction of a slot.
+func isZeroSlot(req *sproto.AllocateRequest) bool {
+	return req.SlotsNeeded == 0 && req.SlotFraction == 0
 }
 
 type candidateList []*fittingState
@@ -68,6 +88,7 @@ func findFits(
 	// TODO(DET-4035): Some of this code is duplicated in calculateDesiredNewAgentNum()
 	//    to prevent the provisioner from scaling up for jobs that can never be scheduled in
 	//    the current cluster configuration.
+	fittingMethod = withAgentAffinity(fittingMethod)
 	if fit := findSharedAgentFit(req, agents, fittingMethod); fit != nil {
 		return []*fittingState{fit}
 	}
@@ -103,7 +124,9 @@ func findDedicatedAgentFits(
 	// 2) Multi-agent tasks will receive all the slots on every agent they are scheduled on.
 	agentsByNumSlots := make(map[int][]*agentState)
 	for _, agent := range agentStates {
-		constraints := []HardConstraint{labelSatisfied, agentSlotUnusedSatisfied}
+		constraints := []HardConstraint{
+			labelSatisfied, agentSlotUnusedSatisfied, hostResourcesSatisfied,
+		}
 		if isViable(req, agent, constraints...) {
 			agentsByNumSlots[agent.numEmptySlots()] = append(agentsByNumSlots[agent.numEmptySlots()], agent)
 		}
@@ -160,6 +183,8 @@ func findDedicatedAgentFits(
 	fits := candidates[:numContainers]
 	for _, c := range fits {
 		c.Slots = slotsPerContainer
+		c.CPUs = req.CPUsNeeded
+		c.Memory = req.MemoryNeeded
 	}
 
 	return fits
@@ -170,7 +195,8 @@ func findSharedAgentFit(
 ) *fittingState {
 	var candidates candidateList
 	for _, agent := range agents {
-		if !isViable(req, agent, slotsSatisfied, maxZeroSlotContainersSatisfied, labelSatisfied) {
+		if !isViable(req, agent, slotsSatisfied, slotFractionSatisfied,
+			maxZeroSlotContainersSatisfied, labelSatisfied, hostResourcesSatisfied) {
 			continue
 		}
 
@@ -188,6 +214,9 @@ func findSharedAgentFit(
 	sort.Sort(candidates)
 
 	candidates[0].Slots = req.SlotsNeeded
+	candidates[0].SlotFraction = req.SlotFraction
+	candidates[0].CPUs = req.CPUsNeeded
+	candidates[0].Memory = req.MemoryNeeded
 	return candidates[0]
 }
 
@@ -12,17 +12,30 @@ func slotsSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
 	return req.SlotsNeeded <= agent.numEmptySlots()
 }
 
+// labelSatisfied checks both the legacy single agent label and the agent selectors of the task.
 func labelSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
-	return req.Label == agent.label
+	return req.Label == agent.label && req.AgentSelectors.Matches(agent.labels)
+}
+
+// slotFractionSatisfied checks that a single device of the agent has enough free shares for tasks
+// requesting a fraction of a slot.
+func slotFractionSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
+	return req.SlotFraction == 0 || agent.fractionShares(req.SlotFraction) != nil
 }
 
 func maxZeroSlotContainersSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
-	if req.SlotsNeeded == 0 {
+	if isZeroSlot(req) {
 		return agent.numEmptyZeroSlots() > 0
 	}
 	return true
 }
 
+// hostResourcesSatisfied checks that the agent has enough CPUs and memory left for each container
+// of the task.
+func hostResourcesSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
+	return agent.hostResourcesFit(req.CPUsNeeded, req.MemoryNeeded)
+}
+
 func agentSlotUnusedSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool {
 	return agent.numUsedSlots() == 0
 }
@@ -35,6 +48,8 @@ func agentSlotUnusedSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool
 // applications.
 func BestFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 	switch {
+	case req.SlotFraction != 0:
+		return 1.0 / (1.0 + agent.numEmptySlotFraction())
 	case agent.numUsedSlots() != 0 || req.SlotsNeeded != 0:
 		return 1.0 / (1.0 + float64(agent.numEmptySlots()))
 	case agent.numZeroSlots() == 0:
@@ -49,6 +64,8 @@ func BestFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 // method should be used when the cluster is dominated by single-slot applications.
 func WorstFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 	switch {
+	case req.SlotFraction != 0:
+		return agent.numEmptySlotFraction() / float64(agent.numSlots())
 	case agent.numUsedSlots() != 0 || req.SlotsNeeded != 0:
 		return float64(agent.numEmptySlots()) / float64(agent.numSlots())
 	case agent.numZeroSlots() == 0:
@@ -58,6 +75,30 @@ func WorstFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 	}
 }
 
+// AgentAffinityFit returns a float affinity score between 0 and 1 for how well the agent matches
+// the affinity and anti-affinity preferences of the task: agents matching every affinity and no
+// anti-affinity score 1, and agents matching no affinity and every anti-affinity score 0. Tasks
+// without preferences score 0.5 on every agent.
+func AgentAffinityFit(req *sproto.AllocateRequest, agent *agentState) float64 {
+	matched, total := req.AgentAffinity.Weights(agent.labels)
+	antiMatched, antiTotal := req.AgentAntiAffinity.Weights(agent.labels)
+	if total+antiTotal == 0 {
+		return 0.5
+	}
+	return float64(matched+antiTotal-antiMatched) / float64(total+antiTotal)
+}
+
+// withAgentAffinity weighs the fitting method and the agent affinity equally for tasks with agent
+// affinity preferences, and leaves the fitting method unchanged for other tasks.
+func withAgentAffinity(fittingMethod SoftConstraint) SoftConstraint {
+	return func(req *sproto.AllocateRequest, agent *agentState) float64 {
+		if len(req.AgentAffinity) == 0 && len(req.AgentAntiAffinity) == 0 {
+			return fittingMethod(req, agent)
+		}
+		return (fittingMethod(req, agent) + AgentAffinityFit(req, agent)) / 2
+	}
+}
+
 // MakeFitFunction returns the corresponding fitting function.
 func MakeFitFunction(fittingPolicy string) func(*sproto.AllocateRequest, *agentState) float64 {
 	switch fittingPolicy {
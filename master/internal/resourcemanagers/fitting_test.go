@@ -9,6 +9,8 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestIsViable(t *testing.T) {
@@ -23,6 +25,90 @@ func TestIsViable(t *testing.T) {
 		newFakeAgentState(t, system, "agent4", "", 1, 0, 100, 0), slotsSatisfied))
 }
 
+func TestHostResourcesSatisfied(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	agent := newFakeAgentState(t, system, "agent1", "", 4, 0, 100, 0)
+	req := &sproto.AllocateRequest{SlotsNeeded: 1, CPUsNeeded: 6, MemoryNeeded: 32}
+
+	// Agents that do not report their CPUs and memory fit any request for them.
+	assert.Assert(t, hostResourcesSatisfied(req, agent))
+
+	agent.cpus, agent.memory = 8, 64
+	assert.Assert(t, hostResourcesSatisfied(req, agent))
+	agent.allocateHostResources(req.CPUsNeeded, req.MemoryNeeded, "container1")
+	assert.Assert(t, !hostResourcesSatisfied(req, agent))
+	assert.Equal(t, len(findFits(req, map[*actor.Ref]*agentState{agent.handler: agent}, BestFit)), 0)
+
+	agent.deallocateContainer("container1")
+	assert.Assert(t, hostResourcesSatisfied(req, agent))
+}
+
+func TestSlotFractionFits(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	agent := newFakeAgentState(t, system, "agent1", "", 0, 0, 100, 0)
+	for _, d := range device.Share([]device.Device{{ID: 0}, {ID: 1}}, 4) {
+		agent.devices[d] = nil
+	}
+	agents := map[*actor.Ref]*agentState{agent.handler: agent}
+	assert.Equal(t, agent.numSlots(), 2)
+	assert.Equal(t, agent.numEmptySlotFraction(), 2.0)
+
+	half := &sproto.AllocateRequest{SlotFraction: 0.5}
+	fits := findFits(half, agents, BestFit)
+	assert.Equal(t, len(fits), 1)
+	halfDevices := fits[0].allocate("container1")
+	assert.Equal(t, len(halfDevices), 2)
+	assert.Equal(t, halfDevices[0].PhysicalID(), halfDevices[1].PhysicalID())
+	assert.Equal(t, agent.numUsedSlots(), 1)
+	assert.Equal(t, agent.numEmptySlots(), 1)
+
+	// Fractions are packed onto the partially used device to leave whole devices free.
+	quarter := &sproto.AllocateRequest{SlotFraction: 0.25}
+	fits = findFits(quarter, agents, BestFit)
+	assert.Equal(t, len(fits), 1)
+	quarterDevices := fits[0].allocate("container2")
+	assert.Equal(t, len(quarterDevices), 1)
+	assert.Equal(t, quarterDevices[0].PhysicalID(), halfDevices[0].PhysicalID())
+
+	// Whole slots take every share of a free device.
+	whole := &sproto.AllocateRequest{SlotsNeeded: 1}
+	fits = findFits(whole, agents, BestFit)
+	assert.Equal(t, len(fits), 1)
+	wholeDevices := fits[0].allocate("container3")
+	assert.Equal(t, len(wholeDevices), 4)
+	assert.Assert(t, wholeDevices[0].PhysicalID() != halfDevices[0].PhysicalID())
+	assert.Equal(t, agent.numEmptySlotFraction(), 0.25)
+
+	assert.Equal(t, len(findFits(half, agents, BestFit)), 0)
+	agent.deallocateContainer("container1")
+	assert.Equal(t, len(findFits(half, agents, BestFit)), 1)
+}
+
+func TestAgentAffinityFit(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	agent := newFakeAgentState(t, system, "agent1", "", 4, 0, 100, 0)
+	agent.labels = map[string]string{"gpu": "a100", "zone": "us-east-1a"}
+
+	req := &sproto.AllocateRequest{}
+	assert.Equal(t, AgentAffinityFit(req, agent), 0.5)
+	assert.Equal(t, withAgentAffinity(BestFit)(req, agent), BestFit(req, agent))
+
+	req.AgentAffinity = model.AgentAffinitiesConfig{
+		{Weight: 3, AgentSelector: model.AgentSelector{Key: "gpu", Operator: model.SelectorExists}},
+		{Weight: 1, AgentSelector: model.AgentSelector{Key: "nvlink", Operator: model.SelectorExists}},
+	}
+	assert.Equal(t, AgentAffinityFit(req, agent), 0.75)
+
+	req.AgentAntiAffinity = model.AgentAffinitiesConfig{{
+		Weight: 4,
+		AgentSelector: model.AgentSelector{
+			Key: "zone", Operator: model.SelectorIn, Values: []string{"us-east-1a"},
+		},
+	}}
+	assert.Equal(t, AgentAffinityFit(req, agent), 0.375)
+	assert.Equal(t, withAgentAffinity(BestFit)(req, agent), (BestFit(req, agent)+0.375)/2)
+}
+
 func TestFindFits(t *testing.T) {
 	type testCase struct {
 		Name          string
@@ -104,6 +190,46 @@ func TestFindFits(t *testing.T) {
 			FittingMethod:    BestFit,
 			ExpectedAgentFit: 1,
 		},
+		{
+			Name: "1-slot multiple fits, agent selector hard constraint",
+			Task: sproto.AllocateRequest{
+				AllocationID: "task1", SlotsNeeded: 1,
+				AgentSelectors: model.AgentSelectorsConfig{
+					{Key: "gpu", Operator: model.SelectorIn, Values: []string{"a100", "v100"}},
+					{Key: "spot", Operator: model.SelectorDoesNotExist},
+				},
+			},
+			Agents: []*mockAgent{
+				{id: "agent1", slots: 1, labels: map[string]string{"gpu": "k80"}},
+				{id: "agent2", slots: 1, labels: map[string]string{"gpu": "a100", "spot": "true"}},
+				{id: "agent3", slots: 4, labels: map[string]string{"gpu": "v100"}},
+			},
+			FittingMethod:    BestFit,
+			ExpectedAgentFit: 2,
+		},
+		{
+			Name: "1-slot multiple fits, agent affinity",
+			Task: sproto.AllocateRequest{
+				AllocationID: "task1", SlotsNeeded: 1,
+				AgentAffinity: model.AgentAffinitiesConfig{{
+					Weight:        10,
+					AgentSelector: model.AgentSelector{Key: "nvlink", Operator: model.SelectorExists},
+				}},
+				AgentAntiAffinity: model.AgentAffinitiesConfig{{
+					Weight: 1,
+					AgentSelector: model.AgentSelector{
+						Key: "zone", Operator: model.SelectorIn, Values: []string{"us-east-1a"},
+					},
+				}},
+			},
+			Agents: []*mockAgent{
+				{id: "agent1", slots: 1, labels: map[string]string{"zone": "us-east-1b"}},
+				{id: "agent2", slots: 4, labels: map[string]string{"nvlink": "true", "zone": "us-east-1a"}},
+				{id: "agent3", slots: 4, labels: map[string]string{"nvlink": "true", "zone": "us-east-1b"}},
+			},
+			FittingMethod:    BestFit,
+			ExpectedAgentFit: 2,
+		},
 		{
 			Name: "0-slot single fit",
 			Task: sproto.AllocateRequest{AllocationID: "task1", SlotsNeeded: 0},
@@ -331,7 +457,7 @@ func TestFindFits(t *testing.T) {
 			system := actor.NewSystem(t.Name())
 			agents := []*agentState{}
 			for _, agent := range tc.Agents {
-				agents = append(agents, newFakeAgentState(
+				state := newFakeAgentState(
 					t,
 					system,
 					agent.id,
@@ -340,7 +466,9 @@ func TestFindFits(t *testing.T) {
 					agent.slotsUsed,
 					agent.maxZeroSlotContainers,
 					agent.zeroSlotContainers,
-				))
+				)
+				state.labels = agent.labels
+				agents = append(agents, state)
 			}
 			agentsByHandler, agentsByIndex := byHandler(agents...)
 			fits := findFits(&tc.Task, agentsByHandler, tc.FittingMethod)
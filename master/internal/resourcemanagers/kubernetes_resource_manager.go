@@ -1,8 +1,11 @@
 package resourcemanagers
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 
 	"github.com/determined-ai/determined/master/internal/kubernetes"
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -17,7 +20,7 @@ import (
 )
 
 const kubernetesScheduler = "kubernetes"
-const kubernetesDummyResourcePool = "kubernetes"
+const kubernetesDefaultResourcePool = "kubernetes"
 
 // kubernetesResourceProvider manages the lifecycle of k8s resources.
 type kubernetesResourceManager struct {
@@ -26,6 +29,7 @@ type kubernetesResourceManager struct {
 	reqList           *taskList
 	groups            map[*actor.Ref]*group
 	slotsUsedPerGroup map[*group]int
+	slotsUsedPerPool  map[string]int
 
 	// Represent all pods as a single agent.
 	agent *agentState
@@ -49,6 +53,7 @@ func newKubernetesResourceManager(
 		reqList:           newTaskList(),
 		groups:            make(map[*actor.Ref]*group),
 		slotsUsedPerGroup: make(map[*group]int),
+		slotsUsedPerPool:  make(map[string]int),
 
 		echoRef:         echoRef,
 		masterTLSConfig: masterTLSConfig,
@@ -80,6 +85,7 @@ func (k *kubernetesResourceManager) Receive(ctx *actor.Context) error {
 			k.config.DefaultScheduler,
 			k.config.SlotType,
 			kubernetes.PodSlotResourceRequests{CPU: k.config.SlotResourceRequests.CPU},
+			k.config.ResourcePools,
 		)
 		k.agent = &agentState{
 			handler:            podsActor,
@@ -116,27 +122,40 @@ func (k *kubernetesResourceManager) Receive(ctx *actor.Context) error {
 		ctx.Respond(getTaskSummaries(k.reqList, k.groups, kubernetesScheduler))
 
 	case *apiv1.GetResourcePoolsRequest:
-		resourcePoolSummary := k.summarizeDummyResourcePool(ctx)
-		resp := &apiv1.GetResourcePoolsResponse{
-			ResourcePools: []*resourcepoolv1.ResourcePool{resourcePoolSummary},
-		}
-		ctx.Respond(resp)
+		reschedule = false
+		ctx.Respond(&apiv1.GetResourcePoolsResponse{ResourcePools: k.summarizeResourcePools(ctx)})
 
 	case sproto.GetDefaultComputeResourcePoolRequest:
-		ctx.Respond(sproto.GetDefaultComputeResourcePoolResponse{PoolName: ""})
+		reschedule = false
+		ctx.Respond(sproto.GetDefaultComputeResourcePoolResponse{
+			PoolName: k.config.DefaultComputeResourcePool,
+		})
 
 	case sproto.GetDefaultAuxResourcePoolRequest:
-		ctx.Respond(sproto.GetDefaultAuxResourcePoolResponse{PoolName: ""})
+		reschedule = false
+		ctx.Respond(sproto.GetDefaultAuxResourcePoolResponse{
+			PoolName: k.config.DefaultAuxResourcePool,
+		})
+
+	case sproto.HasResourcePoolRequest:
+		reschedule = false
+		_, exists := k.config.resourcePool(msg.PoolName)
+		ctx.Respond(sproto.HasResourcePoolResponse{Exists: exists})
 
 	case sproto.ValidateCommandResourcesRequest:
+		reschedule = false
 		fulfillable := k.config.MaxSlotsPerPod >= msg.Slots
+		if pool, ok := k.config.resourcePool(msg.ResourcePool); ok && pool.MaxSlots > 0 {
+			fulfillable = fulfillable && pool.MaxSlots >= msg.Slots
+		}
 		ctx.Respond(sproto.ValidateCommandResourcesResponse{Fulfillable: fulfillable})
 
 	case schedulerTick:
 		if k.reschedule {
-			k.schedulePendingTasks(ctx)
+			// Allocations held back by gang scheduling wait on capacity that can be freed
+			// outside of Determined, so keep retrying them on every tick.
+			k.reschedule = k.schedulePendingTasks(ctx)
 		}
-		k.reschedule = false
 		reschedule = false
 		actors.NotifyAfter(ctx, actionCoolDown, schedulerTick{})
 
@@ -149,38 +168,46 @@ func (k *kubernetesResourceManager) Receive(ctx *actor.Context) error {
 	return nil
 }
 
-func (k *kubernetesResourceManager) summarizeDummyResourcePool(
+func (k *kubernetesResourceManager) summarizeResourcePools(
 	ctx *actor.Context,
-) *resourcepoolv1.ResourcePool {
-	slotsUsed := 0
-	for _, slotsUsedByGroup := range k.slotsUsedPerGroup {
-		slotsUsed += slotsUsedByGroup
+) []*resourcepoolv1.ResourcePool {
+	capacities := map[string]kubernetes.ResourcePoolCapacity{}
+	resp := ctx.Ask(k.agent.handler, kubernetes.SummarizeResourcePools{})
+	if err := resp.Error(); err != nil {
+		ctx.Log().WithError(err).Error("failed to get the capacity of resource pools")
+	} else {
+		capacities = resp.Get().(map[string]kubernetes.ResourcePoolCapacity)
 	}
 
-	return &resourcepoolv1.ResourcePool{
-		Name:                         kubernetesDummyResourcePool,
-		Description:                  "Kubernetes-managed pool of resources",
-		Type:                         resourcepoolv1.ResourcePoolType_RESOURCE_POOL_TYPE_K8S,
-		NumAgents:                    1,
-		SlotType:                     k.config.SlotType.Proto(),
-		SlotsAvailable:               int32(k.agent.numSlots()),
-		SlotsUsed:                    int32(k.agent.numUsedSlots()),
-		AuxContainerCapacity:         int32(k.agent.maxZeroSlotContainers),
-		AuxContainersRunning:         int32(k.agent.numUsedZeroSlots()),
-		DefaultComputePool:           true,
-		DefaultAuxPool:               true,
-		Preemptible:                  false,
-		MinAgents:                    0,
-		MaxAgents:                    0,
-		SlotsPerAgent:                int32(k.config.MaxSlotsPerPod),
-		AuxContainerCapacityPerAgent: int32(k.agent.maxZeroSlotContainers),
-		SchedulerType:                resourcepoolv1.SchedulerType_SCHEDULER_TYPE_KUBERNETES,
-		SchedulerFittingPolicy:       resourcepoolv1.FittingPolicy_FITTING_POLICY_KUBERNETES,
-		Location:                     "kubernetes",
-		ImageId:                      "",
-		InstanceType:                 "kubernetes",
-		Details:                      &resourcepoolv1.ResourcePoolDetail{},
+	summaries := make([]*resourcepoolv1.ResourcePool, 0, len(k.config.ResourcePools))
+	for _, pool := range k.config.ResourcePools {
+		capacity := capacities[pool.PoolName]
+		summaries = append(summaries, &resourcepoolv1.ResourcePool{
+			Name:                         pool.PoolName,
+			Description:                  pool.Description,
+			Type:                         resourcepoolv1.ResourcePoolType_RESOURCE_POOL_TYPE_K8S,
+			NumAgents:                    int32(capacity.NumNodes),
+			SlotType:                     k.config.SlotType.Proto(),
+			SlotsAvailable:               int32(capacity.Slots),
+			SlotsUsed:                    int32(k.slotsUsedPerPool[pool.PoolName]),
+			AuxContainerCapacity:         int32(k.agent.maxZeroSlotContainers),
+			AuxContainersRunning:         int32(k.agent.numUsedZeroSlots()),
+			DefaultComputePool:           pool.PoolName == k.config.DefaultComputeResourcePool,
+			DefaultAuxPool:               pool.PoolName == k.config.DefaultAuxResourcePool,
+			Preemptible:                  false,
+			MinAgents:                    0,
+			MaxAgents:                    0,
+			SlotsPerAgent:                int32(k.config.MaxSlotsPerPod),
+			AuxContainerCapacityPerAgent: int32(k.agent.maxZeroSlotContainers),
+			SchedulerType:                resourcepoolv1.SchedulerType_SCHEDULER_TYPE_KUBERNETES,
+			SchedulerFittingPolicy:       resourcepoolv1.FittingPolicy_FITTING_POLICY_KUBERNETES,
+			Location:                     pool.Namespace,
+			ImageId:                      "",
+			InstanceType:                 "kubernetes",
+			Details:                      &resourcepoolv1.ResourcePoolDetail{},
+		})
 	}
+	return summaries
 }
 
 func (k *kubernetesResourceManager) receiveRequestMsg(ctx *actor.Context) error {
@@ -192,8 +219,15 @@ func (k *kubernetesResourceManager) receiveRequestMsg(ctx *actor.Context) error
 	case sproto.SetGroupMaxSlots:
 		k.getOrCreateGroup(ctx, msg.Handler).maxSlots = msg.MaxSlots
 
-	case sproto.SetGroupWeight, sproto.SetGroupPriority:
-		// SetGroupWeight and SetGroupPriority are not supported by the Kubernetes RP.
+	case sproto.SetGroupWeight:
+		// SetGroupWeight is not supported by the Kubernetes RP.
+
+	case sproto.SetGroupPriority:
+		// The priority is mapped onto a PriorityClass when pods are created, so it only
+		// applies to pods created after it is set.
+		if msg.Priority != nil {
+			k.getOrCreateGroup(ctx, msg.Handler).priority = msg.Priority
+		}
 
 	case sproto.SetTaskName:
 		k.receiveSetTaskName(ctx, msg)
@@ -211,6 +245,28 @@ func (k *kubernetesResourceManager) receiveRequestMsg(ctx *actor.Context) error
 }
 
 func (k *kubernetesResourceManager) addTask(ctx *actor.Context, msg sproto.AllocateRequest) {
+	// Kubernetes cannot share devices among pods, so fractions of slots are rounded up.
+	if msg.SlotFraction > 0 {
+		msg.SlotsNeeded, msg.SlotFraction = 1, 0
+	}
+	// Experiments created before Kubernetes resource pools existed have no pool set.
+	if len(msg.ResourcePool) == 0 {
+		if msg.SlotsNeeded == 0 {
+			msg.ResourcePool = k.config.DefaultAuxResourcePool
+		} else {
+			msg.ResourcePool = k.config.DefaultComputeResourcePool
+		}
+	}
+	if _, ok := k.config.resourcePool(msg.ResourcePool); !ok {
+		err := errors.Errorf("cannot find resource pool %s for task %s",
+			msg.ResourcePool, msg.TaskActor.Address())
+		ctx.Log().WithError(err).Error("")
+		if ctx.ExpectingResponse() {
+			ctx.Respond(err)
+		}
+		return
+	}
+
 	actors.NotifyOnStop(ctx, msg.TaskActor, sproto.ResourcesReleased{TaskActor: msg.TaskActor})
 
 	if len(msg.AllocationID) == 0 {
@@ -240,44 +296,29 @@ func (k *kubernetesResourceManager) receiveSetTaskName(ctx *actor.Context, msg s
 func (k *kubernetesResourceManager) assignResources(
 	ctx *actor.Context, req *sproto.AllocateRequest,
 ) {
-	numPods := 1
-	slotsPerPod := req.SlotsNeeded
-	if req.SlotsNeeded > 1 {
-		if k.config.MaxSlotsPerPod == 0 {
-			ctx.Log().WithField("allocation-id", req.AllocationID).Error(
-				"set max_slots_per_pod > 0 to schedule tasks with slots")
-			return
-		}
-
-		if req.SlotsNeeded <= k.config.MaxSlotsPerPod {
-			numPods = 1
-			slotsPerPod = req.SlotsNeeded
-		} else {
-			if req.SlotsNeeded%k.config.MaxSlotsPerPod != 0 {
-				ctx.Log().WithField("allocation-id", req.AllocationID).Errorf(
-					"task number of slots (%d) is not schedulable on the configured "+
-						"max_slots_per_pod (%d)", req.SlotsNeeded, k.config.MaxSlotsPerPod)
-				return
-			}
-
-			numPods = req.SlotsNeeded / k.config.MaxSlotsPerPod
-			slotsPerPod = k.config.MaxSlotsPerPod
-		}
+	numPods, slotsPerPod, err := k.podLayout(req)
+	if err != nil {
+		ctx.Log().WithField("allocation-id", req.AllocationID).Error(err)
+		return
 	}
 
 	k.slotsUsedPerGroup[k.groups[req.Group]] += req.SlotsNeeded
+	k.slotsUsedPerPool[req.ResourcePool] += req.SlotsNeeded
 
 	allocations := make([]sproto.Reservation, 0, numPods)
 	for pod := 0; pod < numPods; pod++ {
 		container := newContainer(req, k.agent, slotsPerPod)
 		allocations = append(allocations, &k8sPodReservation{
 			req:       req,
+			priority:  k.groups[req.Group].priority,
 			agent:     k.agent,
 			container: container,
 		})
 	}
 
-	assigned := sproto.ResourcesAllocated{ID: req.AllocationID, Reservations: allocations}
+	assigned := sproto.ResourcesAllocated{
+		ID: req.AllocationID, Reservations: allocations, StartTime: time.Now(),
+	}
 	k.reqList.SetAllocations(req.TaskActor, &assigned)
 	req.TaskActor.System().Tell(req.TaskActor, assigned)
 
@@ -287,17 +328,36 @@ func (k *kubernetesResourceManager) assignResources(
 		Infof("resources assigned with %d pods", numPods)
 }
 
+// podLayout returns the number of pods an allocation is split into and the slots of each pod.
+func (k *kubernetesResourceManager) podLayout(req *sproto.AllocateRequest) (int, int, error) {
+	if req.SlotsNeeded <= 1 {
+		return 1, req.SlotsNeeded, nil
+	}
+	if k.config.MaxSlotsPerPod == 0 {
+		return 0, 0, errors.New("set max_slots_per_pod > 0 to schedule tasks with slots")
+	}
+	if req.SlotsNeeded <= k.config.MaxSlotsPerPod {
+		return 1, req.SlotsNeeded, nil
+	}
+	if req.SlotsNeeded%k.config.MaxSlotsPerPod != 0 {
+		return 0, 0, errors.Errorf(
+			"task number of slots (%d) is not schedulable on the configured "+
+				"max_slots_per_pod (%d)", req.SlotsNeeded, k.config.MaxSlotsPerPod)
+	}
+	return req.SlotsNeeded / k.config.MaxSlotsPerPod, k.config.MaxSlotsPerPod, nil
+}
+
 func (k *kubernetesResourceManager) resourcesReleased(ctx *actor.Context, handler *actor.Ref) {
 	ctx.Log().Infof("resources are released for %s", handler.Address())
-	k.reqList.RemoveTaskByHandler(handler)
-
 	if req, ok := k.reqList.GetTaskByHandler(handler); ok {
-		group := k.groups[handler]
-
-		if group != nil {
-			k.slotsUsedPerGroup[group] -= req.SlotsNeeded
+		if assigned := k.reqList.GetAllocations(handler); assigned != nil {
+			if group := k.groups[req.Group]; group != nil {
+				k.slotsUsedPerGroup[group] -= req.SlotsNeeded
+			}
+			k.slotsUsedPerPool[req.ResourcePool] -= req.SlotsNeeded
 		}
 	}
+	k.reqList.RemoveTaskByHandler(handler)
 }
 
 func (k *kubernetesResourceManager) getOrCreateGroup(
@@ -317,7 +377,11 @@ func (k *kubernetesResourceManager) getOrCreateGroup(
 	return g
 }
 
-func (k *kubernetesResourceManager) schedulePendingTasks(ctx *actor.Context) {
+// schedulePendingTasks assigns resources to the pending tasks that fit within the limits of their
+// group and resource pool. It returns whether some tasks were held back by gang scheduling.
+func (k *kubernetesResourceManager) schedulePendingTasks(ctx *actor.Context) bool {
+	heldBack := false
+	capacities := make(map[string]*kubernetes.PoolCapacity)
 	for it := k.reqList.iterator(); it.next(); {
 		req := it.value()
 		group := k.groups[req.Group]
@@ -328,14 +392,100 @@ func (k *kubernetesResourceManager) schedulePendingTasks(ctx *actor.Context) {
 					continue
 				}
 			}
+			if pool, ok := k.config.resourcePool(req.ResourcePool); ok && pool.MaxSlots > 0 {
+				if k.slotsUsedPerPool[req.ResourcePool]+req.SlotsNeeded > pool.MaxSlots {
+					continue
+				}
+			}
+			if k.config.GangScheduling && !k.reserveGang(ctx, capacities, req) {
+				heldBack = true
+				continue
+			}
 
 			k.assignResources(ctx, req)
 		}
 	}
+	return heldBack
+}
+
+// reserveGang checks that every pod of a multi-pod allocation fits on the free slots of its
+// resource pool before any of them is created, and reserves those slots if they do. Capacity
+// snapshots are taken at most once per pool per scheduling pass and shared by the allocations
+// considered during that pass.
+func (k *kubernetesResourceManager) reserveGang(
+	ctx *actor.Context,
+	capacities map[string]*kubernetes.PoolCapacity,
+	req *sproto.AllocateRequest,
+) bool {
+	numPods, slotsPerPod, err := k.podLayout(req)
+	if err != nil || numPods < 2 {
+		// Single pods are left to the Kubernetes scheduler and invalid layouts are reported
+		// when resources are assigned.
+		return true
+	}
+
+	capacity, ok := capacities[req.ResourcePool]
+	if !ok {
+		capacity = k.poolCapacity(ctx, req.ResourcePool)
+		capacities[req.ResourcePool] = capacity
+	}
+	if capacity == nil {
+		return false
+	}
+
+	if !capacity.Reserve(numPods, slotsPerPod) {
+		ctx.Log().WithField("allocation-id", req.AllocationID).Debugf(
+			"not enough free slots in resource pool %s to place %d pods with %d slots each",
+			req.ResourcePool, numPods, slotsPerPod)
+		return false
+	}
+	return true
+}
+
+// poolCapacity returns the free slots of the resource pool, excluding the slots of pods that were
+// already assigned but that are not bound to a node yet. It returns nil if the capacity of the
+// pool cannot be determined. The pods actor caches the capacity until pods or nodes change, so
+// retrying held back allocations on every tick does not list the pods of the cluster every time.
+func (k *kubernetesResourceManager) poolCapacity(
+	ctx *actor.Context, pool string,
+) *kubernetes.PoolCapacity {
+	var capacity *kubernetes.PoolCapacity
+	resp := ctx.Ask(k.agent.handler, kubernetes.GetPoolCapacity{ResourcePool: pool}).Get()
+	switch resp := resp.(type) {
+	case *kubernetes.PoolCapacity:
+		capacity = resp
+	case error:
+		ctx.Log().WithError(resp).Errorf("failed to get the capacity of resource pool %s", pool)
+		return nil
+	default:
+		ctx.Log().Errorf("unexpected response %T getting the capacity of resource pool %s",
+			resp, pool)
+		return nil
+	}
+
+	for it := k.reqList.iterator(); it.next(); {
+		req := it.value()
+		if req.ResourcePool != pool {
+			continue
+		}
+		assigned := k.reqList.GetAllocations(req.TaskActor)
+		if assigned == nil {
+			continue
+		}
+		for _, reservation := range assigned.Reservations {
+			podReservation, ok := reservation.(*k8sPodReservation)
+			if !ok || capacity.BoundContainers[podReservation.container.id] {
+				continue
+			}
+			capacity.Reserve(1, podReservation.container.slots)
+		}
+	}
+	return capacity
 }
 
 type k8sPodReservation struct {
 	req       *sproto.AllocateRequest
+	priority  *int
 	container *container
 	agent     *agentState
 }
@@ -364,6 +514,9 @@ func (p k8sPodReservation) Start(
 		Spec:      spec,
 		Slots:     p.container.slots,
 		Rank:      rri.AgentRank,
+
+		ResourcePool: p.req.ResourcePool,
+		Priority:     p.priority,
 	})
 }
 
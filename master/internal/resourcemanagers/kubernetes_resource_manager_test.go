@@ -0,0 +1,52 @@
+package resourcemanagers
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/kubernetes"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// testKubernetesResourceManager is a Kubernetes resource manager that does not connect to a
+// cluster when it starts.
+type testKubernetesResourceManager struct {
+	*kubernetesResourceManager
+}
+
+func (k testKubernetesResourceManager) Receive(ctx *actor.Context) error {
+	switch ctx.Message().(type) {
+	case actor.PreStart, actor.PostStop:
+		return nil
+	default:
+		return k.kubernetesResourceManager.Receive(ctx)
+	}
+}
+
+func TestKubernetesResourceManagerUnknownResourcePool(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	config := &KubernetesResourceManagerConfig{
+		ResourcePools: []kubernetes.ResourcePoolConfig{{PoolName: "pool"}},
+	}
+	rm := newKubernetesResourceManager(config, nil, model.TLSClientConfig{}, model.LoggingConfig{})
+	ref, created := system.ActorOf(actor.Addr(t.Name()), testKubernetesResourceManager{
+		rm.(*kubernetesResourceManager),
+	})
+	assert.Assert(t, created)
+	task, created := system.ActorOf(actor.Addr("task"), &mockTask{id: "task"})
+	assert.Assert(t, created)
+
+	err := system.Ask(ref, sproto.AllocateRequest{
+		TaskActor: task, ResourcePool: "missing", SlotsNeeded: 1,
+	}).Error()
+	assert.ErrorContains(t, err, "cannot find resource pool missing")
+
+	assert.NilError(t, system.Ask(ref, sproto.AllocateRequest{
+		TaskActor: task, ResourcePool: "pool", SlotsNeeded: 1,
+	}).Error())
+	summaries := system.Ask(ref, sproto.GetTaskSummaries{}).Get()
+	assert.Equal(t, len(summaries.(map[model.AllocationID]TaskSummary)), 1)
+}
@@ -0,0 +1,185 @@
+package resourcemanagers
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+// pendingExplainer explains why tasks do not fit the agents. It compares the tasks to idle copies
+// of the agents, which it makes once, on the first task it explains, and shares between them.
+type pendingExplainer struct {
+	agents        map[*actor.Ref]*agentState
+	fittingMethod SoftConstraint
+	idle          map[*actor.Ref]*agentState
+}
+
+func newPendingExplainer(
+	agents map[*actor.Ref]*agentState, fittingMethod SoftConstraint,
+) *pendingExplainer {
+	return &pendingExplainer{agents: agents, fittingMethod: fittingMethod}
+}
+
+// idleAgents returns copies of the enabled agents that are not draining, with nothing allocated.
+func (e *pendingExplainer) idleAgents() map[*actor.Ref]*agentState {
+	if e.idle != nil {
+		return e.idle
+	}
+	e.idle = make(map[*actor.Ref]*agentState)
+	for ref, agent := range e.agents {
+		if !agent.enabled || agent.draining {
+			continue
+		}
+		idle := agent.deepCopy()
+		for d := range idle.devices {
+			idle.devices[d] = nil
+		}
+		idle.zeroSlotContainers = make(map[cproto.ID]bool)
+		idle.hostResources = make(map[cproto.ID]hostResources)
+		e.idle[ref] = idle
+	}
+	return e.idle
+}
+
+// explainPending returns why a task does not fit the agents. Explaining several tasks should go
+// through a single pendingExplainer instead, which copies the agents once.
+func explainPending(
+	req *sproto.AllocateRequest, agents map[*actor.Ref]*agentState, fittingMethod SoftConstraint,
+) sproto.PendingReason {
+	return newPendingExplainer(agents, fittingMethod).explain(req)
+}
+
+// explain returns why a task does not fit the agents: no agent satisfies its hard constraints, it
+// does not fit the agents even when they are idle, or the agents do not have enough free slots for
+// it. Schedulers record more specific reasons for tasks that do fit.
+func (e *pendingExplainer) explain(req *sproto.AllocateRequest) sproto.PendingReason {
+	agents := e.agents
+	matching, largest, total := 0, 0, 0
+	largestCPUs, largestMemory := 0.0, int64(0)
+	idleAgents := make(map[*actor.Ref]*agentState)
+	for ref, agent := range agents {
+		if !labelSatisfied(req, agent) {
+			continue
+		}
+		matching++
+		idle, ok := e.idleAgents()[ref]
+		if !ok {
+			continue
+		}
+		idleAgents[ref] = idle
+		largest = max(largest, idle.numSlots())
+		total += idle.numSlots()
+		// Agents that do not report their CPUs or memory fit any request for them.
+		switch {
+		case idle.cpus == 0:
+			largestCPUs = math.Inf(1)
+		case idle.cpus > largestCPUs:
+			largestCPUs = idle.cpus
+		}
+		switch {
+		case idle.memory == 0:
+			largestMemory = math.MaxInt64
+		case idle.memory > largestMemory:
+			largestMemory = idle.memory
+		}
+	}
+
+	switch {
+	case len(agents) == 0:
+		return sproto.PendingReason{
+			Code:    sproto.PendingNoMatchingAgents,
+			Message: "no agents are connected",
+		}
+	case matching == 0:
+		return sproto.PendingReason{
+			Code:    sproto.PendingNoMatchingAgents,
+			Message: "no agent " + agentConstraintsDescription(req),
+		}
+	case len(idleAgents) == 0:
+		return sproto.PendingReason{
+			Code: sproto.PendingNoMatchingAgents,
+			Message: fmt.Sprintf("all %d agents %s are disabled or draining",
+				matching, agentConstraintsDescription(req)),
+		}
+	case len(findFits(req, idleAgents, e.fittingMethod)) == 0:
+		var message string
+		switch {
+		case req.CPUsNeeded > largestCPUs:
+			message = fmt.Sprintf("needs %g CPUs but the largest agent has %g",
+				req.CPUsNeeded, largestCPUs)
+		case req.MemoryNeeded > largestMemory:
+			message = fmt.Sprintf("needs %d bytes of memory but the largest agent has %d",
+				req.MemoryNeeded, largestMemory)
+		case req.SlotFraction > 0:
+			message = fmt.Sprintf("needs %g of a slot but no agent has any slots", req.SlotFraction)
+		case req.SlotsNeeded == 0:
+			message = "no agent accepts zero-slot tasks"
+		case req.FittingRequirements.SingleAgent:
+			message = fmt.Sprintf("needs %d slots on a single agent but the largest agent has %d",
+				req.SlotsNeeded, largest)
+		case largest == 0 || req.SlotsNeeded%largest != 0:
+			message = fmt.Sprintf("needs %d slots but the largest agent has %d",
+				req.SlotsNeeded, largest)
+		default:
+			message = fmt.Sprintf("needs %d slots but the agents have %d slots in total",
+				req.SlotsNeeded, total)
+		}
+		return sproto.PendingReason{Code: sproto.PendingInsufficientAgentCapacity, Message: message}
+	case req.CPUsNeeded > 0 || req.MemoryNeeded > 0:
+		return sproto.PendingReason{
+			Code: sproto.PendingInsufficientFreeSlots,
+			Message: fmt.Sprintf("waiting for %d slots, %g CPUs and %d bytes of memory to be free",
+				req.SlotsNeeded, req.CPUsNeeded, req.MemoryNeeded),
+		}
+	case req.SlotFraction > 0:
+		return sproto.PendingReason{
+			Code:    sproto.PendingInsufficientFreeSlots,
+			Message: fmt.Sprintf("waiting for %g of a slot to be free", req.SlotFraction),
+		}
+	case req.SlotsNeeded == 0:
+		return sproto.PendingReason{
+			Code:    sproto.PendingInsufficientFreeSlots,
+			Message: "waiting for an agent to have room for another zero-slot task",
+		}
+	default:
+		return sproto.PendingReason{
+			Code:    sproto.PendingInsufficientFreeSlots,
+			Message: fmt.Sprintf("waiting for %d slots to be free", req.SlotsNeeded),
+		}
+	}
+}
+
+// agentConstraintsDescription describes the agents that satisfy the hard constraints of a task.
+func agentConstraintsDescription(req *sproto.AllocateRequest) string {
+	description := fmt.Sprintf("with label %q", req.Label)
+	if req.Label == "" {
+		description = "without a label"
+	}
+	if len(req.AgentSelectors) > 0 {
+		description += fmt.Sprintf(" and matching the agent selectors %s", req.AgentSelectors)
+	}
+	return description
+}
+
+// recordPendingReasons records why the pending tasks that the scheduler did not allocate resources
+// to and did not record a reason for do not fit the agents.
+func recordPendingReasons(
+	taskList *taskList, explainer *pendingExplainer, toAllocate []*sproto.AllocateRequest,
+) {
+	allocating := make(map[*actor.Ref]bool, len(toAllocate))
+	for _, req := range toAllocate {
+		allocating[req.TaskActor] = true
+	}
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		if allocating[req.TaskActor] || taskList.GetAllocations(req.TaskActor) != nil {
+			continue
+		}
+		if taskList.GetPendingReason(req.TaskActor) == nil {
+			taskList.SetPendingReason(req.TaskActor, explainer.explain(req))
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package resourcemanagers
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+)
+
+func assertPendingReason(
+	t *testing.T,
+	system *actor.System,
+	taskList *taskList,
+	task *mockTask,
+	code sproto.PendingReasonCode,
+) {
+	reason := taskList.GetPendingReason(system.Get(actor.Addr(task.id)))
+	assert.Assert(t, reason != nil, "no pending reason for %s", task.id)
+	assert.Equal(t, reason.Code, code, "pending reason of %s: %s", task.id, reason.Message)
+}
+
+func TestExplainPending(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 0},
+		{id: "agent2", slots: 4, maxZeroSlotContainers: 0},
+		{id: "agent3", slots: 8, label: "large", maxZeroSlotContainers: 0},
+	}
+	tasks := []*mockTask{
+		{id: "running", slotsNeeded: 4, allocatedAgent: agents[0], containerStarted: true},
+		{id: "free-slots", slotsNeeded: 8},
+		{id: "odd-slots", slotsNeeded: 6},
+		{id: "too-many-slots", slotsNeeded: 12},
+		{id: "missing-label", slotsNeeded: 1, label: "missing"},
+		{id: "zero-slot", slotsNeeded: 0},
+	}
+
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, nil, agents)
+
+	for _, tc := range []struct {
+		task    *mockTask
+		code    sproto.PendingReasonCode
+		message string
+	}{
+		{tasks[1], sproto.PendingInsufficientFreeSlots, "waiting for 8 slots to be free"},
+		{tasks[2], sproto.PendingInsufficientAgentCapacity,
+			"needs 6 slots but the largest agent has 4"},
+		{tasks[3], sproto.PendingInsufficientAgentCapacity,
+			"needs 12 slots but the agents have 8 slots in total"},
+		{tasks[4], sproto.PendingNoMatchingAgents, `no agent with label "missing"`},
+		{tasks[5], sproto.PendingInsufficientAgentCapacity, "no agent accepts zero-slot tasks"},
+	} {
+		req, ok := taskList.GetTaskByHandler(system.Get(actor.Addr(tc.task.id)))
+		assert.Assert(t, ok)
+		reason := explainPending(req, agentMap, BestFit)
+		assert.Equal(t, reason.Code, tc.code, tc.task.id)
+		assert.Equal(t, reason.Message, tc.message, tc.task.id)
+	}
+
+	reason := explainPending(&sproto.AllocateRequest{SlotsNeeded: 1}, nil, BestFit)
+	assert.Equal(t, reason.Code, sproto.PendingNoMatchingAgents)
+	assert.Equal(t, reason.Message, "no agents are connected")
+}
+
+func TestPendingExplainerCopiesAgentsOnce(t *testing.T) {
+	agents := []*mockAgent{{id: "agent1", slots: 4}, {id: "agent2", slots: 4}}
+	tasks := []*mockTask{
+		{id: "running", slotsNeeded: 4, allocatedAgent: agents[0], containerStarted: true},
+		{id: "pending1", slotsNeeded: 8},
+		{id: "pending2", slotsNeeded: 12},
+	}
+
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, nil, agents)
+	explainer := newPendingExplainer(agentMap, BestFit)
+
+	req, ok := taskList.GetTaskByHandler(system.Get(actor.Addr("pending1")))
+	assert.Assert(t, ok)
+	assert.Equal(t, explainer.explain(req).Code, sproto.PendingInsufficientFreeSlots)
+	idle := explainer.idleAgents()
+
+	// The idle copies of the agents are made once and shared by the tasks explained later.
+	req, ok = taskList.GetTaskByHandler(system.Get(actor.Addr("pending2")))
+	assert.Assert(t, ok)
+	assert.Equal(t, explainer.explain(req).Code, sproto.PendingInsufficientAgentCapacity)
+	for ref, agent := range explainer.idleAgents() {
+		assert.Assert(t, agent == idle[ref])
+		assert.Equal(t, agent.numUsedSlots(), 0)
+	}
+	assert.Equal(t, agentMap[system.Get(actor.Addr("agent1"))].numUsedSlots(), 4)
+}
+
+func TestPrioritySchedulingPendingReasons(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, maxZeroSlotContainers: 100},
+	}
+	groups := []*mockGroup{
+		{id: "group1", priority: &lowerPriority},
+		{id: "group2", priority: &higherPriority},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 4, group: groups[1]},
+		{id: "task2", slotsNeeded: 2, group: groups[1]},
+		{id: "task3", slotsNeeded: 1, group: groups[0], nonPreemptible: true},
+		{id: "task4", slotsNeeded: 8, group: groups[0]},
+	}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
+	assertEqualToAllocate(t, toAllocate, []*mockTask{tasks[0]})
+
+	assert.Assert(t, taskList.GetPendingReason(system.Get(actor.Addr(tasks[0].id))) == nil)
+	assertPendingReason(t, system, taskList, tasks[1], sproto.PendingInsufficientFreeSlots)
+	assertPendingReason(t, system, taskList, tasks[2], sproto.PendingLowerPriority)
+	assertPendingReason(t, system, taskList, tasks[3], sproto.PendingInsufficientAgentCapacity)
+}
+
+func TestFairSharePendingReasons(t *testing.T) {
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4},
+	}
+	groups := []*mockGroup{
+		{id: "group1", maxSlots: newMaxSlot(2), weight: 1},
+		{id: "group2", weight: 1},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 2, group: groups[0], allocatedAgent: agents[0]},
+		{id: "task2", slotsNeeded: 1, group: groups[0]},
+		{id: "task3", slotsNeeded: 2, group: groups[1], allocatedAgent: agents[0]},
+		{id: "task4", slotsNeeded: 2, group: groups[1]},
+	}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+
+	toAllocate, _ := fairshareSchedule(taskList, groupMap, agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, []*mockTask{})
+
+	assertPendingReason(t, system, taskList, tasks[1], sproto.PendingGroupMaxSlots)
+	assertPendingReason(t, system, taskList, tasks[3], sproto.PendingFairShare)
+}
@@ -3,6 +3,7 @@ package resourcemanagers
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -14,17 +15,28 @@ import (
 
 type priorityScheduler struct {
 	preemptionEnabled bool
+	minRuntime        time.Duration
+	preemptionBudget  *PreemptionBudgetConfig
+
+	// preempted holds when the scheduler decided to preempt each task of each group, within the
+	// window of the preemption budget.
+	preempted map[*actor.Ref]map[*actor.Ref]time.Time
+	// recheckAt is when the first task that the last pass could not preempt, because of its minimum
+	// runtime or the preemption budget of its group, becomes preemptible.
+	recheckAt time.Time
 }
 
 // NewPriorityScheduler creates a new scheduler that schedules tasks via priority.
 func NewPriorityScheduler(config *SchedulerConfig) Scheduler {
 	return &priorityScheduler{
 		preemptionEnabled: config.Priority.Preemption,
+		minRuntime:        time.Duration(config.Priority.MinRuntime),
+		preemptionBudget:  config.Priority.PreemptionBudget,
 	}
 }
 
 func (p *priorityScheduler) Schedule(rp *ResourcePool) ([]*sproto.AllocateRequest, []*actor.Ref) {
-	return p.prioritySchedule(rp.taskList, rp.groups, rp.agents, rp.fittingMethod)
+	return p.prioritySchedule(rp.taskList, rp.groups, rp.agents, rp.fittingMethod, rp.now())
 }
 
 func (p *priorityScheduler) prioritySchedule(
@@ -32,24 +44,39 @@ func (p *priorityScheduler) prioritySchedule(
 	groups map[*actor.Ref]*group,
 	agents map[*actor.Ref]*agentState,
 	fittingMethod SoftConstraint,
+	now time.Time,
 ) ([]*sproto.AllocateRequest, []*actor.Ref) {
 	toAllocate := make([]*sproto.AllocateRequest, 0)
 	toRelease := make([]*actor.Ref, 0)
-
-	// Since labels are a hard scheduling constraint, process every label independently.
-	for label, agentsWithLabel := range splitAgentsByLabel(agents) {
+	taskList.ClearPendingReasons()
+	p.forgetPreemptions(groups, now)
+	p.recheckAt = time.Time{}
+
+	// Since labels are a hard scheduling constraint, process the tasks of every combination of
+	// agent label and agent selectors independently, on the agents satisfying them. The agents of
+	// different combinations may overlap, so all of them are scheduled on one local copy of the agent
+	// state, from which the slots of the tasks already fitted are taken. The combinations with the
+	// tasks of highest priority are scheduled first, and the most constrained ones break ties.
+	localAgentsState := deepCopyAgents(agents)
+	agentsByConstraints := splitAgentsByConstraints(taskList, localAgentsState)
+	released := make(map[*actor.Ref]bool)
+	explainer := newPendingExplainer(agents, fittingMethod)
+	for _, key := range orderedConstraintKeys(taskList, groups, agentsByConstraints) {
+		agentsSatisfying := agentsByConstraints[key]
 		// Schedule zero-slot and non-zero-slot tasks independently of each other, e.g., a lower priority
 		// zero-slot task can be started while a higher priority non-zero-slot task is pending, and
 		// vice versa.
 		for _, zeroSlots := range []bool{false, true} {
 			allocate, release := p.prioritySchedulerWithFilter(
-				taskList, groups, agentsWithLabel, fittingMethod, taskFilter(label, zeroSlots),
+				taskList, groups, agents, agentsSatisfying, fittingMethod, taskFilter(key, zeroSlots),
+				preemptionFilter(taskList, agentsSatisfying, zeroSlots), released, explainer, now,
 			)
 			toAllocate = append(toAllocate, allocate...)
 			toRelease = append(toRelease, release...)
 		}
 	}
 
+	recordPendingReasons(taskList, explainer, toAllocate)
 	return toAllocate, toRelease
 }
 
@@ -57,27 +84,34 @@ func (p *priorityScheduler) prioritySchedule(
 // 1. Schedule pending tasks without preemption.
 // 2. Search if preempting any lower-priority tasks can make space.
 // 3. Back-fill lower-priority pending tasks if there are no tasks to preempt.
+// The pending tasks passing the filter are fitted on localAgentsState, the local copy of the state
+// of the agents satisfying their constraints, which is updated in place. Running tasks passing
+// preemptible may be preempted for them, unless they are already released.
 func (p *priorityScheduler) prioritySchedulerWithFilter(
 	taskList *taskList,
 	groups map[*actor.Ref]*group,
 	agents map[*actor.Ref]*agentState,
+	localAgentsState map[*actor.Ref]*agentState,
 	fittingMethod SoftConstraint,
 	filter func(*sproto.AllocateRequest) bool,
+	preemptible func(*sproto.AllocateRequest) bool,
+	released map[*actor.Ref]bool,
+	explainer *pendingExplainer,
+	now time.Time,
 ) ([]*sproto.AllocateRequest, []*actor.Ref) {
 	toAllocate := make([]*sproto.AllocateRequest, 0)
 	toRelease := make(map[*actor.Ref]bool)
 
 	// Sort tasks by priorities and timestamps. This sort determines the order in which
 	// tasks are scheduled and preempted.
-	priorityToPendingTasksMap, priorityToScheduledTaskMap := sortTasksByPriorityAndTimestamp(
-		taskList, groups, filter)
-
-	// Make a local copy of the agent state that we will modify.
-	localAgentsState := deepCopyAgents(agents)
+	priorityToPendingTasksMap, _ := sortTasksByPriorityAndTimestamp(taskList, groups, filter)
+	_, priorityToScheduledTaskMap := sortTasksByPriorityAndTimestamp(taskList, groups, preemptible)
 
 	// If there exist any tasks that cannot be scheduled, all the tasks of lower priorities
 	// can only be backfilled if they are preemptible.
 	backfilling := false
+	// The number of tasks of higher priorities than the current one that remain pending.
+	pendingAhead := 0
 
 	for _, priority := range getOrderedPriorities(priorityToPendingTasksMap) {
 		allocationRequests := priorityToPendingTasksMap[priority]
@@ -88,21 +122,31 @@ func (p *priorityScheduler) prioritySchedulerWithFilter(
 			allocationRequests, localAgentsState, fittingMethod)
 
 		// Only start tasks if there are no tasks of higher priorities to preempt.
-		if len(toRelease) == 0 {
-			if !backfilling {
-				for _, allocatedTask := range successfulAllocations {
-					log.Debugf("scheduled task: %s", allocatedTask.Name)
-					toAllocate = append(toAllocate, allocatedTask)
-				}
-			} else if p.preemptionEnabled {
-				for _, allocatedTask := range successfulAllocations {
-					if !allocatedTask.Preemptible {
-						continue
-					}
-					log.Debugf("scheduled task via backfilling: %s", allocatedTask.Name)
-					toAllocate = append(toAllocate, allocatedTask)
-				}
+		allocatedBefore := len(toAllocate)
+		for _, allocatedTask := range successfulAllocations {
+			switch {
+			case len(toRelease) > 0:
+				taskList.SetPendingReason(allocatedTask.TaskActor, sproto.PendingReason{
+					Code: sproto.PendingPreemption,
+					Message: fmt.Sprintf("waiting for %d tasks to be preempted for tasks of "+
+						"higher priority", len(toRelease)),
+				})
+			case !backfilling:
+				log.Debugf("scheduled task: %s", allocatedTask.Name)
+				toAllocate = append(toAllocate, allocatedTask)
+			case p.preemptionEnabled && allocatedTask.Preemptible:
+				log.Debugf("scheduled task via backfilling: %s", allocatedTask.Name)
+				toAllocate = append(toAllocate, allocatedTask)
+			default:
+				taskList.SetPendingReason(allocatedTask.TaskActor, lowerPriorityReason(pendingAhead))
+			}
+		}
+		for _, prioritizedAllocation := range unSuccessfulAllocations {
+			reason := explainer.explain(prioritizedAllocation)
+			if reason.Code == sproto.PendingInsufficientFreeSlots && pendingAhead > 0 {
+				reason = lowerPriorityReason(pendingAhead)
 			}
+			taskList.SetPendingReason(prioritizedAllocation.TaskActor, reason)
 		}
 
 		// Scheduling the tasks of lower priority than the current one is considered to
@@ -110,6 +154,7 @@ func (p *priorityScheduler) prioritySchedulerWithFilter(
 		if len(unSuccessfulAllocations) > 0 {
 			backfilling = true
 		}
+		pendingAhead += len(allocationRequests) - (len(toAllocate) - allocatedBefore)
 
 		if p.preemptionEnabled {
 			for _, prioritizedAllocation := range unSuccessfulAllocations {
@@ -119,20 +164,41 @@ func (p *priorityScheduler) prioritySchedulerWithFilter(
 						"Not preempting tasks for task %s as it will be able to launch "+
 							"once already scheduled preemptions complete", prioritizedAllocation.Name)
 					addTaskToAgents(fits)
+					taskList.SetPendingReason(prioritizedAllocation.TaskActor, sproto.PendingReason{
+						Code:    sproto.PendingPreemption,
+						Message: "waiting for preempted tasks to release their slots",
+					})
 					continue
 				}
 
 				taskPlaced, updatedLocalAgentState, preemptedTasks := trySchedulingTaskViaPreemption(
 					taskList, prioritizedAllocation, priority, fittingMethod, localAgentsState,
-					priorityToScheduledTaskMap, toRelease, filter)
+					priorityToScheduledTaskMap, released, preemptible, p.canPreempt(taskList, now))
 
 				if taskPlaced {
-					localAgentsState = updatedLocalAgentState
+					// The agents are shared with the tasks of other constraints, so they are updated
+					// in place rather than replaced.
+					for ref, agent := range updatedLocalAgentState {
+						*localAgentsState[ref] = *agent
+					}
 					for preemptedTask := range preemptedTasks {
 						log.Debugf("preempting task %s for task %s",
 							preemptedTask.Address().Local(), prioritizedAllocation.Name)
 						toRelease[preemptedTask] = true
+						released[preemptedTask] = true
+						p.recordPreemption(taskList, preemptedTask, now)
+						taskList.SetPreemptionReason(preemptedTask, sproto.PreemptionReason{
+							Reason: fmt.Sprintf("preempted for task %s of higher priority %d",
+								prioritizedAllocation.Name, priority),
+							PreemptedBy: prioritizedAllocation.TaskID,
+							JobID:       prioritizedAllocation.JobID,
+						})
 					}
+					taskList.SetPendingReason(prioritizedAllocation.TaskActor, sproto.PendingReason{
+						Code: sproto.PendingPreemption,
+						Message: fmt.Sprintf("waiting for %d lower priority tasks to be preempted",
+							len(preemptedTasks)),
+					})
 				}
 			}
 		}
@@ -145,6 +211,93 @@ func (p *priorityScheduler) prioritySchedulerWithFilter(
 	return toAllocate, toReleaseSlice
 }
 
+// canPreempt returns whether a running task can be preempted given the tasks already chosen to be
+// preempted alongside it, as of now: it must have run for the minimum runtime, and preempting it
+// must not exceed the preemption budget of its group.
+func (p *priorityScheduler) canPreempt(
+	taskList *taskList, now time.Time,
+) func(*sproto.AllocateRequest, map[*actor.Ref]bool) bool {
+	return func(candidate *sproto.AllocateRequest, preempting map[*actor.Ref]bool) bool {
+		allocated := taskList.GetAllocations(candidate.TaskActor)
+		if allocated != nil && now.Sub(allocated.StartTime) < p.minRuntime {
+			p.recheck(allocated.StartTime.Add(p.minRuntime))
+			return false
+		}
+		if p.preemptionBudget == nil {
+			return true
+		}
+		preempted := p.preempted[candidate.Group]
+		if _, ok := preempted[candidate.TaskActor]; ok {
+			// The task was counted against the budget when it was first chosen to be preempted.
+			return true
+		}
+		count := len(preempted)
+		for ref := range preempting {
+			req, ok := taskList.GetTaskByHandler(ref)
+			if _, counted := preempted[ref]; ok && !counted && req.Group == candidate.Group {
+				count++
+			}
+		}
+		if count < p.preemptionBudget.MaxPreemptions {
+			return true
+		}
+		for _, at := range preempted {
+			p.recheck(at.Add(time.Duration(p.preemptionBudget.Window)))
+		}
+		return false
+	}
+}
+
+func (p *priorityScheduler) recheck(at time.Time) {
+	if p.recheckAt.IsZero() || at.Before(p.recheckAt) {
+		p.recheckAt = at
+	}
+}
+
+func (p *priorityScheduler) rescheduleAt() time.Time {
+	return p.recheckAt
+}
+
+// recordPreemption counts the preemption of the task against the preemption budget of its group.
+func (p *priorityScheduler) recordPreemption(taskList *taskList, task *actor.Ref, now time.Time) {
+	req, ok := taskList.GetTaskByHandler(task)
+	if p.preemptionBudget == nil || !ok {
+		return
+	}
+	if p.preempted == nil {
+		p.preempted = make(map[*actor.Ref]map[*actor.Ref]time.Time)
+	}
+	if p.preempted[req.Group] == nil {
+		p.preempted[req.Group] = make(map[*actor.Ref]time.Time)
+	}
+	if _, ok := p.preempted[req.Group][task]; !ok {
+		p.preempted[req.Group][task] = now
+	}
+}
+
+// forgetPreemptions forgets the preemptions that are out of the window of the preemption budget
+// and those of groups that no longer exist.
+func (p *priorityScheduler) forgetPreemptions(groups map[*actor.Ref]*group, now time.Time) {
+	for g, preempted := range p.preempted {
+		if _, ok := groups[g]; !ok {
+			delete(p.preempted, g)
+			continue
+		}
+		for task, at := range preempted {
+			if now.Sub(at) >= time.Duration(p.preemptionBudget.Window) {
+				delete(preempted, task)
+			}
+		}
+	}
+}
+
+func lowerPriorityReason(pendingAhead int) sproto.PendingReason {
+	return sproto.PendingReason{
+		Code:    sproto.PendingLowerPriority,
+		Message: fmt.Sprintf("lower priority than %d pending tasks", pendingAhead),
+	}
+}
+
 // trySchedulingTaskViaPreemption checks whether preempting lower priority tasks
 // would allow this task to be scheduled.
 func trySchedulingTaskViaPreemption(
@@ -156,6 +309,7 @@ func trySchedulingTaskViaPreemption(
 	priorityToScheduledTaskMap map[int][]*sproto.AllocateRequest,
 	tasksAlreadyPreempted map[*actor.Ref]bool,
 	filter func(*sproto.AllocateRequest) bool,
+	canPreempt func(*sproto.AllocateRequest, map[*actor.Ref]bool) bool,
 ) (bool, map[*actor.Ref]*agentState, map[*actor.Ref]bool) {
 	localAgentsState := deepCopyAgents(agents)
 	preemptedTasks := make(map[*actor.Ref]bool)
@@ -171,6 +325,10 @@ func trySchedulingTaskViaPreemption(
 				continue
 			}
 
+			if !canPreempt(preemptionCandidate, preemptedTasks) {
+				continue
+			}
+
 			resourcesAllocated := taskList.GetAllocations(preemptionCandidate.TaskActor)
 			removeTaskFromAgents(localAgentsState, resourcesAllocated)
 			preemptedTasks[preemptionCandidate.TaskActor] = true
@@ -265,7 +423,7 @@ func deepCopyAgents(agents map[*actor.Ref]*agentState) map[*actor.Ref]*agentStat
 
 func addTaskToAgents(fits []*fittingState) {
 	for _, fit := range fits {
-		fit.Agent.allocateFreeDevices(fit.Slots, cproto.NewID())
+		fit.allocate(cproto.NewID())
 	}
 }
 
@@ -275,10 +433,15 @@ func removeTaskFromAgents(
 ) {
 	for _, allocation := range resourcesAllocated.Reservations {
 		allocation := allocation.(*containerReservation)
+		if _, ok := agents[allocation.agent.handler]; !ok {
+			// The task also runs on agents that are not considered.
+			continue
+		}
 		if len(allocation.devices) == 0 {
 			// Handle zero-slot containers.
 			delete(agents[allocation.agent.handler].zeroSlotContainers, allocation.container.id)
 		}
+		delete(agents[allocation.agent.handler].hostResources, allocation.container.id)
 
 		for _, allocatedDevice := range allocation.devices {
 			// Local devices are a deep copy of the originals so we loop over trying to find
@@ -302,19 +465,114 @@ func getOrderedPriorities(allocationsByPriority map[int][]*sproto.AllocateReques
 	return keys
 }
 
-func splitAgentsByLabel(agents map[*actor.Ref]*agentState) map[string]map[*actor.Ref]*agentState {
-	agentsSplitByLabel := make(map[string]map[*actor.Ref]*agentState)
-	for agentRef, agent := range agents {
-		if _, ok := agentsSplitByLabel[agent.label]; !ok {
-			agentsSplitByLabel[agent.label] = make(map[*actor.Ref]*agentState)
+// agentConstraintsKey identifies the hard agent constraints of a task; tasks with the same key can
+// be scheduled on exactly the same agents. Tasks without agent selectors are keyed by their label.
+func agentConstraintsKey(req *sproto.AllocateRequest) string {
+	if len(req.AgentSelectors) == 0 {
+		return req.Label
+	}
+	return req.Label + "\x00" + req.AgentSelectors.String()
+}
+
+// splitAgentsByConstraints returns the agents satisfying the hard agent constraints of the tasks,
+// keyed by agentConstraintsKey. Constraints that no agent satisfies are left out. Since tasks may
+// select agents by different labels, the agents of different keys may overlap.
+func splitAgentsByConstraints(
+	taskList *taskList, agents map[*actor.Ref]*agentState,
+) map[string]map[*actor.Ref]*agentState {
+	seen := make(map[string]bool)
+	agentsSplitByConstraints := make(map[string]map[*actor.Ref]*agentState)
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		key := agentConstraintsKey(req)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		for agentRef, agent := range agents {
+			if !labelSatisfied(req, agent) {
+				continue
+			}
+			if _, ok := agentsSplitByConstraints[key]; !ok {
+				agentsSplitByConstraints[key] = make(map[*actor.Ref]*agentState)
+			}
+			agentsSplitByConstraints[key][agentRef] = agent
 		}
-		agentsSplitByLabel[agent.label][agentRef] = agent
 	}
-	return agentsSplitByLabel
+	return agentsSplitByConstraints
 }
 
-func taskFilter(label string, zeroSlots bool) func(*sproto.AllocateRequest) bool {
+func taskFilter(key string, zeroSlots bool) func(*sproto.AllocateRequest) bool {
 	return func(request *sproto.AllocateRequest) bool {
-		return request.Label == label && (request.SlotsNeeded == 0) == zeroSlots
+		return agentConstraintsKey(request) == key && isZeroSlot(request) == zeroSlots
+	}
+}
+
+// preemptionFilter returns whether a running task may be preempted to make room on the agents:
+// whatever its constraints are, it must run on any of them.
+func preemptionFilter(
+	taskList *taskList, agents map[*actor.Ref]*agentState, zeroSlots bool,
+) func(*sproto.AllocateRequest) bool {
+	return func(request *sproto.AllocateRequest) bool {
+		if isZeroSlot(request) != zeroSlots {
+			return false
+		}
+		allocated := taskList.GetAllocations(request.TaskActor)
+		if allocated == nil {
+			return false
+		}
+		for _, reservation := range allocated.Reservations {
+			if r, ok := reservation.(*containerReservation); ok {
+				if _, ok := agents[r.agent.handler]; ok {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// orderedConstraintKeys returns the keys of the agents split by constraints, ordered by the highest
+// priority of their pending tasks, then from the most to the least constrained, and then by key.
+func orderedConstraintKeys(
+	taskList *taskList,
+	groups map[*actor.Ref]*group,
+	agentsByConstraints map[string]map[*actor.Ref]*agentState,
+) []string {
+	highest := make(map[string]int, len(agentsByConstraints))
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		key := agentConstraintsKey(req)
+		if _, ok := agentsByConstraints[key]; !ok || taskList.GetAllocations(req.TaskActor) != nil {
+			continue
+		}
+		group := groups[req.Group]
+		if group == nil || group.priority == nil {
+			continue
+		}
+		priority := group.priority
+		if current, ok := highest[key]; !ok || *priority < current {
+			highest[key] = *priority
+		}
+	}
+
+	keys := make([]string, 0, len(agentsByConstraints))
+	for key := range agentsByConstraints {
+		keys = append(keys, key)
 	}
+	sort.Slice(keys, func(i, j int) bool {
+		pi, iPending := highest[keys[i]]
+		pj, jPending := highest[keys[j]]
+		switch {
+		case iPending != jPending:
+			return iPending
+		case pi != pj:
+			return pi < pj
+		case len(agentsByConstraints[keys[i]]) != len(agentsByConstraints[keys[j]]):
+			return len(agentsByConstraints[keys[i]]) < len(agentsByConstraints[keys[j]])
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+	return keys
 }
@@ -2,11 +2,13 @@ package resourcemanagers
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestSortTasksByPriorityAndTimestamps(t *testing.T) {
@@ -84,7 +86,7 @@ func TestPrioritySchedulingMaxZeroSlotContainer(t *testing.T) {
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{preemptionEnabled: true}
-	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	expectedToAllocate := []*mockTask{tasks[0]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
@@ -115,7 +117,7 @@ func TestPrioritySchedulingPreemptionDisabled(t *testing.T) {
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{}
-	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	expectedToAllocate := []*mockTask{tasks[1], tasks[2], tasks[3], tasks[4], tasks[5]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
@@ -148,7 +150,7 @@ func TestPrioritySchedulingPreemptionDisabledHigherPriorityBlocksLowerPriority(t
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{}
-	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	expectedToAllocate := []*mockTask{}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
@@ -181,12 +183,109 @@ func TestPrioritySchedulingPreemptionDisabledWithLabels(t *testing.T) {
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{}
-	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	expectedToAllocate := []*mockTask{tasks[0], tasks[1]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 }
 
+func TestPrioritySchedulingAgentSelectors(t *testing.T) {
+	priority := 50
+
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, labels: map[string]string{"gpu": "a100"}},
+		{id: "agent2", slots: 4, labels: map[string]string{"gpu": "k80"}},
+	}
+	groups := []*mockGroup{
+		{id: "group1", priority: &priority},
+	}
+	a100 := model.AgentSelectorsConfig{
+		{Key: "gpu", Operator: model.SelectorIn, Values: []string{"a100"}},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 4, group: groups[0], agentSelectors: a100},
+		{id: "task2", slotsNeeded: 4, group: groups[0], agentSelectors: a100},
+		{id: "task3", slotsNeeded: 4, group: groups[0]},
+	}
+
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+
+	p := &priorityScheduler{}
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
+
+	expectedToAllocate := []*mockTask{tasks[0], tasks[2]}
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+}
+
+func TestPrioritySchedulingOverlappingAgentSelectors(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, labels: map[string]string{"zone": "a"}},
+		{id: "agent2", slots: 4, labels: map[string]string{"zone": "a", "gpu": "a100"}},
+	}
+	groups := []*mockGroup{
+		{id: "group1", priority: &lowerPriority},
+		{id: "group2", priority: &higherPriority},
+	}
+	zoneA := model.AgentSelectorsConfig{
+		{Key: "zone", Operator: model.SelectorIn, Values: []string{"a"}},
+	}
+	a100 := model.AgentSelectorsConfig{
+		{Key: "gpu", Operator: model.SelectorIn, Values: []string{"a100"}},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 4, group: groups[0], agentSelectors: zoneA},
+		{id: "task2", slotsNeeded: 4, group: groups[0], agentSelectors: zoneA},
+		{id: "task3", slotsNeeded: 4, group: groups[1], agentSelectors: a100},
+	}
+
+	// Both constraints select agent2, whose slots go to the task of higher priority alone.
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	p := &priorityScheduler{}
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
+	assertEqualToAllocate(t, toAllocate, []*mockTask{tasks[0], tasks[2]})
+}
+
+func TestPrioritySchedulingPreemptionAcrossAgentSelectors(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*mockAgent{
+		{id: "agent1", slots: 4, labels: map[string]string{"zone": "a"}},
+		{id: "agent2", slots: 4, labels: map[string]string{"zone": "a", "gpu": "a100"}},
+	}
+	groups := []*mockGroup{
+		{id: "group1", priority: &lowerPriority},
+		{id: "group2", priority: &higherPriority},
+	}
+	zoneA := model.AgentSelectorsConfig{
+		{Key: "zone", Operator: model.SelectorIn, Values: []string{"a"}},
+	}
+	a100 := model.AgentSelectorsConfig{
+		{Key: "gpu", Operator: model.SelectorIn, Values: []string{"a100"}},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 4, group: groups[0], agentSelectors: zoneA,
+			allocatedAgent: agents[0], containerStarted: true},
+		{id: "task2", slotsNeeded: 4, group: groups[0], agentSelectors: zoneA,
+			allocatedAgent: agents[1], containerStarted: true},
+		{id: "task3", slotsNeeded: 4, group: groups[1], agentSelectors: a100},
+	}
+
+	// The task on agent2 is preempted for the task of higher priority, although their constraints
+	// differ.
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
+	assertEqualToAllocate(t, toAllocate, []*mockTask{})
+	assertEqualToRelease(t, taskList, toRelease, []*mockTask{tasks[1]})
+}
+
 func TestPrioritySchedulingPreemptionDisabledAddTasks(t *testing.T) {
 	lowerPriority := 50
 	higherPriority := 40
@@ -212,7 +311,7 @@ func TestPrioritySchedulingPreemptionDisabledAddTasks(t *testing.T) {
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{}
-	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	expectedToAllocate := []*mockTask{tasks[1], tasks[2], tasks[3], tasks[4], tasks[5]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
@@ -230,7 +329,7 @@ func TestPrioritySchedulingPreemptionDisabledAddTasks(t *testing.T) {
 	}
 	AddUnallocatedTasks(t, newTasks, system, taskList)
 
-	toAllocate, _ = p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ = p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	expectedToAllocate = []*mockTask{newTasks[0], newTasks[1]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 }
@@ -260,7 +359,7 @@ func TestPrioritySchedulingPreemptionDisabledAllSlotsAllocated(t *testing.T) {
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{}
-	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	expectedToAllocate := []*mockTask{tasks[1], tasks[2], tasks[3], tasks[4], tasks[5]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
@@ -277,7 +376,7 @@ func TestPrioritySchedulingPreemptionDisabledAllSlotsAllocated(t *testing.T) {
 	}
 	AddUnallocatedTasks(t, newTasks, system, taskList)
 
-	toAllocate, _ = p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ = p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	expectedToAllocate = []*mockTask{}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 }
@@ -305,7 +404,7 @@ func TestPrioritySchedulingPreemptionDisabledLowerPriorityMustWait(t *testing.T)
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{}
-	firstAllocation, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	firstAllocation, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	expectedToAllocate := []*mockTask{tasks[1], tasks[2], tasks[3]}
 	assertEqualToAllocate(t, firstAllocation, expectedToAllocate)
@@ -316,7 +415,7 @@ func TestPrioritySchedulingPreemptionDisabledLowerPriorityMustWait(t *testing.T)
 
 	AllocateTasks(firstAllocation, agentMap, taskList)
 
-	secondAllocation, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	secondAllocation, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	expectedToAllocate = []*mockTask{}
 	assertEqualToAllocate(t, secondAllocation, expectedToAllocate)
 
@@ -324,7 +423,7 @@ func TestPrioritySchedulingPreemptionDisabledLowerPriorityMustWait(t *testing.T)
 		RemoveTask(task.SlotsNeeded, task.TaskActor, taskList, true)
 	}
 
-	thirdAllocation, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	thirdAllocation, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	expectedToAllocate = []*mockTask{tasks[0], tasks[4]}
 	assertEqualToAllocate(t, thirdAllocation, expectedToAllocate)
 }
@@ -346,7 +445,7 @@ func TestPrioritySchedulingPreemptionDisabledTaskFinished(t *testing.T) {
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{}
-	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	for _, agent := range agentMap {
 		assert.Equal(t, agent.numEmptySlots(), 4)
@@ -365,7 +464,7 @@ func TestPrioritySchedulingPreemptionDisabledTaskFinished(t *testing.T) {
 	}
 	AddUnallocatedTasks(t, newTasks, system, taskList)
 
-	toAllocate, _ = p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ = p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	expectedToAllocate := []*mockTask{newTasks[0], newTasks[1], newTasks[2]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 }
@@ -395,7 +494,7 @@ func TestPrioritySchedulingPreemptionDisabledAllTasksFinished(t *testing.T) {
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{}
-	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	expectedToAllocate := []*mockTask{tasks[1], tasks[2], tasks[3], tasks[4], tasks[5]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
@@ -415,7 +514,7 @@ func TestPrioritySchedulingPreemptionDisabledAllTasksFinished(t *testing.T) {
 		RemoveTask(task.SlotsNeeded, task.TaskActor, taskList, true)
 	}
 
-	toAllocate, _ = p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ = p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	expectedToAllocate = []*mockTask{tasks[0], newTasks[0]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 }
@@ -440,7 +539,7 @@ func TestPrioritySchedulingPreemptionDisabledZeroSlotTask(t *testing.T) {
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 
 	p := &priorityScheduler{}
-	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 
 	expectedToAllocate := []*mockTask{tasks[0]}
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
@@ -452,7 +551,7 @@ func TestPrioritySchedulingPreemptionDisabledZeroSlotTask(t *testing.T) {
 	}
 	AddUnallocatedTasks(t, newTasks, system, taskList)
 
-	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	expectedTasks := []*mockTask{}
 	assertEqualToAllocate(t, toAllocate, expectedTasks)
 	assertEqualToRelease(t, taskList, toRelease, expectedTasks)
@@ -491,7 +590,7 @@ func TestPrioritySchedulingPreemption(t *testing.T) {
 	system := actor.NewSystem(t.Name())
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 	p := &priorityScheduler{preemptionEnabled: true}
-	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
 }
@@ -533,7 +632,7 @@ func TestPrioritySchedulingBackfilling(t *testing.T) {
 	system := actor.NewSystem(t.Name())
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 	p := &priorityScheduler{preemptionEnabled: true}
-	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
 }
@@ -569,7 +668,7 @@ func TestPrioritySchedulingPreemptionZeroSlotTask(t *testing.T) {
 	system := actor.NewSystem(t.Name())
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 	p := &priorityScheduler{preemptionEnabled: true}
-	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
 }
@@ -607,7 +706,7 @@ func TestPrioritySchedulingBackfillingZeroSlotTask(t *testing.T) {
 	system := actor.NewSystem(t.Name())
 	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
 	p := &priorityScheduler{preemptionEnabled: true}
-	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit)
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
 }
@@ -678,3 +777,89 @@ func RemoveTask(slots int, toRelease *actor.Ref, taskList *taskList, delete bool
 	}
 	return true
 }
+
+func TestPrioritySchedulingMinRuntime(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*mockAgent{{id: "agent1", slots: 4}}
+	groups := []*mockGroup{
+		{id: "group1", priority: &lowerPriority},
+		{id: "group2", priority: &higherPriority},
+	}
+	startTime := time.Now().Add(-time.Minute)
+	tasks := []*mockTask{
+		{id: "low-priority task", slotsNeeded: 4, group: groups[0],
+			allocatedAgent: agents[0], containerStarted: true, startTime: startTime},
+		{id: "high-priority task", slotsNeeded: 4, group: groups[1]},
+	}
+
+	// The low-priority task has not run for the minimum runtime yet, so it is not preempted, and the
+	// scheduler asks to run again once it has.
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	p := &priorityScheduler{preemptionEnabled: true, minRuntime: 10 * time.Minute}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
+	assertEqualToAllocate(t, toAllocate, []*mockTask{})
+	assertEqualToRelease(t, taskList, toRelease, []*mockTask{})
+	assert.Equal(t, p.rescheduleAt(), startTime.Add(10*time.Minute))
+
+	p.minRuntime = time.Minute / 2
+	toAllocate, toRelease = p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
+	assertEqualToAllocate(t, toAllocate, []*mockTask{})
+	assertEqualToRelease(t, taskList, toRelease, []*mockTask{tasks[0]})
+	assert.Assert(t, p.rescheduleAt().IsZero())
+	reason := taskList.GetPreemptionReason(toRelease[0])
+	assert.Assert(t, reason != nil)
+	assert.Equal(t, reason.Reason, "preempted for task high-priority task of higher priority 40")
+}
+
+func TestPrioritySchedulingPreemptionBudget(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*mockAgent{{id: "agent1", slots: 4}}
+	groups := []*mockGroup{
+		{id: "group1", priority: &lowerPriority},
+		{id: "group2", priority: &higherPriority},
+	}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 2, group: groups[0], allocatedAgent: agents[0],
+			containerStarted: true},
+		{id: "task2", slotsNeeded: 2, group: groups[0], allocatedAgent: agents[0],
+			containerStarted: true},
+		{id: "task3", slotsNeeded: 2, group: groups[1]},
+		{id: "task4", slotsNeeded: 2, group: groups[1]},
+	}
+
+	// Only one task of the low-priority group can be preempted within the window, and it keeps
+	// being preempted on the following passes without using up the budget again.
+	system := actor.NewSystem(t.Name())
+	taskList, groupMap, agentMap := setupSchedulerStates(t, system, tasks, groups, agents)
+	p := &priorityScheduler{
+		preemptionEnabled: true,
+		preemptionBudget: &PreemptionBudgetConfig{
+			MaxPreemptions: 1, Window: model.Duration(time.Hour),
+		},
+	}
+	for i := 0; i < 2; i++ {
+		toAllocate, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
+		assertEqualToAllocate(t, toAllocate, []*mockTask{})
+		assertEqualToRelease(t, taskList, toRelease, []*mockTask{tasks[0]})
+	}
+	assert.Assert(t, !p.rescheduleAt().IsZero())
+
+	// Once the preemption is out of the window, it no longer counts against the budget.
+	for _, preempted := range p.preempted {
+		for task := range preempted {
+			preempted[task] = time.Now().Add(-2 * time.Hour)
+		}
+	}
+	_, toRelease := p.prioritySchedule(taskList, groupMap, agentMap, BestFit, time.Now())
+	assertEqualToRelease(t, taskList, toRelease, []*mockTask{tasks[0]})
+	for _, preempted := range p.preempted {
+		for _, at := range preempted {
+			assert.Assert(t, time.Since(at) < time.Hour)
+		}
+	}
+}
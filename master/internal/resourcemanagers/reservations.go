@@ -0,0 +1,148 @@
+package resourcemanagers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// activeReservations returns the IDs of the reservations that are active at the time, or nil if
+// none is.
+func activeReservations(reservations []model.Reservation, now time.Time) map[int]bool {
+	var active map[int]bool
+	for _, r := range reservations {
+		if r.Active(now) {
+			if active == nil {
+				active = make(map[int]bool)
+			}
+			active[r.ID] = true
+		}
+	}
+	return active
+}
+
+// reservedSlots tracks the slots of the active reservations that the tasks matching them do not
+// use yet.
+type reservedSlots struct {
+	reservations []model.Reservation
+	unused       []int
+}
+
+func newReservedSlots(reservations []model.Reservation, now time.Time) *reservedSlots {
+	r := &reservedSlots{}
+	for _, reservation := range reservations {
+		if reservation.Active(now) {
+			r.reservations = append(r.reservations, reservation)
+			r.unused = append(r.unused, reservation.Slots)
+		}
+	}
+	return r
+}
+
+// matches returns whether the task matches any of the active reservations.
+func (r *reservedSlots) matches(req *sproto.AllocateRequest) bool {
+	for _, reservation := range r.reservations {
+		if reservation.Matches(req.Username, req.Labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// use uses the unused slots of the reservations the task matches for its slots, and returns the
+// number of its slots that the reservations do not cover. The slots are only used if commit is
+// true.
+func (r *reservedSlots) use(req *sproto.AllocateRequest, commit bool) int {
+	slots := req.SlotsNeeded
+	for i, reservation := range r.reservations {
+		if slots == 0 || !reservation.Matches(req.Username, req.Labels) {
+			continue
+		}
+		used := min(slots, r.unused[i])
+		if commit {
+			r.unused[i] -= used
+		}
+		slots -= used
+	}
+	return slots
+}
+
+func (r *reservedSlots) total() (slots int) {
+	for _, unused := range r.unused {
+		slots += unused
+	}
+	return slots
+}
+
+// holdReservedSlots adjusts the decisions of a scheduler to hold the slots of the active
+// reservations for the tasks matching them. Tasks to allocate that would take reserved slots are
+// left pending, and if the free slots of the agents do not cover the reserved slots, preemptible
+// tasks not matching any active reservation are released, most recently registered first.
+func holdReservedSlots(
+	taskList *taskList,
+	agents map[*actor.Ref]*agentState,
+	reservations []model.Reservation,
+	now time.Time,
+	toAllocate []*sproto.AllocateRequest,
+	toRelease []*actor.Ref,
+) ([]*sproto.AllocateRequest, []*actor.Ref) {
+	reserved := newReservedSlots(reservations, now)
+	if len(reserved.reservations) == 0 {
+		return toAllocate, toRelease
+	}
+
+	releasing := make(map[*actor.Ref]bool, len(toRelease))
+	for _, ref := range toRelease {
+		releasing[ref] = true
+	}
+	var preemptible []*sproto.AllocateRequest
+	for it := taskList.iterator(); it.next(); {
+		req := it.value()
+		if taskList.GetAllocations(req.TaskActor) == nil || releasing[req.TaskActor] {
+			continue
+		}
+		reserved.use(req, true)
+		if req.Preemptible && req.SlotsNeeded > 0 && !reserved.matches(req) {
+			preemptible = append(preemptible, req)
+		}
+	}
+
+	free := 0
+	for _, agent := range agents {
+		free += agent.numEmptySlots()
+	}
+
+	allocate := make([]*sproto.AllocateRequest, 0, len(toAllocate))
+	for _, req := range toAllocate {
+		if reserved.use(req, false) > 0 && free-req.SlotsNeeded < reserved.total() {
+			taskList.SetPendingReason(req.TaskActor, sproto.PendingReason{
+				Code:    sproto.PendingReservedSlots,
+				Message: fmt.Sprintf("%d slots are reserved for other tasks", reserved.total()),
+			})
+			continue
+		}
+		reserved.use(req, true)
+		free -= req.SlotsNeeded
+		allocate = append(allocate, req)
+	}
+
+	sort.Slice(preemptible, func(i, j int) bool {
+		return preemptible[i].TaskActor.RegisteredTime().After(
+			preemptible[j].TaskActor.RegisteredTime())
+	})
+	for _, req := range preemptible {
+		if free >= reserved.total() {
+			break
+		}
+		taskList.SetPreemptionReason(req.TaskActor, sproto.PreemptionReason{
+			Reason: fmt.Sprintf("preempted to free %d slots reserved for other tasks", reserved.total()),
+		})
+		toRelease = append(toRelease, req.TaskActor)
+		free += req.SlotsNeeded
+	}
+	return allocate, toRelease
+}
@@ -0,0 +1,96 @@
+package resourcemanagers
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestHoldReservedSlots(t *testing.T) {
+	now := time.Now()
+	reservations := []model.Reservation{{
+		ID: 1, Slots: 2, Username: "alice",
+		StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour),
+	}}
+	agents := []*mockAgent{{id: "agent", slots: 4, maxZeroSlotContainers: 100}}
+	tasks := []*mockTask{
+		{id: "task1", username: "bob", slotsNeeded: 1, allocatedAgent: agents[0], containerStarted: true},
+		{id: "task2", username: "bob", slotsNeeded: 1},
+		{id: "task3", username: "bob", slotsNeeded: 1},
+		{id: "task4", username: "alice", slotsNeeded: 2},
+		{id: "task5", username: "bob", slotsNeeded: 0},
+	}
+
+	// Tasks of other users only get the slots left besides the reserved ones, while zero-slot tasks
+	// are not affected.
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, []*mockGroup{}, agents)
+	requests := func(ids ...model.AllocationID) []*sproto.AllocateRequest {
+		var reqs []*sproto.AllocateRequest
+		for _, id := range ids {
+			req, _ := taskList.GetTaskByID(id)
+			reqs = append(reqs, req)
+		}
+		return reqs
+	}
+	toAllocate, toRelease := holdReservedSlots(taskList, agentMap, reservations, now,
+		requests("task2", "task3", "task5"), nil)
+	assertEqualToAllocate(t, toAllocate, []*mockTask{tasks[1], tasks[4]})
+	assertEqualToRelease(t, taskList, toRelease, []*mockTask{})
+	reason := taskList.GetPendingReason(requests("task3")[0].TaskActor)
+	assert.Assert(t, reason != nil)
+	assert.Equal(t, reason.Code, sproto.PendingReservedSlots)
+
+	// Tasks matching the reservation use the reserved slots.
+	toAllocate, _ = holdReservedSlots(
+		taskList, agentMap, reservations, now, requests("task4"), nil)
+	assertEqualToAllocate(t, toAllocate, []*mockTask{tasks[3]})
+
+	// Outside of the window of the reservation, the slots are not held.
+	toAllocate, _ = holdReservedSlots(
+		taskList, agentMap, reservations, now.Add(2*time.Hour), requests("task3"), nil)
+	assertEqualToAllocate(t, toAllocate, []*mockTask{tasks[2]})
+}
+
+func TestHoldReservedSlotsPreemptsTasks(t *testing.T) {
+	now := time.Now()
+	reservations := []model.Reservation{{
+		ID: 1, Slots: 3, Label: "deadline",
+		StartTime: now.Add(-time.Minute), EndTime: now.Add(time.Hour),
+	}}
+	agents := []*mockAgent{{id: "agent", slots: 4, maxZeroSlotContainers: 100}}
+	tasks := []*mockTask{
+		{id: "task1", slotsNeeded: 2, allocatedAgent: agents[0], containerStarted: true},
+		{id: "task2", slotsNeeded: 1, allocatedAgent: agents[0], containerStarted: true},
+		{
+			id: "task3", slotsNeeded: 1, nonPreemptible: true,
+			allocatedAgent: agents[0], containerStarted: true,
+		},
+	}
+
+	// The most recently registered preemptible tasks are released until the reserved slots are
+	// free; tasks that cannot be preempted keep running.
+	system := actor.NewSystem(t.Name())
+	taskList, _, agentMap := setupSchedulerStates(t, system, tasks, []*mockGroup{}, agents)
+	toAllocate, toRelease := holdReservedSlots(taskList, agentMap, reservations, now, nil, nil)
+	assertEqualToAllocate(t, toAllocate, []*mockTask{})
+	assertEqualToRelease(t, taskList, toRelease, []*mockTask{tasks[0], tasks[1]})
+}
+
+func TestPeakReservedSlots(t *testing.T) {
+	start := time.Date(2021, 11, 15, 0, 0, 0, 0, time.UTC)
+	reservations := []model.Reservation{
+		{Slots: 2, StartTime: start, EndTime: start.Add(2 * time.Hour)},
+		{Slots: 3, StartTime: start.Add(time.Hour), EndTime: start.Add(3 * time.Hour)},
+		// Windows are half-open, so this one does not overlap the first one.
+		{Slots: 4, StartTime: start.Add(2 * time.Hour), EndTime: start.Add(4 * time.Hour)},
+	}
+	assert.Equal(t, model.PeakReservedSlots(reservations), 7)
+	assert.Equal(t, model.PeakReservedSlots(reservations[:2]), 5)
+	assert.Equal(t, model.PeakReservedSlots(nil), 0)
+}
@@ -102,6 +102,11 @@ type KubernetesResourceManagerConfig struct {
 	DefaultScheduler         string                             `json:"default_scheduler"`
 	SlotType                 device.Type                        `json:"slot_type"`
 	SlotResourceRequests     kubernetes.PodSlotResourceRequests `json:"slot_resource_requests"`
+	GangScheduling           bool                               `json:"gang_scheduling"`
+
+	ResourcePools              []kubernetes.ResourcePoolConfig `json:"resource_pools"`
+	DefaultAuxResourcePool     string                          `json:"default_aux_resource_pool"`
+	DefaultComputeResourcePool string                          `json:"default_compute_resource_pool"`
 }
 
 var defaultKubernetesResourceManagerConfig = KubernetesResourceManagerConfig{
@@ -115,6 +120,39 @@ func (k *KubernetesResourceManagerConfig) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, DefaultParser(k))
 }
 
+// resolveResourcePools fills in the implicit resource pool when none are configured, the
+// namespace of pools that do not define their own and the default pools.
+func (k *KubernetesResourceManagerConfig) resolveResourcePools() {
+	if len(k.ResourcePools) == 0 {
+		k.ResourcePools = []kubernetes.ResourcePoolConfig{{
+			PoolName:    kubernetesDefaultResourcePool,
+			Description: "Kubernetes-managed pool of resources",
+		}}
+	}
+	for ix := range k.ResourcePools {
+		if k.ResourcePools[ix].Namespace == "" {
+			k.ResourcePools[ix].Namespace = k.Namespace
+		}
+	}
+	if k.DefaultComputeResourcePool == "" {
+		k.DefaultComputeResourcePool = k.ResourcePools[0].PoolName
+	}
+	if k.DefaultAuxResourcePool == "" {
+		k.DefaultAuxResourcePool = k.ResourcePools[0].PoolName
+	}
+}
+
+func (k KubernetesResourceManagerConfig) resourcePool(
+	name string,
+) (kubernetes.ResourcePoolConfig, bool) {
+	for _, pool := range k.ResourcePools {
+		if pool.PoolName == name {
+			return pool, true
+		}
+	}
+	return kubernetes.ResourcePoolConfig{}, false
+}
+
 // Validate implements the check.Validatable interface.
 func (k KubernetesResourceManagerConfig) Validate() []error {
 	var checkSlotType error
@@ -130,9 +168,32 @@ func (k KubernetesResourceManagerConfig) Validate() []error {
 		checkCPUResource = check.GreaterThan(
 			k.SlotResourceRequests.CPU, float32(0), "slot_resource_requests.cpu must be > 0")
 	}
-	return []error{
+	errs := []error{
 		check.GreaterThanOrEqualTo(k.MaxSlotsPerPod, 0, "max_slots_per_pod must be >= 0"),
 		checkSlotType,
 		checkCPUResource,
 	}
+
+	poolNames := make(map[string]bool)
+	for ix, rp := range k.ResourcePools {
+		if poolNames[rp.PoolName] {
+			errs = append(errs, errors.Errorf(
+				"%d resource pool has a duplicate name: %s", ix, rp.PoolName))
+		}
+		poolNames[rp.PoolName] = true
+	}
+	if _, err := kubernetes.PriorityClasses(k.ResourcePools); err != nil {
+		errs = append(errs, err)
+	}
+	if len(k.ResourcePools) > 0 {
+		if _, ok := k.resourcePool(k.DefaultComputeResourcePool); !ok {
+			errs = append(errs, errors.Errorf(
+				"default_compute_resource_pool %s does not exist", k.DefaultComputeResourcePool))
+		}
+		if _, ok := k.resourcePool(k.DefaultAuxResourcePool); !ok {
+			errs = append(errs, errors.Errorf(
+				"default_aux_resource_pool %s does not exist", k.DefaultAuxResourcePool))
+		}
+	}
+	return errs
 }
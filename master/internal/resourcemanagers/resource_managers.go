@@ -28,7 +28,7 @@ func (rm *ResourceManagers) Receive(ctx *actor.Context) error {
 		sproto.SetGroupMaxSlots, sproto.SetGroupWeight,
 		sproto.SetGroupPriority, sproto.GetTaskSummary,
 		sproto.GetTaskSummaries, sproto.SetTaskName,
-		sproto.GetTaskHandler:
+		sproto.GetTaskHandler, sproto.SetReservations:
 		rm.forward(ctx, msg)
 
 	default:
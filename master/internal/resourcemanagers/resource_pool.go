@@ -2,12 +2,16 @@ package resourcemanagers
 
 import (
 	"crypto/tls"
+	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/model"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/provisioner"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/telemetry"
@@ -25,18 +29,26 @@ type ResourcePool struct {
 	config *ResourcePoolConfig
 	cert   *tls.Certificate
 
-	scheduler        Scheduler
-	fittingMethod    SoftConstraint
-	provisioner      *actor.Ref
-	slotsPerInstance int
+	scheduler     Scheduler
+	fittingMethod SoftConstraint
+	provisioner   *actor.Ref
+	instanceSlots []int
 
 	agents      map[*actor.Ref]*agentState
 	taskList    *taskList
 	groups      map[*actor.Ref]*group
 	scalingInfo *sproto.ScalingInfo
 
+	// reservations are the reservations of the resource pool that have not ended, and
+	// activeReservations are the IDs of those active on the last scheduler tick.
+	reservations       []model.Reservation
+	activeReservations map[int]bool
+
 	reschedule bool
 
+	// now returns the current time, which the scheduler simulator replaces with its simulated clock.
+	now func() time.Time
+
 	// Track notifyOnStop for testing purposes.
 	saveNotifications bool
 	notifications     []<-chan struct{}
@@ -66,6 +78,7 @@ func NewResourcePool(
 		scalingInfo: &sproto.ScalingInfo{},
 
 		reschedule: false,
+		now:        time.Now,
 	}
 	return d
 }
@@ -79,7 +92,7 @@ func (rp *ResourcePool) setupProvisioner(ctx *actor.Context) error {
 	if err != nil {
 		return errors.Wrapf(err, "cannot create resource pool: %s", rp.config.PoolName)
 	}
-	rp.slotsPerInstance = p.SlotsPerInstance()
+	rp.instanceSlots = p.InstanceSlots()
 	rp.provisioner = pRef
 	return nil
 }
@@ -114,10 +127,25 @@ func (rp *ResourcePool) receiveSetTaskName(ctx *actor.Context, msg sproto.SetTas
 // allocateResources assigns resources based on a request and notifies the request
 // handler of the assignment. It returns true if it is successfully allocated.
 func (rp *ResourcePool) allocateResources(ctx *actor.Context, req *sproto.AllocateRequest) bool {
+	allocated := rp.reserveResources(req)
+	if allocated == nil {
+		return false
+	}
+	req.TaskActor.System().Tell(req.TaskActor, *allocated)
+	ctx.Log().Infof("allocated resources to %s", req.TaskActor.Address())
+	prom.ObserveSince(prom.QueueWait.WithLabelValues(rp.config.PoolName),
+		rp.taskList.GetAddedTime(req.TaskActor))
+
+	return true
+}
+
+// reserveResources reserves the resources of the agents that fit a request and records them in
+// the task list. It returns nil if the request does not fit the agents.
+func (rp *ResourcePool) reserveResources(req *sproto.AllocateRequest) *sproto.ResourcesAllocated {
 	fits := findFits(req, rp.agents, rp.fittingMethod)
 
 	if len(fits) == 0 {
-		return false
+		return nil
 	}
 
 	allocations := make([]sproto.Reservation, 0, len(fits))
@@ -127,34 +155,49 @@ func (rp *ResourcePool) allocateResources(ctx *actor.Context, req *sproto.Alloca
 			req:       req,
 			agent:     fit.Agent,
 			container: container,
-			devices:   fit.Agent.allocateFreeDevices(fit.Slots, container.id),
+			devices:   fit.allocate(container.id),
 		})
 	}
 
 	allocated := sproto.ResourcesAllocated{
 		ID: req.AllocationID, ResourcePool: rp.config.PoolName, Reservations: allocations,
+		StartTime: rp.now(),
 	}
 	rp.taskList.SetAllocations(req.TaskActor, &allocated)
-	req.TaskActor.System().Tell(req.TaskActor, allocated)
-	ctx.Log().Infof("allocated resources to %s", req.TaskActor.Address())
-
-	return true
+	return &allocated
 }
 
 func (rp *ResourcePool) releaseResource(ctx *actor.Context, handler *actor.Ref) {
 	ctx.Log().Infof("releasing resources taken by %s", handler.Address())
-	handler.System().Tell(handler, sproto.ReleaseResources{ResourcePool: rp.config.PoolName})
+	preemption := rp.taskList.GetPreemptionReason(handler)
+	if preemption == nil {
+		preemption = &sproto.PreemptionReason{
+			Reason: fmt.Sprintf("preempted by the %s scheduler", rp.config.Scheduler.GetType()),
+		}
+	}
+	handler.System().Tell(handler, sproto.ReleaseResources{
+		ResourcePool: rp.config.PoolName, Preemption: preemption,
+	})
 }
 
 func (rp *ResourcePool) resourcesReleased(ctx *actor.Context, handler *actor.Ref) {
-	if allocated := rp.taskList.GetAllocations(handler); allocated != nil {
+	if rp.freeResources(handler) {
 		ctx.Log().Infof("resources are released for %s", handler.Address())
+	}
+}
+
+// freeResources frees the resources reserved for a task and removes it from the task list. It
+// returns whether any resources were reserved for the task.
+func (rp *ResourcePool) freeResources(handler *actor.Ref) bool {
+	allocated := rp.taskList.GetAllocations(handler)
+	if allocated != nil {
 		for _, allocation := range allocated.Reservations {
 			typed := allocation.(*containerReservation)
 			typed.agent.deallocateContainer(typed.container.id)
 		}
 	}
 	rp.taskList.RemoveTaskByHandler(handler)
+	return allocated != nil
 }
 
 func (rp *ResourcePool) getOrCreateGroup(
@@ -190,14 +233,20 @@ func (rp *ResourcePool) notifyOnStop(
 
 func (rp *ResourcePool) updateScalingInfo() bool {
 	desiredInstanceNum := calculateDesiredNewAgentNum(
-		rp.taskList, rp.slotsPerInstance, rp.config.MaxAuxContainersPerAgent,
+		rp.taskList, rp.instanceSlots, rp.config.MaxAuxContainersPerAgent,
+	)
+	desiredSlots := calculateDesiredNewSlots(rp.taskList, rp.instanceSlots)
+	desiredZeroSlotInstanceNum := calculateDesiredNewZeroSlotAgentNum(
+		rp.taskList, rp.instanceSlots, rp.config.MaxAuxContainersPerAgent,
 	)
 	agents := make(map[string]sproto.AgentSummary)
 	for _, agentState := range rp.agents {
 		summary := newAgentSummary(agentState)
 		agents[summary.Name] = summary
 	}
-	return rp.scalingInfo.Update(desiredInstanceNum, agents)
+	return rp.scalingInfo.Update(
+		desiredInstanceNum, desiredSlots, desiredZeroSlotInstanceNum, agents,
+	)
 }
 
 func (rp *ResourcePool) sendScalingInfo(ctx *actor.Context) {
@@ -206,6 +255,37 @@ func (rp *ResourcePool) sendScalingInfo(ctx *actor.Context) {
 	}
 }
 
+// recordProvisionerPendingReasons records that the pending tasks that do not fit the connected
+// agents but fit the instances of the provisioner are waiting for it to launch instances.
+func (rp *ResourcePool) recordProvisionerPendingReasons() {
+	if rp.provisioner == nil {
+		return
+	}
+	for it := rp.taskList.iterator(); it.next(); {
+		req := it.value()
+		reason := rp.taskList.GetPendingReason(req.TaskActor)
+		if reason == nil {
+			continue
+		}
+		switch {
+		case reason.Code == sproto.PendingNoMatchingAgents && len(rp.agents) == 0:
+		case reason.Code == sproto.PendingInsufficientAgentCapacity:
+		case reason.Code == sproto.PendingInsufficientFreeSlots:
+		default:
+			continue
+		}
+		for _, slots := range rp.instanceSlots {
+			if slots > 0 && (req.SlotsNeeded <= slots || req.SlotsNeeded%slots == 0) {
+				rp.taskList.SetPendingReason(req.TaskActor, sproto.PendingReason{
+					Code:    sproto.PendingProvisioner,
+					Message: "waiting for the provisioner to launch instances",
+				})
+				break
+			}
+		}
+	}
+}
+
 // Receive implements the actor.Actor interface.
 func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 	ctx.AddLabel("resource-pool", rp.config.PoolName)
@@ -243,6 +323,14 @@ func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 		sproto.ResourcesReleased:
 		return rp.receiveRequestMsg(ctx)
 
+	case sproto.SetReservations:
+		rp.reservations = nil
+		for _, r := range msg.Reservations {
+			if r.ResourcePool == rp.config.PoolName {
+				rp.reservations = append(rp.reservations, r)
+			}
+		}
+
 	case sproto.GetTaskHandler:
 		reschedule = false
 		ctx.Respond(getTaskHandler(rp.taskList, msg.ID))
@@ -263,8 +351,30 @@ func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 		ctx.Respond(getResourceSummary(rp.agents))
 
 	case schedulerTick:
+		// Reschedule as reservations start and end to hold and release their slots.
+		now := rp.now()
+		if active := activeReservations(rp.reservations, now); !reflect.DeepEqual(
+			active, rp.activeReservations) {
+			rp.activeReservations = active
+			rp.reschedule = true
+		}
+		if s, ok := rp.scheduler.(timedScheduler); ok {
+			if at := s.rescheduleAt(); !at.IsZero() && !now.Before(at) {
+				rp.reschedule = true
+			}
+		}
 		if rp.reschedule {
+			rp.taskList.ClearPreemptionReasons()
+			start := time.Now()
 			toAllocate, toRelease := rp.scheduler.Schedule(rp)
+			prom.ObserveSince(prom.ScheduleDuration.WithLabelValues(rp.config.PoolName), start)
+			toAllocate, toRelease = holdReservedSlots(
+				rp.taskList, rp.agents, rp.reservations, now, toAllocate, toRelease)
+			prom.ScheduledAllocations.WithLabelValues(rp.config.PoolName).Observe(
+				float64(len(toAllocate)))
+			prom.ScheduledPreemptions.WithLabelValues(rp.config.PoolName).Observe(
+				float64(len(toRelease)))
+			rp.recordProvisionerPendingReasons()
 			for _, req := range toAllocate {
 				rp.allocateResources(ctx, req)
 			}
@@ -273,14 +383,19 @@ func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 			}
 			rp.sendScalingInfo(ctx)
 		}
+		rp.recordQueueDepth()
 		rp.reschedule = false
 		reschedule = false
 		actors.NotifyAfter(ctx, actionCoolDown, schedulerTick{})
 
 	case sproto.ValidateCommandResourcesRequest:
 		fulfillable := true // Default to "true" when unknown.
-		if rp.slotsPerInstance > 0 {
-			fulfillable = rp.slotsPerInstance >= msg.Slots
+		maxSlotsPerInstance := 0
+		for _, slots := range rp.instanceSlots {
+			maxSlotsPerInstance = max(maxSlotsPerInstance, slots)
+		}
+		if maxSlotsPerInstance > 0 {
+			fulfillable = maxSlotsPerInstance >= msg.Slots
 		}
 		ctx.Respond(sproto.ValidateCommandResourcesResponse{Fulfillable: fulfillable})
 
@@ -291,6 +406,17 @@ func (rp *ResourcePool) Receive(ctx *actor.Context) error {
 	return nil
 }
 
+// recordQueueDepth records the number of tasks waiting for resources.
+func (rp *ResourcePool) recordQueueDepth() {
+	pending := 0
+	for it := rp.taskList.iterator(); it.next(); {
+		if rp.taskList.GetAllocations(it.value().TaskActor) == nil {
+			pending++
+		}
+	}
+	prom.QueueDepth.WithLabelValues(rp.config.PoolName).Set(float64(pending))
+}
+
 func (rp *ResourcePool) receiveAgentMsg(ctx *actor.Context) error {
 	switch msg := ctx.Message().(type) {
 	case sproto.AddAgent:
@@ -435,7 +561,7 @@ func reportResourcePoolCreated(system *actor.System, config *ResourcePoolConfig)
 		preemptionEnabled = true
 	case config.Scheduler.Priority != nil:
 		preemptionEnabled = config.Scheduler.Priority.Preemption
-	case config.Scheduler.RoundRobin != nil:
+	case config.Scheduler.RoundRobin != nil, config.Scheduler.DRF != nil:
 		preemptionEnabled = false
 	}
 
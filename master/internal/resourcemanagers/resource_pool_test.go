@@ -2,6 +2,7 @@ package resourcemanagers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/model"
 
@@ -114,13 +115,14 @@ func TestScalingInfoAgentSummary(t *testing.T) {
 		{id: "unallocated-gpu-task5", slotsNeeded: 5},
 	}
 	rp, _ := setupResourcePool(t, system, nil, tasks, nil, agents)
-	rp.slotsPerInstance = 4
+	rp.instanceSlots = []int{4}
 
 	// Test basic.
 	updated := rp.updateScalingInfo()
 	assert.Check(t, updated)
 	assert.DeepEqual(t, *rp.scalingInfo, sproto.ScalingInfo{
 		DesiredNewInstances: 1,
+		DesiredNewSlots:     1,
 		Agents: map[string]sproto.AgentSummary{
 			"agent1": {Name: "agent1", IsIdle: false},
 			"agent2": {Name: "agent2", IsIdle: false},
@@ -134,6 +136,7 @@ func TestScalingInfoAgentSummary(t *testing.T) {
 	assert.Check(t, updated)
 	assert.DeepEqual(t, *rp.scalingInfo, sproto.ScalingInfo{
 		DesiredNewInstances: 1,
+		DesiredNewSlots:     1,
 		Agents: map[string]sproto.AgentSummary{
 			"agent1": {Name: "agent1", IsIdle: false},
 			"agent2": {Name: "agent2", IsIdle: false},
@@ -149,6 +152,7 @@ func TestScalingInfoAgentSummary(t *testing.T) {
 	assert.Check(t, updated)
 	assert.DeepEqual(t, *rp.scalingInfo, sproto.ScalingInfo{
 		DesiredNewInstances: 1,
+		DesiredNewSlots:     1,
 		Agents: map[string]sproto.AgentSummary{
 			"agent2": {Name: "agent2", IsIdle: false},
 			"agent3": {Name: "agent3", IsIdle: true},
@@ -170,6 +174,7 @@ func TestScalingInfoAgentSummary(t *testing.T) {
 	assert.Check(t, updated)
 	assert.DeepEqual(t, *rp.scalingInfo, sproto.ScalingInfo{
 		DesiredNewInstances: 1,
+		DesiredNewSlots:     1,
 		Agents: map[string]sproto.AgentSummary{
 			"agent2": {Name: "agent2", IsIdle: false},
 			"agent3": {Name: "agent3", IsIdle: false},
@@ -211,3 +216,25 @@ func TestSettingGroupPriority(t *testing.T) {
 	assert.Equal(t, *rp.groups[groupRefOne].priority, updatedPriority)
 	assert.Equal(t, *rp.groups[groupRefTwo].priority, defaultPriority)
 }
+
+func TestReserveResourcesStartTime(t *testing.T) {
+	system := actor.NewSystem(t.Name())
+	agents := []*mockAgent{{id: "agent", slots: 1}}
+	tasks := []*mockTask{{id: "task", slotsNeeded: 1}}
+	config := &ResourcePoolConfig{
+		PoolName:  "pool",
+		Scheduler: &SchedulerConfig{FairShare: &FairShareSchedulerConfig{}, FittingPolicy: best},
+	}
+	rp := NewResourcePool(config, nil, MakeScheduler(config.Scheduler), BestFit)
+	rp.taskList, rp.groups, rp.agents = setupSchedulerStates(t, system, tasks, nil, agents)
+
+	// Allocations start at the time of the clock of the resource pool, e.g., the simulated clock of
+	// the scheduler simulator.
+	now := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+	rp.now = func() time.Time { return now }
+	req, ok := rp.taskList.GetTaskByID(tasks[0].id)
+	assert.Assert(t, ok)
+	allocated := rp.reserveResources(req)
+	assert.Assert(t, allocated != nil)
+	assert.Equal(t, allocated.StartTime, now)
+}
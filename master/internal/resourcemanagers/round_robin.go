@@ -25,6 +25,7 @@ func roundRobinSchedule(
 	agents map[*actor.Ref]*agentState,
 	fittingMethod SoftConstraint,
 ) ([]*sproto.AllocateRequest, []*actor.Ref) {
+	taskList.ClearPendingReasons()
 	var states []*groupState
 	groupMapping := make(map[*group]*groupState)
 	for it := taskList.iterator(); it.next(); {
@@ -70,5 +71,6 @@ func roundRobinSchedule(
 		states = filtered
 	}
 
+	recordPendingReasons(taskList, newPendingExplainer(agents, fittingMethod), toAllocate)
 	return toAllocate, make([]*actor.Ref, 0)
 }
@@ -1,51 +1,76 @@
 package resourcemanagers
 
-// calculateDesiredNewAgentNum calculates the new instances based on pending tasks and
-// slots per instance.
+// calculateDesiredNewAgentNum calculates the new instances based on pending tasks and the slots
+// per instance of each instance type that the provisioner may launch, in the order of
+// preference. The slots of a pending task count toward the first instance type it fits.
 func calculateDesiredNewAgentNum(
-	taskList *taskList, slotsPerAgent int, maxZeroSlotTasksPerAgent int,
+	taskList *taskList, instanceSlots []int, maxZeroSlotTasksPerAgent int,
 ) int {
-	slotSum := 0
-	allTasks := 0
+	slotSums, _ := pendingSlotsByInstanceType(taskList, instanceSlots)
+
+	numAgentByZeroSlot := calculateDesiredNewZeroSlotAgentNum(
+		taskList, instanceSlots, maxZeroSlotTasksPerAgent,
+	)
+	numAgentBySlot := 0
+	for i, slotSum := range slotSums {
+		if slotSum > 0 {
+			numAgentBySlot += (slotSum + instanceSlots[i] - 1) / instanceSlots[i]
+		}
+	}
+	return max(numAgentByZeroSlot, numAgentBySlot)
+}
+
+// calculateDesiredNewZeroSlotAgentNum calculates the new instances that pending zero-slot tasks
+// need.
+func calculateDesiredNewZeroSlotAgentNum(
+	taskList *taskList, instanceSlots []int, maxZeroSlotTasksPerAgent int,
+) int {
+	_, zeroSlotTasks := pendingSlotsByInstanceType(taskList, instanceSlots)
+	if zeroSlotTasks == 0 || maxZeroSlotTasksPerAgent == 0 {
+		return 0
+	}
+	return (zeroSlotTasks + maxZeroSlotTasksPerAgent - 1) / maxZeroSlotTasksPerAgent
+}
+
+// calculateDesiredNewSlots calculates the slots that pending tasks need on new instances of any
+// of the instance types that the provisioner may launch.
+func calculateDesiredNewSlots(taskList *taskList, instanceSlots []int) int {
+	slotSums, _ := pendingSlotsByInstanceType(taskList, instanceSlots)
+	desiredSlots := 0
+	for _, slotSum := range slotSums {
+		desiredSlots += slotSum
+	}
+	return desiredSlots
+}
+
+// pendingSlotsByInstanceType sums the slots of the pending tasks by the first instance type each
+// task fits and counts the pending zero-slot tasks.
+func pendingSlotsByInstanceType(taskList *taskList, instanceSlots []int) ([]int, int) {
+	slotSums := make([]int, len(instanceSlots))
 	zeroSlotTasks := 0
 	for it := taskList.iterator(); it.next(); {
 		// TODO(DET-4035): This code is duplicated from the fitting functions in the
 		//    scheduler. To determine is a task is schedulable, we would ideally interface
 		//    with the scheduler in some way and not duplicate this logic.
+		slotsNeeded := it.value().SlotsNeeded
+		if it.value().SlotFraction > 0 {
+			// A fraction of a slot needs an instance with at least one slot.
+			slotsNeeded = 1
+		}
 		switch {
 		case taskList.GetAllocations(it.value().TaskActor) != nil:
 			// If a task is already allocated, skip it.
 			continue
-		case it.value().SlotsNeeded == 0:
+		case slotsNeeded == 0:
 			zeroSlotTasks++
-			allTasks++
-		case slotsPerAgent == 0:
-			continue
-		case it.value().SlotsNeeded <= slotsPerAgent:
-			slotSum += it.value().SlotsNeeded
-			allTasks++
-		case it.value().SlotsNeeded%slotsPerAgent == 0:
-			slotSum += it.value().SlotsNeeded
-			allTasks++
+		default:
+			for i, slots := range instanceSlots {
+				if slots > 0 && (slotsNeeded <= slots || slotsNeeded%slots == 0) {
+					slotSums[i] += slotsNeeded
+					break
+				}
+			}
 		}
 	}
-
-	numAgentByZeroSlot, numAgentBySlot := 0, 0
-	switch {
-	case zeroSlotTasks == 0:
-		numAgentByZeroSlot = 0
-	case maxZeroSlotTasksPerAgent == 0:
-		numAgentByZeroSlot = 0
-	default:
-		numAgentByZeroSlot = (zeroSlotTasks + maxZeroSlotTasksPerAgent - 1) / maxZeroSlotTasksPerAgent
-	}
-	switch {
-	case slotSum == 0:
-		numAgentBySlot = 0
-	case slotsPerAgent == 0:
-		numAgentBySlot = 0
-	default:
-		numAgentBySlot = (slotSum + slotsPerAgent - 1) / slotsPerAgent
-	}
-	return max(numAgentByZeroSlot, numAgentBySlot)
+	return slotSums, zeroSlotTasks
 }
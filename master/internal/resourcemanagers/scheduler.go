@@ -2,6 +2,7 @@ package resourcemanagers
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/actor"
@@ -17,6 +18,14 @@ type Scheduler interface {
 	Schedule(rp *ResourcePool) ([]*sproto.AllocateRequest, []*actor.Ref)
 }
 
+// timedScheduler is implemented by schedulers whose decisions can change with time alone, e.g.,
+// when running tasks reach their minimum runtime and become preemptible.
+type timedScheduler interface {
+	// rescheduleAt returns when the scheduler should run again even if nothing else changes, or the
+	// zero time if it need not.
+	rescheduleAt() time.Time
+}
+
 // MakeScheduler returns the corresponding scheduler implementation.
 func MakeScheduler(config *SchedulerConfig) Scheduler {
 	switch config.GetType() {
@@ -26,6 +35,8 @@ func MakeScheduler(config *SchedulerConfig) Scheduler {
 		return NewFairShareScheduler()
 	case roundRobinScheduling:
 		return NewRoundRobinScheduler()
+	case drfScheduling:
+		return NewDRFScheduler()
 	default:
 		panic(fmt.Sprintf("invalid scheduler: %s", config.GetType()))
 	}
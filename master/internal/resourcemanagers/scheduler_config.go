@@ -14,6 +14,7 @@ const (
 	fairShareScheduling  = "fair_share"
 	priorityScheduling   = "priority"
 	roundRobinScheduling = "round_robin"
+	drfScheduling        = "drf"
 
 	best             = "best"
 	worst            = "worst"
@@ -33,6 +34,7 @@ type SchedulerConfig struct {
 	FairShare     *FairShareSchedulerConfig  `union:"type,fair_share" json:"-"`
 	Priority      *PrioritySchedulerConfig   `union:"type,priority" json:"-"`
 	RoundRobin    *RoundRobinSchedulerConfig `union:"type,round_robin" json:"-"`
+	DRF           *DRFSchedulerConfig        `union:"type,drf" json:"-"`
 	FittingPolicy string                     `json:"fitting_policy"`
 }
 
@@ -53,7 +55,7 @@ func (s *SchedulerConfig) UnmarshalJSON(data []byte) error {
 	}
 
 	// Fill in the default
-	if s.FairShare == nil && s.Priority == nil && s.RoundRobin == nil {
+	if s.FairShare == nil && s.Priority == nil && s.RoundRobin == nil && s.DRF == nil {
 		s.FairShare = &FairShareSchedulerConfig{}
 	}
 	if s.Priority != nil && s.Priority.DefaultPriority == nil {
@@ -85,6 +87,8 @@ func (s *SchedulerConfig) GetType() string {
 		return priorityScheduling
 	case s.RoundRobin != nil:
 		return roundRobinScheduling
+	case s.DRF != nil:
+		return drfScheduling
 	default:
 		panic("neither scheduler type configured")
 	}
@@ -97,12 +101,36 @@ type FairShareSchedulerConfig struct{}
 type PrioritySchedulerConfig struct {
 	Preemption      bool `json:"preemption"`
 	DefaultPriority *int `json:"default_priority"`
+	// MinRuntime is how long a task keeps its resources before it can be preempted.
+	MinRuntime model.Duration `json:"min_runtime"`
+	// PreemptionBudget limits how often the tasks of a group can be preempted.
+	PreemptionBudget *PreemptionBudgetConfig `json:"preemption_budget"`
+}
+
+// PreemptionBudgetConfig holds the maximum number of tasks of a group that the priority scheduler
+// can preempt within a sliding window of time.
+type PreemptionBudgetConfig struct {
+	MaxPreemptions int            `json:"max_preemptions"`
+	Window         model.Duration `json:"window"`
 }
 
 // RoundRobinSchedulerConfig holds the configurations for the round robing scheduler.
 type RoundRobinSchedulerConfig struct{}
 
+// DRFSchedulerConfig holds the configurations for the dominant resource fairness scheduler.
+type DRFSchedulerConfig struct{}
+
 // Validate implements the check.Validatable interface.
 func (p PrioritySchedulerConfig) Validate() []error {
-	return model.ValidatePrioritySetting(p.DefaultPriority)
+	return append(model.ValidatePrioritySetting(p.DefaultPriority),
+		check.GreaterThanOrEqualTo(int64(p.MinRuntime), int64(0), "min_runtime must be non-negative"),
+	)
+}
+
+// Validate implements the check.Validatable interface.
+func (p PreemptionBudgetConfig) Validate() []error {
+	return []error{
+		check.GreaterThanOrEqualTo(p.MaxPreemptions, 0, "max_preemptions must be non-negative"),
+		check.GreaterThan(int64(p.Window), int64(0), "window must be positive"),
+	}
 }
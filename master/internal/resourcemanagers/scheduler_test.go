@@ -2,6 +2,7 @@ package resourcemanagers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/model"
 
@@ -52,12 +53,18 @@ type mockTask struct {
 
 	id               model.AllocationID
 	group            *mockGroup
+	username         string
 	slotsNeeded      int
+	slotFraction     float64
+	cpusNeeded       float64
+	memoryNeeded     int64
 	nonPreemptible   bool
 	label            string
+	agentSelectors   model.AgentSelectorsConfig
 	resourcePool     string
 	allocatedAgent   *mockAgent
 	containerStarted bool
+	startTime        time.Time
 }
 
 func (t *mockTask) Receive(ctx *actor.Context) error {
@@ -66,13 +73,18 @@ func (t *mockTask) Receive(ctx *actor.Context) error {
 	case actor.PostStop:
 	case SendRequestResourcesToResourceManager:
 		task := sproto.AllocateRequest{
-			AllocationID: t.id,
-			Name:         string(t.id),
-			SlotsNeeded:  t.slotsNeeded,
-			Preemptible:  !t.nonPreemptible,
-			Label:        t.label,
-			ResourcePool: t.resourcePool,
-			TaskActor:    ctx.Self(),
+			AllocationID:   t.id,
+			Name:           string(t.id),
+			Username:       t.username,
+			SlotsNeeded:    t.slotsNeeded,
+			SlotFraction:   t.slotFraction,
+			CPUsNeeded:     t.cpusNeeded,
+			MemoryNeeded:   t.memoryNeeded,
+			Preemptible:    !t.nonPreemptible,
+			Label:          t.label,
+			AgentSelectors: t.agentSelectors,
+			ResourcePool:   t.resourcePool,
+			TaskActor:      ctx.Self(),
 		}
 		if t.group == nil {
 			task.Group = ctx.Self()
@@ -118,10 +130,13 @@ func (t *mockTask) Receive(ctx *actor.Context) error {
 type mockAgent struct {
 	id                    string
 	label                 string
+	labels                map[string]string
 	slots                 int
 	slotsUsed             int
 	maxZeroSlotContainers int
 	zeroSlotContainers    int
+	cpus                  float64
+	memory                int64
 }
 
 func newMockAgent(
@@ -315,10 +330,14 @@ func setupSchedulerStates(
 		agent := &agentState{
 			handler:               ref,
 			label:                 mockAgent.label,
+			labels:                mockAgent.labels,
 			devices:               make(map[device.Device]*cproto.ID),
 			zeroSlotContainers:    make(map[cproto.ID]bool),
 			maxZeroSlotContainers: mockAgent.maxZeroSlotContainers,
 			enabled:               true,
+			cpus:                  mockAgent.cpus,
+			memory:                mockAgent.memory,
+			hostResources:         make(map[cproto.ID]hostResources),
 		}
 		for i := 0; i < mockAgent.slots; i++ {
 			agent.devices[device.Device{ID: i}] = nil
@@ -350,11 +369,17 @@ func setupSchedulerStates(
 		groups[ref] = &group{handler: ref}
 
 		req := &sproto.AllocateRequest{
-			AllocationID: mockTask.id,
-			SlotsNeeded:  mockTask.slotsNeeded,
-			Label:        mockTask.label,
-			TaskActor:    ref,
-			Preemptible:  !mockTask.nonPreemptible,
+			AllocationID:   mockTask.id,
+			Name:           string(mockTask.id),
+			Username:       mockTask.username,
+			SlotsNeeded:    mockTask.slotsNeeded,
+			SlotFraction:   mockTask.slotFraction,
+			CPUsNeeded:     mockTask.cpusNeeded,
+			MemoryNeeded:   mockTask.memoryNeeded,
+			Label:          mockTask.label,
+			AgentSelectors: mockTask.agentSelectors,
+			TaskActor:      ref,
+			Preemptible:    !mockTask.nonPreemptible,
 		}
 		if mockTask.group == nil {
 			req.Group = ref
@@ -370,6 +395,7 @@ func setupSchedulerStates(
 			container := newContainer(req, agentState, req.SlotsNeeded)
 
 			devices := make([]device.Device, 0)
+			agentState.allocateHostResources(req.CPUsNeeded, req.MemoryNeeded, container.id)
 			if mockTask.containerStarted {
 				if mockTask.slotsNeeded == 0 {
 					agentState.zeroSlotContainers[container.id] = true
@@ -391,7 +417,8 @@ func setupSchedulerStates(
 			}
 
 			allocated := &sproto.ResourcesAllocated{
-				ID: req.AllocationID,
+				ID:        req.AllocationID,
+				StartTime: mockTask.startTime,
 				Reservations: []sproto.Reservation{
 					&containerReservation{
 						req:       req,
@@ -0,0 +1,491 @@
+package resourcemanagers
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Trace is a recording of the agents and tasks of a cluster that the scheduler simulator replays.
+type Trace struct {
+	Agents []TraceAgent `json:"agents"`
+	Tasks  []TraceTask  `json:"tasks"`
+}
+
+// TraceAgent is an agent that joins the cluster and possibly leaves it later.
+type TraceAgent struct {
+	ID           string     `json:"id" db:"id"`
+	ResourcePool string     `json:"resource_pool" db:"resource_pool"`
+	Label        string     `json:"label" db:"label"`
+	Slots        int        `json:"slots" db:"slots"`
+	JoinTime     time.Time  `json:"join_time" db:"join_time"`
+	LeaveTime    *time.Time `json:"leave_time,omitempty" db:"leave_time"`
+}
+
+// TraceTask is a task that is submitted to the cluster and runs for a fixed amount of time once
+// it is allocated resources. Tasks of the same group share the weight, priority and max slots
+// of the group.
+type TraceTask struct {
+	ID              string    `json:"id" db:"id"`
+	Group           string    `json:"group" db:"group"`
+	ResourcePool    string    `json:"resource_pool" db:"resource_pool"`
+	Label           string    `json:"label" db:"label"`
+	Slots           int       `json:"slots" db:"slots"`
+	Priority        *int      `json:"priority,omitempty" db:"priority"`
+	Weight          float64   `json:"weight" db:"weight"`
+	MaxSlots        *int      `json:"max_slots,omitempty" db:"max_slots"`
+	Preemptible     bool      `json:"preemptible" db:"preemptible"`
+	SubmitTime      time.Time `json:"submit_time" db:"submit_time"`
+	DurationSeconds float64   `json:"duration_seconds" db:"duration_seconds"`
+}
+
+// Validate implements the check.Validatable interface.
+func (t Trace) Validate() []error {
+	var errs []error
+	agents := make(map[string]bool)
+	for _, agent := range t.Agents {
+		errs = append(errs,
+			check.False(agents[agent.ID], "agent %q appears more than once", agent.ID),
+			check.GreaterThanOrEqualTo(agent.Slots, 0, "agent %q has negative slots", agent.ID),
+		)
+		if agent.LeaveTime != nil {
+			errs = append(errs, check.False(agent.LeaveTime.Before(agent.JoinTime),
+				"agent %q leaves before it joins", agent.ID))
+		}
+		agents[agent.ID] = true
+	}
+	tasks := make(map[string]bool)
+	for _, task := range t.Tasks {
+		errs = append(errs,
+			check.False(tasks[task.ID], "task %q appears more than once", task.ID),
+			check.GreaterThanOrEqualTo(task.Slots, 0, "task %q has negative slots", task.ID),
+			check.GreaterThan(task.DurationSeconds, float64(0),
+				"task %q must have a positive duration", task.ID),
+			check.GreaterThanOrEqualTo(task.Weight, float64(0),
+				"task %q has a negative weight", task.ID),
+		)
+		tasks[task.ID] = true
+	}
+	return errs
+}
+
+// SimulationConfig configures a replay of a trace by the scheduler simulator.
+type SimulationConfig struct {
+	// Scheduler is the scheduling policy and fitting policy to simulate.
+	Scheduler *SchedulerConfig
+	// ResourcePool restricts the simulation to the agents and tasks of a resource pool. All of
+	// them are simulated as a single resource pool if it is empty.
+	ResourcePool string
+	// MaxAuxContainersPerAgent is the number of zero-slot tasks each agent can run.
+	MaxAuxContainersPerAgent int
+	// PreemptionDelay is how long preempted tasks take to release their resources, e.g., for
+	// trials to checkpoint.
+	PreemptionDelay time.Duration
+}
+
+// SimulationReport summarizes the replay of a trace with a scheduling policy.
+type SimulationReport struct {
+	Scheduler     string `json:"scheduler"`
+	FittingPolicy string `json:"fitting_policy"`
+
+	Tasks int `json:"tasks"`
+	// Completed is the number of tasks that ran for their whole duration.
+	Completed int `json:"completed"`
+	// Unscheduled is the number of tasks that were never allocated resources.
+	Unscheduled int `json:"unscheduled"`
+	// Preemptions is the number of times the scheduler preempted a running task.
+	Preemptions int `json:"preemptions"`
+	// Interruptions is the number of times a task was stopped because its agent left.
+	Interruptions int `json:"interruptions"`
+
+	// The queueing delay of a task is the time from its submission to its first allocation.
+	MeanQueueingDelaySeconds float64 `json:"mean_queueing_delay_seconds"`
+	P50QueueingDelaySeconds  float64 `json:"p50_queueing_delay_seconds"`
+	P95QueueingDelaySeconds  float64 `json:"p95_queueing_delay_seconds"`
+	MaxQueueingDelaySeconds  float64 `json:"max_queueing_delay_seconds"`
+
+	// Utilization is the fraction of the slot-seconds of the agents that tasks used.
+	Utilization float64 `json:"utilization"`
+	// MakespanSeconds is the time from the first submission to the last completion.
+	MakespanSeconds float64 `json:"makespan_seconds"`
+}
+
+type simulationEventType int
+
+const (
+	agentJoined simulationEventType = iota
+	agentLeft
+	taskSubmitted
+	taskFinished
+	taskReleased
+	simulatedSchedulerTick
+)
+
+type simulationEvent struct {
+	time      time.Time
+	seq       int
+	eventType simulationEventType
+	agent     *TraceAgent
+	task      *simulatedTask
+	// run identifies the allocation of the task that the event applies to, so that the events
+	// of an allocation that was preempted or interrupted are ignored.
+	run int
+}
+
+type simulationEvents []*simulationEvent
+
+func (e simulationEvents) Len() int { return len(e) }
+func (e simulationEvents) Less(i, j int) bool {
+	if !e[i].time.Equal(e[j].time) {
+		return e[i].time.Before(e[j].time)
+	}
+	return e[i].seq < e[j].seq
+}
+func (e simulationEvents) Swap(i, j int)       { e[i], e[j] = e[j], e[i] }
+func (e *simulationEvents) Push(x interface{}) { *e = append(*e, x.(*simulationEvent)) }
+func (e *simulationEvents) Pop() interface{} {
+	old := *e
+	event := old[len(old)-1]
+	*e = old[:len(old)-1]
+	return event
+}
+
+// simulatedActor stands in for the agents, tasks and groups of a simulation, which the resource
+// pool only uses as identities.
+var simulatedActor = actor.ActorFunc(func(*actor.Context) error { return nil })
+
+type simulatedTask struct {
+	*TraceTask
+	handler   *actor.Ref
+	group     *actor.Ref
+	remaining time.Duration
+
+	run        int
+	running    bool
+	releasing  bool
+	startTime  time.Time
+	firstStart *time.Time
+}
+
+type simulator struct {
+	config  SimulationConfig
+	system  *actor.System
+	rp      *ResourcePool
+	events  simulationEvents
+	seq     int
+	now     time.Time
+	agents  map[string]*actor.Ref
+	tasks   map[*actor.Ref]*simulatedTask
+	order   []*simulatedTask
+	groups  map[string]*actor.Ref
+	nextRun map[time.Time]bool
+	report  SimulationReport
+
+	firstSubmit, lastFinish time.Time
+	usedSlotSeconds         float64
+	totalSlotSeconds        float64
+}
+
+// Simulate replays a trace through the scheduler and the resource pool logic on a simulated
+// clock. Tasks are scheduled whenever agents join or leave and tasks are submitted or finish.
+func Simulate(trace Trace, config SimulationConfig) (*SimulationReport, error) {
+	if err := check.Validate(trace); err != nil {
+		return nil, err
+	}
+	s := &simulator{
+		config: config,
+		system: actor.NewSystem("simulator"),
+		rp: NewResourcePool(
+			&ResourcePoolConfig{
+				PoolName:                 config.ResourcePool,
+				Scheduler:                config.Scheduler,
+				MaxAuxContainersPerAgent: config.MaxAuxContainersPerAgent,
+			},
+			nil,
+			MakeScheduler(config.Scheduler),
+			MakeFitFunction(config.Scheduler.FittingPolicy),
+		),
+		agents:  make(map[string]*actor.Ref),
+		tasks:   make(map[*actor.Ref]*simulatedTask),
+		groups:  make(map[string]*actor.Ref),
+		nextRun: make(map[time.Time]bool),
+		report: SimulationReport{
+			Scheduler:     config.Scheduler.GetType(),
+			FittingPolicy: config.Scheduler.FittingPolicy,
+		},
+	}
+	s.rp.now = func() time.Time { return s.now }
+	defer s.system.Ref.Stop()
+	for _, parent := range []string{"agents", "tasks", "groups"} {
+		s.system.MustActorOf(actor.Addr(parent), simulatedActor)
+	}
+
+	for i := range trace.Agents {
+		agent := &trace.Agents[i]
+		if config.ResourcePool != "" && agent.ResourcePool != config.ResourcePool {
+			continue
+		}
+		s.push(&simulationEvent{time: agent.JoinTime, eventType: agentJoined, agent: agent})
+		if agent.LeaveTime != nil {
+			s.push(&simulationEvent{time: *agent.LeaveTime, eventType: agentLeft, agent: agent})
+		}
+	}
+	for i := range trace.Tasks {
+		task := &trace.Tasks[i]
+		if config.ResourcePool != "" && task.ResourcePool != config.ResourcePool {
+			continue
+		}
+		s.report.Tasks++
+		s.push(&simulationEvent{
+			time:      task.SubmitTime,
+			eventType: taskSubmitted,
+			task: &simulatedTask{
+				TraceTask: task,
+				remaining: time.Duration(task.DurationSeconds * float64(time.Second)),
+			},
+		})
+	}
+
+	for s.events.Len() > 0 {
+		now := s.events[0].time
+		s.advance(now)
+		for s.events.Len() > 0 && s.events[0].time.Equal(now) {
+			if err := s.handle(heap.Pop(&s.events).(*simulationEvent)); err != nil {
+				return nil, err
+			}
+		}
+		s.schedule()
+	}
+
+	s.summarize()
+	return &s.report, nil
+}
+
+func (s *simulator) push(event *simulationEvent) {
+	event.seq = s.seq
+	s.seq++
+	heap.Push(&s.events, event)
+}
+
+// advance moves the simulated clock and accounts for the slots used in the meantime.
+func (s *simulator) advance(now time.Time) {
+	if !s.now.IsZero() {
+		elapsed := now.Sub(s.now).Seconds()
+		for _, agent := range s.rp.agents {
+			s.usedSlotSeconds += float64(agent.numUsedSlots()) * elapsed
+			s.totalSlotSeconds += float64(agent.numSlots()) * elapsed
+		}
+	}
+	s.now = now
+}
+
+func (s *simulator) handle(event *simulationEvent) error {
+	switch event.eventType {
+	case agentJoined:
+		address := actor.Addr("agents", event.agent.ID)
+		ref, ok := s.system.ActorOf(address, simulatedActor)
+		if !ok {
+			return fmt.Errorf("agent %s joins more than once", event.agent.ID)
+		}
+		state := newAgentState(
+			sproto.AddAgent{Agent: ref, Label: event.agent.Label}, s.config.MaxAuxContainersPerAgent)
+		for i := 0; i < event.agent.Slots; i++ {
+			state.devices[device.Device{ID: i, Type: device.GPU}] = nil
+		}
+		s.agents[event.agent.ID] = ref
+		s.rp.agents[ref] = state
+
+	case agentLeft:
+		ref := s.agents[event.agent.ID]
+		state := s.rp.agents[ref]
+		for _, task := range s.order {
+			if !task.running {
+				continue
+			}
+			for _, reservation := range s.rp.taskList.GetAllocations(task.handler).Reservations {
+				if reservation.(*containerReservation).agent == state {
+					s.report.Interruptions++
+					s.stop(task)
+					break
+				}
+			}
+		}
+		delete(s.rp.agents, ref)
+
+	case taskSubmitted:
+		task := event.task
+		if s.firstSubmit.IsZero() || task.SubmitTime.Before(s.firstSubmit) {
+			s.firstSubmit = task.SubmitTime
+		}
+		ref, ok := s.system.ActorOf(actor.Addr("tasks", task.ID), simulatedActor)
+		if !ok {
+			return fmt.Errorf("task %s is submitted more than once", task.ID)
+		}
+		task.handler = ref
+		task.group = ref
+		if task.Group != "" {
+			if task.group, ok = s.groups[task.Group]; !ok {
+				task.group = s.system.MustActorOf(actor.Addr("groups", task.Group), simulatedActor)
+				s.groups[task.Group] = task.group
+			}
+		}
+		group := s.rp.getOrCreateGroup(nil, task.group)
+		group.weight = task.Weight
+		group.maxSlots = task.MaxSlots
+		if task.Priority != nil {
+			group.priority = task.Priority
+		}
+		s.tasks[ref] = task
+		s.order = append(s.order, task)
+		s.submit(task)
+
+	case taskFinished:
+		task := event.task
+		if task.run != event.run || !task.running {
+			return nil
+		}
+		task.running = false
+		task.remaining = 0
+		s.rp.freeResources(task.handler)
+		s.report.Completed++
+		s.lastFinish = s.now
+
+	case taskReleased:
+		task := event.task
+		if task.run != event.run || !task.running {
+			return nil
+		}
+		s.stop(task)
+
+	case simulatedSchedulerTick:
+		delete(s.nextRun, event.time)
+	}
+	return nil
+}
+
+// submit requests resources for the remaining duration of a task.
+func (s *simulator) submit(task *simulatedTask) {
+	s.rp.taskList.AddTask(&sproto.AllocateRequest{
+		TaskID:       model.TaskID(task.ID),
+		AllocationID: model.AllocationID(uuid.New().String()),
+		Name:         task.ID,
+		Group:        task.group,
+		SlotsNeeded:  task.Slots,
+		Label:        task.Label,
+		Preemptible:  task.Preemptible,
+		TaskActor:    task.handler,
+		ResourcePool: s.config.ResourcePool,
+	})
+}
+
+// stop releases the resources of a running task and requests resources for the rest of it.
+func (s *simulator) stop(task *simulatedTask) {
+	task.running = false
+	task.releasing = false
+	task.remaining -= s.now.Sub(task.startTime)
+	s.rp.freeResources(task.handler)
+	if task.remaining > 0 {
+		s.submit(task)
+	}
+}
+
+// schedule runs a pass of the scheduler and, if it changed anything, another one after the cool
+// down of the resource pool, the same way the resource pool keeps scheduling while it changes.
+func (s *simulator) schedule() {
+	toAllocate, toRelease := s.rp.scheduler.Schedule(s.rp)
+	changed := false
+	for _, req := range toAllocate {
+		if s.rp.reserveResources(req) == nil {
+			continue
+		}
+		changed = true
+		task := s.tasks[req.TaskActor]
+		task.run++
+		task.running = true
+		task.startTime = s.now
+		if task.firstStart == nil {
+			start := s.now
+			task.firstStart = &start
+		}
+		s.push(&simulationEvent{
+			time: s.now.Add(task.remaining), eventType: taskFinished, task: task, run: task.run,
+		})
+	}
+	for _, handler := range toRelease {
+		task := s.tasks[handler]
+		if task == nil || !task.running || task.releasing {
+			continue
+		}
+		changed = true
+		task.releasing = true
+		s.report.Preemptions++
+		s.push(&simulationEvent{
+			time:      s.now.Add(s.config.PreemptionDelay),
+			eventType: taskReleased,
+			task:      task,
+			run:       task.run,
+		})
+	}
+	if changed {
+		s.scheduleAt(s.now.Add(actionCoolDown))
+	}
+	// Schedulers whose decisions change with time alone, e.g., as tasks reach their minimum
+	// runtime, run again when they ask to, the same way the resource pool checks on every tick.
+	if timed, ok := s.rp.scheduler.(timedScheduler); ok {
+		if at := timed.rescheduleAt(); at.After(s.now) {
+			s.scheduleAt(at)
+		}
+	}
+}
+
+// scheduleAt runs a pass of the scheduler at the time, unless one is already due then.
+func (s *simulator) scheduleAt(at time.Time) {
+	if !s.nextRun[at] {
+		s.nextRun[at] = true
+		s.push(&simulationEvent{time: at, eventType: simulatedSchedulerTick})
+	}
+}
+
+func (s *simulator) summarize() {
+	var delays []float64
+	s.report.Unscheduled = s.report.Tasks - len(s.order)
+	for _, task := range s.order {
+		if task.firstStart == nil {
+			s.report.Unscheduled++
+			continue
+		}
+		delays = append(delays, task.firstStart.Sub(task.SubmitTime).Seconds())
+	}
+
+	if len(delays) > 0 {
+		sort.Float64s(delays)
+		total := 0.0
+		for _, delay := range delays {
+			total += delay
+		}
+		s.report.MeanQueueingDelaySeconds = total / float64(len(delays))
+		s.report.P50QueueingDelaySeconds = percentile(delays, 0.5)
+		s.report.P95QueueingDelaySeconds = percentile(delays, 0.95)
+		s.report.MaxQueueingDelaySeconds = delays[len(delays)-1]
+	}
+	if s.totalSlotSeconds > 0 {
+		s.report.Utilization = s.usedSlotSeconds / s.totalSlotSeconds
+	}
+	if !s.lastFinish.IsZero() {
+		s.report.MakespanSeconds = s.lastFinish.Sub(s.firstSubmit).Seconds()
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	return sorted[int(p*float64(len(sorted)-1)+0.5)]
+}
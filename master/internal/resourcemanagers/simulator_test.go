@@ -0,0 +1,168 @@
+package resourcemanagers
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestSimulateQueueing(t *testing.T) {
+	start := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+	trace := Trace{
+		Agents: []TraceAgent{{ID: "agent1", Slots: 4, JoinTime: start}},
+		Tasks: []TraceTask{
+			{ID: "task1", Slots: 4, Weight: 1, SubmitTime: start, DurationSeconds: 60},
+			{ID: "task2", Slots: 4, Weight: 1, SubmitTime: start, DurationSeconds: 60},
+			{ID: "task3", Slots: 8, Weight: 1, SubmitTime: start, DurationSeconds: 60},
+		},
+	}
+
+	report, err := Simulate(trace, SimulationConfig{
+		Scheduler:                DefaultSchedulerConfig(),
+		MaxAuxContainersPerAgent: 100,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, report.Scheduler, fairShareScheduling)
+	assert.Equal(t, report.Tasks, 3)
+	assert.Equal(t, report.Completed, 2)
+	assert.Equal(t, report.Unscheduled, 1)
+	assert.Equal(t, report.Preemptions, 0)
+	assert.Equal(t, report.MeanQueueingDelaySeconds, float64(30))
+	assert.Equal(t, report.MaxQueueingDelaySeconds, float64(60))
+	assert.Equal(t, report.MakespanSeconds, float64(120))
+	assert.Equal(t, report.Utilization, float64(1))
+}
+
+func TestSimulatePreemption(t *testing.T) {
+	start := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+	lowerPriority, higherPriority := 50, 40
+	trace := Trace{
+		Agents: []TraceAgent{{ID: "agent1", Slots: 4, JoinTime: start}},
+		Tasks: []TraceTask{
+			{
+				ID: "low", Slots: 4, Weight: 1, Priority: &lowerPriority, Preemptible: true,
+				SubmitTime: start, DurationSeconds: 100,
+			},
+			{
+				ID: "high", Slots: 4, Weight: 1, Priority: &higherPriority,
+				SubmitTime: start.Add(40 * time.Second), DurationSeconds: 50,
+			},
+		},
+	}
+
+	defaultPriority := defaultSchedulingPriority
+	report, err := Simulate(trace, SimulationConfig{
+		Scheduler: &SchedulerConfig{
+			Priority: &PrioritySchedulerConfig{
+				Preemption: true, DefaultPriority: &defaultPriority,
+			},
+			FittingPolicy: best,
+		},
+		MaxAuxContainersPerAgent: 100,
+		PreemptionDelay:          10 * time.Second,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, report.Completed, 2)
+	assert.Equal(t, report.Preemptions, 1)
+	// The lower priority task runs for 50 seconds before it releases its slots, waits for the
+	// higher priority task to finish and runs for its remaining 50 seconds.
+	assert.Equal(t, report.MakespanSeconds, float64(50+50+50))
+	assert.Equal(t, report.MaxQueueingDelaySeconds, float64(10))
+}
+
+func TestSimulateMinRuntime(t *testing.T) {
+	start := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+	lowerPriority, higherPriority := 50, 40
+	trace := Trace{
+		Agents: []TraceAgent{{ID: "agent1", Slots: 4, JoinTime: start}},
+		Tasks: []TraceTask{
+			{
+				ID: "low", Slots: 4, Weight: 1, Priority: &lowerPriority, Preemptible: true,
+				SubmitTime: start, DurationSeconds: 100,
+			},
+			{
+				ID: "high", Slots: 4, Weight: 1, Priority: &higherPriority,
+				SubmitTime: start.Add(40 * time.Second), DurationSeconds: 50,
+			},
+		},
+	}
+
+	defaultPriority := defaultSchedulingPriority
+	report, err := Simulate(trace, SimulationConfig{
+		Scheduler: &SchedulerConfig{
+			Priority: &PrioritySchedulerConfig{
+				Preemption:      true,
+				DefaultPriority: &defaultPriority,
+				MinRuntime:      model.Duration(60 * time.Second),
+			},
+			FittingPolicy: best,
+		},
+		MaxAuxContainersPerAgent: 100,
+		PreemptionDelay:          10 * time.Second,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, report.Completed, 2)
+	assert.Equal(t, report.Preemptions, 1)
+	// The lower priority task is only preempted once it has run for the minimum runtime of 60
+	// seconds on the simulated clock, and releases its slots 10 seconds later.
+	assert.Equal(t, report.MaxQueueingDelaySeconds, float64(30))
+	assert.Equal(t, report.MakespanSeconds, float64(70+50+30))
+}
+
+func TestSimulatePreemptionBudget(t *testing.T) {
+	start := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+	lowerPriority, higherPriority := 50, 40
+	low := func(id string) TraceTask {
+		return TraceTask{
+			ID: id, Group: "low", Slots: 2, Weight: 1, Priority: &lowerPriority,
+			Preemptible: true, SubmitTime: start, DurationSeconds: 1000,
+		}
+	}
+	high := func(id string, submit time.Duration) TraceTask {
+		return TraceTask{
+			ID: id, Group: "high", Slots: 2, Weight: 1, Priority: &higherPriority,
+			SubmitTime: start.Add(submit), DurationSeconds: 50,
+		}
+	}
+	trace := Trace{
+		Agents: []TraceAgent{{ID: "agent1", Slots: 4, JoinTime: start}},
+		Tasks: []TraceTask{
+			low("low1"), low("low2"), high("high1", 10*time.Second), high("high2", 20*time.Second),
+		},
+	}
+
+	defaultPriority := defaultSchedulingPriority
+	report, err := Simulate(trace, SimulationConfig{
+		Scheduler: &SchedulerConfig{
+			Priority: &PrioritySchedulerConfig{
+				Preemption:      true,
+				DefaultPriority: &defaultPriority,
+				PreemptionBudget: &PreemptionBudgetConfig{
+					MaxPreemptions: 1, Window: model.Duration(100 * time.Second),
+				},
+			},
+			FittingPolicy: best,
+		},
+		MaxAuxContainersPerAgent: 100,
+		PreemptionDelay:          10 * time.Second,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, report.Completed, 4)
+	// Only one task of the low priority group is preempted within the window, so the second high
+	// priority task waits for the first one to finish.
+	assert.Equal(t, report.Preemptions, 1)
+	assert.Equal(t, report.MaxQueueingDelaySeconds, float64(50))
+}
+
+func TestTraceValidation(t *testing.T) {
+	start := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+	_, err := Simulate(Trace{
+		Tasks: []TraceTask{
+			{ID: "task1", Slots: 1, SubmitTime: start, DurationSeconds: 0},
+		},
+	}, SimulationConfig{Scheduler: DefaultSchedulerConfig()})
+	assert.ErrorContains(t, err, `task "task1" must have a positive duration`)
+}
@@ -19,14 +19,17 @@ type TaskSummary struct {
 	RegisteredTime time.Time                 `json:"registered_time"`
 	ResourcePool   string                    `json:"resource_pool"`
 	SlotsNeeded    int                       `json:"slots_needed"`
+	SlotFraction   float64                   `json:"slot_fraction,omitempty"`
 	Containers     []sproto.ContainerSummary `json:"containers"`
 	SchedulerType  string                    `json:"scheduler_type"`
 	Priority       *int                      `json:"priority"`
+	PendingReason  *sproto.PendingReason     `json:"pending_reason"`
 }
 
 func newTaskSummary(
 	request *sproto.AllocateRequest,
 	allocated *sproto.ResourcesAllocated,
+	pendingReason *sproto.PendingReason,
 	groups map[*actor.Ref]*group,
 	schedulerType string,
 ) TaskSummary {
@@ -44,9 +47,13 @@ func newTaskSummary(
 		RegisteredTime: request.TaskActor.RegisteredTime(),
 		ResourcePool:   request.ResourcePool,
 		SlotsNeeded:    request.SlotsNeeded,
+		SlotFraction:   request.SlotFraction,
 		Containers:     containerSummaries,
 		SchedulerType:  schedulerType,
 	}
+	if allocated == nil {
+		summary.PendingReason = pendingReason
+	}
 
 	if group, ok := groups[request.Group]; ok {
 		summary.Priority = group.priority
@@ -79,7 +86,9 @@ func getTaskSummary(
 	schedulerType string,
 ) *TaskSummary {
 	if req, ok := reqList.GetTaskByID(id); ok {
-		summary := newTaskSummary(req, reqList.GetAllocations(req.TaskActor), groups, schedulerType)
+		summary := newTaskSummary(
+			req, reqList.GetAllocations(req.TaskActor), reqList.GetPendingReason(req.TaskActor),
+			groups, schedulerType)
 		return &summary
 	}
 	return nil
@@ -94,7 +103,8 @@ func getTaskSummaries(
 	for it := reqList.iterator(); it.next(); {
 		req := it.value()
 		ret[req.AllocationID] = newTaskSummary(
-			req, reqList.GetAllocations(req.TaskActor), groups, schedulerType)
+			req, reqList.GetAllocations(req.TaskActor), reqList.GetPendingReason(req.TaskActor),
+			groups, schedulerType)
 	}
 	return ret
 }
@@ -2,6 +2,7 @@ package resourcemanagers
 
 import (
 	"strings"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/model"
 
@@ -17,14 +18,25 @@ type taskList struct {
 	taskByHandler map[*actor.Ref]*sproto.AllocateRequest
 	taskByID      map[model.AllocationID]*sproto.AllocateRequest
 	allocations   map[*actor.Ref]*sproto.ResourcesAllocated
+	// addedTimes holds when each task was added, to measure how long tasks wait for resources.
+	addedTimes map[*actor.Ref]time.Time
+
+	// pendingReasons holds why the scheduler did not allocate resources to each pending task on
+	// its last pass.
+	pendingReasons map[*actor.Ref]sproto.PendingReason
+	// preemptionReasons holds why the scheduler released each task it released on its last pass.
+	preemptionReasons map[*actor.Ref]sproto.PreemptionReason
 }
 
 func newTaskList() *taskList {
 	return &taskList{
-		taskByTime:    treeset.NewWith(taskComparator),
-		taskByHandler: make(map[*actor.Ref]*sproto.AllocateRequest),
-		taskByID:      make(map[model.AllocationID]*sproto.AllocateRequest),
-		allocations:   make(map[*actor.Ref]*sproto.ResourcesAllocated),
+		taskByTime:        treeset.NewWith(taskComparator),
+		taskByHandler:     make(map[*actor.Ref]*sproto.AllocateRequest),
+		taskByID:          make(map[model.AllocationID]*sproto.AllocateRequest),
+		allocations:       make(map[*actor.Ref]*sproto.ResourcesAllocated),
+		addedTimes:        make(map[*actor.Ref]time.Time),
+		pendingReasons:    make(map[*actor.Ref]sproto.PendingReason),
+		preemptionReasons: make(map[*actor.Ref]sproto.PreemptionReason),
 	}
 }
 
@@ -54,6 +66,7 @@ func (l *taskList) AddTask(req *sproto.AllocateRequest) bool {
 	l.taskByTime.Add(req)
 	l.taskByHandler[req.TaskActor] = req
 	l.taskByID[req.AllocationID] = req
+	l.addedTimes[req.TaskActor] = time.Now()
 	return true
 }
 
@@ -67,21 +80,60 @@ func (l *taskList) RemoveTaskByHandler(handler *actor.Ref) *sproto.AllocateReque
 	delete(l.taskByHandler, handler)
 	delete(l.taskByID, req.AllocationID)
 	delete(l.allocations, handler)
+	delete(l.addedTimes, handler)
+	delete(l.pendingReasons, handler)
+	delete(l.preemptionReasons, handler)
 	return req
 }
 
+// GetAddedTime returns when the task was added to the list.
+func (l *taskList) GetAddedTime(handler *actor.Ref) time.Time {
+	return l.addedTimes[handler]
+}
+
 func (l *taskList) GetAllocations(handler *actor.Ref) *sproto.ResourcesAllocated {
 	return l.allocations[handler]
 }
 
 func (l *taskList) SetAllocations(handler *actor.Ref, assigned *sproto.ResourcesAllocated) {
 	l.allocations[handler] = assigned
+	delete(l.pendingReasons, handler)
 }
 
 func (l *taskList) RemoveAllocations(handler *actor.Ref) {
 	delete(l.allocations, handler)
 }
 
+func (l *taskList) GetPendingReason(handler *actor.Ref) *sproto.PendingReason {
+	if reason, ok := l.pendingReasons[handler]; ok {
+		return &reason
+	}
+	return nil
+}
+
+func (l *taskList) SetPendingReason(handler *actor.Ref, reason sproto.PendingReason) {
+	l.pendingReasons[handler] = reason
+}
+
+func (l *taskList) ClearPendingReasons() {
+	l.pendingReasons = make(map[*actor.Ref]sproto.PendingReason)
+}
+
+func (l *taskList) GetPreemptionReason(handler *actor.Ref) *sproto.PreemptionReason {
+	if reason, ok := l.preemptionReasons[handler]; ok {
+		return &reason
+	}
+	return nil
+}
+
+func (l *taskList) SetPreemptionReason(handler *actor.Ref, reason sproto.PreemptionReason) {
+	l.preemptionReasons[handler] = reason
+}
+
+func (l *taskList) ClearPreemptionReasons() {
+	l.preemptionReasons = make(map[*actor.Ref]sproto.PreemptionReason)
+}
+
 type taskIterator struct{ it treeset.Iterator }
 
 func (i *taskIterator) next() bool {
@@ -3,11 +3,13 @@ package sproto
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 // DeviceID is the unique identifier for a device in the cluster.
@@ -20,8 +22,10 @@ type DeviceID struct {
 type (
 	// AddAgent adds the agent to the cluster.
 	AddAgent struct {
-		Agent *actor.Ref
-		Label string
+		Agent    *actor.Ref
+		Label    string
+		Labels   map[string]string
+		HostInfo aproto.HostInfo
 	}
 	// AddDevice makes the device immediately available for scheduling.
 	AddDevice struct {
@@ -71,14 +75,24 @@ type AgentSummary struct {
 // ScalingInfo describes the information that is needed for scaling.
 type ScalingInfo struct {
 	DesiredNewInstances int
-	Agents              map[string]AgentSummary
+	// DesiredNewSlots is the number of slots that pending tasks need on new instances, which the
+	// provisioner uses when it launches instance types with different numbers of slots.
+	DesiredNewSlots int
+	// DesiredNewZeroSlotInstances is the number of new instances that pending zero-slot tasks
+	// need, which the provisioner launches in addition to the slots.
+	DesiredNewZeroSlotInstances int
+	Agents                      map[string]AgentSummary
 }
 
-// Update updates its desired new instance number and the agent summaries.
-func (s *ScalingInfo) Update(desiredNewInstanceNum int, agents map[string]AgentSummary) bool {
+// Update updates its desired new instance and slot numbers and the agent summaries.
+func (s *ScalingInfo) Update(
+	desiredNewInstanceNum, desiredNewSlots, desiredNewZeroSlotInstanceNum int,
+	agents map[string]AgentSummary,
+) bool {
 	updated := false
 
-	if desiredNewInstanceNum != s.DesiredNewInstances {
+	if desiredNewInstanceNum != s.DesiredNewInstances || desiredNewSlots != s.DesiredNewSlots ||
+		desiredNewZeroSlotInstanceNum != s.DesiredNewZeroSlotInstances {
 		updated = true
 	}
 
@@ -95,12 +109,27 @@ func (s *ScalingInfo) Update(desiredNewInstanceNum int, agents map[string]AgentS
 
 	if updated {
 		s.DesiredNewInstances = desiredNewInstanceNum
+		s.DesiredNewSlots = desiredNewSlots
+		s.DesiredNewZeroSlotInstances = desiredNewZeroSlotInstanceNum
 		s.Agents = agents
 	}
 
 	return updated
 }
 
+// ProvisionedInstances describes the instances that the provisioner of a resource pool has
+// running at a time, so that their lifetimes are recorded for cost accounting.
+type ProvisionedInstances struct {
+	ResourcePool string
+	Instances    []model.ProvisionedInstance
+	Time         time.Time
+}
+
+// SetReservations replaces the reservations that the resource pools hold slots for.
+type SetReservations struct {
+	Reservations []model.Reservation
+}
+
 // Constant protocol for the reasons of terminating an instance.
 const (
 	// TerminateStoppedInstances represents the reason for terminating stopped instances.
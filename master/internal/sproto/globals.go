@@ -17,6 +17,8 @@ var (
 	AgentsAddr = actor.Addr("agents")
 	// PodsAddr is the actor address of the pods.
 	PodsAddr = actor.Addr("pods")
+	// InstanceRecorderAddr is the actor address of the recorder of provisioned instances.
+	InstanceRecorderAddr = actor.Addr("instance-recorder")
 )
 
 type (
@@ -37,6 +39,17 @@ type (
 	GetDefaultAuxResourcePoolResponse struct {
 		PoolName string
 	}
+
+	// HasResourcePoolRequest is a message asking the kubernetes resource manager whether it
+	// manages the given resource pool.
+	HasResourcePoolRequest struct {
+		PoolName string
+	}
+
+	// HasResourcePoolResponse is the response to HasResourcePoolRequest.
+	HasResourcePoolResponse struct {
+		Exists bool
+	}
 )
 
 // GetRM returns the resource manager router.
@@ -75,15 +88,22 @@ func GetCurrentRM(system *actor.System) *actor.Ref {
 }
 
 // ValidateResourcePool validates if the resource pool exists when using the agent resource manager,
-// or if it's the dummy kubernetes pool.
+// or if it's one of the kubernetes resource pools.
 func ValidateResourcePool(system *actor.System, name string) error {
 	if name == "" || UseAgentRM(system) && GetRP(system, name) != nil ||
-		UseK8sRM(system) && name == "kubernetes" {
+		UseK8sRM(system) && hasK8sResourcePool(system, name) {
 		return nil
 	}
 	return errors.Errorf("cannot find resource pool: %s", name)
 }
 
+func hasK8sResourcePool(system *actor.System, name string) bool {
+	resp, ok := system.Ask(
+		system.Get(K8sRMAddr), HasResourcePoolRequest{PoolName: name},
+	).Get().(HasResourcePoolResponse)
+	return ok && resp.Exists
+}
+
 // GetResourcePool returns the validated resource pool name based on the value set in
 // the configuration.
 func GetResourcePool(
@@ -14,6 +14,10 @@ type (
 		Spec      tasks.TaskSpec
 		Slots     int
 		Rank      int
+
+		ResourcePool string
+		// Priority is the priority of the group of the task, if one was set.
+		Priority *int
 	}
 	// KillTaskPod notifies the pods actor to kill a pod.
 	KillTaskPod struct {
@@ -1,7 +1,64 @@
 package sproto
 
+import "github.com/determined-ai/determined/proto/pkg/taskv1"
+
 // FittingRequirements allow tasks to specify requirements for their placement.
 type FittingRequirements struct {
 	// SingleAgent specifies that the task must be located within a single agent.
 	SingleAgent bool
 }
+
+// PendingReasonCode identifies why a scheduler did not allocate resources to a task.
+type PendingReasonCode string
+
+// Constant protocol for the reasons that a task is pending.
+const (
+	// PendingNoMatchingAgents means no agent satisfies the label and agent selectors of the task.
+	PendingNoMatchingAgents PendingReasonCode = "NO_MATCHING_AGENTS"
+	// PendingInsufficientAgentCapacity means the task does not fit the agents even if they are idle.
+	PendingInsufficientAgentCapacity PendingReasonCode = "INSUFFICIENT_AGENT_CAPACITY"
+	// PendingInsufficientFreeSlots means the task is waiting for other tasks to release slots.
+	PendingInsufficientFreeSlots PendingReasonCode = "INSUFFICIENT_FREE_SLOTS"
+	// PendingGroupMaxSlots means the group of the task is at its max slots.
+	PendingGroupMaxSlots PendingReasonCode = "GROUP_MAX_SLOTS"
+	// PendingFairShare means the group of the task is using its fair share of the slots.
+	PendingFairShare PendingReasonCode = "FAIR_SHARE"
+	// PendingLowerPriority means tasks of higher priority are scheduled first.
+	PendingLowerPriority PendingReasonCode = "LOWER_PRIORITY"
+	// PendingPreemption means the task is waiting for lower priority tasks to be preempted.
+	PendingPreemption PendingReasonCode = "PREEMPTION"
+	// PendingProvisioner means the task is waiting for the provisioner to launch agents.
+	PendingProvisioner PendingReasonCode = "WAITING_FOR_PROVISIONER"
+	// PendingReservedSlots means the free slots are reserved for other tasks.
+	PendingReservedSlots PendingReasonCode = "RESERVED_SLOTS"
+)
+
+// PendingReason describes why a scheduler did not allocate resources to a task on its last pass.
+type PendingReason struct {
+	Code    PendingReasonCode `json:"code"`
+	Message string            `json:"message"`
+}
+
+// Proto returns the proto representation of the reason code.
+func (c PendingReasonCode) Proto() taskv1.PendingReason {
+	switch c {
+	case PendingNoMatchingAgents:
+		return taskv1.PendingReason_PENDING_REASON_NO_MATCHING_AGENTS
+	case PendingInsufficientAgentCapacity:
+		return taskv1.PendingReason_PENDING_REASON_INSUFFICIENT_AGENT_CAPACITY
+	case PendingInsufficientFreeSlots:
+		return taskv1.PendingReason_PENDING_REASON_INSUFFICIENT_FREE_SLOTS
+	case PendingGroupMaxSlots:
+		return taskv1.PendingReason_PENDING_REASON_GROUP_MAX_SLOTS
+	case PendingFairShare:
+		return taskv1.PendingReason_PENDING_REASON_FAIR_SHARE
+	case PendingLowerPriority:
+		return taskv1.PendingReason_PENDING_REASON_LOWER_PRIORITY
+	case PendingPreemption:
+		return taskv1.PendingReason_PENDING_REASON_PREEMPTION
+	case PendingProvisioner:
+		return taskv1.PendingReason_PENDING_REASON_WAITING_FOR_PROVISIONER
+	default:
+		return taskv1.PendingReason_PENDING_REASON_UNSPECIFIED
+	}
+}
@@ -19,10 +19,21 @@ type (
 		Name         string
 		TaskActor    *actor.Ref
 		Group        *actor.Ref
+		JobID        model.JobID
+		// Username and Labels are the owner and the experiment labels of the task, which
+		// reservations match tasks by.
+		Username string
+		Labels   []string
 
 		// Resource configuration.
 		SlotsNeeded         int
+		SlotFraction        float64
+		CPUsNeeded          float64
+		MemoryNeeded        int64
 		Label               string
+		AgentSelectors      model.AgentSelectorsConfig
+		AgentAffinity       model.AgentAffinitiesConfig
+		AgentAntiAffinity   model.AgentAffinitiesConfig
 		ResourcePool        string
 		FittingRequirements FittingRequirements
 
@@ -43,11 +54,13 @@ type (
 		TimeoutDuration time.Duration
 	}
 
-	// PortProxyConfig configures a proxy the allocation should start.
+	// PortProxyConfig configures a proxy the allocation should start. Besides admins, only the
+	// owner of the task and the users in SharedWith may access the proxied port.
 	PortProxyConfig struct {
-		ServiceID string
-		Port      int
-		ProxyTCP  bool
+		ServiceID  string
+		Port       int
+		ProxyTCP   bool
+		SharedWith []string
 	}
 
 	// EventStreamConfig configures an event stream.
@@ -114,10 +127,20 @@ type (
 		ID           model.AllocationID
 		ResourcePool string
 		Reservations []Reservation
+		// StartTime is when the resources were allocated.
+		StartTime time.Time
 	}
 	// ReleaseResources notifies the task actor to release resources.
 	ReleaseResources struct {
 		ResourcePool string
+		// Preemption describes why the resource manager preempts the task, if it does.
+		Preemption *PreemptionReason
+	}
+	// PreemptionReason describes why a task is preempted and which task, if any, caused it.
+	PreemptionReason struct {
+		Reason      string       `json:"reason"`
+		PreemptedBy model.TaskID `json:"preempted_by,omitempty"`
+		JobID       model.JobID  `json:"job_id,omitempty"`
 	}
 	// ReservationRuntimeInfo is all the inforamation provided at runtime to make a task spec.
 	ReservationRuntimeInfo struct {
@@ -55,6 +55,9 @@ type (
 		killCooldown *time.Time
 		// tracks if we have finished termination.
 		exited bool
+		// Marks that we recorded the preemption of the allocation by the resource manager, which
+		// repeats its request to release resources until they are released.
+		preemptionRecorded bool
 
 		// State for specific sub-behaviors of an allocation.
 		// Encapsulates the preemption state of the currently allocated task.
@@ -67,6 +70,8 @@ type (
 		idleTimeoutWatcher *IdleTimeoutWatcher
 		// proxy state
 		proxies []string
+		// Tracks the ports forwarded on demand through the proxy.
+		portForwards *PortForwards
 		// log-based readiness state
 		logBasedReadinessPassed bool
 	}
@@ -116,6 +121,10 @@ const (
 
 // NewAllocation returns a new allocation, which tracks allocation state in a fairly generic way.
 func NewAllocation(req sproto.AllocateRequest, db db.DB, rm *actor.Ref) actor.Actor {
+	var sharedWith []string
+	if req.ProxyPort != nil {
+		sharedWith = req.ProxyPort.SharedWith
+	}
 	return &Allocation{
 		db: db,
 		rm: rm,
@@ -128,9 +137,11 @@ func NewAllocation(req sproto.AllocateRequest, db db.DB, rm *actor.Ref) actor.Ac
 			AgentLabel:   req.Name,
 			ResourcePool: req.ResourcePool,
 			StartTime:    time.Now().UTC(),
+			Username:     req.Username,
 		},
 
 		reservations: reservations{},
+		portForwards: NewPortForwards(req.Username, sharedWith),
 	}
 }
 
@@ -168,6 +179,7 @@ func (a *Allocation) Receive(ctx *actor.Context) error {
 	case sproto.TaskContainerStateChanged:
 		a.TaskContainerStateChanged(ctx, msg)
 	case sproto.ReleaseResources:
+		a.recordPreemption(ctx, msg)
 		a.Terminate(ctx)
 	case actor.PostStop:
 		a.Cleanup(ctx)
@@ -223,6 +235,10 @@ func (a *Allocation) Receive(ctx *actor.Context) error {
 			ctx.Tell(ctx.Self(), sproto.ContainerLog{AuxMessage: ptrs.StringPtr(err.Error())})
 			a.Error(ctx, err)
 		}
+	case ForwardPort, StopForwardingPort, portForwardExpired:
+		if err := a.portForwards.ReceiveMsg(ctx, a.reservations); err != nil {
+			a.Error(ctx, err)
+		}
 	case IdleTimeoutWatcherTick, IdleWatcherNoteActivity:
 		if a.req.IdleTimeout == nil {
 			if ctx.ExpectingResponse() {
@@ -460,6 +476,32 @@ func (a *Allocation) Terminate(ctx *actor.Context) {
 	}
 }
 
+// recordPreemption records why the resource manager preempts the allocation and which task, if any,
+// it is preempted for, the first time the resource manager asks it to release its resources.
+func (a *Allocation) recordPreemption(ctx *actor.Context, msg sproto.ReleaseResources) {
+	if msg.Preemption == nil || a.preemptionRecorded || len(a.reservations) == 0 {
+		return
+	}
+	a.preemptionRecorded = true
+
+	p := model.AllocationPreemption{
+		AllocationID: a.model.AllocationID,
+		TaskID:       a.model.TaskID,
+		Reason:       msg.Preemption.Reason,
+		PreemptedAt:  time.Now().UTC(),
+	}
+	if msg.Preemption.PreemptedBy != "" {
+		p.PreemptedBy = &msg.Preemption.PreemptedBy
+	}
+	if msg.Preemption.JobID != "" {
+		p.PreemptedByJobID = &msg.Preemption.JobID
+	}
+	ctx.Tell(ctx.Self(), sproto.ContainerLog{AuxMessage: ptrs.StringPtr(msg.Preemption.Reason)})
+	if err := a.db.AddAllocationPreemption(&p); err != nil {
+		ctx.Log().WithError(err).Error("failed to record preemption")
+	}
+}
+
 // Kill attempts to close an allocation by killing it.
 func (a *Allocation) Kill(ctx *actor.Context) {
 	if exited := a.Exit(ctx); exited {
@@ -539,7 +581,9 @@ func (a *Allocation) registerProxies(ctx *actor.Context, msg sproto.TaskContaine
 				Scheme: "http",
 				Host:   fmt.Sprintf("%s:%d", address.HostIP, address.HostPort),
 			},
-			ProxyTCP: cfg.ProxyTCP,
+			ProxyTCP:   cfg.ProxyTCP,
+			Owner:      a.req.Username,
+			SharedWith: cfg.SharedWith,
 		})
 		a.proxies = append(a.proxies, cfg.ServiceID)
 	}
@@ -574,6 +618,7 @@ func (a *Allocation) terminated(ctx *actor.Context) {
 	defer ctx.Tell(ctx.Self().Parent(), exit)
 	defer ctx.Tell(a.rm, sproto.ResourcesReleased{TaskActor: ctx.Self()})
 	defer a.unregisterProxies(ctx)
+	defer a.portForwards.Close(ctx)
 	defer ctx.Self().Stop()
 	if len(a.reservations) == 0 {
 		return
@@ -0,0 +1,219 @@
+package task
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/proxy"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/actor/actors"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+)
+
+const (
+	// DefaultPortForwardExpiry is how long a port is forwarded if no expiry is requested.
+	DefaultPortForwardExpiry = time.Hour
+	// MaxPortForwardExpiry is the longest a port can be forwarded for at once.
+	MaxPortForwardExpiry = 24 * time.Hour
+)
+
+type (
+	// ForwardPort forwards a port of a running container of the allocation through the master
+	// proxy until the expiry. The container may be omitted if the allocation has only one.
+	// Forwarding a port again extends its expiry.
+	ForwardPort struct {
+		User        model.User
+		ContainerID cproto.ID
+		Port        int
+		ProxyTCP    bool
+		Expiry      time.Duration
+	}
+	// ForwardedPort is the response to ForwardPort.
+	ForwardedPort struct {
+		ServiceID  string
+		ExpireTime time.Time
+	}
+	// StopForwardingPort stops forwarding a port forwarded by ForwardPort.
+	StopForwardingPort struct {
+		User        model.User
+		ContainerID cproto.ID
+		Port        int
+	}
+	// portForwardExpired notifies that a forwarded port reached the expiry it was forwarded with.
+	portForwardExpired struct {
+		ServiceID  string
+		ExpireTime time.Time
+	}
+)
+
+// PortForwards tracks the ports of the containers of an allocation that were forwarded through the
+// master proxy on demand, after the containers started.
+type PortForwards struct {
+	owner      string
+	sharedWith []string
+	expiries   map[string]time.Time
+}
+
+// NewPortForwards creates a new tracker of forwarded ports. Only the owner of the allocation and
+// admins may forward ports, which are then accessible to the users the allocation is shared with.
+func NewPortForwards(owner string, sharedWith []string) *PortForwards {
+	return &PortForwards{
+		owner:      owner,
+		sharedWith: sharedWith,
+		expiries:   map[string]time.Time{},
+	}
+}
+
+// ReceiveMsg should be called on receiving related messages. Errors from invalid requests are
+// responded to the sender rather than returned.
+func (p *PortForwards) ReceiveMsg(ctx *actor.Context, rs reservations) error {
+	switch msg := ctx.Message().(type) {
+	case ForwardPort:
+		resp, err := p.forward(ctx, rs, msg)
+		if err != nil {
+			ctx.Respond(err)
+			return nil
+		}
+		ctx.Respond(resp)
+
+	case StopForwardingPort:
+		if err := p.checkAccess(msg.User); err != nil {
+			ctx.Respond(err)
+			return nil
+		}
+		r, err := forwardingReservation(rs, msg.ContainerID, false)
+		if err != nil {
+			ctx.Respond(err)
+			return nil
+		}
+		serviceID := forwardedServiceID(r.Summary().ID, msg.Port)
+		if _, ok := p.expiries[serviceID]; !ok {
+			ctx.Respond(api.AsErrNotFound("port %d is not forwarded", msg.Port))
+			return nil
+		}
+		p.unregister(ctx, serviceID)
+
+	case portForwardExpired:
+		if expireTime, ok := p.expiries[msg.ServiceID]; ok && !expireTime.After(msg.ExpireTime) {
+			ctx.Log().Infof("forwarding of %s expired", msg.ServiceID)
+			p.unregister(ctx, msg.ServiceID)
+		}
+
+	default:
+		return actor.ErrUnexpectedMessage(ctx)
+	}
+	return nil
+}
+
+// Close stops forwarding all ports; it should be called when the allocation terminates.
+func (p *PortForwards) Close(ctx *actor.Context) {
+	for serviceID := range p.expiries {
+		p.unregister(ctx, serviceID)
+	}
+}
+
+func (p *PortForwards) forward(
+	ctx *actor.Context, rs reservations, msg ForwardPort,
+) (ForwardedPort, error) {
+	if err := p.checkAccess(msg.User); err != nil {
+		return ForwardedPort{}, err
+	}
+
+	expiry := msg.Expiry
+	switch {
+	case expiry == 0:
+		expiry = DefaultPortForwardExpiry
+	case expiry < 0 || expiry > MaxPortForwardExpiry:
+		return ForwardedPort{}, api.AsValidationError(
+			"expiry must be positive and at most %s", MaxPortForwardExpiry)
+	}
+
+	r, err := forwardingReservation(rs, msg.ContainerID, true)
+	if err != nil {
+		return ForwardedPort{}, err
+	}
+	address, err := tasks.ExposedPortAddress(r.start.Addresses, msg.Port)
+	if err != nil {
+		return ForwardedPort{}, api.AsValidationError("%s", err)
+	}
+
+	serviceID := forwardedServiceID(r.Summary().ID, msg.Port)
+	if err := ctx.Ask(ctx.Self().System().Get(actor.Addr("proxy")), proxy.Register{
+		ServiceID: serviceID,
+		URL: &url.URL{
+			Scheme: "http",
+			Host:   fmt.Sprintf("%s:%d", address.HostIP, address.HostPort),
+		},
+		ProxyTCP:   msg.ProxyTCP,
+		Owner:      p.owner,
+		SharedWith: p.sharedWith,
+	}).Error(); err != nil {
+		return ForwardedPort{}, err
+	}
+
+	expireTime := time.Now().Add(expiry)
+	p.expiries[serviceID] = expireTime
+	actors.NotifyAfter(ctx, expiry, portForwardExpired{
+		ServiceID: serviceID, ExpireTime: expireTime,
+	})
+	ctx.Log().Infof("forwarding %s for %s", serviceID, expiry)
+	return ForwardedPort{ServiceID: serviceID, ExpireTime: expireTime}, nil
+}
+
+func (p *PortForwards) checkAccess(user model.User) error {
+	if user.Admin || user.Username == p.owner {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied,
+		"only the owner of the task and admins may forward its ports")
+}
+
+func (p *PortForwards) unregister(ctx *actor.Context, serviceID string) {
+	delete(p.expiries, serviceID)
+	ctx.Tell(ctx.Self().System().Get(actor.Addr("proxy")), proxy.Unregister{
+		ServiceID: serviceID,
+	})
+}
+
+// forwardingReservation returns the reservation of the container to forward a port of, which is
+// the only reservation of the allocation if no container is given.
+func forwardingReservation(
+	rs reservations, id cproto.ID, running bool,
+) (*reservationWithState, error) {
+	if running {
+		rs = rs.started()
+	}
+	var r *reservationWithState
+	switch {
+	case id != "":
+		r = rs[id]
+		if r == nil {
+			return nil, api.AsErrNotFound("container %s not found", id)
+		}
+	case len(rs) == 1:
+		for _, only := range rs {
+			r = only
+		}
+	case len(rs) == 0:
+		return nil, api.AsValidationError("the allocation has no running containers")
+	default:
+		return nil, api.AsValidationError(
+			"the allocation has %d containers; the container must be specified", len(rs))
+	}
+	if running && r.exit != nil {
+		return nil, api.AsValidationError("container %s has exited", r.Summary().ID)
+	}
+	return r, nil
+}
+
+// forwardedServiceID returns the ID of the proxied service that forwards the port of a container.
+func forwardedServiceID(id cproto.ID, port int) string {
+	return fmt.Sprintf("%s-%d", id, port)
+}
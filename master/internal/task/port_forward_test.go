@@ -0,0 +1,102 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/mocks"
+	"github.com/determined-ai/determined/master/internal/proxy"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+type MockPortForwarder struct {
+	portForwards *PortForwards
+	reservations reservations
+}
+
+func (m *MockPortForwarder) Receive(ctx *actor.Context) error {
+	switch ctx.Message().(type) {
+	case ForwardPort, StopForwardingPort, portForwardExpired:
+		return m.portForwards.ReceiveMsg(ctx, m.reservations)
+	case actor.PreStart, actor.PostStop:
+	default:
+		return actor.ErrUnexpectedMessage(ctx)
+	}
+	return nil
+}
+
+func TestPortForwards(t *testing.T) {
+	containerID := cproto.NewID()
+	rsrv := &mocks.Reservation{}
+	rsrv.On("Summary").Return(sproto.ContainerSummary{ID: containerID})
+	rs := reservations{containerID: &reservationWithState{
+		Reservation: rsrv,
+		start: &sproto.TaskContainerStarted{Addresses: []cproto.Address{{
+			ContainerIP: "172.17.0.2", ContainerPort: 8265, HostIP: "10.0.0.1", HostPort: 32768,
+		}}},
+	}}
+
+	system := actor.NewSystem(t.Name())
+	proxyRef, _ := system.ActorOf(actor.Addr("proxy"), &proxy.Proxy{})
+	m := &MockPortForwarder{
+		portForwards: NewPortForwards("alice", []string{"bob"}),
+		reservations: rs,
+	}
+	mActor, created := system.ActorOf(actor.Addr("MockPortForwarder"), m)
+	assert.Assert(t, created)
+	services := func() map[string]proxy.Service {
+		return system.Ask(proxyRef, proxy.GetSummary{}).Get().(map[string]proxy.Service)
+	}
+
+	// Only the owner and admins may forward ports, and only exposed ones.
+	resp := system.Ask(mActor, ForwardPort{User: model.User{Username: "bob"}, Port: 8265})
+	assert.ErrorContains(t, resp.Error(), "only the owner")
+	resp = system.Ask(mActor, ForwardPort{User: model.User{Username: "alice"}, Port: 5678})
+	assert.ErrorContains(t, resp.Error(), "not exposed")
+	resp = system.Ask(mActor, ForwardPort{
+		User: model.User{Username: "alice"}, Port: 8265, Expiry: 48 * time.Hour,
+	})
+	assert.ErrorContains(t, resp.Error(), "expiry")
+
+	resp = system.Ask(mActor, ForwardPort{
+		User: model.User{Username: "alice"}, Port: 8265, ProxyTCP: true,
+	})
+	assert.NilError(t, resp.Error())
+	forwarded := resp.Get().(ForwardedPort)
+	assert.Equal(t, forwarded.ServiceID, forwardedServiceID(containerID, 8265))
+	service, ok := services()[forwarded.ServiceID]
+	assert.Assert(t, ok)
+	assert.Equal(t, service.URL.Host, "10.0.0.1:32768")
+	assert.Equal(t, service.ProxyTCP, true)
+	assert.Equal(t, service.Owner, "alice")
+	assert.DeepEqual(t, service.SharedWith, []string{"bob"})
+
+	// An expiry notification for an earlier forward of the port does not stop the later one.
+	system.Ask(mActor, portForwardExpired{
+		ServiceID: forwarded.ServiceID, ExpireTime: forwarded.ExpireTime.Add(-time.Minute),
+	}).Get()
+	_, ok = services()[forwarded.ServiceID]
+	assert.Assert(t, ok)
+
+	resp = system.Ask(mActor, StopForwardingPort{User: model.User{Admin: true}, Port: 8265})
+	assert.NilError(t, resp.Error())
+	_, ok = services()[forwarded.ServiceID]
+	assert.Assert(t, !ok)
+	resp = system.Ask(mActor, StopForwardingPort{User: model.User{Username: "alice"}, Port: 8265})
+	assert.ErrorContains(t, resp.Error(), "not forwarded")
+
+	// Forwards expire.
+	resp = system.Ask(mActor, ForwardPort{
+		User: model.User{Username: "alice"}, Port: 8265, Expiry: time.Millisecond,
+	})
+	assert.NilError(t, resp.Error())
+	time.Sleep(10 * time.Millisecond)
+	system.Ask(mActor, actor.Ping{}).Get()
+	_, ok = services()[forwarded.ServiceID]
+	assert.Assert(t, !ok)
+}
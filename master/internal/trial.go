@@ -14,6 +14,7 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/updates"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -64,6 +65,8 @@ type trial struct {
 
 	// a ref to the current allocation
 	allocation *actor.Ref
+	// the ID of the current allocation
+	allocationID model.AllocationID
 }
 
 // newTrial creates a trial which will try to schedule itself after it receives its first workload.
@@ -203,16 +206,29 @@ func (t *trial) maybeAllocateTask(ctx *actor.Context) error {
 	}
 
 	ctx.Log().Info("decided to allocate trial")
+	slots, slotFraction := model.SlotRequests(
+		t.config.Resources().SlotsPerTrial(), t.config.Resources().SlotFraction())
+	cpus, memory := model.HostRequests(t.config.Resources().CPUs(), t.config.Resources().Memory())
+	t.allocationID = model.NewAllocationID(fmt.Sprintf("%s.%d", t.taskID, t.runID))
 	t.allocation, _ = ctx.ActorOf(t.runID, taskAllocator(sproto.AllocateRequest{
-		AllocationID: model.NewAllocationID(fmt.Sprintf("%s.%d", t.taskID, t.runID)),
+		AllocationID: t.allocationID,
 		TaskID:       t.taskID,
 		Name:         name,
 		TaskActor:    ctx.Self(),
 		Group:        ctx.Self().Parent(),
-
-		SlotsNeeded:  t.config.Resources().SlotsPerTrial(),
-		Label:        t.config.Resources().AgentLabel(),
-		ResourcePool: t.config.Resources().ResourcePool(),
+		JobID:        t.jobID,
+		Username:     t.owner(),
+		Labels:       t.labels(),
+
+		SlotsNeeded:       slots,
+		SlotFraction:      slotFraction,
+		CPUsNeeded:        cpus,
+		MemoryNeeded:      memory,
+		Label:             t.config.Resources().AgentLabel(),
+		ResourcePool:      t.config.Resources().ResourcePool(),
+		AgentSelectors:    model.ToModelAgentSelectors(t.config.Resources().AgentSelectors()),
+		AgentAffinity:     model.ToModelAgentAffinities(t.config.Resources().AgentAffinity()),
+		AgentAntiAffinity: model.ToModelAgentAffinities(t.config.Resources().AgentAntiAffinity()),
 		FittingRequirements: sproto.FittingRequirements{
 			SingleAgent: false,
 		},
@@ -220,6 +236,7 @@ func (t *trial) maybeAllocateTask(ctx *actor.Context) error {
 		Preemptible:  true,
 		DoRendezvous: true,
 	}, t.db, t.rm))
+	t.publishAllocationUpdate(ctx, model.AllocationStatePending)
 	return nil
 }
 
@@ -246,6 +263,7 @@ func (t *trial) buildTaskSpec(ctx *actor.Context) (tasks.TaskSpec, error) {
 		t.id = modelTrial.ID
 		t.idSet = true
 		ctx.AddLabel("trial-id", t.id)
+		t.publishUpdate(ctx)
 		ctx.Tell(t.rm, sproto.SetTaskName{
 			Name:        fmt.Sprintf("Trial %d (Experiment %d)", t.id, t.experimentID),
 			TaskHandler: t.allocation,
@@ -257,6 +275,7 @@ func (t *trial) buildTaskSpec(ctx *actor.Context) (tasks.TaskSpec, error) {
 	if err := t.db.UpdateTrialRunID(t.id, t.runID); err != nil {
 		return tasks.TaskSpec{}, errors.Wrap(err, "failed to save trial run ID")
 	}
+	t.publishAllocationUpdate(ctx, model.AllocationStateAssigned)
 
 	var latestBatch int
 	latestCheckpoint, err := t.db.LatestCheckpointForTrial(t.id)
@@ -289,6 +308,7 @@ func (t *trial) allocationExited(ctx *actor.Context, exit *task.AllocationExited
 		ctx.Log().WithError(err).Error("trial allocation failed")
 	}
 	t.allocation = nil
+	t.publishAllocationUpdate(ctx, model.AllocationStateTerminated)
 
 	// Decide if this is permanent.
 	switch {
@@ -357,6 +377,7 @@ func (t *trial) transition(ctx *actor.Context, state model.State) error {
 			}
 		}
 		t.state = state
+		t.publishUpdate(ctx)
 	}
 
 	// Rectify our state and the allocation state with the transition.
@@ -397,6 +418,55 @@ func (t *trial) transition(ctx *actor.Context, state model.State) error {
 	return nil
 }
 
+// publishUpdate publishes the current state of the trial to the update log, once it has an ID.
+func (t *trial) publishUpdate(ctx *actor.Context) {
+	if !t.idSet {
+		return
+	}
+	updates.Publish(ctx.Self().System(), model.Update{
+		Kind:         model.UpdateKindTrial,
+		ExperimentID: t.experimentID,
+		TrialID:      ptrs.IntPtr(t.id),
+		State:        string(t.state),
+		Owner:        t.owner(),
+		Labels:       t.labels(),
+	})
+}
+
+// publishAllocationUpdate publishes a state of the current allocation of the trial to the update
+// log.
+func (t *trial) publishAllocationUpdate(ctx *actor.Context, state model.AllocationState) {
+	update := model.Update{
+		Kind:         model.UpdateKindAllocation,
+		ExperimentID: t.experimentID,
+		AllocationID: ptrs.StringPtr(string(t.allocationID)),
+		State:        state.String(),
+		Owner:        t.owner(),
+		Labels:       t.labels(),
+	}
+	if t.idSet {
+		update.TrialID = ptrs.IntPtr(t.id)
+	}
+	updates.Publish(ctx.Self().System(), update)
+}
+
+// owner returns the username of the owner of the experiment of the trial.
+func (t *trial) owner() string {
+	if t.taskSpec.Owner == nil {
+		return ""
+	}
+	return t.taskSpec.Owner.Username
+}
+
+// labels returns the labels of the experiment of the trial.
+func (t *trial) labels() []string {
+	var labels []string
+	for label := range t.config.Labels() {
+		labels = append(labels, label)
+	}
+	return labels
+}
+
 func (t *trial) enrichTrialLog(log model.TrialLog) (model.TrialLog, error) {
 	if !t.idSet {
 		return model.TrialLog{}, fmt.Errorf(
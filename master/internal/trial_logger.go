@@ -3,6 +3,7 @@ package internal
 import (
 	"time"
 
+	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/pkg/actor"
 	"github.com/determined-ai/determined/master/pkg/actor/actors"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -66,6 +67,9 @@ func (l *trialLogger) Receive(ctx *actor.Context) error {
 
 func (l *trialLogger) tryFlushLogs(ctx *actor.Context, forceFlush bool) {
 	if forceFlush || len(l.pending) >= logBuffer {
+		if len(l.pending) > 0 {
+			prom.TrialLogFlushSize.Observe(float64(len(l.pending)))
+		}
 		if err := l.backend.AddTrialLogs(l.pending); err != nil {
 			ctx.Log().WithError(err).Errorf("failed to save trial logs")
 		}
@@ -0,0 +1,161 @@
+// Package updates records the state changes of experiments and of their trials, checkpoints and
+// allocations in the update log, and pushes them to the subscribers of the StreamUpdates API.
+package updates
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/actor/actors"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// retention is how long updates are kept in the update log, and so how long after disconnecting
+	// a client can resume its stream.
+	retention = 7 * 24 * time.Hour
+	// pruneInterval is the time between deletions of expired updates.
+	pruneInterval = time.Hour
+	// subscriptionBuffer is the number of updates a subscription can fall behind before it is
+	// closed.
+	subscriptionBuffer = 256
+)
+
+var addr = actor.Addr("updates")
+
+// Publish records an update and pushes it to the matching subscriptions. The update should carry
+// the owner and labels of its experiment, which subscriptions filter on.
+func Publish(system *actor.System, u model.Update) {
+	if u.Time.IsZero() {
+		u.Time = time.Now().UTC()
+	}
+	system.TellAt(addr, u)
+}
+
+// Subscription receives the updates published after it was created that match its filter. C is
+// closed if the subscription falls too far behind the updates.
+type Subscription struct {
+	C <-chan model.Update
+	// Head is the sequence number of the latest update published before the subscription was
+	// created; updates up to it should be read from the update log.
+	Head int64
+
+	c      chan model.Update
+	filter model.UpdateFilter
+	system *actor.System
+}
+
+// Subscribe creates a subscription to the updates matching the filter.
+func Subscribe(system *actor.System, filter model.UpdateFilter) (*Subscription, error) {
+	resp := system.AskAt(addr, subscribe{filter: filter})
+	if err := resp.Error(); err != nil {
+		return nil, err
+	}
+	sub, ok := resp.Get().(*Subscription)
+	if !ok {
+		return nil, errors.New("updates are not available")
+	}
+	return sub, nil
+}
+
+// Close stops the subscription.
+func (s *Subscription) Close() {
+	s.system.TellAt(addr, unsubscribe{sub: s})
+}
+
+type (
+	subscribe struct {
+		filter model.UpdateFilter
+	}
+	unsubscribe struct {
+		sub *Subscription
+	}
+	prune struct{}
+)
+
+type publisher struct {
+	db            db.DB
+	head          int64
+	subscriptions map[*Subscription]bool
+}
+
+// NewPublisher creates the actor that records and publishes updates.
+func NewPublisher(system *actor.System, db db.DB) (*actor.Ref, error) {
+	head, err := db.LatestUpdateSeq()
+	if err != nil {
+		return nil, err
+	}
+	ref, _ := system.ActorOf(addr, &publisher{
+		db:            db,
+		head:          head,
+		subscriptions: map[*Subscription]bool{},
+	})
+	return ref, nil
+}
+
+func (p *publisher) Receive(ctx *actor.Context) error {
+	switch msg := ctx.Message().(type) {
+	case actor.PreStart:
+		ctx.Tell(ctx.Self(), prune{})
+
+	case model.Update:
+		p.publish(ctx, msg)
+
+	case subscribe:
+		c := make(chan model.Update, subscriptionBuffer)
+		sub := &Subscription{
+			C:      c,
+			Head:   p.head,
+			c:      c,
+			filter: msg.filter,
+			system: ctx.Self().System(),
+		}
+		p.subscriptions[sub] = true
+		ctx.Respond(sub)
+
+	case unsubscribe:
+		if p.subscriptions[msg.sub] {
+			delete(p.subscriptions, msg.sub)
+			close(msg.sub.c)
+		}
+
+	case prune:
+		if err := p.db.DeleteUpdatesBefore(time.Now().Add(-retention)); err != nil {
+			ctx.Log().WithError(err).Error("failed to prune the update log")
+		}
+		actors.NotifyAfter(ctx, pruneInterval, prune{})
+
+	case actor.PostStop:
+		for sub := range p.subscriptions {
+			close(sub.c)
+		}
+
+	default:
+		return actor.ErrUnexpectedMessage(ctx)
+	}
+	return nil
+}
+
+func (p *publisher) publish(ctx *actor.Context, u model.Update) {
+	if err := p.db.AddUpdate(&u); err != nil {
+		ctx.Log().WithError(err).Errorf("failed to record %s update", u.Kind)
+		return
+	}
+	p.head = u.Seq
+
+	for sub := range p.subscriptions {
+		if !sub.filter.Matches(u.ExperimentID, u.Owner, u.Labels) {
+			continue
+		}
+		select {
+		case sub.c <- u:
+		default:
+			ctx.Log().Warn("closing subscription to updates that fell behind")
+			delete(p.subscriptions, sub)
+			close(sub.c)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package updates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/mocks"
+	"github.com/determined-ai/determined/master/pkg/actor"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func setupPublisher(t *testing.T) (*actor.System, *mocks.DB) {
+	system := actor.NewSystem(t.Name())
+	db := &mocks.DB{}
+	db.On("LatestUpdateSeq").Return(int64(10), nil)
+	db.On("DeleteUpdatesBefore", mock.Anything).Return(nil)
+	seq := int64(10)
+	db.On("AddUpdate", mock.MatchedBy(func(u *model.Update) bool {
+		return u.State != "ERROR"
+	})).Run(func(args mock.Arguments) {
+		seq++
+		args.Get(0).(*model.Update).Seq = seq
+	}).Return(nil)
+	db.On("AddUpdate", mock.Anything).Return(errors.New("cannot record update"))
+	_, err := NewPublisher(system, db)
+	assert.NilError(t, err)
+	return system, db
+}
+
+func receive(t *testing.T, sub *Subscription) (model.Update, bool) {
+	select {
+	case u, ok := <-sub.C:
+		return u, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an update")
+		return model.Update{}, false
+	}
+}
+
+func assertNoUpdate(t *testing.T, sub *Subscription) {
+	select {
+	case u, ok := <-sub.C:
+		t.Fatalf("unexpected update %v (open %t)", u, ok)
+	default:
+	}
+}
+
+func TestPublisherFanOut(t *testing.T) {
+	system, _ := setupPublisher(t)
+
+	byOwner, err := Subscribe(system, model.UpdateFilter{Users: []string{"alice"}})
+	assert.NilError(t, err)
+	byLabel, err := Subscribe(system, model.UpdateFilter{Labels: []string{"nlp"}})
+	assert.NilError(t, err)
+	byExperiment, err := Subscribe(system, model.UpdateFilter{ExperimentIDs: []int{2}})
+	assert.NilError(t, err)
+	assert.Equal(t, byOwner.Head, int64(10))
+
+	Publish(system, model.Update{
+		Kind: model.UpdateKindExperiment, ExperimentID: 1, State: "ACTIVE",
+		Owner: "alice", Labels: []string{"prod"},
+	})
+	Publish(system, model.Update{
+		Kind: model.UpdateKindTrial, ExperimentID: 2, State: "ACTIVE",
+		Owner: "bob", Labels: []string{"nlp"},
+	})
+	// Updates that fail to be recorded are not published.
+	Publish(system, model.Update{
+		Kind: model.UpdateKindExperiment, ExperimentID: 1, State: "ERROR", Owner: "alice",
+	})
+
+	// Subscriptions start at the head of the update log.
+	late, err := Subscribe(system, model.UpdateFilter{})
+	assert.NilError(t, err)
+	assert.Equal(t, late.Head, int64(12))
+
+	u, ok := receive(t, byOwner)
+	assert.Assert(t, ok)
+	assert.Equal(t, u.Seq, int64(11))
+	assert.Equal(t, u.ExperimentID, 1)
+	assert.Assert(t, !u.Time.IsZero())
+	assertNoUpdate(t, byOwner)
+
+	u, ok = receive(t, byLabel)
+	assert.Assert(t, ok)
+	assert.Equal(t, u.Seq, int64(12))
+	assertNoUpdate(t, byLabel)
+
+	u, ok = receive(t, byExperiment)
+	assert.Assert(t, ok)
+	assert.Equal(t, u.Seq, int64(12))
+	assertNoUpdate(t, byExperiment)
+	assertNoUpdate(t, late)
+
+	byOwner.Close()
+	_, ok = receive(t, byOwner)
+	assert.Assert(t, !ok)
+}
+
+func TestPublisherClosesSubscriptionsThatFallBehind(t *testing.T) {
+	system, _ := setupPublisher(t)
+
+	slow, err := Subscribe(system, model.UpdateFilter{})
+	assert.NilError(t, err)
+	other, err := Subscribe(system, model.UpdateFilter{ExperimentIDs: []int{2}})
+	assert.NilError(t, err)
+
+	for i := 0; i <= subscriptionBuffer; i++ {
+		Publish(system, model.Update{Kind: model.UpdateKindTrial, ExperimentID: 1, State: "ACTIVE"})
+	}
+	Publish(system, model.Update{Kind: model.UpdateKindTrial, ExperimentID: 2, State: "ACTIVE"})
+	// Subscribing waits for the publisher to handle the updates.
+	late, err := Subscribe(system, model.UpdateFilter{ExperimentIDs: []int{3}})
+	assert.NilError(t, err)
+	assert.Equal(t, late.Head, int64(10+subscriptionBuffer+2))
+
+	// The subscription that fell behind keeps the buffered updates and is then closed.
+	for i := 0; i < subscriptionBuffer; i++ {
+		u, ok := receive(t, slow)
+		assert.Assert(t, ok)
+		assert.Equal(t, u.Seq, int64(11+i))
+	}
+	_, ok := receive(t, slow)
+	assert.Assert(t, !ok)
+
+	u, ok := receive(t, other)
+	assert.Assert(t, ok)
+	assert.Equal(t, u.ExperimentID, 2)
+
+	// Closing a subscription that was already closed is a no-op.
+	slow.Close()
+	other.Close()
+	_, ok = receive(t, other)
+	assert.Assert(t, !ok)
+}
@@ -41,6 +41,7 @@ type Ref struct {
 	log *log.Entry
 
 	address        Address
+	typeName       string
 	registeredTime time.Time
 
 	system       *System
@@ -74,6 +75,7 @@ func newRef(system *System, parent *Ref, address Address, actor Actor) *Ref {
 			"system", system.id),
 
 		address:        address,
+		typeName:       typeName,
 		registeredTime: time.Now(),
 
 		system:       system,
@@ -108,3 +108,16 @@ func (s *System) MustActorOf(address Address, actor Actor) *Ref {
 	}
 	return ref
 }
+
+// InboxLengths returns the number of messages waiting in the inboxes of the actors of the system,
+// summed by the type of the actor.
+func (s *System) InboxLengths() map[string]int {
+	s.refsLock.RLock()
+	defer s.refsLock.RUnlock()
+
+	lengths := map[string]int{s.typeName: s.inbox.len()}
+	for _, ref := range s.refs {
+		lengths[ref.typeName] += ref.inbox.len()
+	}
+	return lengths
+}
@@ -1,6 +1,7 @@
 package actor
 
 import (
+	"runtime"
 	"testing"
 
 	"gotest.tools/assert"
@@ -78,3 +79,35 @@ func TestSystem_AskAll(t *testing.T) {
 	}
 	assert.Equal(t, index, 3)
 }
+
+type blockingActor struct {
+	unblock chan struct{}
+}
+
+func (a *blockingActor) Receive(context *Context) error {
+	if _, ok := context.Message().(string); ok {
+		<-a.unblock
+	}
+	return nil
+}
+
+func TestSystem_InboxLengths(t *testing.T) {
+	system := NewSystem(t.Name())
+	actor := &blockingActor{unblock: make(chan struct{})}
+	ref, _ := system.ActorOf(Addr("blocking"), actor)
+	system.ActorOf(Addr("mock"), &mockActor{})
+
+	// The first message blocks the actor and the rest wait in its inbox.
+	for i := 0; i < 3; i++ {
+		system.Tell(ref, "block")
+	}
+	for ref.inbox.len() != 2 {
+		runtime.Gosched()
+	}
+	lengths := system.InboxLengths()
+	assert.Equal(t, lengths["blockingActor"], 2)
+	assert.Equal(t, lengths["mockActor"], 0)
+
+	close(actor.unblock)
+	assert.NilError(t, system.StopAndAwaitTermination())
+}
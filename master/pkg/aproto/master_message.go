@@ -39,9 +39,18 @@ type MasterMessage struct {
 
 // AgentStarted notifies the master that the agent has started up.
 type AgentStarted struct {
-	Version string
-	Label   string
-	Devices []device.Device
+	Version  string
+	Label    string
+	Labels   map[string]string
+	Devices  []device.Device
+	HostInfo HostInfo
+}
+
+// HostInfo describes the CPU and memory of the host of an agent. Agents that do not report them
+// leave them zero.
+type HostInfo struct {
+	CPUs   int
+	Memory int64
 }
 
 // ContainerStateChanged notifies the master that the agent transitioned the container state.
@@ -29,11 +29,14 @@ func (c Container) Transition(new State) Container {
 		Parent: c.Parent, ID: c.ID, State: new, Devices: c.Devices}
 }
 
-// GPUDeviceUUIDs returns the UUIDs of the devices for this container that are GPUs.
+// GPUDeviceUUIDs returns the UUIDs of the devices for this container that are GPUs. The UUID of a
+// GPU that the container holds several shares of is only returned once.
 func (c Container) GPUDeviceUUIDs() []string {
 	var uuids []string
+	seen := make(map[string]bool)
 	for _, d := range c.Devices {
-		if d.Type == device.GPU {
+		if d.Type == device.GPU && !seen[d.UUID] {
+			seen[d.UUID] = true
 			uuids = append(uuids, d.UUID)
 		}
 	}
@@ -32,12 +32,50 @@ func (t Type) Proto() devicev1.Type {
 	}
 }
 
-// Device represents a single computational device on an agent.
+// Device represents a single computational device on an agent, or a share of one when the agent
+// splits its physical devices into several slots.
 type Device struct {
 	ID    int    `json:"id"`
 	Brand string `json:"brand"`
 	UUID  string `json:"uuid"`
 	Type  Type   `json:"type"`
+	// Sharing is the number of slots that the physical device is split into; zero and one mean that
+	// it is not shared. The shares of a physical device have its UUID and consecutive IDs starting
+	// from its index times Sharing.
+	Sharing int `json:"sharing,omitempty"`
+}
+
+// Share splits each of the devices into the given number of slots.
+func Share(devices []Device, sharing int) []Device {
+	if sharing <= 1 {
+		return devices
+	}
+	shared := make([]Device, 0, len(devices)*sharing)
+	for _, d := range devices {
+		for i := 0; i < sharing; i++ {
+			share := d
+			share.ID = d.ID*sharing + i
+			share.Sharing = sharing
+			shared = append(shared, share)
+		}
+	}
+	return shared
+}
+
+// PhysicalID returns the index of the physical device that the device is a share of.
+func (d Device) PhysicalID() int {
+	if d.Sharing <= 1 {
+		return d.ID
+	}
+	return d.ID / d.Sharing
+}
+
+// SlotFraction returns the fraction of the physical device that the device is.
+func (d Device) SlotFraction() float64 {
+	if d.Sharing <= 1 {
+		return 1
+	}
+	return 1 / float64(d.Sharing)
 }
 
 func (d *Device) String() string {
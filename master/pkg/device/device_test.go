@@ -0,0 +1,26 @@
+package device
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestShare(t *testing.T) {
+	devices := []Device{
+		{ID: 0, Brand: "Tesla T4", UUID: "GPU-0", Type: GPU},
+		{ID: 1, Brand: "Tesla T4", UUID: "GPU-1", Type: GPU},
+	}
+	assert.DeepEqual(t, Share(devices, 1), devices)
+
+	shared := Share(devices, 4)
+	assert.Equal(t, len(shared), 8)
+	for i, d := range shared {
+		assert.Equal(t, d.ID, i)
+		assert.Equal(t, d.PhysicalID(), i/4)
+		assert.Equal(t, d.UUID, devices[i/4].UUID)
+		assert.Equal(t, d.SlotFraction(), 0.25)
+	}
+	assert.Equal(t, devices[1].PhysicalID(), 1)
+	assert.Equal(t, devices[1].SlotFraction(), 1.0)
+}
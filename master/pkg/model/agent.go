@@ -11,15 +11,16 @@ import (
 
 // AgentSummary summarizes the state on an agent.
 type AgentSummary struct {
-	ID             string       `json:"id"`
-	RegisteredTime time.Time    `json:"registered_time"`
-	Slots          SlotsSummary `json:"slots"`
-	NumContainers  int          `json:"num_containers"`
-	ResourcePool   string       `json:"resource_pool"`
-	Label          string       `json:"label"`
-	Addresses      []string     `json:"addresses"`
-	Enabled        bool         `json:"enabled"`
-	Draining       bool         `json:"draining"`
+	ID             string            `json:"id"`
+	RegisteredTime time.Time         `json:"registered_time"`
+	Slots          SlotsSummary      `json:"slots"`
+	NumContainers  int               `json:"num_containers"`
+	ResourcePool   string            `json:"resource_pool"`
+	Label          string            `json:"label"`
+	Labels         map[string]string `json:"labels"`
+	Addresses      []string          `json:"addresses"`
+	Enabled        bool              `json:"enabled"`
+	Draining       bool              `json:"draining"`
 }
 
 // ToProto converts an agent summary to a proto struct.
@@ -34,6 +35,7 @@ func (a AgentSummary) ToProto() *agentv1.Agent {
 		Slots:          slots,
 		Containers:     nil,
 		Label:          a.Label,
+		Labels:         a.Labels,
 		ResourcePool:   a.ResourcePool,
 		Addresses:      a.Addresses,
 		Enabled:        a.Enabled,
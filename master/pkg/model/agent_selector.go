@@ -0,0 +1,166 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+// AgentSelectorOperator is the operator an agent selector applies to the labels of an agent.
+type AgentSelectorOperator string
+
+const (
+	// SelectorIn matches agents whose label for the key is one of the values.
+	SelectorIn AgentSelectorOperator = "In"
+	// SelectorNotIn matches agents without the label or whose label is none of the values.
+	SelectorNotIn AgentSelectorOperator = "NotIn"
+	// SelectorExists matches agents with the label, regardless of its value.
+	SelectorExists AgentSelectorOperator = "Exists"
+	// SelectorDoesNotExist matches agents without the label.
+	SelectorDoesNotExist AgentSelectorOperator = "DoesNotExist"
+)
+
+// AgentSelector restricts the agents a task may be scheduled on based on their labels.
+type AgentSelector struct {
+	Key      string                `json:"key"`
+	Operator AgentSelectorOperator `json:"operator"`
+	Values   []string              `json:"values,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *AgentSelector) UnmarshalJSON(data []byte) error {
+	s.Operator = SelectorIn
+	type DefaultParser *AgentSelector
+	return errors.Wrap(json.Unmarshal(data, DefaultParser(s)), "failed to parse agent selector")
+}
+
+// Validate implements the check.Validatable interface.
+func (s AgentSelector) Validate() []error {
+	errs := []error{
+		check.NotEmpty(s.Key, "agent selector key must be non-empty"),
+	}
+	switch s.Operator {
+	case SelectorIn, SelectorNotIn:
+		errs = append(errs, check.GreaterThan(len(s.Values), 0,
+			"agent selector values must be non-empty for operator %s", s.Operator))
+	case SelectorExists, SelectorDoesNotExist:
+		errs = append(errs, check.Equal(len(s.Values), 0,
+			"agent selector values must be empty for operator %s", s.Operator))
+	default:
+		errs = append(errs, errors.Errorf(
+			"agent selector operator must be one of In, NotIn, Exists or DoesNotExist: %s",
+			s.Operator))
+	}
+	return errs
+}
+
+// Matches returns true if the agent labels satisfy the selector.
+func (s AgentSelector) Matches(labels map[string]string) bool {
+	value, ok := labels[s.Key]
+	switch s.Operator {
+	case SelectorIn:
+		return ok && s.hasValue(value)
+	case SelectorNotIn:
+		return !ok || !s.hasValue(value)
+	case SelectorExists:
+		return ok
+	case SelectorDoesNotExist:
+		return !ok
+	default:
+		return false
+	}
+}
+
+func (s AgentSelector) hasValue(value string) bool {
+	for _, v := range s.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a canonical representation of the selector.
+func (s AgentSelector) String() string {
+	values := append([]string{}, s.Values...)
+	sort.Strings(values)
+	return fmt.Sprintf("%s %s (%s)", s.Key, s.Operator, strings.Join(values, ","))
+}
+
+// AgentSelectorsConfig is a list of agent selectors that must all match.
+type AgentSelectorsConfig []AgentSelector
+
+// Matches returns true if the agent labels satisfy every selector.
+func (s AgentSelectorsConfig) Matches(labels map[string]string) bool {
+	for _, selector := range s {
+		if !selector.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a canonical representation of the selectors, which is the same for every
+// ordering of the same selectors.
+func (s AgentSelectorsConfig) String() string {
+	selectors := make([]string, 0, len(s))
+	for _, selector := range s {
+		selectors = append(selectors, selector.String())
+	}
+	sort.Strings(selectors)
+	return strings.Join(selectors, "; ")
+}
+
+// AgentAffinity is a soft preference for, or against, agents that match the selector. The weight
+// is the relative importance of the preference among all the preferences of a task.
+type AgentAffinity struct {
+	Weight int `json:"weight"`
+	AgentSelector
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (a *AgentAffinity) UnmarshalJSON(data []byte) error {
+	var selector AgentSelector
+	if err := json.Unmarshal(data, &selector); err != nil {
+		return err
+	}
+	weight := struct {
+		Weight *int `json:"weight"`
+	}{}
+	if err := json.Unmarshal(data, &weight); err != nil {
+		return errors.Wrap(err, "failed to parse agent affinity")
+	}
+	a.AgentSelector = selector
+	a.Weight = 1
+	if weight.Weight != nil {
+		a.Weight = *weight.Weight
+	}
+	return nil
+}
+
+// Validate implements the check.Validatable interface.
+func (a AgentAffinity) Validate() []error {
+	return []error{
+		check.GreaterThanOrEqualTo(a.Weight, 1, "agent affinity weight must be >= 1"),
+		check.LessThanOrEqualTo(a.Weight, 100, "agent affinity weight must be <= 100"),
+	}
+}
+
+// AgentAffinitiesConfig is a list of agent affinities.
+type AgentAffinitiesConfig []AgentAffinity
+
+// Weights returns the total weight of the affinities and the weight of those matching the labels.
+func (a AgentAffinitiesConfig) Weights(labels map[string]string) (matched, total int) {
+	for _, affinity := range a {
+		total += affinity.Weight
+		if affinity.Matches(labels) {
+			matched += affinity.Weight
+		}
+	}
+	return matched, total
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+func TestAgentSelectorMatches(t *testing.T) {
+	labels := map[string]string{"gpu": "a100", "zone": "us-east-1a"}
+
+	type testCase struct {
+		selector AgentSelector
+		matches  bool
+	}
+	tests := []testCase{
+		{AgentSelector{Key: "gpu", Operator: SelectorIn, Values: []string{"a100", "v100"}}, true},
+		{AgentSelector{Key: "gpu", Operator: SelectorIn, Values: []string{"k80"}}, false},
+		{AgentSelector{Key: "nvlink", Operator: SelectorIn, Values: []string{"true"}}, false},
+		{AgentSelector{Key: "gpu", Operator: SelectorNotIn, Values: []string{"a100"}}, false},
+		{AgentSelector{Key: "gpu", Operator: SelectorNotIn, Values: []string{"k80"}}, true},
+		{AgentSelector{Key: "nvlink", Operator: SelectorNotIn, Values: []string{"true"}}, true},
+		{AgentSelector{Key: "zone", Operator: SelectorExists}, true},
+		{AgentSelector{Key: "nvlink", Operator: SelectorExists}, false},
+		{AgentSelector{Key: "zone", Operator: SelectorDoesNotExist}, false},
+		{AgentSelector{Key: "nvlink", Operator: SelectorDoesNotExist}, true},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.selector.Matches(labels), tc.matches, tc.selector.String())
+	}
+
+	assert.Assert(t, AgentSelectorsConfig(nil).Matches(nil))
+	assert.Assert(t, !AgentSelectorsConfig{tests[0].selector, tests[1].selector}.Matches(labels))
+	assert.Equal(t,
+		AgentSelectorsConfig{tests[0].selector, tests[6].selector}.String(),
+		AgentSelectorsConfig{tests[6].selector, tests[0].selector}.String())
+}
+
+func TestAgentSelectorsUnmarshalAndValidate(t *testing.T) {
+	resources := ResourcesConfig{Weight: 1}
+	assert.NilError(t, json.Unmarshal([]byte(`{
+	"agent_selectors": [{"key": "gpu", "values": ["a100"]}],
+	"agent_affinity": [{"key": "nvlink", "operator": "Exists", "weight": 10}],
+	"agent_anti_affinity": [{"key": "zone", "operator": "NotIn", "values": ["a"]}]
+}`), &resources))
+
+	assert.DeepEqual(t, resources.AgentSelectors, AgentSelectorsConfig{
+		{Key: "gpu", Operator: SelectorIn, Values: []string{"a100"}},
+	})
+	assert.DeepEqual(t, resources.AgentAffinity, AgentAffinitiesConfig{
+		{Weight: 10, AgentSelector: AgentSelector{Key: "nvlink", Operator: SelectorExists}},
+	})
+	assert.DeepEqual(t, resources.AgentAntiAffinity, AgentAffinitiesConfig{{
+		Weight:        1,
+		AgentSelector: AgentSelector{Key: "zone", Operator: SelectorNotIn, Values: []string{"a"}},
+	}})
+	assert.NilError(t, check.Validate(resources))
+
+	matched, total := resources.AgentAffinity.Weights(map[string]string{"nvlink": "true"})
+	assert.Equal(t, matched, 10)
+	assert.Equal(t, total, 10)
+
+	err := check.Validate(AgentSelector{Key: "gpu", Operator: SelectorNotIn})
+	assert.ErrorContains(t, err, "agent selector values must be non-empty for operator NotIn")
+	err = check.Validate(AgentSelector{Key: "gpu", Operator: "Gt", Values: []string{"1"}})
+	assert.ErrorContains(t, err, "agent selector operator must be one of")
+	err = check.Validate(AgentAffinity{
+		Weight:        0,
+		AgentSelector: AgentSelector{Key: "gpu", Operator: SelectorExists, Values: []string{"x"}},
+	})
+	assert.ErrorContains(t, err, "agent affinity weight must be >= 1")
+	assert.ErrorContains(t, err, "agent selector values must be empty for operator Exists")
+}
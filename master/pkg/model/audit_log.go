@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// AuditLogEntry represents a row from the `audit_log` table: a mutating API call, who made it and
+// its outcome.
+type AuditLogEntry struct {
+	ID           int64         `db:"id" json:"id"`
+	Time         time.Time     `db:"time" json:"time"`
+	UserID       *UserID       `db:"user_id" json:"user_id"`
+	Username     string        `db:"username" json:"username"`
+	AllocationID *AllocationID `db:"allocation_id" json:"allocation_id"`
+	Method       string        `db:"method" json:"method"`
+	Targets      JSONObj       `db:"targets" json:"targets"`
+	Request      JSONObj       `db:"request" json:"request"`
+	ResultCode   string        `db:"result_code" json:"result_code"`
+	SourceIP     string        `db:"source_ip" json:"source_ip"`
+}
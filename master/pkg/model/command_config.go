@@ -29,6 +29,7 @@ type CommandConfig struct {
 	TensorBoardArgs []string         `json:"tensorboard_args,omitempty"`
 	IdleTimeout     *Duration        `json:"idle_timeout"`
 	WorkDir         *string          `json:"work_dir"`
+	ShareWith       []string         `json:"share_with,omitempty"`
 }
 
 // Validate implements the check.Validatable interface.
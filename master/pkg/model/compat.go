@@ -36,10 +36,73 @@ func (r ResourcesConfig) ToExpconf() expconf.ResourcesConfig {
 		RawAgentLabel:     ptrs.StringPtr(r.AgentLabel),
 		RawResourcePool:   ptrs.StringPtr(r.ResourcePool),
 		RawPriority:       r.Priority,
+		RawCPUs:           r.CPUs,
+		RawMemory:         r.Memory,
+		RawSlotFraction:   r.SlotFraction,
 		RawDevices:        r.Devices.ToExpconf(),
+
+		RawAgentSelectors:    r.AgentSelectors.ToExpconf(),
+		RawAgentAffinity:     r.AgentAffinity.ToExpconf(),
+		RawAgentAntiAffinity: r.AgentAntiAffinity.ToExpconf(),
 	}).(expconf.ResourcesConfig)
 }
 
+// ToExpconf translates old model objects into an expconf object.
+func (s AgentSelectorsConfig) ToExpconf() []expconf.AgentSelector {
+	out := []expconf.AgentSelector{}
+	for _, selector := range s {
+		out = append(out, expconf.AgentSelector{
+			RawKey:      selector.Key,
+			RawOperator: ptrs.StringPtr(string(selector.Operator)),
+			RawValues:   selector.Values,
+		})
+	}
+	return out
+}
+
+// ToExpconf translates old model objects into an expconf object.
+func (a AgentAffinitiesConfig) ToExpconf() []expconf.AgentAffinity {
+	out := []expconf.AgentAffinity{}
+	for _, affinity := range a {
+		out = append(out, expconf.AgentAffinity{
+			RawWeight:   ptrs.IntPtr(affinity.Weight),
+			RawKey:      affinity.Key,
+			RawOperator: ptrs.StringPtr(string(affinity.Operator)),
+			RawValues:   affinity.Values,
+		})
+	}
+	return out
+}
+
+// ToModelAgentSelectors converts new expconf agent selectors into old model agent selectors.
+func ToModelAgentSelectors(selectors []expconf.AgentSelector) AgentSelectorsConfig {
+	var out AgentSelectorsConfig
+	for _, s := range selectors {
+		out = append(out, AgentSelector{
+			Key:      s.Key(),
+			Operator: AgentSelectorOperator(s.Operator()),
+			Values:   s.Values(),
+		})
+	}
+	return out
+}
+
+// ToModelAgentAffinities converts new expconf agent affinities into old model agent affinities.
+func ToModelAgentAffinities(affinities []expconf.AgentAffinity) AgentAffinitiesConfig {
+	var out AgentAffinitiesConfig
+	for _, a := range affinities {
+		out = append(out, AgentAffinity{
+			Weight: a.Weight(),
+			AgentSelector: AgentSelector{
+				Key:      a.Key(),
+				Operator: AgentSelectorOperator(a.Operator()),
+				Values:   a.Values(),
+			},
+		})
+	}
+	return out
+}
+
 // ToExpconf translates old model objects into an expconf object.
 func (b BindMount) ToExpconf() expconf.BindMount {
 	return schemas.WithDefaults(expconf.BindMount{
@@ -99,3 +162,26 @@ func (e Environment) ToExpconf() expconf.EnvironmentConfig {
 		RawPodSpec:              (*expconf.PodSpec)(e.PodSpec),
 	}).(expconf.EnvironmentConfig)
 }
+
+// HostRequests returns the CPUs and memory a task needs from the optional CPU and memory requests
+// of its resources config; unset requests need nothing.
+func HostRequests(cpus *float64, memory *int64) (float64, int64) {
+	var cpusNeeded float64
+	var memoryNeeded int64
+	if cpus != nil {
+		cpusNeeded = *cpus
+	}
+	if memory != nil {
+		memoryNeeded = *memory
+	}
+	return cpusNeeded, memoryNeeded
+}
+
+// SlotRequests returns the whole slots and the fraction of a shared slot a task needs from its
+// slots and optional slot fraction; the slot fraction only applies to tasks needing a single slot.
+func SlotRequests(slots int, slotFraction *float64) (int, float64) {
+	if slotFraction == nil || slots != 1 || *slotFraction >= 1 {
+		return slots, 0
+	}
+	return 0, *slotFraction
+}
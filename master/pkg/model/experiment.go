@@ -618,6 +618,25 @@ type MetricHPImportance struct {
 	InProgress         bool               `json:"in_progress"`
 	ExperimentProgress float64            `json:"experiment_progress"`
 	HpImportance       map[string]float64 `json:"hp_importance"`
+	// HpImportanceIntervals holds the 95% confidence interval of the importance of each
+	// hyperparameter.
+	HpImportanceIntervals map[string]ConfidenceInterval `json:"hp_importance_intervals,omitempty"`
+	// HpInteractions holds the importance of the interactions between pairs of hyperparameters, if
+	// they are computed.
+	HpInteractions []HPInteraction `json:"hp_interactions,omitempty"`
+}
+
+// ConfidenceInterval is the confidence interval of an estimate.
+type ConfidenceInterval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// HPInteraction is the importance of the interaction between two hyperparameters: the share of the
+// variance of the metric explained by the two together beyond their individual effects.
+type HPInteraction struct {
+	Hyperparameters [2]string `json:"hyperparameters"`
+	Importance      float64   `json:"importance"`
 }
 
 // SetMetricHPImportance is a convenience function when modifying results for a specific metric.
@@ -241,6 +241,18 @@ type ResourcesConfig struct {
 	ResourcePool   string  `json:"resource_pool"`
 	Priority       *int    `json:"priority,omitempty"`
 
+	// CPUs and Memory (in bytes) are what each container of a task needs besides its slots.
+	CPUs   *float64 `json:"cpus,omitempty"`
+	Memory *int64   `json:"memory,omitempty"`
+	// SlotFraction is the fraction of a shared slot that a single-slot task needs.
+	SlotFraction *float64 `json:"slot_fraction,omitempty"`
+
+	// AgentSelectors must all match the labels of an agent for a task to be scheduled on it.
+	AgentSelectors AgentSelectorsConfig `json:"agent_selectors,omitempty"`
+	// AgentAffinity and AgentAntiAffinity are soft preferences for or against agents.
+	AgentAffinity     AgentAffinitiesConfig `json:"agent_affinity,omitempty"`
+	AgentAntiAffinity AgentAffinitiesConfig `json:"agent_anti_affinity,omitempty"`
+
 	Devices DevicesConfig `json:"devices"`
 }
 
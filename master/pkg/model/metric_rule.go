@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+// MetricRuleFiring represents a row from the `metric_rule_firings` table: a metric rule of an
+// experiment config whose condition was met by a metric reported by a trial.
+type MetricRuleFiring struct {
+	ID           int       `db:"id" json:"id"`
+	ExperimentID int       `db:"experiment_id" json:"experiment_id"`
+	TrialID      int       `db:"trial_id" json:"trial_id"`
+	RuleName     string    `db:"rule_name" json:"rule_name"`
+	MetricName   string    `db:"metric_name" json:"metric_name"`
+	MetricType   string    `db:"metric_type" json:"metric_type"`
+	Condition    string    `db:"condition" json:"condition"`
+	Value        float64   `db:"value" json:"value"`
+	Action       string    `db:"action" json:"action"`
+	TotalBatches int       `db:"total_batches" json:"total_batches"`
+	FiredAt      time.Time `db:"fired_at" json:"fired_at"`
+}
+
+// Proto converts a metric rule firing to its protobuf representation.
+func (f MetricRuleFiring) Proto() *experimentv1.MetricRuleFiring {
+	return &experimentv1.MetricRuleFiring{
+		Id:           int32(f.ID),
+		ExperimentId: int32(f.ExperimentID),
+		TrialId:      int32(f.TrialID),
+		RuleName:     f.RuleName,
+		MetricName:   f.MetricName,
+		MetricType:   f.MetricType,
+		Condition:    f.Condition,
+		Value:        f.Value,
+		Action:       f.Action,
+		TotalBatches: int32(f.TotalBatches),
+		FiredAt:      timestamppb.New(f.FiredAt),
+	}
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+// Reservation represents a row from the `reservations` table: slots of a resource pool booked for
+// a time window. During the window, the schedulers hold the slots for the tasks of the user and
+// with the experiment label of the reservation.
+type Reservation struct {
+	ID           int       `db:"id" json:"id"`
+	ResourcePool string    `db:"resource_pool" json:"resource_pool"`
+	Slots        int       `db:"slots" json:"slots"`
+	Username     string    `db:"username" json:"username"`
+	Label        string    `db:"label" json:"label"`
+	StartTime    time.Time `db:"start_time" json:"start_time"`
+	EndTime      time.Time `db:"end_time" json:"end_time"`
+}
+
+// Validate implements the check.Validatable interface.
+func (r Reservation) Validate() []error {
+	return []error{
+		check.NotEmpty(r.ResourcePool, "resource_pool must be set"),
+		check.GreaterThan(r.Slots, 0, "slots must be > 0"),
+		check.True(r.Username != "" || r.Label != "", "either username or label must be set"),
+		check.True(r.EndTime.After(r.StartTime), "end_time must be after start_time"),
+	}
+}
+
+// Active returns whether the window of the reservation contains the time.
+func (r Reservation) Active(t time.Time) bool {
+	return !t.Before(r.StartTime) && t.Before(r.EndTime)
+}
+
+// Overlaps returns whether the windows of the reservations overlap.
+func (r Reservation) Overlaps(other Reservation) bool {
+	return r.StartTime.Before(other.EndTime) && other.StartTime.Before(r.EndTime)
+}
+
+// Matches returns whether a task of the user with the experiment labels may use the reserved
+// slots.
+func (r Reservation) Matches(username string, labels []string) bool {
+	if r.Username != "" && r.Username != username {
+		return false
+	}
+	if r.Label == "" {
+		return true
+	}
+	for _, label := range labels {
+		if label == r.Label {
+			return true
+		}
+	}
+	return false
+}
+
+// PeakReservedSlots returns the largest number of slots that the reservations reserve at the same
+// time.
+func PeakReservedSlots(reservations []Reservation) int {
+	type event struct {
+		time  time.Time
+		slots int
+	}
+	events := make([]event, 0, 2*len(reservations))
+	for _, r := range reservations {
+		events = append(events, event{r.StartTime, r.Slots}, event{r.EndTime, -r.Slots})
+	}
+	// Windows are half-open, so a reservation ending when another starts does not overlap it.
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].time.Equal(events[j].time) {
+			return events[i].time.Before(events[j].time)
+		}
+		return events[i].slots < events[j].slots
+	})
+	peak, reserved := 0, 0
+	for _, e := range events {
+		reserved += e.slots
+		if reserved > peak {
+			peak = reserved
+		}
+	}
+	return peak
+}
@@ -66,6 +66,34 @@ type Allocation struct {
 	ResourcePool string       `db:"resource_pool"`
 	StartTime    time.Time    `db:"start_time"`
 	EndTime      *time.Time   `db:"end_time"`
+	// Username is the owner of the task of the allocation.
+	Username string `db:"username"`
+}
+
+// AllocationPreemption is the model for the preemption of an allocation by the resource manager in
+// the database.
+type AllocationPreemption struct {
+	ID           int          `db:"id" json:"id"`
+	AllocationID AllocationID `db:"allocation_id" json:"allocation_id"`
+	TaskID       TaskID       `db:"task_id" json:"task_id"`
+	Reason       string       `db:"reason" json:"reason"`
+	// PreemptedBy and PreemptedByJobID are the task and job the allocation was preempted for, if
+	// any.
+	PreemptedBy      *TaskID   `db:"preempted_by" json:"preempted_by"`
+	PreemptedByJobID *JobID    `db:"preempted_by_job_id" json:"preempted_by_job_id"`
+	PreemptedAt      time.Time `db:"preempted_at" json:"preempted_at"`
+}
+
+// ProvisionedInstance is the model for the lifetime of an instance launched by the provisioner
+// of a resource pool in the database.
+type ProvisionedInstance struct {
+	InstanceID   string     `db:"instance_id"`
+	ResourcePool string     `db:"resource_pool"`
+	InstanceType string     `db:"instance_type"`
+	Slots        int        `db:"slots"`
+	HourlyPrice  float64    `db:"hourly_price"`
+	StartTime    time.Time  `db:"start_time"`
+	EndTime      *time.Time `db:"end_time"`
 }
 
 // AllocationState represents the current state of the task. Value indicates a partial ordering.
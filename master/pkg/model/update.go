@@ -0,0 +1,112 @@
+package model
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// UpdateKind is the kind of object whose state changed in an update.
+type UpdateKind string
+
+const (
+	// UpdateKindExperiment is the kind of updates to the state of experiments.
+	UpdateKindExperiment UpdateKind = "experiment"
+	// UpdateKindTrial is the kind of updates to the state of trials.
+	UpdateKindTrial UpdateKind = "trial"
+	// UpdateKindCheckpoint is the kind of updates to the state of checkpoints.
+	UpdateKindCheckpoint UpdateKind = "checkpoint"
+	// UpdateKindAllocation is the kind of updates to the state of the allocations of trials.
+	UpdateKindAllocation UpdateKind = "allocation"
+)
+
+var updateKindProtos = map[UpdateKind]apiv1.UpdateKind{
+	UpdateKindExperiment: apiv1.UpdateKind_UPDATE_KIND_EXPERIMENT,
+	UpdateKindTrial:      apiv1.UpdateKind_UPDATE_KIND_TRIAL,
+	UpdateKindCheckpoint: apiv1.UpdateKind_UPDATE_KIND_CHECKPOINT,
+	UpdateKindAllocation: apiv1.UpdateKind_UPDATE_KIND_ALLOCATION,
+}
+
+// Update represents a row from the `update_log` table: a change of the state of an experiment or
+// of one of its trials, checkpoints or allocations. Seq orders all updates.
+type Update struct {
+	Seq            int64      `db:"seq" json:"seq"`
+	Kind           UpdateKind `db:"kind" json:"kind"`
+	ExperimentID   int        `db:"experiment_id" json:"experiment_id"`
+	TrialID        *int       `db:"trial_id" json:"trial_id"`
+	AllocationID   *string    `db:"allocation_id" json:"allocation_id"`
+	CheckpointUUID *string    `db:"checkpoint_uuid" json:"checkpoint_uuid"`
+	State          string     `db:"state" json:"state"`
+	Time           time.Time  `db:"time" json:"time"`
+	// Owner and Labels are the username of the owner and the labels of the experiment, which
+	// publishers set so that subscriptions filter live updates without loading the experiment.
+	// They are not recorded in the update log.
+	Owner  string   `db:"-" json:"-"`
+	Labels []string `db:"-" json:"-"`
+}
+
+// Proto converts an update to its protobuf representation.
+func (u Update) Proto() *apiv1.StreamUpdatesResponse {
+	resp := &apiv1.StreamUpdatesResponse{
+		Seq:          u.Seq,
+		Kind:         updateKindProtos[u.Kind],
+		ExperimentId: int32(u.ExperimentID),
+		State:        u.State,
+		Time:         timestamppb.New(u.Time),
+	}
+	if u.TrialID != nil {
+		resp.TrialId = int32(*u.TrialID)
+	}
+	if u.AllocationID != nil {
+		resp.AllocationId = *u.AllocationID
+	}
+	if u.CheckpointUUID != nil {
+		resp.CheckpointUuid = *u.CheckpointUUID
+	}
+	return resp
+}
+
+// UpdateFilter selects the updates of experiments by ID, owner and labels. Empty fields match
+// every experiment.
+type UpdateFilter struct {
+	ExperimentIDs []int
+	Users         []string
+	Labels        []string
+}
+
+// Matches returns whether the filter selects the updates of the experiment with the ID, owner and
+// labels.
+func (f UpdateFilter) Matches(experimentID int, user string, labels []string) bool {
+	return (len(f.ExperimentIDs) == 0 || containsInt(f.ExperimentIDs, experimentID)) &&
+		(len(f.Users) == 0 || containsString(f.Users, user)) &&
+		(len(f.Labels) == 0 || intersects(f.Labels, labels))
+}
+
+func containsInt(xs []int, x int) bool {
+	for _, y := range xs {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(xs []string, x string) bool {
+	for _, y := range xs {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
+
+func intersects(xs, ys []string) bool {
+	for _, y := range ys {
+		if containsString(xs, y) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestUpdateFilterMatches(t *testing.T) {
+	type testCase struct {
+		name    string
+		filter  UpdateFilter
+		matches bool
+	}
+	tests := []testCase{
+		{"empty filter", UpdateFilter{}, true},
+		{"experiment ID", UpdateFilter{ExperimentIDs: []int{1, 2}}, true},
+		{"other experiment ID", UpdateFilter{ExperimentIDs: []int{3}}, false},
+		{"owner", UpdateFilter{Users: []string{"bob", "alice"}}, true},
+		{"other owner", UpdateFilter{Users: []string{"bob"}}, false},
+		{"label", UpdateFilter{Labels: []string{"nlp", "prod"}}, true},
+		{"other label", UpdateFilter{Labels: []string{"vision"}}, false},
+		{
+			"all fields",
+			UpdateFilter{ExperimentIDs: []int{1}, Users: []string{"alice"}, Labels: []string{"prod"}},
+			true,
+		},
+		{
+			"one field differs",
+			UpdateFilter{ExperimentIDs: []int{1}, Users: []string{"bob"}, Labels: []string{"prod"}},
+			false,
+		},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.filter.Matches(1, "alice", []string{"prod", "test"}), tc.matches, tc.name)
+	}
+
+	assert.Assert(t, !UpdateFilter{Labels: []string{"prod"}}.Matches(1, "alice", nil))
+	assert.Assert(t, !UpdateFilter{Users: []string{"alice"}}.Matches(1, "", nil))
+}
@@ -29,6 +29,7 @@ type ExperimentConfigV0 struct {
 	RawInternal                 *InternalConfigV0           `json:"internal,omitempty"`
 	RawLabels                   LabelsV0                    `json:"labels"`
 	RawMaxRestarts              *int                        `json:"max_restarts"`
+	RawMetricRules              []MetricRuleV0              `json:"metric_rules"`
 	RawMinCheckpointPeriod      *LengthV0                   `json:"min_checkpoint_period"`
 	RawMinValidationPeriod      *LengthV0                   `json:"min_validation_period"`
 	RawName                     Name                        `json:"name"`
@@ -194,6 +195,13 @@ type ResourcesConfigV0 struct {
 	RawAgentLabel     *string  `json:"agent_label"`
 	RawResourcePool   *string  `json:"resource_pool"`
 	RawPriority       *int     `json:"priority"`
+	RawCPUs           *float64 `json:"cpus"`
+	RawMemory         *int64   `json:"memory"`
+	RawSlotFraction   *float64 `json:"slot_fraction"`
+
+	RawAgentSelectors    []AgentSelectorV0 `json:"agent_selectors"`
+	RawAgentAffinity     []AgentAffinityV0 `json:"agent_affinity"`
+	RawAgentAntiAffinity []AgentAffinityV0 `json:"agent_anti_affinity"`
 
 	RawDevices DevicesConfigV0 `json:"devices"`
 }
@@ -283,6 +291,37 @@ type DeviceV0 struct {
 	RawMode          *string `json:"mode"`
 }
 
+//go:generate ../gen.sh
+// AgentSelectorV0 restricts the agents a task may be scheduled on based on their labels.
+type AgentSelectorV0 struct {
+	RawKey      string   `json:"key"`
+	RawOperator *string  `json:"operator"`
+	RawValues   []string `json:"values"`
+}
+
+//go:generate ../gen.sh
+// AgentAffinityV0 is a weighted preference for, or against, agents matching a selector.
+type AgentAffinityV0 struct {
+	RawWeight   *int     `json:"weight"`
+	RawKey      string   `json:"key"`
+	RawOperator *string  `json:"operator"`
+	RawValues   []string `json:"values"`
+}
+
+//go:generate ../gen.sh
+// MetricRuleV0 is a rule evaluated by the master on the metrics reported by each trial, which
+// takes an action when its condition is met.
+type MetricRuleV0 struct {
+	RawName            string   `json:"name"`
+	RawMetric          string   `json:"metric"`
+	RawMetricType      *string  `json:"metric_type"`
+	RawCondition       string   `json:"condition"`
+	RawThreshold       *float64 `json:"threshold"`
+	RawPatience        *int     `json:"patience"`
+	RawSmallerIsBetter *bool    `json:"smaller_is_better"`
+	RawAction          *string  `json:"action"`
+}
+
 //go:generate ../gen.sh
 // ReproducibilityConfigV0 configures parameters related to reproducibility.
 type ReproducibilityConfigV0 struct {
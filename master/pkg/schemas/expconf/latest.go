@@ -3,6 +3,8 @@ package expconf
 // This file defines the latest version of each config, which should be used throughout the system.
 
 type AdaptiveASHAConfig = AdaptiveASHAConfigV0
+type AgentAffinity = AgentAffinityV0
+type AgentSelector = AgentSelectorV0
 type AsyncHalvingConfig = AsyncHalvingConfigV0
 type AzureConfig = AzureConfigV0
 type BindMount = BindMountV0
@@ -28,6 +30,7 @@ type IntHyperparameter = IntHyperparameterV0
 type Labels = LabelsV0
 type Length = LengthV0
 type LogHyperparameter = LogHyperparameterV0
+type MetricRule = MetricRuleV0
 type OptimizationsConfig = OptimizationsConfigV0
 type PBTConfig = PBTConfigV0
 type ProfilingConfig = ProfilingConfigV0
@@ -139,6 +139,10 @@ func objectForURL(url string) interface{} {
 		return &BindMountsConfigV0{}
 	case "http://determined.ai/schemas/expconf/v0/devices.json":
 		return &DevicesConfigV0{}
+	case "http://determined.ai/schemas/expconf/v0/resources.json":
+		return &ResourcesConfigV0{}
+	case "http://determined.ai/schemas/expconf/v0/metric-rule.json":
+		return &MetricRuleV0{}
 	case "http://determined.ai/schemas/expconf/v0/environment.json":
 		return &EnvironmentConfigV0{}
 	case "http://determined.ai/schemas/expconf/v0/data-layer.json":
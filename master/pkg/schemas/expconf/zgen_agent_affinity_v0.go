@@ -0,0 +1,59 @@
+// Code generated by gen.py. DO NOT EDIT.
+
+package expconf
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v2"
+
+	"github.com/determined-ai/determined/master/pkg/schemas"
+)
+
+func (a AgentAffinityV0) Weight() int {
+	if a.RawWeight == nil {
+		panic("You must call WithDefaults on AgentAffinityV0 before .Weight")
+	}
+	return *a.RawWeight
+}
+
+func (a *AgentAffinityV0) SetWeight(val int) {
+	a.RawWeight = &val
+}
+
+func (a AgentAffinityV0) Key() string {
+	return a.RawKey
+}
+
+func (a *AgentAffinityV0) SetKey(val string) {
+	a.RawKey = val
+}
+
+func (a AgentAffinityV0) Operator() string {
+	if a.RawOperator == nil {
+		panic("You must call WithDefaults on AgentAffinityV0 before .Operator")
+	}
+	return *a.RawOperator
+}
+
+func (a *AgentAffinityV0) SetOperator(val string) {
+	a.RawOperator = &val
+}
+
+func (a AgentAffinityV0) Values() []string {
+	return a.RawValues
+}
+
+func (a *AgentAffinityV0) SetValues(val []string) {
+	a.RawValues = val
+}
+
+func (a AgentAffinityV0) ParsedSchema() interface{} {
+	return schemas.ParsedAgentAffinityV0()
+}
+
+func (a AgentAffinityV0) SanityValidator() *jsonschema.Schema {
+	return schemas.GetSanityValidator("http://determined.ai/schemas/expconf/v0/agent-affinity.json")
+}
+
+func (a AgentAffinityV0) CompletenessValidator() *jsonschema.Schema {
+	return schemas.GetCompletenessValidator("http://determined.ai/schemas/expconf/v0/agent-affinity.json")
+}
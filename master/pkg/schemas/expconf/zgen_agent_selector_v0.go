@@ -0,0 +1,48 @@
+// Code generated by gen.py. DO NOT EDIT.
+
+package expconf
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v2"
+
+	"github.com/determined-ai/determined/master/pkg/schemas"
+)
+
+func (a AgentSelectorV0) Key() string {
+	return a.RawKey
+}
+
+func (a *AgentSelectorV0) SetKey(val string) {
+	a.RawKey = val
+}
+
+func (a AgentSelectorV0) Operator() string {
+	if a.RawOperator == nil {
+		panic("You must call WithDefaults on AgentSelectorV0 before .Operator")
+	}
+	return *a.RawOperator
+}
+
+func (a *AgentSelectorV0) SetOperator(val string) {
+	a.RawOperator = &val
+}
+
+func (a AgentSelectorV0) Values() []string {
+	return a.RawValues
+}
+
+func (a *AgentSelectorV0) SetValues(val []string) {
+	a.RawValues = val
+}
+
+func (a AgentSelectorV0) ParsedSchema() interface{} {
+	return schemas.ParsedAgentSelectorV0()
+}
+
+func (a AgentSelectorV0) SanityValidator() *jsonschema.Schema {
+	return schemas.GetSanityValidator("http://determined.ai/schemas/expconf/v0/agent-selector.json")
+}
+
+func (a AgentSelectorV0) CompletenessValidator() *jsonschema.Schema {
+	return schemas.GetCompletenessValidator("http://determined.ai/schemas/expconf/v0/agent-selector.json")
+}
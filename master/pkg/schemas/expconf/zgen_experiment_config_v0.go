@@ -130,6 +130,14 @@ func (e *ExperimentConfigV0) SetMaxRestarts(val int) {
 	e.RawMaxRestarts = &val
 }
 
+func (e ExperimentConfigV0) MetricRules() []MetricRuleV0 {
+	return e.RawMetricRules
+}
+
+func (e *ExperimentConfigV0) SetMetricRules(val []MetricRuleV0) {
+	e.RawMetricRules = val
+}
+
 func (e ExperimentConfigV0) MinCheckpointPeriod() LengthV0 {
 	if e.RawMinCheckpointPeriod == nil {
 		panic("You must call WithDefaults on ExperimentConfigV0 before .MinCheckpointPeriod")
@@ -0,0 +1,94 @@
+// Code generated by gen.py. DO NOT EDIT.
+
+package expconf
+
+import (
+	"github.com/santhosh-tekuri/jsonschema/v2"
+
+	"github.com/determined-ai/determined/master/pkg/schemas"
+)
+
+func (m MetricRuleV0) Name() string {
+	return m.RawName
+}
+
+func (m *MetricRuleV0) SetName(val string) {
+	m.RawName = val
+}
+
+func (m MetricRuleV0) Metric() string {
+	return m.RawMetric
+}
+
+func (m *MetricRuleV0) SetMetric(val string) {
+	m.RawMetric = val
+}
+
+func (m MetricRuleV0) MetricType() string {
+	if m.RawMetricType == nil {
+		panic("You must call WithDefaults on MetricRuleV0 before .MetricType")
+	}
+	return *m.RawMetricType
+}
+
+func (m *MetricRuleV0) SetMetricType(val string) {
+	m.RawMetricType = &val
+}
+
+func (m MetricRuleV0) Condition() string {
+	return m.RawCondition
+}
+
+func (m *MetricRuleV0) SetCondition(val string) {
+	m.RawCondition = val
+}
+
+func (m MetricRuleV0) Threshold() *float64 {
+	return m.RawThreshold
+}
+
+func (m *MetricRuleV0) SetThreshold(val *float64) {
+	m.RawThreshold = val
+}
+
+func (m MetricRuleV0) Patience() *int {
+	return m.RawPatience
+}
+
+func (m *MetricRuleV0) SetPatience(val *int) {
+	m.RawPatience = val
+}
+
+func (m MetricRuleV0) SmallerIsBetter() bool {
+	if m.RawSmallerIsBetter == nil {
+		panic("You must call WithDefaults on MetricRuleV0 before .SmallerIsBetter")
+	}
+	return *m.RawSmallerIsBetter
+}
+
+func (m *MetricRuleV0) SetSmallerIsBetter(val bool) {
+	m.RawSmallerIsBetter = &val
+}
+
+func (m MetricRuleV0) Action() string {
+	if m.RawAction == nil {
+		panic("You must call WithDefaults on MetricRuleV0 before .Action")
+	}
+	return *m.RawAction
+}
+
+func (m *MetricRuleV0) SetAction(val string) {
+	m.RawAction = &val
+}
+
+func (m MetricRuleV0) ParsedSchema() interface{} {
+	return schemas.ParsedMetricRuleV0()
+}
+
+func (m MetricRuleV0) SanityValidator() *jsonschema.Schema {
+	return schemas.GetSanityValidator("http://determined.ai/schemas/expconf/v0/metric-rule.json")
+}
+
+func (m MetricRuleV0) CompletenessValidator() *jsonschema.Schema {
+	return schemas.GetCompletenessValidator("http://determined.ai/schemas/expconf/v0/metric-rule.json")
+}
@@ -95,6 +95,54 @@ func (r *ResourcesConfigV0) SetPriority(val *int) {
 	r.RawPriority = val
 }
 
+func (r ResourcesConfigV0) CPUs() *float64 {
+	return r.RawCPUs
+}
+
+func (r *ResourcesConfigV0) SetCPUs(val *float64) {
+	r.RawCPUs = val
+}
+
+func (r ResourcesConfigV0) Memory() *int64 {
+	return r.RawMemory
+}
+
+func (r *ResourcesConfigV0) SetMemory(val *int64) {
+	r.RawMemory = val
+}
+
+func (r ResourcesConfigV0) SlotFraction() *float64 {
+	return r.RawSlotFraction
+}
+
+func (r *ResourcesConfigV0) SetSlotFraction(val *float64) {
+	r.RawSlotFraction = val
+}
+
+func (r ResourcesConfigV0) AgentSelectors() []AgentSelectorV0 {
+	return r.RawAgentSelectors
+}
+
+func (r *ResourcesConfigV0) SetAgentSelectors(val []AgentSelectorV0) {
+	r.RawAgentSelectors = val
+}
+
+func (r ResourcesConfigV0) AgentAffinity() []AgentAffinityV0 {
+	return r.RawAgentAffinity
+}
+
+func (r *ResourcesConfigV0) SetAgentAffinity(val []AgentAffinityV0) {
+	r.RawAgentAffinity = val
+}
+
+func (r ResourcesConfigV0) AgentAntiAffinity() []AgentAffinityV0 {
+	return r.RawAgentAntiAffinity
+}
+
+func (r *ResourcesConfigV0) SetAgentAntiAffinity(val []AgentAffinityV0) {
+	r.RawAgentAntiAffinity = val
+}
+
 func (r ResourcesConfigV0) Devices() DevicesConfigV0 {
 	return r.RawDevices
 }
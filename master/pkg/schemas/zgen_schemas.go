@@ -8,6 +8,205 @@ import (
 )
 
 var (
+	textAgentAffinityV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/agent-affinity.json",
+    "title": "AgentAffinity",
+    "additionalProperties": false,
+    "required": [
+        "key"
+    ],
+    "type": "object",
+    "properties": {
+        "weight": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "maximum": 100,
+            "default": 1
+        },
+        "key": {
+            "type": "string",
+            "checks": {
+                "key must be non-empty": {
+                    "minLength": 1
+                }
+            }
+        },
+        "operator": {
+            "enum": [
+                null,
+                "In",
+                "NotIn",
+                "Exists",
+                "DoesNotExist"
+            ],
+            "default": "In"
+        },
+        "values": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "type": "string"
+            },
+            "default": null
+        }
+    },
+    "checks": {
+        "values must be non-empty for the In and NotIn operators": {
+            "conditional": {
+                "$comment": "when the operator is In or NotIn, expect values",
+                "when": {
+                    "properties": {
+                        "operator": {
+                            "enum": [
+                                null,
+                                "In",
+                                "NotIn"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "required": [
+                        "values"
+                    ],
+                    "properties": {
+                        "values": {
+                            "type": "array",
+                            "minItems": 1
+                        }
+                    }
+                }
+            }
+        },
+        "values must be empty for the Exists and DoesNotExist operators": {
+            "conditional": {
+                "$comment": "when the operator is Exists or DoesNotExist, forbid values",
+                "when": {
+                    "required": [
+                        "operator"
+                    ],
+                    "properties": {
+                        "operator": {
+                            "enum": [
+                                "Exists",
+                                "DoesNotExist"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "properties": {
+                        "values": {
+                            "maxItems": 0
+                        }
+                    }
+                }
+            }
+        }
+    }
+}
+`)
+	textAgentSelectorV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/agent-selector.json",
+    "title": "AgentSelector",
+    "additionalProperties": false,
+    "required": [
+        "key"
+    ],
+    "type": "object",
+    "properties": {
+        "key": {
+            "type": "string",
+            "checks": {
+                "key must be non-empty": {
+                    "minLength": 1
+                }
+            }
+        },
+        "operator": {
+            "enum": [
+                null,
+                "In",
+                "NotIn",
+                "Exists",
+                "DoesNotExist"
+            ],
+            "default": "In"
+        },
+        "values": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "type": "string"
+            },
+            "default": null
+        }
+    },
+    "checks": {
+        "values must be non-empty for the In and NotIn operators": {
+            "conditional": {
+                "$comment": "when the operator is In or NotIn, expect values",
+                "when": {
+                    "properties": {
+                        "operator": {
+                            "enum": [
+                                null,
+                                "In",
+                                "NotIn"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "required": [
+                        "values"
+                    ],
+                    "properties": {
+                        "values": {
+                            "type": "array",
+                            "minItems": 1
+                        }
+                    }
+                }
+            }
+        },
+        "values must be empty for the Exists and DoesNotExist operators": {
+            "conditional": {
+                "$comment": "when the operator is Exists or DoesNotExist, forbid values",
+                "when": {
+                    "required": [
+                        "operator"
+                    ],
+                    "properties": {
+                        "operator": {
+                            "enum": [
+                                "Exists",
+                                "DoesNotExist"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "properties": {
+                        "values": {
+                            "maxItems": 0
+                        }
+                    }
+                }
+            }
+        }
+    }
+}
+`)
 	textAzureConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
     "$id": "http://determined.ai/schemas/expconf/v0/azure.json",
@@ -1080,6 +1279,16 @@ var (
             "minimum": 0,
             "default": 5
         },
+        "metric_rules": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/metric-rule.json"
+            }
+        },
         "min_checkpoint_period": {
             "type": [
                 "object",
@@ -1702,6 +1911,133 @@ var (
         ]
     }
 }
+`)
+	textMetricRuleV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/metric-rule.json",
+    "title": "MetricRule",
+    "additionalProperties": false,
+    "required": [
+        "name",
+        "metric",
+        "condition"
+    ],
+    "type": "object",
+    "properties": {
+        "name": {
+            "type": "string",
+            "checks": {
+                "name must be non-empty": {
+                    "minLength": 1
+                }
+            }
+        },
+        "metric": {
+            "type": "string",
+            "checks": {
+                "metric must be non-empty": {
+                    "minLength": 1
+                }
+            }
+        },
+        "metric_type": {
+            "enum": [
+                null,
+                "training",
+                "validation"
+            ],
+            "default": "validation"
+        },
+        "condition": {
+            "enum": [
+                "nan",
+                "greater_than",
+                "less_than",
+                "no_improvement"
+            ]
+        },
+        "threshold": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "default": null
+        },
+        "patience": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": null
+        },
+        "smaller_is_better": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": true
+        },
+        "action": {
+            "enum": [
+                null,
+                "kill_trial",
+                "pause_experiment",
+                "notify"
+            ],
+            "default": "notify"
+        }
+    },
+    "checks": {
+        "threshold must be set for the greater_than and less_than conditions": {
+            "conditional": {
+                "$comment": "when the condition compares to a threshold, expect a threshold",
+                "when": {
+                    "properties": {
+                        "condition": {
+                            "enum": [
+                                "greater_than",
+                                "less_than"
+                            ]
+                        }
+                    }
+                },
+                "enforce": {
+                    "required": [
+                        "threshold"
+                    ],
+                    "properties": {
+                        "threshold": {
+                            "type": "number"
+                        }
+                    }
+                }
+            }
+        },
+        "patience must be set for the no_improvement condition": {
+            "conditional": {
+                "$comment": "when the condition is no_improvement, expect a patience",
+                "when": {
+                    "properties": {
+                        "condition": {
+                            "const": "no_improvement"
+                        }
+                    }
+                },
+                "enforce": {
+                    "required": [
+                        "patience"
+                    ],
+                    "properties": {
+                        "patience": {
+                            "type": "integer"
+                        }
+                    }
+                }
+            }
+        }
+    }
+}
 `)
 	textNativeConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -1949,6 +2285,44 @@ var (
             ],
             "default": ""
         },
+        "agent_selectors": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/agent-selector.json"
+            },
+            "default": []
+        },
+        "agent_affinity": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/agent-affinity.json"
+            },
+            "default": []
+        },
+        "agent_anti_affinity": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/agent-affinity.json"
+            },
+            "default": []
+        },
+        "cpus": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "minimum": 0,
+            "default": null
+        },
         "devices": {
             "type": [
                 "array",
@@ -1964,6 +2338,14 @@ var (
             ],
             "default": null
         },
+        "memory": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 0,
+            "default": null
+        },
         "native_parallel": {
             "type": [
                 "boolean",
@@ -1994,6 +2376,15 @@ var (
             ],
             "default": null
         },
+        "slot_fraction": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "maximum": 1,
+            "default": null
+        },
         "slots": {
             "type": [
                 "integer",
@@ -3366,6 +3757,10 @@ var (
     }
 }
 `)
+	schemaAgentAffinityV0 interface{}
+
+	schemaAgentSelectorV0 interface{}
+
 	schemaAzureConfigV0 interface{}
 
 	schemaBindMountV0 interface{}
@@ -3432,6 +3827,8 @@ var (
 
 	schemaLengthV0 interface{}
 
+	schemaMetricRuleV0 interface{}
+
 	schemaNativeConfigV0 interface{}
 
 	schemaOptimizationsConfigV0 interface{}
@@ -3489,6 +3886,46 @@ var (
 	cachedSchemaBytesMap map[string][]byte
 )
 
+func ParsedAgentAffinityV0() interface{} {
+	cacheLock.RLock()
+	if schemaAgentAffinityV0 != nil {
+		cacheLock.RUnlock()
+		return schemaAgentAffinityV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaAgentAffinityV0 != nil {
+		return schemaAgentAffinityV0
+	}
+	err := json.Unmarshal(textAgentAffinityV0, &schemaAgentAffinityV0)
+	if err != nil {
+		panic("invalid embedded json for AgentAffinityV0")
+	}
+	return schemaAgentAffinityV0
+}
+
+func ParsedAgentSelectorV0() interface{} {
+	cacheLock.RLock()
+	if schemaAgentSelectorV0 != nil {
+		cacheLock.RUnlock()
+		return schemaAgentSelectorV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaAgentSelectorV0 != nil {
+		return schemaAgentSelectorV0
+	}
+	err := json.Unmarshal(textAgentSelectorV0, &schemaAgentSelectorV0)
+	if err != nil {
+		panic("invalid embedded json for AgentSelectorV0")
+	}
+	return schemaAgentSelectorV0
+}
+
 func ParsedAzureConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaAzureConfigV0 != nil {
@@ -4149,6 +4586,26 @@ func ParsedLengthV0() interface{} {
 	return schemaLengthV0
 }
 
+func ParsedMetricRuleV0() interface{} {
+	cacheLock.RLock()
+	if schemaMetricRuleV0 != nil {
+		cacheLock.RUnlock()
+		return schemaMetricRuleV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaMetricRuleV0 != nil {
+		return schemaMetricRuleV0
+	}
+	err := json.Unmarshal(textMetricRuleV0, &schemaMetricRuleV0)
+	if err != nil {
+		panic("invalid embedded json for MetricRuleV0")
+	}
+	return schemaMetricRuleV0
+}
+
 func ParsedNativeConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaNativeConfigV0 != nil {
@@ -4664,6 +5121,10 @@ func schemaBytesMap() map[string][]byte {
 	}
 	var url string
 	cachedSchemaBytesMap = map[string][]byte{}
+	url = "http://determined.ai/schemas/expconf/v0/agent-affinity.json"
+	cachedSchemaBytesMap[url] = textAgentAffinityV0
+	url = "http://determined.ai/schemas/expconf/v0/agent-selector.json"
+	cachedSchemaBytesMap[url] = textAgentSelectorV0
 	url = "http://determined.ai/schemas/expconf/v0/azure.json"
 	cachedSchemaBytesMap[url] = textAzureConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/bind-mount.json"
@@ -4730,6 +5191,8 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textKerberosConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/length.json"
 	cachedSchemaBytesMap[url] = textLengthV0
+	url = "http://determined.ai/schemas/expconf/v0/metric-rule.json"
+	cachedSchemaBytesMap[url] = textMetricRuleV0
 	url = "http://determined.ai/schemas/expconf/v0/native.json"
 	cachedSchemaBytesMap[url] = textNativeConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/optimizations.json"
@@ -2,15 +2,21 @@ package tasks
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
 
+	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
 )
 
 const (
 	hostMode container.NetworkMode = "host"
+
+	minPort = 1
+	maxPort = 65535
 )
 
 // trialUniquePortOffset determines a deterministic, unique offset for ports that would otherwise
@@ -35,3 +41,30 @@ func toPortSet(ports map[string]int) nat.PortSet {
 	}
 	return dockerPorts
 }
+
+// ExposedPortAddress returns the address at which a port of a running task container can be
+// reached from outside of it, given the addresses of the ports it exposes. Only the ports that a
+// task container exposes, which are the ports of its environment, are published by the container
+// runtime, so any other port is rejected.
+func ExposedPortAddress(addresses []cproto.Address, port int) (cproto.Address, error) {
+	if port < minPort || port > maxPort {
+		return cproto.Address{}, errors.Errorf(
+			"port %d is not in the range %d-%d", port, minPort, maxPort)
+	}
+
+	seen := map[int]bool{}
+	var exposed []int
+	for _, address := range addresses {
+		if address.ContainerPort == port {
+			return address, nil
+		}
+		if !seen[address.ContainerPort] {
+			seen[address.ContainerPort] = true
+			exposed = append(exposed, address.ContainerPort)
+		}
+	}
+	sort.Ints(exposed)
+	return cproto.Address{}, errors.Errorf(
+		"port %d is not exposed by the container, which exposes %v; "+
+			"add it to environment.ports to expose it", port, exposed)
+}
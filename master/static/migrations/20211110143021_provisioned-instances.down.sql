@@ -0,0 +1 @@
+DROP TABLE public.provisioned_instances;
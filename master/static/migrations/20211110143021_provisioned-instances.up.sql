@@ -0,0 +1,13 @@
+CREATE TABLE public.provisioned_instances (
+    instance_id text NOT NULL UNIQUE,
+    resource_pool text NOT NULL,
+    instance_type text NOT NULL,
+    slots integer NOT NULL,
+    -- The price per hour of the instance in the currency of the configured instance prices.
+    hourly_price double precision NOT NULL,
+    start_time timestamp with time zone NOT NULL,
+    end_time timestamp with time zone NULL
+);
+
+CREATE INDEX ix_provisioned_instances_resource_pool ON public.provisioned_instances
+    USING btree (resource_pool);
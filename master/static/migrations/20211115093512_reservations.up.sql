@@ -0,0 +1,14 @@
+CREATE TABLE public.reservations (
+    id SERIAL PRIMARY KEY,
+    resource_pool text NOT NULL,
+    slots integer NOT NULL CHECK (slots > 0),
+    -- The tasks that may use the reserved slots; an empty value matches every task.
+    username text NOT NULL DEFAULT '',
+    label text NOT NULL DEFAULT '',
+    start_time timestamp with time zone NOT NULL,
+    end_time timestamp with time zone NOT NULL,
+    CHECK (end_time > start_time)
+);
+
+CREATE INDEX ix_reservations_resource_pool ON public.reservations
+    USING btree (resource_pool);
@@ -0,0 +1 @@
+DROP TABLE public.allocation_preemptions;
@@ -0,0 +1,13 @@
+CREATE TABLE public.allocation_preemptions (
+    id SERIAL PRIMARY KEY,
+    allocation_id text NOT NULL,
+    task_id text NOT NULL REFERENCES public.tasks(task_id),
+    reason text NOT NULL,
+    -- The task and job that the allocation was preempted for, if any.
+    preempted_by text NULL,
+    preempted_by_job_id text NULL,
+    preempted_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX ix_allocation_preemptions_task_id ON public.allocation_preemptions
+    USING btree (task_id);
@@ -0,0 +1 @@
+DROP TABLE public.metric_rule_firings;
@@ -0,0 +1,17 @@
+CREATE TABLE public.metric_rule_firings (
+    id SERIAL PRIMARY KEY,
+    experiment_id integer NOT NULL REFERENCES public.experiments(id) ON DELETE CASCADE,
+    trial_id integer NOT NULL REFERENCES public.trials(id) ON DELETE CASCADE,
+    rule_name text NOT NULL,
+    metric_name text NOT NULL,
+    metric_type text NOT NULL,
+    condition text NOT NULL,
+    -- The value of the metric that fired the rule, which may be NaN.
+    value double precision NOT NULL,
+    action text NOT NULL,
+    total_batches integer NOT NULL,
+    fired_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX ix_metric_rule_firings_experiment_id ON public.metric_rule_firings
+    USING btree (experiment_id);
@@ -0,0 +1 @@
+DROP TABLE public.update_log;
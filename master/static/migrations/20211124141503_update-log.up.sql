@@ -0,0 +1,12 @@
+CREATE TABLE public.update_log (
+    seq BIGSERIAL PRIMARY KEY,
+    kind text NOT NULL,
+    experiment_id integer NOT NULL REFERENCES public.experiments(id) ON DELETE CASCADE,
+    trial_id integer NULL REFERENCES public.trials(id) ON DELETE CASCADE,
+    allocation_id text NULL,
+    checkpoint_uuid text NULL,
+    state text NOT NULL,
+    time timestamp with time zone NOT NULL
+);
+
+CREATE INDEX ix_update_log_time ON public.update_log USING btree (time);
@@ -0,0 +1,24 @@
+CREATE TABLE public.audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    time timestamp with time zone NOT NULL,
+    user_id integer NULL,
+    username text NOT NULL DEFAULT '',
+    allocation_id text NULL,
+    method text NOT NULL,
+    targets jsonb NOT NULL DEFAULT '{}',
+    request jsonb NOT NULL DEFAULT '{}',
+    result_code text NOT NULL,
+    source_ip text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ix_audit_log_time ON public.audit_log USING btree (time);
+
+-- Entries are only ever added, and deleted once they are older than the retention period.
+CREATE FUNCTION public.audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit log entries cannot be modified';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE ON public.audit_log
+    FOR EACH ROW EXECUTE PROCEDURE public.audit_log_append_only();
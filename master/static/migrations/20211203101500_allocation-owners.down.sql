@@ -0,0 +1,2 @@
+ALTER TABLE public.allocations
+    DROP COLUMN username;
@@ -0,0 +1,4 @@
+-- The owner of the task of an allocation, by which the cost of tasks other than trials, whose
+-- owners are not otherwise stored, is attributed to users.
+ALTER TABLE public.allocations
+    ADD COLUMN username text NULL;
@@ -0,0 +1,31 @@
+WITH filtered_entries AS (
+    SELECT
+        a.id,
+        a.time,
+        coalesce(a.user_id, 0) AS user_id,
+        a.username,
+        coalesce(a.allocation_id, '') AS allocation_id,
+        a.method,
+        a.targets,
+        a.request,
+        a.result_code,
+        a.source_ip
+    FROM audit_log a
+    WHERE
+        (coalesce(cardinality($1::text[]), 0) = 0 OR a.username = ANY($1::text[]))
+        AND ($2 = '' OR a.method ILIKE ('%' || $2 || '%'))
+        AND ($3 = '' OR EXISTS (SELECT 1 FROM jsonb_each_text(a.targets) t WHERE t.value = $3))
+        AND ($4 = '' OR a.result_code = $4)
+        AND ($5::timestamptz IS NULL OR a.time >= $5::timestamptz)
+        AND ($6::timestamptz IS NULL OR a.time < $6::timestamptz)
+), page_info AS (
+    SELECT public.page_info((SELECT COUNT(*) AS count FROM filtered_entries), $7, $8) AS page_info
+)
+SELECT
+   (SELECT coalesce(json_agg(paginated_entries), '[]'::json) FROM (
+        SELECT * FROM filtered_entries
+        ORDER BY id DESC
+        OFFSET (SELECT p.page_info->>'start_index' FROM page_info p)::bigint
+        LIMIT (SELECT (p.page_info->>'end_index')::bigint - (p.page_info->>'start_index')::bigint FROM page_info p)
+    ) AS paginated_entries) AS entries,
+    (SELECT p.page_info FROM page_info p) AS pagination
@@ -0,0 +1,151 @@
+WITH const AS (
+    SELECT
+        tstzrange($1 :: timestamptz, $2 :: timestamptz) AS period
+),
+-- Provisioned instances that were running at any time during the target interval, along with the
+-- length of the overlap of their lifetime with the requested period.
+instances AS (
+    SELECT
+        provisioned_instances.resource_pool,
+        provisioned_instances.slots,
+        provisioned_instances.hourly_price,
+        extract(
+            epoch
+            FROM
+                -- `*` computes the intersection of the two ranges.
+                upper(const.period * lifetime) - lower(const.period * lifetime)
+        ) AS seconds
+    FROM
+        (
+            SELECT
+                *,
+                tstzrange(start_time, coalesce(end_time, now())) AS lifetime
+            FROM
+                provisioned_instances
+        ) AS provisioned_instances,
+        const
+    WHERE
+        -- `&&` determines whether the ranges overlap.
+        const.period && lifetime
+),
+-- The cost of the instances of each resource pool and the slot-seconds they provided.
+pools AS (
+    SELECT
+        resource_pool,
+        sum(hourly_price * seconds / 3600) AS cost,
+        sum(slots * seconds) AS slot_seconds
+    FROM
+        instances
+    GROUP BY
+        resource_pool
+),
+-- Allocations in those resource pools during the target interval, along with the slot-seconds
+-- they used during the requested period.
+allocations_in_period AS (
+    SELECT
+        a.task_id,
+        a.resource_pool,
+        a.username,
+        a.slots * extract(
+            epoch
+            FROM
+                upper(const.period * lifetime) - lower(const.period * lifetime)
+        ) AS slot_seconds
+    FROM
+        (
+            SELECT
+                *,
+                -- Allocation times are stored in UTC without a time zone.
+                tstzrange(
+                    start_time AT TIME ZONE 'UTC',
+                    coalesce(end_time AT TIME ZONE 'UTC', now())
+                ) AS lifetime
+            FROM
+                allocations
+        ) AS a,
+        const
+    WHERE
+        const.period && lifetime
+        AND a.resource_pool IN (
+            SELECT
+                resource_pool
+            FROM
+                pools
+        )
+),
+-- The slot-seconds used by each group of tasks; trials are grouped by experiment. Trials are
+-- attributed to the owners of their experiments and other tasks to the owners recorded on their
+-- allocations.
+usage AS (
+    SELECT
+        a.resource_pool,
+        lower(tasks.task_type :: text) AS kind,
+        experiments.id AS experiment_id,
+        coalesce(users.username, a.username) AS username,
+        experiments.config -> 'labels' AS labels,
+        sum(a.slot_seconds) AS slot_seconds
+    FROM
+        allocations_in_period a
+        JOIN tasks ON a.task_id = tasks.task_id
+        LEFT JOIN trials ON a.task_id = trials.task_id
+        LEFT JOIN experiments ON trials.experiment_id = experiments.id
+        LEFT JOIN users ON experiments.owner_id = users.id
+    GROUP BY
+        a.resource_pool,
+        tasks.task_type,
+        experiments.id,
+        coalesce(users.username, a.username)
+),
+-- The cost of a slot-second in each resource pool. If tasks used more slot-seconds than the
+-- instances provided, e.g. on agents that the provisioner did not launch, the cost is spread over
+-- the slot-seconds the tasks used instead.
+rates AS (
+    SELECT
+        pools.resource_pool,
+        pools.cost,
+        pools.slot_seconds,
+        coalesce(used.slot_seconds, 0) AS used_slot_seconds,
+        CASE
+            WHEN greatest(pools.slot_seconds, used.slot_seconds) > 0
+            THEN pools.cost / greatest(pools.slot_seconds, used.slot_seconds)
+            ELSE 0
+        END AS rate
+    FROM
+        pools
+        LEFT JOIN (
+            SELECT
+                resource_pool,
+                sum(slot_seconds) AS slot_seconds
+            FROM
+                usage
+            GROUP BY
+                resource_pool
+        ) AS used ON pools.resource_pool = used.resource_pool
+)
+SELECT
+    usage.resource_pool,
+    usage.kind,
+    usage.experiment_id,
+    usage.username,
+    usage.labels,
+    usage.slot_seconds :: float8 AS slot_seconds,
+    (usage.slot_seconds * rates.rate) :: float8 AS cost
+FROM
+    usage
+    JOIN rates ON usage.resource_pool = rates.resource_pool
+UNION ALL
+-- The cost of the slots that no task used is attributed to the resource pool as idle.
+SELECT
+    resource_pool,
+    'idle' AS kind,
+    NULL AS experiment_id,
+    NULL AS username,
+    NULL AS labels,
+    greatest(slot_seconds - used_slot_seconds, 0) :: float8 AS slot_seconds,
+    (cost - used_slot_seconds * rate) :: float8 AS cost
+FROM
+    rates
+ORDER BY
+    resource_pool,
+    kind,
+    experiment_id
@@ -0,0 +1,15 @@
+-- Provisioned instances that were running at any time during the target interval. Agents that
+-- the provisioners did not launch are not recorded, so they are not part of the trace.
+SELECT
+    instance_id AS id,
+    resource_pool,
+    '' AS label,
+    slots,
+    start_time AS join_time,
+    end_time AS leave_time
+FROM
+    provisioned_instances
+WHERE
+    tstzrange(start_time, coalesce(end_time, 'infinity')) && tstzrange($1 :: timestamptz, $2 :: timestamptz)
+ORDER BY
+    start_time
@@ -0,0 +1,53 @@
+WITH runs AS (
+    SELECT
+        a.allocation_id,
+        a.task_id,
+        a.resource_pool,
+        a.agent_label,
+        a.slots,
+        a.start_time,
+        coalesce(a.end_time, now() AT TIME ZONE 'UTC') AS end_time,
+        -- A task is submitted when it is created and resubmitted when its previous allocation
+        -- ends, e.g. when a trial is paused and activated again or restarts after a failure.
+        coalesce(
+            LAG(a.end_time, 1) OVER (
+                PARTITION BY a.task_id
+                ORDER BY
+                    a.start_time
+            ),
+            t.start_time
+        ) AS submit_time
+    FROM
+        allocations a
+        JOIN tasks t ON a.task_id = t.task_id
+)
+SELECT
+    runs.allocation_id AS id,
+    -- Trials are grouped by experiment, the same way the scheduler groups them.
+    coalesce(trials.experiment_id :: text, runs.task_id) AS "group",
+    runs.resource_pool,
+    runs.agent_label AS label,
+    runs.slots,
+    (experiments.config -> 'resources' ->> 'priority') :: int AS priority,
+    coalesce((experiments.config -> 'resources' ->> 'weight') :: float8, 1) AS weight,
+    (experiments.config -> 'resources' ->> 'max_slots') :: int AS max_slots,
+    trials.id IS NOT NULL AS preemptible,
+    -- Allocation times are stored in UTC without a time zone.
+    least(runs.submit_time, runs.start_time) AT TIME ZONE 'UTC' AS submit_time,
+    extract(
+        epoch
+        FROM
+            runs.end_time - runs.start_time
+    ) :: float8 AS duration_seconds
+FROM
+    runs
+    LEFT JOIN trials ON runs.task_id = trials.task_id
+    LEFT JOIN experiments ON trials.experiment_id = experiments.id
+WHERE
+    runs.end_time > runs.start_time
+    AND tstzrange(
+        least(runs.submit_time, runs.start_time) AT TIME ZONE 'UTC',
+        runs.end_time AT TIME ZONE 'UTC'
+    ) && tstzrange($1 :: timestamptz, $2 :: timestamptz)
+ORDER BY
+    submit_time
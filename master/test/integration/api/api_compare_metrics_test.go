@@ -0,0 +1,90 @@
+//go:build integration
+// +build integration
+
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/test/testutils"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+func TestCompareMetrics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, cl, creds, err := testutils.RunMaster(ctx, nil)
+	defer cancel()
+	assert.NilError(t, err, "failed to start master")
+
+	experiment := testutils.ExperimentModel()
+	assert.NilError(t, pgDB.AddExperiment(experiment), "failed to insert experiment")
+	losses := [][]float64{{4, 2}, {3, 1}}
+	for _, trialLosses := range losses {
+		trial := testutils.TrialModel(experiment.ID)
+		assert.NilError(t, pgDB.AddTrial(trial), "failed to insert trial")
+		for i, loss := range trialLosses {
+			err := pgDB.AddTrainingMetrics(context.Background(), &trialv1.TrialMetrics{
+				TrialId:     int32(trial.ID),
+				LatestBatch: int32((i + 1) * 100),
+				Metrics: &structpb.Struct{Fields: map[string]*structpb.Value{
+					"loss": structpb.NewNumberValue(loss),
+				}},
+			})
+			assert.NilError(t, err, "failed to insert training metrics")
+		}
+	}
+
+	reqCtx, reqCancel := context.WithTimeout(creds, 10*time.Second)
+	defer reqCancel()
+	resp, err := cl.CompareMetrics(reqCtx, &apiv1.CompareMetricsRequest{
+		ExperimentIds: []int32{int32(experiment.ID)},
+		MetricNames:   []string{"loss"},
+		MetricType:    apiv1.MetricType_METRIC_TYPE_TRAINING,
+		Aggregation:   apiv1.MetricAggregation_METRIC_AGGREGATION_MIN,
+	})
+	assert.NilError(t, err, "failed to compare metrics")
+	assert.Equal(t, len(resp.Experiments), 1)
+	assert.Equal(t, len(resp.Experiments[0].Trials), len(losses))
+	for i, trial := range resp.Experiments[0].Trials {
+		assert.Equal(t, len(trial.Metrics), 1)
+		assert.Equal(t, len(trial.Metrics[0].Data), 2)
+		for j, point := range trial.Metrics[0].Data {
+			assert.Equal(t, point.Batches, int32((j+1)*100))
+			assert.Equal(t, point.Value, losses[i][j])
+		}
+	}
+	aggregate := resp.Experiments[0].Aggregates[0].Data
+	assert.Equal(t, aggregate[0].Value, float64(3))
+	assert.Equal(t, aggregate[1].Value, float64(1))
+}
+
+func TestCompareMetricsTooManyTrials(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, cl, creds, err := testutils.RunMaster(ctx, nil)
+	defer cancel()
+	assert.NilError(t, err, "failed to start master")
+
+	experiment := testutils.ExperimentModel()
+	assert.NilError(t, pgDB.AddExperiment(experiment), "failed to insert experiment")
+	for i := 0; i < 101; i++ {
+		assert.NilError(t, pgDB.AddTrial(testutils.TrialModel(experiment.ID)),
+			"failed to insert trial")
+	}
+
+	reqCtx, reqCancel := context.WithTimeout(creds, 10*time.Second)
+	defer reqCancel()
+	_, err = cl.CompareMetrics(reqCtx, &apiv1.CompareMetricsRequest{
+		ExperimentIds: []int32{int32(experiment.ID)},
+		MetricNames:   []string{"loss"},
+		MetricType:    apiv1.MetricType_METRIC_TYPE_TRAINING,
+	})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+}
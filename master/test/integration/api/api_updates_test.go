@@ -0,0 +1,220 @@
+//go:build integration
+// +build integration
+
+package api
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/master/test/testutils"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+var adminUserID model.UserID = 1
+
+func withLabels(labels ...string) testutils.ExperimentModelOption {
+	return testutils.ExperimentModelOptionFunc(func(e *model.Experiment) {
+		l := expconf.Labels{}
+		for _, label := range labels {
+			l[label] = true
+		}
+		e.Config.SetLabels(l)
+	})
+}
+
+func withOwner(id model.UserID) testutils.ExperimentModelOption {
+	return testutils.ExperimentModelOptionFunc(func(e *model.Experiment) {
+		e.OwnerID = &id
+	})
+}
+
+// setLegacyLabels stores the labels of the experiment as the keys of an object, the form of
+// configs of older versions.
+func setLegacyLabels(t *testing.T, id int, labels string) {
+	sql, err := sqlx.Connect("pgx", os.Getenv("DET_INTEGRATION_POSTGRES_URL"))
+	assert.NilError(t, err, "failed to connect to postgres")
+	defer sql.Close()
+	_, err = sql.Exec(
+		`UPDATE experiments SET config = jsonb_set(config, '{labels}', $2::jsonb) WHERE id = $1`,
+		id, labels)
+	assert.NilError(t, err, "failed to set legacy labels")
+}
+
+func addExperimentUpdate(t *testing.T, id int) int64 {
+	u := model.Update{
+		Kind:         model.UpdateKindExperiment,
+		ExperimentID: id,
+		State:        string(model.ActiveState),
+		Time:         time.Now().UTC(),
+	}
+	assert.NilError(t, pgDB.AddUpdate(&u), "failed to insert update")
+	return u.Seq
+}
+
+// reportCheckpoint reports a checkpoint of the trial, which the master publishes as an update.
+func reportCheckpoint(
+	ctx context.Context, t *testing.T, cl apiv1.DeterminedClient, trialID, batches int,
+) string {
+	id := uuid.New().String()
+	_, err := cl.ReportTrialCheckpointMetadata(ctx, &apiv1.ReportTrialCheckpointMetadataRequest{
+		CheckpointMetadata: &trialv1.CheckpointMetadata{
+			TrialId:           int32(trialID),
+			Uuid:              id,
+			Resources:         map[string]int64{"model.pt": 1},
+			Framework:         "pytorch",
+			Format:            "pickle",
+			DeterminedVersion: "1.0.0",
+			LatestBatch:       int32(batches),
+		},
+	})
+	assert.NilError(t, err, "failed to report checkpoint")
+	return id
+}
+
+func receiveUpdates(
+	t *testing.T, stream apiv1.Determined_StreamUpdatesClient, n int,
+) []*apiv1.StreamUpdatesResponse {
+	var resps []*apiv1.StreamUpdatesResponse
+	for len(resps) < n {
+		resp, err := stream.Recv()
+		assert.NilError(t, err, "failed to receive update")
+		resps = append(resps, resp)
+	}
+	return resps
+}
+
+func TestExperimentOwnerAndLabels(t *testing.T) {
+	experiment := testutils.ExperimentModel(withOwner(adminUserID), withLabels("a"))
+	assert.NilError(t, pgDB.AddExperiment(experiment), "failed to insert experiment")
+
+	owner, labels, err := pgDB.ExperimentOwnerAndLabels(experiment.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, owner, "admin")
+	assert.DeepEqual(t, labels, []string{"a"})
+
+	setLegacyLabels(t, experiment.ID, `{"b": true}`)
+	_, labels, err = pgDB.ExperimentOwnerAndLabels(experiment.ID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, labels, []string{"b"})
+}
+
+func TestUpdatesWindow(t *testing.T) {
+	experiment := testutils.ExperimentModel()
+	assert.NilError(t, pgDB.AddExperiment(experiment), "failed to insert experiment")
+	var seqs []int64
+	for i := 0; i < 4; i++ {
+		seqs = append(seqs, addExperimentUpdate(t, experiment.ID))
+	}
+
+	filter := model.UpdateFilter{ExperimentIDs: []int{experiment.ID}}
+	updates, err := pgDB.Updates(filter, seqs[0], seqs[2], 10)
+	assert.NilError(t, err)
+	assert.Equal(t, len(updates), 2)
+	assert.Equal(t, updates[0].Seq, seqs[1])
+	assert.Equal(t, updates[1].Seq, seqs[2])
+
+	updates, err = pgDB.Updates(filter, seqs[0], seqs[3], 1)
+	assert.NilError(t, err)
+	assert.Equal(t, len(updates), 1)
+	assert.Equal(t, updates[0].Seq, seqs[1])
+}
+
+func TestStreamUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, cl, creds, err := testutils.RunMaster(ctx, nil)
+	defer cancel()
+	assert.NilError(t, err, "failed to start master")
+
+	label := uuid.New().String()
+	labeled := testutils.ExperimentModel(withLabels(label))
+	legacy := testutils.ExperimentModel(withOwner(adminUserID))
+	other := testutils.ExperimentModel(withLabels("other"))
+	for _, e := range []*model.Experiment{labeled, legacy, other} {
+		assert.NilError(t, pgDB.AddExperiment(e), "failed to insert experiment")
+	}
+	setLegacyLabels(t, legacy.ID, `{"`+label+`": true}`)
+	trial := testutils.TrialModel(labeled.ID)
+	assert.NilError(t, pgDB.AddTrial(trial), "failed to insert trial")
+	ids := []int32{int32(labeled.ID), int32(legacy.ID), int32(other.ID)}
+
+	// Streams resume after this update.
+	since := addExperimentUpdate(t, other.ID)
+	labeledSeq := addExperimentUpdate(t, labeled.ID)
+	legacySeq := addExperimentUpdate(t, legacy.ID)
+	addExperimentUpdate(t, other.ID)
+
+	reqCtx, reqCancel := context.WithTimeout(creds, 30*time.Second)
+	defer reqCancel()
+	// The master publishes the first checkpoint before the streams subscribe, so the streams read
+	// it from the update log along with the updates inserted above.
+	replayed := reportCheckpoint(reqCtx, t, cl, trial.ID, 100)
+
+	byLabel, err := cl.StreamUpdates(reqCtx, &apiv1.StreamUpdatesRequest{
+		ExperimentIds: ids, Labels: []string{label}, SinceSeq: since,
+	})
+	assert.NilError(t, err, "failed to stream updates")
+	byUser, err := cl.StreamUpdates(reqCtx, &apiv1.StreamUpdatesRequest{
+		ExperimentIds: ids, Users: []string{"admin"}, SinceSeq: since,
+	})
+	assert.NilError(t, err, "failed to stream updates")
+
+	resps := receiveUpdates(t, byLabel, 3)
+	assert.Equal(t, resps[0].Seq, labeledSeq)
+	assert.Equal(t, resps[1].Seq, legacySeq)
+	assert.Equal(t, resps[2].Kind, apiv1.UpdateKind_UPDATE_KIND_CHECKPOINT)
+	assert.Equal(t, resps[2].CheckpointUuid, replayed)
+
+	resps = receiveUpdates(t, byUser, 1)
+	assert.Equal(t, resps[0].Seq, legacySeq)
+	assert.Equal(t, int(resps[0].ExperimentId), legacy.ID)
+
+	// Updates published after the streams subscribed are pushed to the matching streams.
+	live := reportCheckpoint(reqCtx, t, cl, trial.ID, 200)
+	resps = receiveUpdates(t, byLabel, 1)
+	assert.Equal(t, resps[0].CheckpointUuid, live)
+	assert.Assert(t, resps[0].Seq > since)
+}
+
+func TestStreamUpdatesResumesInBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, cl, creds, err := testutils.RunMaster(ctx, nil)
+	defer cancel()
+	assert.NilError(t, err, "failed to start master")
+
+	experiment := testutils.ExperimentModel()
+	assert.NilError(t, pgDB.AddExperiment(experiment), "failed to insert experiment")
+	trial := testutils.TrialModel(experiment.ID)
+	assert.NilError(t, pgDB.AddTrial(trial), "failed to insert trial")
+
+	// The stream resumes after this update.
+	since := addExperimentUpdate(t, experiment.ID)
+	// More updates than the master reads from the update log at once.
+	const numUpdates = 1001
+	for i := 0; i < numUpdates; i++ {
+		addExperimentUpdate(t, experiment.ID)
+	}
+
+	reqCtx, reqCancel := context.WithTimeout(creds, time.Minute)
+	defer reqCancel()
+	reportCheckpoint(reqCtx, t, cl, trial.ID, 100)
+
+	stream, err := cl.StreamUpdates(reqCtx, &apiv1.StreamUpdatesRequest{
+		ExperimentIds: []int32{int32(experiment.ID)}, SinceSeq: since,
+	})
+	assert.NilError(t, err, "failed to stream updates")
+	resps := receiveUpdates(t, stream, numUpdates+1)
+	for i := 1; i < len(resps); i++ {
+		assert.Assert(t, resps[i].Seq > resps[i-1].Seq)
+	}
+	assert.Equal(t, resps[numUpdates].Kind, apiv1.UpdateKind_UPDATE_KIND_CHECKPOINT)
+}
@@ -0,0 +1,88 @@
+//go:build integration
+// +build integration
+
+package ha
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal"
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/leader"
+	"github.com/determined-ai/determined/master/pkg/logger"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/test/testutils"
+	"github.com/determined-ai/determined/master/version"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// haMasterConfig returns the configuration of a highly available master listening on the port.
+func haMasterConfig(t *testing.T, port int) *config.Config {
+	c, err := testutils.DefaultMasterConfig()
+	assert.NilError(t, err)
+	c.Port = port
+	c.HighAvailability = &leader.Config{
+		LockID:        leader.DefaultLockID + 1,
+		CheckInterval: model.Duration(100 * time.Millisecond),
+	}
+	return c
+}
+
+// runMaster runs a master until the context is canceled, returning a channel that receives the
+// error it exits with.
+func runMaster(ctx context.Context, c *config.Config) <-chan error {
+	exited := make(chan error, 1)
+	m := internal.New(version.Version, logger.NewLogBuffer(100), c)
+	go func() {
+		exited <- m.Run(ctx)
+	}()
+	return exited
+}
+
+// serving returns whether a master accepts requests on the port of the configuration.
+func serving(c *config.Config) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, fmt.Sprintf("localhost:%d", c.Port),
+		grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	_, err = apiv1.NewDeterminedClient(conn).GetMaster(ctx, &apiv1.GetMasterRequest{})
+	return err == nil
+}
+
+func TestStandbyTakesOver(t *testing.T) {
+	leaderConfig := haMasterConfig(t, 8081)
+	standbyConfig := haMasterConfig(t, 8082)
+
+	leaderCtx, stopLeader := context.WithCancel(context.Background())
+	defer stopLeader()
+	leaderExited := runMaster(leaderCtx, leaderConfig)
+	_, err := testutils.ConnectMaster(leaderConfig)
+	assert.NilError(t, err)
+
+	standbyCtx, stopStandby := context.WithCancel(context.Background())
+	defer stopStandby()
+	standbyExited := runMaster(standbyCtx, standbyConfig)
+
+	// The standby waits for the lock without serving requests.
+	time.Sleep(time.Second)
+	assert.Assert(t, !serving(standbyConfig), "standby serves requests while another master leads")
+
+	// Once the leader stops, the standby restores from the database and serves requests.
+	stopLeader()
+	assert.Equal(t, <-leaderExited, context.Canceled)
+	_, err = testutils.ConnectMaster(standbyConfig)
+	assert.NilError(t, err)
+
+	stopStandby()
+	assert.Equal(t, <-standbyExited, context.Canceled)
+}
@@ -10,7 +10,6 @@ import (
 	_ "github.com/goreleaser/goreleaser"
 	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-grpc-gateway"
 	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger"
-	_ "github.com/ryanbressler/CloudForest/growforest"
 	_ "github.com/swaggo/swag/cmd/swag"
 	_ "github.com/vektra/mockery/v2"
 	_ "golang.org/x/tools/cmd/goimports"
@@ -1,6 +1,7 @@
 import React, { useCallback, useState } from 'react';
 
 import awsLogo from 'assets/aws-logo.svg';
+import azureLogo from 'assets/azure-logo.svg';
 import gcpLogo from 'assets/gcp-logo.svg';
 import k8sLogo from 'assets/k8s-logo.svg';
 import staticLogo from 'assets/on-prem-logo.svg';
@@ -31,6 +32,9 @@ export const rpLogo = (type: V1ResourcePoolType): React.ReactNode => {
     case V1ResourcePoolType.GCP:
       iconSrc = gcpLogo;
       break;
+    case V1ResourcePoolType.AZURE:
+      iconSrc = azureLogo;
+      break;
     case V1ResourcePoolType.K8S:
       iconSrc = k8sLogo;
       break;
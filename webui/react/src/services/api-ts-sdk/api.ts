@@ -78,6 +78,26 @@ export class RequiredError extends Error {
     }
 }
 
+/**
+ * A possibly down-sampled series of readings of a metric. The series of a metric share the batches at which they are reported.
+ * @export
+ * @interface CompareMetricsResponseSeries
+ */
+export interface CompareMetricsResponseSeries {
+    /**
+     * The name of the metric.
+     * @type {string}
+     * @memberof CompareMetricsResponseSeries
+     */
+    metricName: string;
+    /**
+     * The readings of the metric.
+     * @type {Array<V1CompareMetricsResponseDataPoint>}
+     * @memberof CompareMetricsResponseSeries
+     */
+    data: Array<V1CompareMetricsResponseDataPoint>;
+}
+
 /**
  * The current state of the checkpoint.   - STATE_UNSPECIFIED: The state of the checkpoint is unknown.  - STATE_ACTIVE: The checkpoint is in an active state.  - STATE_COMPLETED: The checkpoint is persisted to checkpoint storage.  - STATE_ERROR: The checkpoint errored.  - STATE_DELETED: The checkpoint has been deleted.
  * @export
@@ -470,6 +490,26 @@ export interface StreamResultOfV1NotebookLogsResponse {
     error?: RuntimeStreamError;
 }
 
+/**
+ * 
+ * @export
+ * @interface StreamResultOfV1StreamUpdatesResponse
+ */
+export interface StreamResultOfV1StreamUpdatesResponse {
+    /**
+     * 
+     * @type {V1StreamUpdatesResponse}
+     * @memberof StreamResultOfV1StreamUpdatesResponse
+     */
+    result?: V1StreamUpdatesResponse;
+    /**
+     * 
+     * @type {RuntimeStreamError}
+     * @memberof StreamResultOfV1StreamUpdatesResponse
+     */
+    error?: RuntimeStreamError;
+}
+
 /**
  * 
  * @export
@@ -770,6 +810,12 @@ export interface V1Agent {
      * @memberof V1Agent
      */
     draining?: boolean;
+    /**
+     * Key/value labels applied to the agent for agent selectors and affinities.
+     * @type {{ [key: string]: string; }}
+     * @memberof V1Agent
+     */
+    labels?: { [key: string]: string; };
 }
 
 /**
@@ -836,6 +882,74 @@ export interface V1ArchiveExperimentResponse {
 export interface V1ArchiveModelResponse {
 }
 
+/**
+ * A mutating API call recorded in the audit log.
+ * @export
+ * @interface V1AuditLogEntry
+ */
+export interface V1AuditLogEntry {
+    /**
+     * The id of the entry, increasing across all entries.
+     * @type {string}
+     * @memberof V1AuditLogEntry
+     */
+    id: string;
+    /**
+     * The time the call completed.
+     * @type {Date}
+     * @memberof V1AuditLogEntry
+     */
+    time: Date;
+    /**
+     * The id of the user that made the call, if it was authenticated as a user.
+     * @type {number}
+     * @memberof V1AuditLogEntry
+     */
+    userId?: number;
+    /**
+     * The username of the user that made the call. For logins, the username that was logged in as.
+     * @type {string}
+     * @memberof V1AuditLogEntry
+     */
+    username?: string;
+    /**
+     * The allocation whose session made the call, if it was made by a task.
+     * @type {string}
+     * @memberof V1AuditLogEntry
+     */
+    allocationId?: string;
+    /**
+     * The called method: the full gRPC method, or the HTTP method and path of calls outside of /api/v1.
+     * @type {string}
+     * @memberof V1AuditLogEntry
+     */
+    method: string;
+    /**
+     * The ids of the objects the call targeted, from the request and response.
+     * @type {any}
+     * @memberof V1AuditLogEntry
+     */
+    targets: any;
+    /**
+     * A summary of the request, with passwords and other secrets redacted.
+     * @type {any}
+     * @memberof V1AuditLogEntry
+     */
+    request: any;
+    /**
+     * The gRPC status code of the call, or the HTTP status code of calls outside of /api/v1.
+     * @type {string}
+     * @memberof V1AuditLogEntry
+     */
+    resultCode: string;
+    /**
+     * The address of the client that made the call.
+     * @type {string}
+     * @memberof V1AuditLogEntry
+     */
+    sourceIp?: string;
+}
+
 /**
  * 
  * @export
@@ -1124,6 +1238,92 @@ export interface V1Command {
     jobId: string;
 }
 
+/**
+ * Response to CompareMetricsRequest.
+ * @export
+ * @interface V1CompareMetricsResponse
+ */
+export interface V1CompareMetricsResponse {
+    /**
+     * The experiments in the order they are requested, followed by the experiments of other requested trials.
+     * @type {Array<V1CompareMetricsResponseExperiment>}
+     * @memberof V1CompareMetricsResponse
+     */
+    experiments: Array<V1CompareMetricsResponseExperiment>;
+}
+
+/**
+ * One datapoint in a series of metrics.
+ * @export
+ * @interface V1CompareMetricsResponseDataPoint
+ */
+export interface V1CompareMetricsResponseDataPoint {
+    /**
+     * Total batches processed by the time this measurement is taken.
+     * @type {number}
+     * @memberof V1CompareMetricsResponseDataPoint
+     */
+    batches: number;
+    /**
+     * Value of the metric at this point.
+     * @type {number}
+     * @memberof V1CompareMetricsResponseDataPoint
+     */
+    value: number;
+}
+
+/**
+ * The trials of an experiment.
+ * @export
+ * @interface V1CompareMetricsResponseExperiment
+ */
+export interface V1CompareMetricsResponseExperiment {
+    /**
+     * The id of the experiment.
+     * @type {number}
+     * @memberof V1CompareMetricsResponseExperiment
+     */
+    experimentId: number;
+    /**
+     * The requested trials of the experiment.
+     * @type {Array<V1CompareMetricsResponseTrial>}
+     * @memberof V1CompareMetricsResponseExperiment
+     */
+    trials: Array<V1CompareMetricsResponseTrial>;
+    /**
+     * A series for each requested metric aggregated over the trials, if an aggregation is requested.
+     * @type {Array<CompareMetricsResponseSeries>}
+     * @memberof V1CompareMetricsResponseExperiment
+     */
+    aggregates?: Array<CompareMetricsResponseSeries>;
+}
+
+/**
+ * Metadata and metrics of a trial.
+ * @export
+ * @interface V1CompareMetricsResponseTrial
+ */
+export interface V1CompareMetricsResponseTrial {
+    /**
+     * The id of the trial.
+     * @type {number}
+     * @memberof V1CompareMetricsResponseTrial
+     */
+    trialId: number;
+    /**
+     * Hyperparameter values for this specific trial.
+     * @type {any}
+     * @memberof V1CompareMetricsResponseTrial
+     */
+    hparams: any;
+    /**
+     * A series for each requested metric.
+     * @type {Array<CompareMetricsResponseSeries>}
+     * @memberof V1CompareMetricsResponseTrial
+     */
+    metrics: Array<CompareMetricsResponseSeries>;
+}
+
 /**
  * 
  * @export
@@ -1584,6 +1784,70 @@ export enum V1FittingPolicy {
     KUBERNETES = <any> 'FITTING_POLICY_KUBERNETES'
 }
 
+/**
+ * Forward a port of a running container of an allocation through the master proxy.
+ * @export
+ * @interface V1ForwardAllocationPortRequest
+ */
+export interface V1ForwardAllocationPortRequest {
+    /**
+     * The id of the allocation.
+     * @type {string}
+     * @memberof V1ForwardAllocationPortRequest
+     */
+    allocationId: string;
+    /**
+     * The port to forward, which the container must expose.
+     * @type {number}
+     * @memberof V1ForwardAllocationPortRequest
+     */
+    port: number;
+    /**
+     * The id of the container, which may be omitted if the allocation has only one container.
+     * @type {string}
+     * @memberof V1ForwardAllocationPortRequest
+     */
+    containerId?: string;
+    /**
+     * Whether to tunnel TCP connections over WebSocket rather than proxy HTTP and WebSocket requests.
+     * @type {boolean}
+     * @memberof V1ForwardAllocationPortRequest
+     */
+    tcp?: boolean;
+    /**
+     * The number of seconds to forward the port for. Defaults to an hour and may be at most a day.
+     * @type {number}
+     * @memberof V1ForwardAllocationPortRequest
+     */
+    expirySeconds?: number;
+}
+
+/**
+ * Response to ForwardAllocationPortRequest.
+ * @export
+ * @interface V1ForwardAllocationPortResponse
+ */
+export interface V1ForwardAllocationPortResponse {
+    /**
+     * The id of the proxied service that forwards the port.
+     * @type {string}
+     * @memberof V1ForwardAllocationPortResponse
+     */
+    serviceId: string;
+    /**
+     * The path on the master that the port is forwarded at.
+     * @type {string}
+     * @memberof V1ForwardAllocationPortResponse
+     */
+    proxyAddress: string;
+    /**
+     * The time at which the port stops being forwarded.
+     * @type {Date}
+     * @memberof V1ForwardAllocationPortResponse
+     */
+    expireTime: Date;
+}
+
 /**
  * Response to GetAgentRequest.
  * @export
@@ -1629,6 +1893,26 @@ export interface V1GetAgentsResponse {
     pagination?: V1Pagination;
 }
 
+/**
+ * Response to GetAuditLogRequest.
+ * @export
+ * @interface V1GetAuditLogResponse
+ */
+export interface V1GetAuditLogResponse {
+    /**
+     * The entries of the audit log.
+     * @type {Array<V1AuditLogEntry>}
+     * @memberof V1GetAuditLogResponse
+     */
+    entries: Array<V1AuditLogEntry>;
+    /**
+     * Pagination information of the full dataset.
+     * @type {V1Pagination}
+     * @memberof V1GetAuditLogResponse
+     */
+    pagination: V1Pagination;
+}
+
 /**
  * Response to GetBestSearcherValidationMetricRequest.
  * @export
@@ -1780,6 +2064,20 @@ export interface V1GetExperimentLabelsResponse {
     labels?: Array<string>;
 }
 
+/**
+ * Response to GetExperimentMetricRuleFiringsRequest.
+ * @export
+ * @interface V1GetExperimentMetricRuleFiringsResponse
+ */
+export interface V1GetExperimentMetricRuleFiringsResponse {
+    /**
+     * The firings of the metric rules of the experiment, ordered by time.
+     * @type {Array<V1MetricRuleFiring>}
+     * @memberof V1GetExperimentMetricRuleFiringsResponse
+     */
+    firings: Array<V1MetricRuleFiring>;
+}
+
 /**
  * Response to GetExperimentRequest.
  * @export
@@ -2164,6 +2462,20 @@ export interface V1GetNotebooksResponse {
     pagination?: V1Pagination;
 }
 
+/**
+ * Response to GetPendingTasksRequest.
+ * @export
+ * @interface V1GetPendingTasksResponse
+ */
+export interface V1GetPendingTasksResponse {
+    /**
+     * The tasks that are waiting for resources.
+     * @type {Array<V1PendingTask>}
+     * @memberof V1GetPendingTasksResponse
+     */
+    pendingTasks: Array<V1PendingTask>;
+}
+
 /**
  * Response to GetResourcePoolsRequest.
  * @export
@@ -2948,6 +3260,18 @@ export interface V1MasterLogsResponse {
     logEntry?: V1LogEntry;
 }
 
+/**
+ * How series of metrics are aggregated over the trials of an experiment.   - METRIC_AGGREGATION_UNSPECIFIED: Do not aggregate the series.  - METRIC_AGGREGATION_MEAN: The mean over the trials.  - METRIC_AGGREGATION_MIN: The minimum over the trials.  - METRIC_AGGREGATION_MAX: The maximum over the trials.
+ * @export
+ * @enum {string}
+ */
+export enum V1MetricAggregation {
+    UNSPECIFIED = <any> 'METRIC_AGGREGATION_UNSPECIFIED',
+    MEAN = <any> 'METRIC_AGGREGATION_MEAN',
+    MIN = <any> 'METRIC_AGGREGATION_MIN',
+    MAX = <any> 'METRIC_AGGREGATION_MAX'
+}
+
 /**
  * Response to MetricBatchesRequest.
  * @export
@@ -2988,6 +3312,80 @@ export interface V1MetricNamesResponse {
     validationMetrics?: Array<string>;
 }
 
+/**
+ * MetricRuleFiring is a firing of a metric rule of an experiment: a metric reported by a trial that met the condition of the rule.
+ * @export
+ * @interface V1MetricRuleFiring
+ */
+export interface V1MetricRuleFiring {
+    /**
+     * The id of the firing.
+     * @type {number}
+     * @memberof V1MetricRuleFiring
+     */
+    id: number;
+    /**
+     * The id of the experiment of the rule.
+     * @type {number}
+     * @memberof V1MetricRuleFiring
+     */
+    experimentId: number;
+    /**
+     * The id of the trial that reported the metric.
+     * @type {number}
+     * @memberof V1MetricRuleFiring
+     */
+    trialId: number;
+    /**
+     * The name of the rule.
+     * @type {string}
+     * @memberof V1MetricRuleFiring
+     */
+    ruleName: string;
+    /**
+     * The name of the metric.
+     * @type {string}
+     * @memberof V1MetricRuleFiring
+     */
+    metricName: string;
+    /**
+     * The type of the metric, "training" or "validation".
+     * @type {string}
+     * @memberof V1MetricRuleFiring
+     */
+    metricType: string;
+    /**
+     * The condition of the rule that the metric met.
+     * @type {string}
+     * @memberof V1MetricRuleFiring
+     */
+    condition: string;
+    /**
+     * The value of the metric.
+     * @type {number}
+     * @memberof V1MetricRuleFiring
+     */
+    value: number;
+    /**
+     * The action taken when the rule fired.
+     * @type {string}
+     * @memberof V1MetricRuleFiring
+     */
+    action: string;
+    /**
+     * The number of batches the trial had trained on when it reported the metric.
+     * @type {number}
+     * @memberof V1MetricRuleFiring
+     */
+    totalBatches: number;
+    /**
+     * The time the rule fired.
+     * @type {Date}
+     * @memberof V1MetricRuleFiring
+     */
+    firedAt: Date;
+}
+
 /**
  * To distinguish the 2 different categories of metrics.   - METRIC_TYPE_UNSPECIFIED: Zero-value (not allowed).  - METRIC_TYPE_TRAINING: For metrics emitted during training.  - METRIC_TYPE_VALIDATION: For metrics emitted during validation.
  * @export
@@ -3509,41 +3907,108 @@ export interface V1PauseExperimentResponse {
 }
 
 /**
- * Request for updating a checkpoints metadata.
+ * The reason a task is waiting for resources.   - PENDING_REASON_UNSPECIFIED: The reason is unknown, e.g. the scheduler has not considered the task yet.  - PENDING_REASON_NO_MATCHING_AGENTS: No enabled agent satisfies the label and agent selectors of the task.  - PENDING_REASON_INSUFFICIENT_AGENT_CAPACITY: The task does not fit the agents even if they were idle.  - PENDING_REASON_INSUFFICIENT_FREE_SLOTS: The agents do not have enough free slots for the task.  - PENDING_REASON_GROUP_MAX_SLOTS: The group of the task would exceed its maximum number of slots.  - PENDING_REASON_FAIR_SHARE: The fair share of the group of the task is too small for the task.  - PENDING_REASON_LOWER_PRIORITY: Tasks of higher priority are waiting for resources.  - PENDING_REASON_PREEMPTION: The task is waiting for other tasks to be preempted.  - PENDING_REASON_WAITING_FOR_PROVISIONER: The task is waiting for the provisioner to launch instances.
  * @export
- * @interface V1PostCheckpointMetadataRequest
+ * @enum {string}
  */
-export interface V1PostCheckpointMetadataRequest {
-    /**
-     * The desired checkpoint fields and values.
-     * @type {V1Checkpoint}
-     * @memberof V1PostCheckpointMetadataRequest
-     */
-    checkpoint?: V1Checkpoint;
+export enum V1PendingReason {
+    UNSPECIFIED = <any> 'PENDING_REASON_UNSPECIFIED',
+    NOMATCHINGAGENTS = <any> 'PENDING_REASON_NO_MATCHING_AGENTS',
+    INSUFFICIENTAGENTCAPACITY = <any> 'PENDING_REASON_INSUFFICIENT_AGENT_CAPACITY',
+    INSUFFICIENTFREESLOTS = <any> 'PENDING_REASON_INSUFFICIENT_FREE_SLOTS',
+    GROUPMAXSLOTS = <any> 'PENDING_REASON_GROUP_MAX_SLOTS',
+    FAIRSHARE = <any> 'PENDING_REASON_FAIR_SHARE',
+    LOWERPRIORITY = <any> 'PENDING_REASON_LOWER_PRIORITY',
+    PREEMPTION = <any> 'PENDING_REASON_PREEMPTION',
+    WAITINGFORPROVISIONER = <any> 'PENDING_REASON_WAITING_FOR_PROVISIONER'
 }
 
 /**
- * Response to PostCheckpointRequest.
+ * A task that is waiting for resources.
  * @export
- * @interface V1PostCheckpointMetadataResponse
+ * @interface V1PendingTask
  */
-export interface V1PostCheckpointMetadataResponse {
+export interface V1PendingTask {
     /**
-     * The updated checkpoint.
-     * @type {V1Checkpoint}
-     * @memberof V1PostCheckpointMetadataResponse
+     * The id of the task.
+     * @type {string}
+     * @memberof V1PendingTask
      */
-    checkpoint?: V1Checkpoint;
-}
-
-/**
- * Request for creating a model in the registry.
- * @export
- * @interface V1PostModelRequest
- */
-export interface V1PostModelRequest {
+    taskId?: string;
     /**
-     * The name of the model.
+     * The id of the allocation of the task.
+     * @type {string}
+     * @memberof V1PendingTask
+     */
+    allocationId?: string;
+    /**
+     * The name of the task.
+     * @type {string}
+     * @memberof V1PendingTask
+     */
+    name?: string;
+    /**
+     * The resource pool the task is waiting for.
+     * @type {string}
+     * @memberof V1PendingTask
+     */
+    resourcePool?: string;
+    /**
+     * The number of slots the task needs.
+     * @type {number}
+     * @memberof V1PendingTask
+     */
+    slotsNeeded?: number;
+    /**
+     * Why the scheduler has not allocated resources to the task.
+     * @type {V1PendingReason}
+     * @memberof V1PendingTask
+     */
+    reason?: V1PendingReason;
+    /**
+     * A description of the reason.
+     * @type {string}
+     * @memberof V1PendingTask
+     */
+    message?: string;
+}
+
+/**
+ * Request for updating a checkpoints metadata.
+ * @export
+ * @interface V1PostCheckpointMetadataRequest
+ */
+export interface V1PostCheckpointMetadataRequest {
+    /**
+     * The desired checkpoint fields and values.
+     * @type {V1Checkpoint}
+     * @memberof V1PostCheckpointMetadataRequest
+     */
+    checkpoint?: V1Checkpoint;
+}
+
+/**
+ * Response to PostCheckpointRequest.
+ * @export
+ * @interface V1PostCheckpointMetadataResponse
+ */
+export interface V1PostCheckpointMetadataResponse {
+    /**
+     * The updated checkpoint.
+     * @type {V1Checkpoint}
+     * @memberof V1PostCheckpointMetadataResponse
+     */
+    checkpoint?: V1Checkpoint;
+}
+
+/**
+ * Request for creating a model in the registry.
+ * @export
+ * @interface V1PostModelRequest
+ */
+export interface V1PostModelRequest {
+    /**
+     * The name of the model.
      * @type {string}
      * @memberof V1PostModelRequest
      */
@@ -3969,6 +4434,126 @@ export interface V1ResourceAllocationRawResponse {
     resourceEntries?: Array<V1ResourceAllocationRawEntry>;
 }
 
+/**
+ * The cost of the slots of provisioned instances that a group of tasks used, or that were left idle, in a resource pool during a period.
+ * @export
+ * @interface V1ResourceCostEntry
+ */
+export interface V1ResourceCostEntry {
+    /**
+     * The resource pool of the instances.
+     * @type {string}
+     * @memberof V1ResourceCostEntry
+     */
+    resourcePool: string;
+    /**
+     * The type of the tasks (trial, notebook, shell, command, tensorboard or checkpoint_gc), or idle for the slots that no task used.
+     * @type {string}
+     * @memberof V1ResourceCostEntry
+     */
+    kind: string;
+    /**
+     * The ID of the experiment the trials are a part of.
+     * @type {number}
+     * @memberof V1ResourceCostEntry
+     */
+    experimentId?: number;
+    /**
+     * The username of the user who ran the experiment.
+     * @type {string}
+     * @memberof V1ResourceCostEntry
+     */
+    username?: string;
+    /**
+     * The labels assigned to the experiment.
+     * @type {Array<string>}
+     * @memberof V1ResourceCostEntry
+     */
+    labels?: Array<string>;
+    /**
+     * The number of slot-seconds used during the requested period.
+     * @type {number}
+     * @memberof V1ResourceCostEntry
+     */
+    slotSeconds: number;
+    /**
+     * The cost of the slot-seconds, in the currency of the instance prices.
+     * @type {number}
+     * @memberof V1ResourceCostEntry
+     */
+    cost: number;
+}
+
+/**
+ * The cost of the provisioned instances in the cluster during a period, attributed to the experiments, users, labels and resource pools that used them.
+ * @export
+ * @interface V1ResourceCostReport
+ */
+export interface V1ResourceCostReport {
+    /**
+     * The total cost of the instances.
+     * @type {number}
+     * @memberof V1ResourceCostReport
+     */
+    totalCost: number;
+    /**
+     * The cost of the slots that no task used.
+     * @type {number}
+     * @memberof V1ResourceCostReport
+     */
+    idleCost: number;
+    /**
+     * The cost of the slots used by each experiment.
+     * @type {{ [key: string]: number; }}
+     * @memberof V1ResourceCostReport
+     */
+    byExperiment: { [key: string]: number; };
+    /**
+     * The cost of the slots used by experiments belonging to each user.
+     * @type {{ [key: string]: number; }}
+     * @memberof V1ResourceCostReport
+     */
+    byUsername: { [key: string]: number; };
+    /**
+     * The cost of the slots used by experiments labeled with each label.
+     * @type {{ [key: string]: number; }}
+     * @memberof V1ResourceCostReport
+     */
+    byExperimentLabel: { [key: string]: number; };
+    /**
+     * The total cost of the instances of each resource pool.
+     * @type {{ [key: string]: number; }}
+     * @memberof V1ResourceCostReport
+     */
+    byResourcePool: { [key: string]: number; };
+    /**
+     * The cost of the slots that no task used in each resource pool.
+     * @type {{ [key: string]: number; }}
+     * @memberof V1ResourceCostReport
+     */
+    idleByResourcePool: { [key: string]: number; };
+}
+
+/**
+ * Response to ResourceCostRequest.
+ * @export
+ * @interface V1ResourceCostResponse
+ */
+export interface V1ResourceCostResponse {
+    /**
+     * The cost attributed to experiments, users, labels and resource pools.
+     * @type {V1ResourceCostReport}
+     * @memberof V1ResourceCostResponse
+     */
+    report: V1ResourceCostReport;
+    /**
+     * An entry for the cost of each group of tasks and of the idle slots of each resource pool.
+     * @type {Array<V1ResourceCostEntry>}
+     * @memberof V1ResourceCostResponse
+     */
+    costEntries: Array<V1ResourceCostEntry>;
+}
+
 /**
  * A Resource Pool is a pool of resources where containers are run.
  * @export
@@ -4048,13 +4633,13 @@ export interface V1ResourcePool {
      */
     preemptible: boolean;
     /**
-     * When using dynamic agents, the minimum number of agents that can exist in the resource pool.
+     * When using dynamic agents, the minimum number of agents that can exist in the resource pool, as currently set by the scaling schedules.
      * @type {number}
      * @memberof V1ResourcePool
      */
     minAgents: number;
     /**
-     * When using dynamic agents, the maximum number of agents that can exist in the resource pool.
+     * When using dynamic agents, the maximum number of agents that can exist in the resource pool, as currently set by the scaling schedules.
      * @type {number}
      * @memberof V1ResourcePool
      */
@@ -4442,7 +5027,7 @@ export interface V1ResourcePoolPrioritySchedulerDetail {
 }
 
 /**
- * The type of the ResourcePool.   - RESOURCE_POOL_TYPE_UNSPECIFIED: Unspecified. This value will never actually be returned by the API, it is just an artifact of using protobuf.  - RESOURCE_POOL_TYPE_AWS: An AWS resource pool.  - RESOURCE_POOL_TYPE_GCP: A GCP resource pool.  - RESOURCE_POOL_TYPE_STATIC: A static resource pool.  - RESOURCE_POOL_TYPE_K8S: The kubernetes resource pool.
+ * The type of the ResourcePool.   - RESOURCE_POOL_TYPE_UNSPECIFIED: Unspecified. This value will never actually be returned by the API, it is just an artifact of using protobuf.  - RESOURCE_POOL_TYPE_AWS: An AWS resource pool.  - RESOURCE_POOL_TYPE_GCP: A GCP resource pool.  - RESOURCE_POOL_TYPE_STATIC: A static resource pool.  - RESOURCE_POOL_TYPE_K8S: The kubernetes resource pool.  - RESOURCE_POOL_TYPE_AZURE: An Azure resource pool.
  * @export
  * @enum {string}
  */
@@ -4451,7 +5036,8 @@ export enum V1ResourcePoolType {
     AWS = <any> 'RESOURCE_POOL_TYPE_AWS',
     GCP = <any> 'RESOURCE_POOL_TYPE_GCP',
     STATIC = <any> 'RESOURCE_POOL_TYPE_STATIC',
-    K8S = <any> 'RESOURCE_POOL_TYPE_K8S'
+    K8S = <any> 'RESOURCE_POOL_TYPE_K8S',
+    AZURE = <any> 'RESOURCE_POOL_TYPE_AZURE'
 }
 
 /**
@@ -4806,6 +5392,70 @@ export interface V1Slot {
     draining?: boolean;
 }
 
+/**
+ * Response to StopForwardingAllocationPortRequest.
+ * @export
+ * @interface V1StopForwardingAllocationPortResponse
+ */
+export interface V1StopForwardingAllocationPortResponse {
+}
+
+/**
+ * Response to StreamUpdatesRequest: one state change.
+ * @export
+ * @interface V1StreamUpdatesResponse
+ */
+export interface V1StreamUpdatesResponse {
+    /**
+     * The sequence number of the update, increasing across all updates.
+     * @type {string}
+     * @memberof V1StreamUpdatesResponse
+     */
+    seq: string;
+    /**
+     * The kind of object whose state changed.
+     * @type {V1UpdateKind}
+     * @memberof V1StreamUpdatesResponse
+     */
+    kind: V1UpdateKind;
+    /**
+     * The experiment of the object.
+     * @type {number}
+     * @memberof V1StreamUpdatesResponse
+     */
+    experimentId: number;
+    /**
+     * The trial of the object, for trial, checkpoint and allocation updates.
+     * @type {number}
+     * @memberof V1StreamUpdatesResponse
+     */
+    trialId?: number;
+    /**
+     * The id of the allocation, for allocation updates.
+     * @type {string}
+     * @memberof V1StreamUpdatesResponse
+     */
+    allocationId?: string;
+    /**
+     * The uuid of the checkpoint, for checkpoint updates.
+     * @type {string}
+     * @memberof V1StreamUpdatesResponse
+     */
+    checkpointUuid?: string;
+    /**
+     * The new state of the object.
+     * @type {string}
+     * @memberof V1StreamUpdatesResponse
+     */
+    state: string;
+    /**
+     * The time of the state change.
+     * @type {Date}
+     * @memberof V1StreamUpdatesResponse
+     */
+    time: Date;
+}
+
 /**
  * Templates move settings that are shared by many experiments into a single YAML file.
  * @export
@@ -5266,6 +5916,19 @@ export interface V1UnarchiveExperimentResponse {
 export interface V1UnarchiveModelResponse {
 }
 
+/**
+ * The kind of object whose state changed.   - UPDATE_KIND_UNSPECIFIED: Zero-value (not allowed).  - UPDATE_KIND_EXPERIMENT: The state of an experiment changed.  - UPDATE_KIND_TRIAL: The state of a trial changed.  - UPDATE_KIND_CHECKPOINT: A checkpoint of a trial was reported.  - UPDATE_KIND_ALLOCATION: The state of an allocation of a trial changed.
+ * @export
+ * @enum {string}
+ */
+export enum V1UpdateKind {
+    UNSPECIFIED = <any> 'UPDATE_KIND_UNSPECIFIED',
+    EXPERIMENT = <any> 'UPDATE_KIND_EXPERIMENT',
+    TRIAL = <any> 'UPDATE_KIND_TRIAL',
+    CHECKPOINT = <any> 'UPDATE_KIND_CHECKPOINT',
+    ALLOCATION = <any> 'UPDATE_KIND_ALLOCATION'
+}
+
 /**
  * User is an account in the determined cluster.
  * @export
@@ -5971,6 +6634,58 @@ export const ClusterApiFetchParamCreator = function (configuration?: Configurati
                 options: localVarRequestOptions,
             };
         },
+        /**
+         * 
+         * @summary Forward a port of a running container of an allocation through the master proxy until the expiry. The port is then accessible at /proxy/{service_id}/, over HTTP and WebSocket or, for TCP ports, with the TCP over WebSocket tunnel of the CLI.
+         * @param {string} allocationId The id of the allocation.
+         * @param {number} port The port to forward, which the container must expose.
+         * @param {V1ForwardAllocationPortRequest} body 
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        forwardAllocationPort(allocationId: string, port: number, body: V1ForwardAllocationPortRequest, options: any = {}): FetchArgs {
+            // verify required parameter 'allocationId' is not null or undefined
+            if (allocationId === null || allocationId === undefined) {
+                throw new RequiredError('allocationId','Required parameter allocationId was null or undefined when calling forwardAllocationPort.');
+            }
+            // verify required parameter 'port' is not null or undefined
+            if (port === null || port === undefined) {
+                throw new RequiredError('port','Required parameter port was null or undefined when calling forwardAllocationPort.');
+            }
+            // verify required parameter 'body' is not null or undefined
+            if (body === null || body === undefined) {
+                throw new RequiredError('body','Required parameter body was null or undefined when calling forwardAllocationPort.');
+            }
+            const localVarPath = `/api/v1/allocations/{allocationId}/ports/{port}`
+                .replace(`{${"allocationId"}}`, encodeURIComponent(String(allocationId)))
+                .replace(`{${"port"}}`, encodeURIComponent(String(port)));
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'POST' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication BearerToken required
+            if (configuration && configuration.apiKey) {
+                const localVarApiKeyValue = typeof configuration.apiKey === 'function'
+					? configuration.apiKey("Authorization")
+					: configuration.apiKey;
+                localVarHeaderParameter["Authorization"] = localVarApiKeyValue;
+            }
+
+            localVarHeaderParameter['Content-Type'] = 'application/json';
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            delete localVarUrlObj.search;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+            const needsSerialization = (<any>"V1ForwardAllocationPortRequest" !== "string") || localVarRequestOptions.headers['Content-Type'] === 'application/json';
+            localVarRequestOptions.body =  needsSerialization ? JSON.stringify(body || {}) : (body || "");
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
         /**
          * 
          * @summary Get the requested agent.
@@ -6122,6 +6837,77 @@ export const ClusterApiFetchParamCreator = function (configuration?: Configurati
                 options: localVarRequestOptions,
             };
         },
+        /**
+         * 
+         * @summary Get the audit log of mutating API calls. Only admins may read it.
+         * @param {number} [offset] Skip the number of entries before returning results. Negative values denote number of entries to skip from the end before returning results.
+         * @param {number} [limit] Limit the number of entries. A value of 0 denotes no limit.
+         * @param {Array<string>} [usernames] Limit the entries to calls made by these users.
+         * @param {string} [method] Limit the entries to calls of methods containing this string, e.g., KillExperiment.
+         * @param {string} [targetId] Limit the entries to calls that targeted an object with this id.
+         * @param {string} [resultCode] Limit the entries to calls with this result code.
+         * @param {Date} [since] Limit the entries to calls made at or after this time.
+         * @param {Date} [until] Limit the entries to calls made before this time.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getAuditLog(offset?: number, limit?: number, usernames?: Array<string>, method?: string, targetId?: string, resultCode?: string, since?: Date, until?: Date, options: any = {}): FetchArgs {
+            const localVarPath = `/api/v1/audit-log`;
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'GET' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication BearerToken required
+            if (configuration && configuration.apiKey) {
+                const localVarApiKeyValue = typeof configuration.apiKey === 'function'
+					? configuration.apiKey("Authorization")
+					: configuration.apiKey;
+                localVarHeaderParameter["Authorization"] = localVarApiKeyValue;
+            }
+
+            if (offset !== undefined) {
+                localVarQueryParameter['offset'] = offset;
+            }
+
+            if (limit !== undefined) {
+                localVarQueryParameter['limit'] = limit;
+            }
+
+            if (usernames) {
+                localVarQueryParameter['usernames'] = usernames;
+            }
+
+            if (method !== undefined) {
+                localVarQueryParameter['method'] = method;
+            }
+
+            if (targetId !== undefined) {
+                localVarQueryParameter['targetId'] = targetId;
+            }
+
+            if (resultCode !== undefined) {
+                localVarQueryParameter['resultCode'] = resultCode;
+            }
+
+            if (since !== undefined) {
+                localVarQueryParameter['since'] = since;
+            }
+
+            if (until !== undefined) {
+                localVarQueryParameter['until'] = until;
+            }
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            delete localVarUrlObj.search;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
         /**
          * 
          * @summary Get master information.
@@ -6176,6 +6962,42 @@ export const ClusterApiFetchParamCreator = function (configuration?: Configurati
                 options: localVarRequestOptions,
             };
         },
+        /**
+         * 
+         * @summary Get the tasks that are waiting for resources and why the scheduler has not allocated resources to them.
+         * @param {string} [resourcePool] Only return the tasks waiting for this resource pool.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getPendingTasks(resourcePool?: string, options: any = {}): FetchArgs {
+            const localVarPath = `/api/v1/tasks/pending`;
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'GET' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication BearerToken required
+            if (configuration && configuration.apiKey) {
+                const localVarApiKeyValue = typeof configuration.apiKey === 'function'
+					? configuration.apiKey("Authorization")
+					: configuration.apiKey;
+                localVarHeaderParameter["Authorization"] = localVarApiKeyValue;
+            }
+
+            if (resourcePool !== undefined) {
+                localVarQueryParameter['resourcePool'] = resourcePool;
+            }
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            delete localVarUrlObj.search;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
         /**
          * 
          * @summary Get a detailed view of resource allocation during the given time period (CSV).
@@ -6438,7 +7260,96 @@ export const ClusterApiFetchParamCreator = function (configuration?: Configurati
                 options: localVarRequestOptions,
             };
         },
-    }
+        /**
+         * 
+         * @summary Get the cost of the provisioned instances during the given time period, attributed to experiments, users, labels and resource pools.
+         * @param {Date} [timestampAfter] The start of the period to consider.
+         * @param {Date} [timestampBefore] The end of the period to consider.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        resourceCost(timestampAfter?: Date, timestampBefore?: Date, options: any = {}): FetchArgs {
+            const localVarPath = `/api/v1/resources/cost`;
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'GET' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication BearerToken required
+            if (configuration && configuration.apiKey) {
+                const localVarApiKeyValue = typeof configuration.apiKey === 'function'
+					? configuration.apiKey("Authorization")
+					: configuration.apiKey;
+                localVarHeaderParameter["Authorization"] = localVarApiKeyValue;
+            }
+
+            if (timestampAfter !== undefined) {
+                localVarQueryParameter['timestampAfter'] = (timestampAfter as any).toISOString();
+            }
+
+            if (timestampBefore !== undefined) {
+                localVarQueryParameter['timestampBefore'] = (timestampBefore as any).toISOString();
+            }
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            delete localVarUrlObj.search;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+    }        /**
+         * 
+         * @summary Stop forwarding a port of a container of an allocation.
+         * @param {string} allocationId The id of the allocation.
+         * @param {number} port The forwarded port.
+         * @param {string} [containerId] The id of the container, which may be omitted if the allocation has only one container.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        stopForwardingAllocationPort(allocationId: string, port: number, containerId?: string, options: any = {}): FetchArgs {
+            // verify required parameter 'allocationId' is not null or undefined
+            if (allocationId === null || allocationId === undefined) {
+                throw new RequiredError('allocationId','Required parameter allocationId was null or undefined when calling stopForwardingAllocationPort.');
+            }
+            // verify required parameter 'port' is not null or undefined
+            if (port === null || port === undefined) {
+                throw new RequiredError('port','Required parameter port was null or undefined when calling stopForwardingAllocationPort.');
+            }
+            const localVarPath = `/api/v1/allocations/{allocationId}/ports/{port}`
+                .replace(`{${"allocationId"}}`, encodeURIComponent(String(allocationId)))
+                .replace(`{${"port"}}`, encodeURIComponent(String(port)));
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'DELETE' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication BearerToken required
+            if (configuration && configuration.apiKey) {
+                const localVarApiKeyValue = typeof configuration.apiKey === 'function'
+					? configuration.apiKey("Authorization")
+					: configuration.apiKey;
+                localVarHeaderParameter["Authorization"] = localVarApiKeyValue;
+            }
+
+            if (containerId !== undefined) {
+                localVarQueryParameter['containerId'] = containerId;
+            }
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            delete localVarUrlObj.search;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
+
 };
 
 /**
@@ -6526,6 +7437,27 @@ export const ClusterApiFp = function(configuration?: Configuration) {
                 });
             };
         },
+        /**
+         * 
+         * @summary Forward a port of a running container of an allocation through the master proxy until the expiry. The port is then accessible at /proxy/{service_id}/, over HTTP and WebSocket or, for TCP ports, with the TCP over WebSocket tunnel of the CLI.
+         * @param {string} allocationId The id of the allocation.
+         * @param {number} port The port to forward, which the container must expose.
+         * @param {V1ForwardAllocationPortRequest} body 
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        forwardAllocationPort(allocationId: string, port: number, body: V1ForwardAllocationPortRequest, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<V1ForwardAllocationPortResponse> {
+            const localVarFetchArgs = ClusterApiFetchParamCreator(configuration).forwardAllocationPort(allocationId, port, body, options);
+            return (fetch: FetchAPI = portableFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
         /**
          * 
          * @summary Get the requested agent.
@@ -6589,6 +7521,32 @@ export const ClusterApiFp = function(configuration?: Configuration) {
                 });
             };
         },
+        /**
+         * 
+         * @summary Get the audit log of mutating API calls. Only admins may read it.
+         * @param {number} [offset] Skip the number of entries before returning results. Negative values denote number of entries to skip from the end before returning results.
+         * @param {number} [limit] Limit the number of entries. A value of 0 denotes no limit.
+         * @param {Array<string>} [usernames] Limit the entries to calls made by these users.
+         * @param {string} [method] Limit the entries to calls of methods containing this string, e.g., KillExperiment.
+         * @param {string} [targetId] Limit the entries to calls that targeted an object with this id.
+         * @param {string} [resultCode] Limit the entries to calls with this result code.
+         * @param {Date} [since] Limit the entries to calls made at or after this time.
+         * @param {Date} [until] Limit the entries to calls made before this time.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getAuditLog(offset?: number, limit?: number, usernames?: Array<string>, method?: string, targetId?: string, resultCode?: string, since?: Date, until?: Date, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<V1GetAuditLogResponse> {
+            const localVarFetchArgs = ClusterApiFetchParamCreator(configuration).getAuditLog(offset, limit, usernames, method, targetId, resultCode, since, until, options);
+            return (fetch: FetchAPI = portableFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
         /**
          * 
          * @summary Get master information.
@@ -6625,6 +7583,25 @@ export const ClusterApiFp = function(configuration?: Configuration) {
                 });
             };
         },
+        /**
+         * 
+         * @summary Get the tasks that are waiting for resources and why the scheduler has not allocated resources to them.
+         * @param {string} [resourcePool] Only return the tasks waiting for this resource pool.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getPendingTasks(resourcePool?: string, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<V1GetPendingTasksResponse> {
+            const localVarFetchArgs = ClusterApiFetchParamCreator(configuration).getPendingTasks(resourcePool, options);
+            return (fetch: FetchAPI = portableFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
         /**
          * 
          * @summary Get a detailed view of resource allocation during the given time period (CSV).
@@ -6746,7 +7723,48 @@ export const ClusterApiFp = function(configuration?: Configuration) {
                 });
             };
         },
-    }
+        /**
+         * 
+         * @summary Get the cost of the provisioned instances during the given time period, attributed to experiments, users, labels and resource pools.
+         * @param {Date} [timestampAfter] The start of the period to consider.
+         * @param {Date} [timestampBefore] The end of the period to consider.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        resourceCost(timestampAfter?: Date, timestampBefore?: Date, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<V1ResourceCostResponse> {
+            const localVarFetchArgs = ClusterApiFetchParamCreator(configuration).resourceCost(timestampAfter, timestampBefore, options);
+            return (fetch: FetchAPI = portableFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
+    }        /**
+         * 
+         * @summary Stop forwarding a port of a container of an allocation.
+         * @param {string} allocationId The id of the allocation.
+         * @param {number} port The forwarded port.
+         * @param {string} [containerId] The id of the container, which may be omitted if the allocation has only one container.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        stopForwardingAllocationPort(allocationId: string, port: number, containerId?: string, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<V1StopForwardingAllocationPortResponse> {
+            const localVarFetchArgs = ClusterApiFetchParamCreator(configuration).stopForwardingAllocationPort(allocationId, port, containerId, options);
+            return (fetch: FetchAPI = portableFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
+
 };
 
 /**
@@ -6798,6 +7816,18 @@ export const ClusterApiFactory = function (configuration?: Configuration, fetch?
         enableSlot(agentId: string, slotId: string, options?: any) {
             return ClusterApiFp(configuration).enableSlot(agentId, slotId, options)(fetch, basePath);
         },
+        /**
+         * 
+         * @summary Forward a port of a running container of an allocation through the master proxy until the expiry. The port is then accessible at /proxy/{service_id}/, over HTTP and WebSocket or, for TCP ports, with the TCP over WebSocket tunnel of the CLI.
+         * @param {string} allocationId The id of the allocation.
+         * @param {number} port The port to forward, which the container must expose.
+         * @param {V1ForwardAllocationPortRequest} body 
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        forwardAllocationPort(allocationId: string, port: number, body: V1ForwardAllocationPortRequest, options?: any) {
+            return ClusterApiFp(configuration).forwardAllocationPort(allocationId, port, body, options)(fetch, basePath);
+        },
         /**
          * 
          * @summary Get the requested agent.
@@ -6834,6 +7864,23 @@ export const ClusterApiFactory = function (configuration?: Configuration, fetch?
         getAggregatedResourceAllocationCsv(startDate: string, endDate: string, period: string, options?: any) {
             return ClusterApiFp(configuration).getAggregatedResourceAllocationCsv(startDate, endDate, period, options)(fetch, basePath);
         },
+        /**
+         * 
+         * @summary Get the audit log of mutating API calls. Only admins may read it.
+         * @param {number} [offset] Skip the number of entries before returning results. Negative values denote number of entries to skip from the end before returning results.
+         * @param {number} [limit] Limit the number of entries. A value of 0 denotes no limit.
+         * @param {Array<string>} [usernames] Limit the entries to calls made by these users.
+         * @param {string} [method] Limit the entries to calls of methods containing this string, e.g., KillExperiment.
+         * @param {string} [targetId] Limit the entries to calls that targeted an object with this id.
+         * @param {string} [resultCode] Limit the entries to calls with this result code.
+         * @param {Date} [since] Limit the entries to calls made at or after this time.
+         * @param {Date} [until] Limit the entries to calls made before this time.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getAuditLog(offset?: number, limit?: number, usernames?: Array<string>, method?: string, targetId?: string, resultCode?: string, since?: Date, until?: Date, options?: any) {
+            return ClusterApiFp(configuration).getAuditLog(offset, limit, usernames, method, targetId, resultCode, since, until, options)(fetch, basePath);
+        },
         /**
          * 
          * @summary Get master information.
@@ -6852,6 +7899,16 @@ export const ClusterApiFactory = function (configuration?: Configuration, fetch?
         getMasterConfig(options?: any) {
             return ClusterApiFp(configuration).getMasterConfig(options)(fetch, basePath);
         },
+        /**
+         * 
+         * @summary Get the tasks that are waiting for resources and why the scheduler has not allocated resources to them.
+         * @param {string} [resourcePool] Only return the tasks waiting for this resource pool.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getPendingTasks(resourcePool?: string, options?: any) {
+            return ClusterApiFp(configuration).getPendingTasks(resourcePool, options)(fetch, basePath);
+        },
         /**
          * 
          * @summary Get a detailed view of resource allocation during the given time period (CSV).
@@ -6919,7 +7976,30 @@ export const ClusterApiFactory = function (configuration?: Configuration, fetch?
         resourceAllocationRaw(timestampAfter?: Date, timestampBefore?: Date, options?: any) {
             return ClusterApiFp(configuration).resourceAllocationRaw(timestampAfter, timestampBefore, options)(fetch, basePath);
         },
-    };
+        /**
+         * 
+         * @summary Get the cost of the provisioned instances during the given time period, attributed to experiments, users, labels and resource pools.
+         * @param {Date} [timestampAfter] The start of the period to consider.
+         * @param {Date} [timestampBefore] The end of the period to consider.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        resourceCost(timestampAfter?: Date, timestampBefore?: Date, options?: any) {
+            return ClusterApiFp(configuration).resourceCost(timestampAfter, timestampBefore, options)(fetch, basePath);
+        },
+    };        /**
+         * 
+         * @summary Stop forwarding a port of a container of an allocation.
+         * @param {string} allocationId The id of the allocation.
+         * @param {number} port The forwarded port.
+         * @param {string} [containerId] The id of the container, which may be omitted if the allocation has only one container.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        stopForwardingAllocationPort(allocationId: string, port: number, containerId?: string, options?: any) {
+            return ClusterApiFp(configuration).stopForwardingAllocationPort(allocationId, port, containerId, options)(fetch, basePath);
+        },
+
 };
 
 /**
@@ -6980,6 +8060,20 @@ export class ClusterApi extends BaseAPI {
         return ClusterApiFp(this.configuration).enableSlot(agentId, slotId, options)(this.fetch, this.basePath);
     }
 
+    /**
+     * 
+     * @summary Forward a port of a running container of an allocation through the master proxy until the expiry. The port is then accessible at /proxy/{service_id}/, over HTTP and WebSocket or, for TCP ports, with the TCP over WebSocket tunnel of the CLI.
+     * @param {string} allocationId The id of the allocation.
+     * @param {number} port The port to forward, which the container must expose.
+     * @param {V1ForwardAllocationPortRequest} body 
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof ClusterApi
+     */
+    public forwardAllocationPort(allocationId: string, port: number, body: V1ForwardAllocationPortRequest, options?: any) {
+        return ClusterApiFp(this.configuration).forwardAllocationPort(allocationId, port, body, options)(this.fetch, this.basePath);
+    }
+
     /**
      * 
      * @summary Get the requested agent.
@@ -7022,6 +8116,25 @@ export class ClusterApi extends BaseAPI {
         return ClusterApiFp(this.configuration).getAggregatedResourceAllocationCsv(startDate, endDate, period, options)(this.fetch, this.basePath);
     }
 
+    /**
+     * 
+     * @summary Get the audit log of mutating API calls. Only admins may read it.
+     * @param {number} [offset] Skip the number of entries before returning results. Negative values denote number of entries to skip from the end before returning results.
+     * @param {number} [limit] Limit the number of entries. A value of 0 denotes no limit.
+     * @param {Array<string>} [usernames] Limit the entries to calls made by these users.
+     * @param {string} [method] Limit the entries to calls of methods containing this string, e.g., KillExperiment.
+     * @param {string} [targetId] Limit the entries to calls that targeted an object with this id.
+     * @param {string} [resultCode] Limit the entries to calls with this result code.
+     * @param {Date} [since] Limit the entries to calls made at or after this time.
+     * @param {Date} [until] Limit the entries to calls made before this time.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof ClusterApi
+     */
+    public getAuditLog(offset?: number, limit?: number, usernames?: Array<string>, method?: string, targetId?: string, resultCode?: string, since?: Date, until?: Date, options?: any) {
+        return ClusterApiFp(this.configuration).getAuditLog(offset, limit, usernames, method, targetId, resultCode, since, until, options)(this.fetch, this.basePath);
+    }
+
     /**
      * 
      * @summary Get master information.
@@ -7044,6 +8157,18 @@ export class ClusterApi extends BaseAPI {
         return ClusterApiFp(this.configuration).getMasterConfig(options)(this.fetch, this.basePath);
     }
 
+    /**
+     * 
+     * @summary Get the tasks that are waiting for resources and why the scheduler has not allocated resources to them.
+     * @param {string} [resourcePool] Only return the tasks waiting for this resource pool.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof ClusterApi
+     */
+    public getPendingTasks(resourcePool?: string, options?: any) {
+        return ClusterApiFp(this.configuration).getPendingTasks(resourcePool, options)(this.fetch, this.basePath);
+    }
+
     /**
      * 
      * @summary Get a detailed view of resource allocation during the given time period (CSV).
@@ -7123,6 +8248,33 @@ export class ClusterApi extends BaseAPI {
         return ClusterApiFp(this.configuration).resourceAllocationRaw(timestampAfter, timestampBefore, options)(this.fetch, this.basePath);
     }
 
+    /**
+     * 
+     * @summary Get the cost of the provisioned instances during the given time period, attributed to experiments, users, labels and resource pools.
+     * @param {Date} [timestampAfter] The start of the period to consider.
+     * @param {Date} [timestampBefore] The end of the period to consider.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof ClusterApi
+     */
+    public resourceCost(timestampAfter?: Date, timestampBefore?: Date, options?: any) {
+        return ClusterApiFp(this.configuration).resourceCost(timestampAfter, timestampBefore, options)(this.fetch, this.basePath);
+    }
+    /**
+     * 
+     * @summary Stop forwarding a port of a container of an allocation.
+     * @param {string} allocationId The id of the allocation.
+     * @param {number} port The forwarded port.
+     * @param {string} [containerId] The id of the container, which may be omitted if the allocation has only one container.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof ClusterApi
+     */
+    public stopForwardingAllocationPort(allocationId: string, port: number, containerId?: string, options?: any) {
+        return ClusterApiFp(this.configuration).stopForwardingAllocationPort(allocationId, port, containerId, options)(this.fetch, this.basePath);
+    }
+
+
 }
 
 /**
@@ -7714,6 +8866,85 @@ export const ExperimentsApiFetchParamCreator = function (configuration?: Configu
                 options: localVarRequestOptions,
             };
         },
+        /**
+         * 
+         * @summary Compare metrics across experiments and trials, aligned on the number of batches processed.
+         * @param {Array<string>} metricNames The names of the metrics.
+         * @param {'METRIC_TYPE_UNSPECIFIED' | 'METRIC_TYPE_TRAINING' | 'METRIC_TYPE_VALIDATION'} metricType The type of the metrics.   - METRIC_TYPE_UNSPECIFIED: Zero-value (not allowed).  - METRIC_TYPE_TRAINING: For metrics emitted during training.  - METRIC_TYPE_VALIDATION: For metrics emitted during validation.
+         * @param {Array<number>} [experimentIds] The ids of the experiments to include all trials of.
+         * @param {Array<number>} [trialIds] The ids of other trials to include.
+         * @param {number} [startBatches] Beginning of window (inclusive) to fetch data for.
+         * @param {number} [endBatches] Ending of window (inclusive) to fetch data for.
+         * @param {number} [maxDatapoints] Maximum number of data points in each series.
+         * @param {'METRIC_AGGREGATION_UNSPECIFIED' | 'METRIC_AGGREGATION_MEAN' | 'METRIC_AGGREGATION_MIN' | 'METRIC_AGGREGATION_MAX'} [aggregation] How to aggregate the series over the trials of each experiment.   - METRIC_AGGREGATION_UNSPECIFIED: Do not aggregate the series.  - METRIC_AGGREGATION_MEAN: The mean over the trials.  - METRIC_AGGREGATION_MIN: The minimum over the trials.  - METRIC_AGGREGATION_MAX: The maximum over the trials.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        compareMetrics(metricNames: Array<string>, metricType: 'METRIC_TYPE_UNSPECIFIED' | 'METRIC_TYPE_TRAINING' | 'METRIC_TYPE_VALIDATION', experimentIds?: Array<number>, trialIds?: Array<number>, startBatches?: number, endBatches?: number, maxDatapoints?: number, aggregation?: 'METRIC_AGGREGATION_UNSPECIFIED' | 'METRIC_AGGREGATION_MEAN' | 'METRIC_AGGREGATION_MIN' | 'METRIC_AGGREGATION_MAX', options: any = {}): FetchArgs {
+            // verify required parameter 'metricNames' is not null or undefined
+            if (metricNames === null || metricNames === undefined) {
+                throw new RequiredError('metricNames','Required parameter metricNames was null or undefined when calling compareMetrics.');
+            }
+            // verify required parameter 'metricType' is not null or undefined
+            if (metricType === null || metricType === undefined) {
+                throw new RequiredError('metricType','Required parameter metricType was null or undefined when calling compareMetrics.');
+            }
+            const localVarPath = `/api/v1/metrics/compare`;
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'GET' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication BearerToken required
+            if (configuration && configuration.apiKey) {
+                const localVarApiKeyValue = typeof configuration.apiKey === 'function'
+					? configuration.apiKey("Authorization")
+					: configuration.apiKey;
+                localVarHeaderParameter["Authorization"] = localVarApiKeyValue;
+            }
+
+            if (metricNames) {
+                localVarQueryParameter['metricNames'] = metricNames;
+            }
+
+            if (metricType !== undefined) {
+                localVarQueryParameter['metricType'] = metricType;
+            }
+
+            if (experimentIds) {
+                localVarQueryParameter['experimentIds'] = experimentIds;
+            }
+
+            if (trialIds) {
+                localVarQueryParameter['trialIds'] = trialIds;
+            }
+
+            if (startBatches !== undefined) {
+                localVarQueryParameter['startBatches'] = startBatches;
+            }
+
+            if (endBatches !== undefined) {
+                localVarQueryParameter['endBatches'] = endBatches;
+            }
+
+            if (maxDatapoints !== undefined) {
+                localVarQueryParameter['maxDatapoints'] = maxDatapoints;
+            }
+
+            if (aggregation !== undefined) {
+                localVarQueryParameter['aggregation'] = aggregation;
+            }
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            delete localVarUrlObj.search;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
         /**
          * 
          * @summary Delete the requested experiment.
@@ -7886,6 +9117,48 @@ export const ExperimentsApiFetchParamCreator = function (configuration?: Configu
                 options: localVarRequestOptions,
             };
         },
+        /**
+         * 
+         * @summary Get the firings of the metric rules of an experiment.
+         * @param {number} experimentId The id of the experiment.
+         * @param {number} [trialId] Limit the firings to those of the trial with this id, if set.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getExperimentMetricRuleFirings(experimentId: number, trialId?: number, options: any = {}): FetchArgs {
+            // verify required parameter 'experimentId' is not null or undefined
+            if (experimentId === null || experimentId === undefined) {
+                throw new RequiredError('experimentId','Required parameter experimentId was null or undefined when calling getExperimentMetricRuleFirings.');
+            }
+            const localVarPath = `/api/v1/experiments/{experimentId}/metric-rule-firings`
+                .replace(`{${"experimentId"}}`, encodeURIComponent(String(experimentId)));
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'GET' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication BearerToken required
+            if (configuration && configuration.apiKey) {
+                const localVarApiKeyValue = typeof configuration.apiKey === 'function'
+					? configuration.apiKey("Authorization")
+					: configuration.apiKey;
+                localVarHeaderParameter["Authorization"] = localVarApiKeyValue;
+            }
+
+            if (trialId !== undefined) {
+                localVarQueryParameter['trialId'] = trialId;
+            }
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            delete localVarUrlObj.search;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
         /**
          * 
          * @summary Get the list of trials for an experiment.
@@ -8404,6 +9677,57 @@ export const ExperimentsApiFetchParamCreator = function (configuration?: Configu
                 options: localVarRequestOptions,
             };
         },
+        /**
+         * 
+         * @summary Stream the state changes of experiments, trials, checkpoints and allocations. Over HTTP, updates are streamed as newline-delimited JSON, or as server-sent events if the request accepts text/event-stream.
+         * @param {string} [sinceSeq] Only stream the updates after this sequence number, to resume a stream after reconnecting. If not set, only new updates are streamed.
+         * @param {Array<number>} [experimentIds] Limit the updates to those of these experiments.
+         * @param {Array<string>} [users] Limit the updates to those of the experiments of these users.
+         * @param {Array<string>} [labels] Limit the updates to those of the experiments with any of these labels.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        streamUpdates(sinceSeq?: string, experimentIds?: Array<number>, users?: Array<string>, labels?: Array<string>, options: any = {}): FetchArgs {
+            const localVarPath = `/api/v1/updates`;
+            const localVarUrlObj = url.parse(localVarPath, true);
+            const localVarRequestOptions = Object.assign({ method: 'GET' }, options);
+            const localVarHeaderParameter = {} as any;
+            const localVarQueryParameter = {} as any;
+
+            // authentication BearerToken required
+            if (configuration && configuration.apiKey) {
+                const localVarApiKeyValue = typeof configuration.apiKey === 'function'
+					? configuration.apiKey("Authorization")
+					: configuration.apiKey;
+                localVarHeaderParameter["Authorization"] = localVarApiKeyValue;
+            }
+
+            if (sinceSeq !== undefined) {
+                localVarQueryParameter['sinceSeq'] = sinceSeq;
+            }
+
+            if (experimentIds) {
+                localVarQueryParameter['experimentIds'] = experimentIds;
+            }
+
+            if (users) {
+                localVarQueryParameter['users'] = users;
+            }
+
+            if (labels) {
+                localVarQueryParameter['labels'] = labels;
+            }
+
+            localVarUrlObj.query = Object.assign({}, localVarUrlObj.query, localVarQueryParameter, options.query);
+            // fix override query string Detail: https://stackoverflow.com/a/7517673/1077943
+            delete localVarUrlObj.search;
+            localVarRequestOptions.headers = Object.assign({}, localVarHeaderParameter, options.headers);
+
+            return {
+                url: url.format(localVarUrlObj),
+                options: localVarRequestOptions,
+            };
+        },
         /**
          * 
          * @summary Stream trial logs.
@@ -8641,6 +9965,32 @@ export const ExperimentsApiFp = function(configuration?: Configuration) {
                 });
             };
         },
+        /**
+         * 
+         * @summary Compare metrics across experiments and trials, aligned on the number of batches processed.
+         * @param {Array<string>} metricNames The names of the metrics.
+         * @param {'METRIC_TYPE_UNSPECIFIED' | 'METRIC_TYPE_TRAINING' | 'METRIC_TYPE_VALIDATION'} metricType The type of the metrics.   - METRIC_TYPE_UNSPECIFIED: Zero-value (not allowed).  - METRIC_TYPE_TRAINING: For metrics emitted during training.  - METRIC_TYPE_VALIDATION: For metrics emitted during validation.
+         * @param {Array<number>} [experimentIds] The ids of the experiments to include all trials of.
+         * @param {Array<number>} [trialIds] The ids of other trials to include.
+         * @param {number} [startBatches] Beginning of window (inclusive) to fetch data for.
+         * @param {number} [endBatches] Ending of window (inclusive) to fetch data for.
+         * @param {number} [maxDatapoints] Maximum number of data points in each series.
+         * @param {'METRIC_AGGREGATION_UNSPECIFIED' | 'METRIC_AGGREGATION_MEAN' | 'METRIC_AGGREGATION_MIN' | 'METRIC_AGGREGATION_MAX'} [aggregation] How to aggregate the series over the trials of each experiment.   - METRIC_AGGREGATION_UNSPECIFIED: Do not aggregate the series.  - METRIC_AGGREGATION_MEAN: The mean over the trials.  - METRIC_AGGREGATION_MIN: The minimum over the trials.  - METRIC_AGGREGATION_MAX: The maximum over the trials.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        compareMetrics(metricNames: Array<string>, metricType: 'METRIC_TYPE_UNSPECIFIED' | 'METRIC_TYPE_TRAINING' | 'METRIC_TYPE_VALIDATION', experimentIds?: Array<number>, trialIds?: Array<number>, startBatches?: number, endBatches?: number, maxDatapoints?: number, aggregation?: 'METRIC_AGGREGATION_UNSPECIFIED' | 'METRIC_AGGREGATION_MEAN' | 'METRIC_AGGREGATION_MIN' | 'METRIC_AGGREGATION_MAX', options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<V1CompareMetricsResponse> {
+            const localVarFetchArgs = ExperimentsApiFetchParamCreator(configuration).compareMetrics(metricNames, metricType, experimentIds, trialIds, startBatches, endBatches, maxDatapoints, aggregation, options);
+            return (fetch: FetchAPI = portableFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
         /**
          * 
          * @summary Delete the requested experiment.
@@ -8722,6 +10072,26 @@ export const ExperimentsApiFp = function(configuration?: Configuration) {
                 });
             };
         },
+        /**
+         * 
+         * @summary Get the firings of the metric rules of an experiment.
+         * @param {number} experimentId The id of the experiment.
+         * @param {number} [trialId] Limit the firings to those of the trial with this id, if set.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getExperimentMetricRuleFirings(experimentId: number, trialId?: number, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<V1GetExperimentMetricRuleFiringsResponse> {
+            const localVarFetchArgs = ExperimentsApiFetchParamCreator(configuration).getExperimentMetricRuleFirings(experimentId, trialId, options);
+            return (fetch: FetchAPI = portableFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
         /**
          * 
          * @summary Get the list of trials for an experiment.
@@ -8952,6 +10322,28 @@ export const ExperimentsApiFp = function(configuration?: Configuration) {
                 });
             };
         },
+        /**
+         * 
+         * @summary Stream the state changes of experiments, trials, checkpoints and allocations. Over HTTP, updates are streamed as newline-delimited JSON, or as server-sent events if the request accepts text/event-stream.
+         * @param {string} [sinceSeq] Only stream the updates after this sequence number, to resume a stream after reconnecting. If not set, only new updates are streamed.
+         * @param {Array<number>} [experimentIds] Limit the updates to those of these experiments.
+         * @param {Array<string>} [users] Limit the updates to those of the experiments of these users.
+         * @param {Array<string>} [labels] Limit the updates to those of the experiments with any of these labels.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        streamUpdates(sinceSeq?: string, experimentIds?: Array<number>, users?: Array<string>, labels?: Array<string>, options?: any): (fetch?: FetchAPI, basePath?: string) => Promise<StreamResultOfV1StreamUpdatesResponse> {
+            const localVarFetchArgs = ExperimentsApiFetchParamCreator(configuration).streamUpdates(sinceSeq, experimentIds, users, labels, options);
+            return (fetch: FetchAPI = portableFetch, basePath: string = BASE_PATH) => {
+                return fetch(basePath + localVarFetchArgs.url, localVarFetchArgs.options).then((response) => {
+                    if (response.status >= 200 && response.status < 300) {
+                        return response.json();
+                    } else {
+                        throw response;
+                    }
+                });
+            };
+        },
         /**
          * 
          * @summary Stream trial logs.
@@ -9060,6 +10452,23 @@ export const ExperimentsApiFactory = function (configuration?: Configuration, fe
         cancelExperiment(id: number, options?: any) {
             return ExperimentsApiFp(configuration).cancelExperiment(id, options)(fetch, basePath);
         },
+        /**
+         * 
+         * @summary Compare metrics across experiments and trials, aligned on the number of batches processed.
+         * @param {Array<string>} metricNames The names of the metrics.
+         * @param {'METRIC_TYPE_UNSPECIFIED' | 'METRIC_TYPE_TRAINING' | 'METRIC_TYPE_VALIDATION'} metricType The type of the metrics.   - METRIC_TYPE_UNSPECIFIED: Zero-value (not allowed).  - METRIC_TYPE_TRAINING: For metrics emitted during training.  - METRIC_TYPE_VALIDATION: For metrics emitted during validation.
+         * @param {Array<number>} [experimentIds] The ids of the experiments to include all trials of.
+         * @param {Array<number>} [trialIds] The ids of other trials to include.
+         * @param {number} [startBatches] Beginning of window (inclusive) to fetch data for.
+         * @param {number} [endBatches] Ending of window (inclusive) to fetch data for.
+         * @param {number} [maxDatapoints] Maximum number of data points in each series.
+         * @param {'METRIC_AGGREGATION_UNSPECIFIED' | 'METRIC_AGGREGATION_MEAN' | 'METRIC_AGGREGATION_MIN' | 'METRIC_AGGREGATION_MAX'} [aggregation] How to aggregate the series over the trials of each experiment.   - METRIC_AGGREGATION_UNSPECIFIED: Do not aggregate the series.  - METRIC_AGGREGATION_MEAN: The mean over the trials.  - METRIC_AGGREGATION_MIN: The minimum over the trials.  - METRIC_AGGREGATION_MAX: The maximum over the trials.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        compareMetrics(metricNames: Array<string>, metricType: 'METRIC_TYPE_UNSPECIFIED' | 'METRIC_TYPE_TRAINING' | 'METRIC_TYPE_VALIDATION', experimentIds?: Array<number>, trialIds?: Array<number>, startBatches?: number, endBatches?: number, maxDatapoints?: number, aggregation?: 'METRIC_AGGREGATION_UNSPECIFIED' | 'METRIC_AGGREGATION_MEAN' | 'METRIC_AGGREGATION_MIN' | 'METRIC_AGGREGATION_MAX', options?: any) {
+            return ExperimentsApiFp(configuration).compareMetrics(metricNames, metricType, experimentIds, trialIds, startBatches, endBatches, maxDatapoints, aggregation, options)(fetch, basePath);
+        },
         /**
          * 
          * @summary Delete the requested experiment.
@@ -9105,6 +10514,17 @@ export const ExperimentsApiFactory = function (configuration?: Configuration, fe
         getExperimentLabels(options?: any) {
             return ExperimentsApiFp(configuration).getExperimentLabels(options)(fetch, basePath);
         },
+        /**
+         * 
+         * @summary Get the firings of the metric rules of an experiment.
+         * @param {number} experimentId The id of the experiment.
+         * @param {number} [trialId] Limit the firings to those of the trial with this id, if set.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        getExperimentMetricRuleFirings(experimentId: number, trialId?: number, options?: any) {
+            return ExperimentsApiFp(configuration).getExperimentMetricRuleFirings(experimentId, trialId, options)(fetch, basePath);
+        },
         /**
          * 
          * @summary Get the list of trials for an experiment.
@@ -9236,6 +10656,19 @@ export const ExperimentsApiFactory = function (configuration?: Configuration, fe
         previewHPSearch(body: V1PreviewHPSearchRequest, options?: any) {
             return ExperimentsApiFp(configuration).previewHPSearch(body, options)(fetch, basePath);
         },
+        /**
+         * 
+         * @summary Stream the state changes of experiments, trials, checkpoints and allocations. Over HTTP, updates are streamed as newline-delimited JSON, or as server-sent events if the request accepts text/event-stream.
+         * @param {string} [sinceSeq] Only stream the updates after this sequence number, to resume a stream after reconnecting. If not set, only new updates are streamed.
+         * @param {Array<number>} [experimentIds] Limit the updates to those of these experiments.
+         * @param {Array<string>} [users] Limit the updates to those of the experiments of these users.
+         * @param {Array<string>} [labels] Limit the updates to those of the experiments with any of these labels.
+         * @param {*} [options] Override http request option.
+         * @throws {RequiredError}
+         */
+        streamUpdates(sinceSeq?: string, experimentIds?: Array<number>, users?: Array<string>, labels?: Array<string>, options?: any) {
+            return ExperimentsApiFp(configuration).streamUpdates(sinceSeq, experimentIds, users, labels, options)(fetch, basePath);
+        },
         /**
          * 
          * @summary Stream trial logs.
@@ -9324,6 +10757,25 @@ export class ExperimentsApi extends BaseAPI {
         return ExperimentsApiFp(this.configuration).cancelExperiment(id, options)(this.fetch, this.basePath);
     }
 
+    /**
+     * 
+     * @summary Compare metrics across experiments and trials, aligned on the number of batches processed.
+     * @param {Array<string>} metricNames The names of the metrics.
+     * @param {'METRIC_TYPE_UNSPECIFIED' | 'METRIC_TYPE_TRAINING' | 'METRIC_TYPE_VALIDATION'} metricType The type of the metrics.   - METRIC_TYPE_UNSPECIFIED: Zero-value (not allowed).  - METRIC_TYPE_TRAINING: For metrics emitted during training.  - METRIC_TYPE_VALIDATION: For metrics emitted during validation.
+     * @param {Array<number>} [experimentIds] The ids of the experiments to include all trials of.
+     * @param {Array<number>} [trialIds] The ids of other trials to include.
+     * @param {number} [startBatches] Beginning of window (inclusive) to fetch data for.
+     * @param {number} [endBatches] Ending of window (inclusive) to fetch data for.
+     * @param {number} [maxDatapoints] Maximum number of data points in each series.
+     * @param {'METRIC_AGGREGATION_UNSPECIFIED' | 'METRIC_AGGREGATION_MEAN' | 'METRIC_AGGREGATION_MIN' | 'METRIC_AGGREGATION_MAX'} [aggregation] How to aggregate the series over the trials of each experiment.   - METRIC_AGGREGATION_UNSPECIFIED: Do not aggregate the series.  - METRIC_AGGREGATION_MEAN: The mean over the trials.  - METRIC_AGGREGATION_MIN: The minimum over the trials.  - METRIC_AGGREGATION_MAX: The maximum over the trials.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof ExperimentsApi
+     */
+    public compareMetrics(metricNames: Array<string>, metricType: 'METRIC_TYPE_UNSPECIFIED' | 'METRIC_TYPE_TRAINING' | 'METRIC_TYPE_VALIDATION', experimentIds?: Array<number>, trialIds?: Array<number>, startBatches?: number, endBatches?: number, maxDatapoints?: number, aggregation?: 'METRIC_AGGREGATION_UNSPECIFIED' | 'METRIC_AGGREGATION_MEAN' | 'METRIC_AGGREGATION_MIN' | 'METRIC_AGGREGATION_MAX', options?: any) {
+        return ExperimentsApiFp(this.configuration).compareMetrics(metricNames, metricType, experimentIds, trialIds, startBatches, endBatches, maxDatapoints, aggregation, options)(this.fetch, this.basePath);
+    }
+
     /**
      * 
      * @summary Delete the requested experiment.
@@ -9377,6 +10829,19 @@ export class ExperimentsApi extends BaseAPI {
         return ExperimentsApiFp(this.configuration).getExperimentLabels(options)(this.fetch, this.basePath);
     }
 
+    /**
+     * 
+     * @summary Get the firings of the metric rules of an experiment.
+     * @param {number} experimentId The id of the experiment.
+     * @param {number} [trialId] Limit the firings to those of the trial with this id, if set.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof ExperimentsApi
+     */
+    public getExperimentMetricRuleFirings(experimentId: number, trialId?: number, options?: any) {
+        return ExperimentsApiFp(this.configuration).getExperimentMetricRuleFirings(experimentId, trialId, options)(this.fetch, this.basePath);
+    }
+
     /**
      * 
      * @summary Get the list of trials for an experiment.
@@ -9530,6 +10995,21 @@ export class ExperimentsApi extends BaseAPI {
         return ExperimentsApiFp(this.configuration).previewHPSearch(body, options)(this.fetch, this.basePath);
     }
 
+    /**
+     * 
+     * @summary Stream the state changes of experiments, trials, checkpoints and allocations. Over HTTP, updates are streamed as newline-delimited JSON, or as server-sent events if the request accepts text/event-stream.
+     * @param {string} [sinceSeq] Only stream the updates after this sequence number, to resume a stream after reconnecting. If not set, only new updates are streamed.
+     * @param {Array<number>} [experimentIds] Limit the updates to those of these experiments.
+     * @param {Array<string>} [users] Limit the updates to those of the experiments of these users.
+     * @param {Array<string>} [labels] Limit the updates to those of the experiments with any of these labels.
+     * @param {*} [options] Override http request option.
+     * @throws {RequiredError}
+     * @memberof ExperimentsApi
+     */
+    public streamUpdates(sinceSeq?: string, experimentIds?: Array<number>, users?: Array<string>, labels?: Array<string>, options?: any) {
+        return ExperimentsApiFp(this.configuration).streamUpdates(sinceSeq, experimentIds, users, labels, options)(this.fetch, this.basePath);
+    }
+
     /**
      * 
      * @summary Stream trial logs.